@@ -40,6 +40,7 @@ import (
 	"net/rpc"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/spf13/viper"
@@ -62,8 +63,19 @@ func RegisterAdminHandler(name string, handler interface{}) {
 	_ = instance.rpcServer.RegisterName(name, handler) // @TODO fix ignored error
 }
 
+// RegisterStatusHandler is used by other packages to
+// expose their status over the Status admin function, keyed by name
+func RegisterStatusHandler(name string, handler PackageStatusHandler) {
+	instance.statusHandlersMu.Lock()
+	defer instance.statusHandlersMu.Unlock()
+	instance.statusHandlers[name] = handler
+}
+
 type Admin struct {
 	rpcServer *rpc.Server
+
+	statusHandlersMu sync.RWMutex
+	statusHandlers   map[string]PackageStatusHandler
 }
 
 var (
@@ -73,12 +85,34 @@ var (
 
 func Init() {
 	instance = &Admin{
-		rpcServer: rpc.NewServer(),
+		rpcServer:      rpc.NewServer(),
+		statusHandlers: make(map[string]PackageStatusHandler),
 	}
 	_ = instance.rpcServer.Register(instance) // @TODO fix ignored error
 	pkgLogger = logger.NewLogger().Child("admin")
 }
 
+// Status returns the status of the packages registered as status handlers as json, keyed by their names
+func (a *Admin) Status(_ struct{}, reply *string) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			pkgLogger.Error(r)
+			err = fmt.Errorf("internal Rudder server error: %v", r)
+		}
+	}()
+
+	a.statusHandlersMu.RLock()
+	statuses := make(map[string]interface{}, len(a.statusHandlers))
+	for name, handler := range a.statusHandlers {
+		statuses[name] = handler.Status()
+	}
+	a.statusHandlersMu.RUnlock()
+
+	formattedOutput, err := json.MarshalIndent(statuses, "", "  ")
+	*reply = string(formattedOutput)
+	return err
+}
+
 // ServerConfig fetches current configuration as set in viper
 func (*Admin) ServerConfig(_ struct{}, reply *string) (err error) {
 	defer func() {
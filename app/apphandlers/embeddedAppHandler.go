@@ -11,6 +11,7 @@ import (
 	"github.com/rudderlabs/rudder-go-kit/config"
 	"github.com/rudderlabs/rudder-go-kit/logger"
 	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-server/admin"
 	"github.com/rudderlabs/rudder-server/app"
 	"github.com/rudderlabs/rudder-server/app/cluster"
 	"github.com/rudderlabs/rudder-server/archiver"
@@ -277,6 +278,9 @@ func (a *embeddedApp) StartRudderCore(ctx context.Context, options *app.Options)
 		AdaptiveLimit:    adaptiveLimit,
 	}
 	rt := routerManager.New(rtFactory, brtFactory, backendconfig.DefaultBackendConfig, logger.NewLogger())
+	admin.RegisterAdminHandler("Router", rt.Admin())
+	admin.RegisterStatusHandler("processor", proc)
+	admin.RegisterStatusHandler("router", rt.Admin())
 
 	dm := cluster.Dynamic{
 		Provider:        modeProvider,
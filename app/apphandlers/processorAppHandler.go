@@ -24,6 +24,7 @@ import (
 
 	kithttputil "github.com/rudderlabs/rudder-go-kit/httputil"
 	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-server/admin"
 	"github.com/rudderlabs/rudder-server/app"
 	"github.com/rudderlabs/rudder-server/app/cluster"
 	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
@@ -269,6 +270,9 @@ func (a *processorApp) StartRudderCore(ctx context.Context, options *app.Options
 		AdaptiveLimit:    adaptiveLimit,
 	}
 	rt := routerManager.New(rtFactory, brtFactory, backendconfig.DefaultBackendConfig, logger.NewLogger())
+	admin.RegisterAdminHandler("Router", rt.Admin())
+	admin.RegisterStatusHandler("processor", p)
+	admin.RegisterStatusHandler("router", rt.Admin())
 
 	dm := cluster.Dynamic{
 		Provider:         modeProvider,
@@ -140,6 +140,16 @@ func QueryJobIDStatus(c *cli.Context, dsType string) error {
 	return err
 }
 
+func QueryJobTimeline(c *cli.Context, dsType string) error {
+	var reply string
+	kwargs := c.String("jobid")
+	err := client.GetUDSClient().Call(fmt.Sprintf("%s.GetJobTimeline", getModuleFromType(dsType)), kwargs, &reply)
+	if err == nil {
+		fmt.Println(reply)
+	}
+	return err
+}
+
 func formatResponse(response FailedStatusStats) FailedStatusStats {
 	var updatedResponse FailedStatusStats
 	for _, statusT := range response.FailedStatusStats {
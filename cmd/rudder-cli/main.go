@@ -132,10 +132,23 @@ func main() {
 					Name:  "off, o",
 					Usage: `Use this to turn off explicit warehouse upload triggers. Warehouse uploads will continue to be done as per schedule in control plane.`,
 				},
+				&cli.StringFlag{
+					Name:    "warehouse",
+					Usage:   `Specify the warehouse identifier e.g. POSTGRES:sourceID:destinationID, to trigger the uploads of that warehouse only`,
+					Aliases: []string{"w"},
+				},
 			},
 			Action: func(c *cli.Context) error {
 				var reply string
-				err := client.GetUDSClient().Call("Warehouse.TriggerUpload", c.Bool("off"), &reply)
+				var err error
+				if identifier := c.String("warehouse"); identifier != "" {
+					err = client.GetUDSClient().Call("Warehouse.TriggerWarehouseUpload", struct {
+						WarehouseIdentifier string
+						Off                 bool
+					}{WarehouseIdentifier: identifier, Off: c.Bool("off")}, &reply)
+				} else {
+					err = client.GetUDSClient().Call("Warehouse.TriggerUpload", c.Bool("off"), &reply)
+				}
 				fmt.Println(reply)
 				return err
 			},
@@ -354,6 +367,26 @@ func main() {
 				return err
 			},
 		},
+		{
+			Name:  "job-timeline",
+			Usage: "Get all the statuses of the given Job ID, ordered by exec time",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    "type",
+					Usage:   `Specify ds type, only rt is supported`,
+					Aliases: []string{"t"},
+				},
+				&cli.StringFlag{
+					Name:    "jobid",
+					Usage:   `Specify the jobid`,
+					Aliases: []string{"j"},
+				},
+			},
+			Action: func(c *cli.Context) error {
+				err := db.QueryJobTimeline(c, c.String("type"))
+				return err
+			},
+		},
 		{
 			Name:  "logging",
 			Usage: "Set log level for module. It will affect the module and it's children",
@@ -80,6 +80,23 @@ func storeJobs(ctx context.Context, objects []OrderedJobs, dbHandle *jobsdb.Hand
 	return nil
 }
 
+// storeJobsInBatches stores the jobs of the objects, batchSize objects at a time in the order they were listed
+func storeJobsInBatches(ctx context.Context, objects []OrderedJobs, batchSize int, dbHandle *jobsdb.Handle, log logger.Logger) error {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	for start := 0; start < len(objects); start += batchSize {
+		end := start + batchSize
+		if end > len(objects) {
+			end = len(objects)
+		}
+		if err := storeJobs(ctx, objects[start:end], dbHandle, log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Setup sets up dumps-loader.
 func Setup(ctx context.Context, config *config.Config, db *jobsdb.Handle, tablePrefix string, uploader filemanager.FileManager, bucket string, log logger.Logger) (DumpsLoader, error) {
 	ctx, cancel := context.WithCancel(ctx)
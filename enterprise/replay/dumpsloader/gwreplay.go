@@ -2,6 +2,7 @@ package dumpsloader
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -14,6 +15,7 @@ import (
 	"github.com/rudderlabs/rudder-go-kit/config"
 	"github.com/rudderlabs/rudder-go-kit/filemanager"
 	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/utils/filemanagerutil"
 )
 
 func (g *gwReplayRequestHandler) Start() {
@@ -100,24 +102,17 @@ func (g *gwReplayRequestHandler) fetchDumpsList(ctx context.Context) error {
 				objects = append(objects, OrderedJobs{Job: &job, SortIndex: int(firstEventAt)})
 			}
 		}
-
-		if len(objects) >= uploadMaxItems {
-			err := storeJobs(ctx, objects, g.dbHandle, g.log)
-			if err != nil {
-				return err
-			}
-			objects = nil
-		}
 	}
 
-	if iter.Err() != nil {
+	if errors.Is(iter.Err(), filemanagerutil.ErrListingTruncated) {
+		// the files are only stored once all of them are listed, so that a partial list of files is never replayed
+		g.log.Errorf("Listing of gw dump files truncated after the maximum number of listed objects, none of them is replayed. Replay.maxListedObjects needs to be increased")
+		return fmt.Errorf("failed to list all the gw dump files: %w", iter.Err())
+	} else if iter.Err() != nil {
 		return fmt.Errorf("failed to iterate gw dump files with error: %w", iter.Err())
 	}
-	if len(objects) != 0 {
-		err := storeJobs(ctx, objects, g.dbHandle, g.log)
-		if err != nil {
-			return err
-		}
+	if err := storeJobsInBatches(ctx, objects, uploadMaxItems, g.dbHandle, g.log); err != nil {
+		return err
 	}
 
 	g.log.Info("Dumps loader job is done")
@@ -2,6 +2,7 @@ package dumpsloader
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/rudderlabs/rudder-go-kit/config"
 	"github.com/rudderlabs/rudder-go-kit/filemanager"
 	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/utils/filemanagerutil"
 )
 
 func (p *procErrorRequestHandler) Start() {
@@ -72,23 +74,16 @@ func (p *procErrorRequestHandler) fetchDumpsList(ctx context.Context) error {
 			}
 			objects = append(objects, OrderedJobs{Job: &job, SortIndex: idx})
 		}
-		if len(objects) >= uploadMaxItems {
-			err := storeJobs(ctx, objects, p.dbHandle, p.log)
-			if err != nil {
-				return err
-			}
-			objects = nil
-		}
-
 	}
-	if iter.Err() != nil {
+	if errors.Is(iter.Err(), filemanagerutil.ErrListingTruncated) {
+		// the files are only stored once all of them are listed, so that a partial list of files is never replayed
+		p.log.Errorf("Listing of proc err files truncated after the maximum number of listed objects, none of them is replayed. Replay.maxListedObjects needs to be increased")
+		return fmt.Errorf("failed to list all the proc err files: %w", iter.Err())
+	} else if iter.Err() != nil {
 		return fmt.Errorf("failed to iterate proc err files with error: %w", iter.Err())
 	}
-	if len(objects) != 0 {
-		err := storeJobs(ctx, objects, p.dbHandle, p.log)
-		if err != nil {
-			return err
-		}
+	if err := storeJobsInBatches(ctx, objects, uploadMaxItems, p.dbHandle, p.log); err != nil {
+		return err
 	}
 
 	p.log.Info("Dumps loader job is done")
@@ -25,7 +25,7 @@ func initFileManager(ctx context.Context, config *config.Config, log logger.Logg
 	provider := config.GetString("JOBS_BACKUP_STORAGE_PROVIDER", "S3")
 	uploader, err := filemanager.New(&filemanager.Settings{
 		Provider: provider,
-		Config:   filemanager.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(ctx, provider, config)),
+		Config:   filemanagerutil.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(ctx, provider, config)),
 		Conf:     config,
 	})
 	if err != nil {
@@ -33,7 +33,9 @@ func initFileManager(ctx context.Context, config *config.Config, log logger.Logg
 		return nil, "", err
 	}
 
-	return uploader, bucket, nil
+	// the dumps are listed from the whole prefix of the bucket, hence the listing is bounded.
+	// The dumps loaders fail without replaying anything if there are more dumps than Replay.maxListedObjects, bounding the dumps they keep in memory meanwhile
+	return filemanagerutil.WithMaxListedObjects(uploader, config.GetInt64("Replay.maxListedObjects", 1000000)), bucket, nil
 }
 
 type Factory struct {
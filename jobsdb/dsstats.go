@@ -0,0 +1,538 @@
+package jobsdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lib/pq"
+	"golang.org/x/sync/errgroup"
+)
+
+// DSStats are aggregated statistics about the jobs of a dataset, used for debugging e.g. through rudder-cli
+type DSStats struct {
+	JobCountsByStateAndDestination []JobCountsByStateAndDestination
+	ErrorCodeCountsByDestination   []ErrorCodeCountsByDestination
+	JobCountByConnections          []JobCountByConnections
+	LatestJobStatusCounts          []LatestJobStatusCounts
+	UnprocessedJobCounts           int
+}
+
+type JobCountsByStateAndDestination struct {
+	Count       int
+	State       string
+	Destination string
+}
+
+type ErrorCodeCountsByDestination struct {
+	Count         int
+	ErrorCode     string
+	Destination   string
+	DestinationID string
+}
+
+// JobCountByConnections is the number of jobs of a connection.
+// The source or destination id of the jobs lacking it in their parameters is UnknownParameter.
+type JobCountByConnections struct {
+	Count         int
+	SourceId      string
+	DestinationId string
+}
+
+// UnknownParameter replaces the parameters missing from the jobs in the statistics
+const UnknownParameter = "(unknown)"
+
+type LatestJobStatusCounts struct {
+	Count int
+	State string
+	Rank  int
+}
+
+// GetDSStats returns the aggregated statistics of the dataset with the given index.
+// Every aggregation runs with its own timeout (JobsDB.dsStats.queryTimeout), so that a slow one doesn't prevent the others from running.
+// The statistics of the aggregations which succeeded are returned along with the errors of the ones which failed.
+// Aggregations run sequentially, unless more of them are allowed to run concurrently (JobsDB.dsStats.queryConcurrency).
+func (jd *Handle) GetDSStats(ctx context.Context, dsIndex string) (*DSStats, error) {
+	ds, ok := jd.dsByIndex(dsIndex)
+	if !ok {
+		return nil, fmt.Errorf("dataset %q not found", dsIndex)
+	}
+
+	var stats DSStats
+	queries := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{name: "job counts by state and destination", run: func(ctx context.Context) (err error) {
+			stats.JobCountsByStateAndDestination, err = jd.getJobCountsByStateAndDestination(ctx, ds)
+			return err
+		}},
+		{name: "error code counts by destination", run: func(ctx context.Context) (err error) {
+			stats.ErrorCodeCountsByDestination, err = jd.getErrorCodeCountsByDestination(ctx, ds)
+			return err
+		}},
+		{name: "job count by connections", run: func(ctx context.Context) (err error) {
+			stats.JobCountByConnections, err = jd.getJobCountByConnections(ctx, ds)
+			return err
+		}},
+		{name: "latest job status counts", run: func(ctx context.Context) (err error) {
+			stats.LatestJobStatusCounts, err = jd.getLatestJobStatusCounts(ctx, ds)
+			return err
+		}},
+		{name: "unprocessed job counts", run: func(ctx context.Context) (err error) {
+			stats.UnprocessedJobCounts, err = jd.getUnprocessedJobCounts(ctx, ds)
+			return err
+		}},
+	}
+	// every aggregation sets its own statistics and error, keeping the errors in the order of the aggregations
+	errs := make([]error, len(queries))
+	var g errgroup.Group
+	g.SetLimit(max(jd.conf.dsStatsQueryConcurrency.Load(), 1))
+	for i, query := range queries {
+		i, query := i, query
+		g.Go(func() error {
+			if err := jd.runDSStatsQuery(ctx, query.run); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", query.name, err)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return &stats, errors.Join(errs...)
+}
+
+// runDSStatsQuery runs the query with the configured timeout, identifying timeouts in the returned error.
+// Queries are executed as read requests on the pooled connections of the jobsdb, so that frequent polling doesn't compete with the readers for more connections than the reader queue allows.
+func (jd *Handle) runDSStatsQuery(ctx context.Context, run func(ctx context.Context) error) error {
+	tags := statTags{CustomValFilters: []string{jd.tablePrefix}}
+	command := func() error {
+		timeout := jd.conf.dsStatsQueryTimeout.Load()
+		queryCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		err := run(queryCtx)
+		if err != nil && ctx.Err() == nil && errors.Is(queryCtx.Err(), context.DeadlineExceeded) {
+			return fmt.Errorf("timed out after %s: %w", timeout, err)
+		}
+		return err
+	}
+	return executeDbRequest(jd, newReadDbRequest("ds_stats", &tags, command))
+}
+
+// DSMetadata describes a dataset of the jobsdb
+type DSMetadata struct {
+	Index          string
+	JobTable       string
+	JobStatusTable string
+	MinJobID       int64 // 0 if the dataset is empty
+	MaxJobID       int64 // 0 if the dataset is empty
+	RowCount       int
+}
+
+// GetDSList returns the job tables of the datasets, one per line
+func (jd *Handle) GetDSList() string {
+	jd.dsListLock.RLock()
+	defer jd.dsListLock.RUnlock()
+
+	var result strings.Builder
+	for _, ds := range jd.getDSList() {
+		result.WriteString(ds.JobTable + "\n")
+	}
+	return result.String()
+}
+
+// GetDSMetadata returns the metadata of every dataset, in the same order as GetDSList
+func (jd *Handle) GetDSMetadata(ctx context.Context) ([]DSMetadata, error) {
+	jd.dsListLock.RLock()
+	dsList := jd.getDSList()
+	jd.dsListLock.RUnlock()
+
+	metadata := make([]DSMetadata, 0, len(dsList))
+	for _, ds := range dsList {
+		m := DSMetadata{
+			Index:          ds.Index,
+			JobTable:       ds.JobTable,
+			JobStatusTable: ds.JobStatusTable,
+		}
+		err := jd.runDSStatsQuery(ctx, func(ctx context.Context) error {
+			var minJobID, maxJobID sql.NullInt64
+			if err := jd.dbHandle.QueryRowContext(ctx, fmt.Sprintf(`SELECT MIN(job_id), MAX(job_id) FROM %q`, ds.JobTable)).Scan(&minJobID, &maxJobID); err != nil {
+				return fmt.Errorf("job id range: %w", err)
+			}
+			m.MinJobID, m.MaxJobID = minJobID.Int64, maxJobID.Int64
+
+			rowCount, err := jd.getTableRowCountContext(ctx, ds.JobTable)
+			if err != nil {
+				return fmt.Errorf("row count: %w", err)
+			}
+			m.RowCount = rowCount
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dataset %s: %w", ds.Index, err)
+		}
+		metadata = append(metadata, m)
+	}
+	return metadata, nil
+}
+
+// OrphanedJobStatusCounts is the number of job statuses of a dataset without a corresponding job
+type OrphanedJobStatusCounts struct {
+	Index string
+	Count int
+}
+
+// GetOrphanedJobStatusCounts counts the job statuses without a corresponding job in every dataset, complementing the unprocessed job counts of GetDSStats.
+// Orphaned job statuses indicate a corruption or a partial migration of the datasets.
+func (jd *Handle) GetOrphanedJobStatusCounts(ctx context.Context) ([]OrphanedJobStatusCounts, error) {
+	jd.dsListLock.RLock()
+	dsList := jd.getDSList()
+	jd.dsListLock.RUnlock()
+
+	counts := make([]OrphanedJobStatusCounts, 0, len(dsList))
+	for _, ds := range dsList {
+		c := OrphanedJobStatusCounts{Index: ds.Index}
+		err := jd.runDSStatsQuery(ctx, func(ctx context.Context) (err error) {
+			c.Count, err = jd.getOrphanedJobStatusCounts(ctx, ds)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dataset %s: %w", ds.Index, err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, nil
+}
+
+// WriteDSFailedJobs writes the jobs of the dataset with the given index whose latest status is failed, along with that status, to the writer as json lines.
+// Only the jobs with the given custom val are written, unless it is empty. The jobs are streamed, so that the failed jobs of large datasets are never kept in memory.
+// It returns the number of jobs written.
+func (jd *Handle) WriteDSFailedJobs(ctx context.Context, dsIndex, customVal string, w io.Writer) (int, error) {
+	ds, ok := jd.dsByIndex(dsIndex)
+	if !ok {
+		return 0, fmt.Errorf("dataset %q not found", dsIndex)
+	}
+
+	rows, err := jd.dbHandle.QueryContext(ctx, fmt.Sprintf(
+		`SELECT j.job_id, j.uuid, j.user_id, j.workspace_id, j.parameters, j.custom_val, j.event_payload, j.event_count, j.created_at, j.expire_at,
+			s.job_state, s.attempt, s.exec_time, s.retry_time, COALESCE(s.error_code, ''), COALESCE(s.error_response, '{}'::JSONB), COALESCE(s.parameters, '{}'::JSONB)
+			FROM %[1]q j JOIN "v_last_%[2]s" s ON j.job_id = s.job_id
+			WHERE s.job_state = 'failed' AND ($1 = '' OR j.custom_val = $1)
+			ORDER BY j.job_id`,
+		ds.JobTable, ds.JobStatusTable,
+	), customVal)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var (
+		count   int
+		encoder = json.NewEncoder(w)
+	)
+	for rows.Next() {
+		var job JobT
+		if err := rows.Scan(&job.JobID, &job.UUID, &job.UserID, &job.WorkspaceId, &job.Parameters, &job.CustomVal, &job.EventPayload, &job.EventCount, &job.CreatedAt, &job.ExpireAt,
+			&job.LastJobStatus.JobState, &job.LastJobStatus.AttemptNum, &job.LastJobStatus.ExecTime, &job.LastJobStatus.RetryTime,
+			&job.LastJobStatus.ErrorCode, &job.LastJobStatus.ErrorResponse, &job.LastJobStatus.Parameters,
+		); err != nil {
+			return count, err
+		}
+		job.LastJobStatus.JobID = job.JobID
+		job.LastJobStatus.WorkspaceId = job.WorkspaceId
+		if err := encoder.Encode(&job); err != nil {
+			return count, fmt.Errorf("writing job %d: %w", job.JobID, err)
+		}
+		count++
+	}
+	return count, rows.Err()
+}
+
+// JobTimeline is the history of the statuses of a job, for tracing how it progressed through its states
+type JobTimeline struct {
+	JobID    int64
+	Index    string // index of the dataset of the job
+	Statuses []*JobStatusT
+}
+
+// GetJobTimeline returns all the statuses of the job with the given id, ordered by exec time.
+// The timeline of a job without any status yet has no statuses, whereas an error is returned if no dataset contains the job.
+func (jd *Handle) GetJobTimeline(ctx context.Context, jobID int64) (*JobTimeline, error) {
+	jd.dsListLock.RLock()
+	dsList := jd.getDSList()
+	jd.dsListLock.RUnlock()
+
+	for _, ds := range dsList {
+		var (
+			found    bool
+			statuses []*JobStatusT
+		)
+		err := jd.runDSStatsQuery(ctx, func(ctx context.Context) (err error) {
+			if err := jd.dbHandle.QueryRowContext(ctx, fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %q WHERE job_id = $1)`, ds.JobTable), jobID).Scan(&found); err != nil {
+				return err
+			}
+			if !found {
+				return nil
+			}
+			statuses, err = jd.getJobStatuses(ctx, ds, jobID)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dataset %s: %w", ds.Index, err)
+		}
+		if found {
+			return &JobTimeline{JobID: jobID, Index: ds.Index, Statuses: statuses}, nil
+		}
+	}
+	return nil, fmt.Errorf("job %d not found", jobID)
+}
+
+// JobIDStatus is the current status of a job, along with the dataset it was found in
+type JobIDStatus struct {
+	JobID  int64
+	Index  string      // index of the dataset of the job
+	Status *JobStatusT // last status of the job, nil if it has no status yet
+}
+
+// GetJobIDStatuses returns the current statuses of the jobs with the given ids, keyed by job id, querying every dataset once for all the jobs.
+// The jobs not found in any dataset are left out of the statuses.
+func (jd *Handle) GetJobIDStatuses(ctx context.Context, jobIDs []int64) (map[int64]*JobIDStatus, error) {
+	jd.dsListLock.RLock()
+	dsList := jd.getDSList()
+	jd.dsListLock.RUnlock()
+
+	statuses := make(map[int64]*JobIDStatus, len(jobIDs))
+	for _, ds := range dsList {
+		pending := make([]int64, 0, len(jobIDs))
+		for _, jobID := range jobIDs {
+			if _, ok := statuses[jobID]; !ok {
+				pending = append(pending, jobID)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		err := jd.runDSStatsQuery(ctx, func(ctx context.Context) error {
+			return jd.getJobIDStatuses(ctx, ds, pending, statuses)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("dataset %s: %w", ds.Index, err)
+		}
+	}
+	return statuses, nil
+}
+
+// getJobIDStatuses adds the jobs of the dataset among the given ones to the statuses, along with their last status
+func (jd *Handle) getJobIDStatuses(ctx context.Context, ds dataSetT, jobIDs []int64, statuses map[int64]*JobIDStatus) error {
+	rows, err := jd.dbHandle.QueryContext(ctx, fmt.Sprintf(`SELECT job_id FROM %q WHERE job_id = ANY($1)`, ds.JobTable), pq.Array(jobIDs))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	found := make([]int64, 0, len(jobIDs))
+	for rows.Next() {
+		var jobID int64
+		if err := rows.Scan(&jobID); err != nil {
+			return err
+		}
+		found = append(found, jobID)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	statusRows, err := jd.dbHandle.QueryContext(ctx, fmt.Sprintf(
+		`SELECT DISTINCT ON (job_id) job_id, job_state, attempt, exec_time, retry_time, COALESCE(error_code, ''), COALESCE(error_response, '{}'::JSONB), COALESCE(parameters, '{}'::JSONB)
+			FROM %q
+			WHERE job_id = ANY($1)
+			ORDER BY job_id, id DESC`,
+		ds.JobStatusTable,
+	), pq.Array(found))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = statusRows.Close() }()
+
+	lastStatuses := make(map[int64]*JobStatusT, len(found))
+	for statusRows.Next() {
+		var s JobStatusT
+		if err := statusRows.Scan(&s.JobID, &s.JobState, &s.AttemptNum, &s.ExecTime, &s.RetryTime, &s.ErrorCode, &s.ErrorResponse, &s.Parameters); err != nil {
+			return err
+		}
+		lastStatuses[s.JobID] = &s
+	}
+	if err := statusRows.Err(); err != nil {
+		return err
+	}
+
+	for _, jobID := range found {
+		statuses[jobID] = &JobIDStatus{JobID: jobID, Index: ds.Index, Status: lastStatuses[jobID]}
+	}
+	return nil
+}
+
+func (jd *Handle) getJobStatuses(ctx context.Context, ds dataSetT, jobID int64) ([]*JobStatusT, error) {
+	rows, err := jd.dbHandle.QueryContext(ctx, fmt.Sprintf(
+		`SELECT job_id, job_state, attempt, exec_time, retry_time, COALESCE(error_code, ''), COALESCE(error_response, '{}'::JSONB), COALESCE(parameters, '{}'::JSONB)
+			FROM %q
+			WHERE job_id = $1
+			ORDER BY exec_time, id`,
+		ds.JobStatusTable,
+	), jobID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	statuses := []*JobStatusT{}
+	for rows.Next() {
+		var s JobStatusT
+		if err := rows.Scan(&s.JobID, &s.JobState, &s.AttemptNum, &s.ExecTime, &s.RetryTime, &s.ErrorCode, &s.ErrorResponse, &s.Parameters); err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, &s)
+	}
+	return statuses, rows.Err()
+}
+
+func (jd *Handle) dsByIndex(dsIndex string) (dataSetT, bool) {
+	jd.dsListLock.RLock()
+	defer jd.dsListLock.RUnlock()
+	for _, ds := range jd.getDSList() {
+		if ds.Index == dsIndex {
+			return ds, true
+		}
+	}
+	return dataSetT{}, false
+}
+
+func (jd *Handle) getJobCountsByStateAndDestination(ctx context.Context, ds dataSetT) ([]JobCountsByStateAndDestination, error) {
+	rows, err := jd.dbHandle.QueryContext(ctx, fmt.Sprintf(
+		`SELECT COUNT(*), s.job_state, j.custom_val
+			FROM %[1]q j JOIN "v_last_%[2]s" s ON j.job_id = s.job_id
+			GROUP BY s.job_state, j.custom_val
+			ORDER BY s.job_state, j.custom_val`,
+		ds.JobTable, ds.JobStatusTable,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []JobCountsByStateAndDestination
+	for rows.Next() {
+		var r JobCountsByStateAndDestination
+		if err := rows.Scan(&r.Count, &r.State, &r.Destination); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+func (jd *Handle) getErrorCodeCountsByDestination(ctx context.Context, ds dataSetT) ([]ErrorCodeCountsByDestination, error) {
+	rows, err := jd.dbHandle.QueryContext(ctx, fmt.Sprintf(
+		`SELECT COUNT(*), COALESCE(s.error_code, ''), j.custom_val, COALESCE(j.parameters->>'destination_id', '')
+			FROM %[1]q j JOIN "v_last_%[2]s" s ON j.job_id = s.job_id
+			WHERE s.job_state = 'failed'
+			GROUP BY 2, 3, 4
+			ORDER BY 3, 4, 2`,
+		ds.JobTable, ds.JobStatusTable,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []ErrorCodeCountsByDestination
+	for rows.Next() {
+		var r ErrorCodeCountsByDestination
+		if err := rows.Scan(&r.Count, &r.ErrorCode, &r.Destination, &r.DestinationID); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+func (jd *Handle) getJobCountByConnections(ctx context.Context, ds dataSetT) ([]JobCountByConnections, error) {
+	rows, err := jd.dbHandle.QueryContext(ctx, fmt.Sprintf(
+		`SELECT COUNT(*), COALESCE(NULLIF(parameters->>'source_id', ''), $1), COALESCE(NULLIF(parameters->>'destination_id', ''), $1)
+			FROM %[1]q
+			GROUP BY 2, 3
+			ORDER BY 2, 3`,
+		ds.JobTable,
+	), UnknownParameter)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []JobCountByConnections
+	for rows.Next() {
+		var r JobCountByConnections
+		if err := rows.Scan(&r.Count, &r.SourceId, &r.DestinationId); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// getLatestJobStatusCounts counts the statuses by state and by rank, rank 1 being the latest status of a job
+func (jd *Handle) getLatestJobStatusCounts(ctx context.Context, ds dataSetT) ([]LatestJobStatusCounts, error) {
+	rows, err := jd.dbHandle.QueryContext(ctx, fmt.Sprintf(
+		`SELECT COUNT(*), job_state, rank FROM (
+				SELECT job_state, ROW_NUMBER() OVER (PARTITION BY job_id ORDER BY id DESC) AS rank FROM %[1]q
+			) statuses
+			GROUP BY job_state, rank
+			ORDER BY rank, job_state`,
+		ds.JobStatusTable,
+	))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var result []LatestJobStatusCounts
+	for rows.Next() {
+		var r LatestJobStatusCounts
+		if err := rows.Scan(&r.Count, &r.State, &r.Rank); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+func (jd *Handle) getUnprocessedJobCounts(ctx context.Context, ds dataSetT) (int, error) {
+	var count int
+	err := jd.dbHandle.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT COUNT(*) FROM %[1]q j WHERE NOT EXISTS (SELECT 1 FROM %[2]q s WHERE s.job_id = j.job_id)`,
+		ds.JobTable, ds.JobStatusTable,
+	)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (jd *Handle) getOrphanedJobStatusCounts(ctx context.Context, ds dataSetT) (int, error) {
+	var count int
+	err := jd.dbHandle.QueryRowContext(ctx, fmt.Sprintf(
+		`SELECT COUNT(*) FROM %[2]q s WHERE NOT EXISTS (SELECT 1 FROM %[1]q j WHERE j.job_id = s.job_id)`,
+		ds.JobTable, ds.JobStatusTable,
+	)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
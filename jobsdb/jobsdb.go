@@ -472,6 +472,8 @@ type Handle struct {
 		minDSRetentionPeriod           misc.ValueLoader[time.Duration]
 		maxDSRetentionPeriod           misc.ValueLoader[time.Duration]
 		refreshDSTimeout               misc.ValueLoader[time.Duration]
+		dsStatsQueryTimeout            misc.ValueLoader[time.Duration]
+		dsStatsQueryConcurrency        misc.ValueLoader[int]
 		jobMaxAge                      func() time.Duration
 		writeCapacity                  chan struct{}
 		readCapacity                   chan struct{}
@@ -865,6 +867,8 @@ func (jd *Handle) loadConfig() {
 	maxDSRetentionPeriodKeys := []string{"JobsDB." + jd.tablePrefix + "." + "maxDSRetention", "JobsDB." + "maxDSRetention"}
 	jd.conf.maxDSRetentionPeriod = jd.config.GetReloadableDurationVar(90, time.Minute, maxDSRetentionPeriodKeys...)
 	jd.conf.refreshDSTimeout = jd.config.GetReloadableDurationVar(10, time.Minute, "JobsDB.refreshDS.timeout")
+	jd.conf.dsStatsQueryTimeout = jd.config.GetReloadableDurationVar(30, time.Second, "JobsDB.dsStats.queryTimeout")
+	jd.conf.dsStatsQueryConcurrency = jd.config.GetReloadableIntVar(1, 1, "JobsDB.dsStats.queryConcurrency")
 
 	// migrationConfig
 
@@ -1185,13 +1189,18 @@ func (jd *Handle) doRefreshDSRangeList(l lock.LockToken) error {
 }
 
 func (jd *Handle) getTableRowCount(jobTable string) int {
+	count, err := jd.getTableRowCountContext(context.Background(), jobTable)
+	jd.assertError(err)
+	return count
+}
+
+func (jd *Handle) getTableRowCountContext(ctx context.Context, jobTable string) (int, error) {
 	var count int
 
 	sqlStatement := fmt.Sprintf(`SELECT COUNT(*) from %q`, jobTable)
-	row := jd.dbHandle.QueryRow(sqlStatement)
+	row := jd.dbHandle.QueryRowContext(ctx, sqlStatement)
 	err := row.Scan(&count)
-	jd.assertError(err)
-	return count
+	return count, err
 }
 
 func (jd *Handle) getTableSize(jobTable string) int64 {
@@ -1206,6 +1206,240 @@ func TestFailExecuting(t *testing.T) {
 	require.Equal(t, 2, len(failed.Jobs))
 }
 
+func TestGetDSStats(t *testing.T) {
+	_ = startPostgres(t)
+	customVal := "CUSTOMVAL"
+	prefix := strings.ToLower(rsRand.String(5))
+	destinationID := strings.ToLower(rsRand.String(5))
+
+	c := config.New()
+	jobsDB := NewForReadWrite(prefix, WithConfig(c))
+	require.NoError(t, jobsDB.Start())
+	defer jobsDB.TearDown()
+
+	jobs := make([]*JobT, 3)
+	for i := range jobs {
+		jobs[i] = &JobT{
+			Parameters:   []byte(fmt.Sprintf(`{"batch_id":1,"source_id":"sourceID","destination_id":%q}`, destinationID)),
+			EventPayload: []byte(`{"testKey":"testValue"}`),
+			UserID:       "a-292e-4e79-9880-f8009e0ae4a3",
+			UUID:         uuid.New(),
+			CustomVal:    customVal,
+			EventCount:   1,
+		}
+	}
+	require.NoError(t, jobsDB.Store(context.Background(), jobs))
+	unprocessed, err := jobsDB.GetUnprocessed(context.Background(), GetQueryParams{CustomValFilters: []string{customVal}, JobsLimit: 100})
+	require.NoError(t, err)
+	require.Len(t, unprocessed.Jobs, 3)
+
+	status := func(job *JobT, state, errorCode string) *JobStatusT {
+		return &JobStatusT{
+			JobID:         job.JobID,
+			JobState:      state,
+			AttemptNum:    1,
+			ExecTime:      time.Now(),
+			RetryTime:     time.Now(),
+			ErrorCode:     errorCode,
+			ErrorResponse: []byte(`{}`),
+			Parameters:    []byte(`{}`),
+			WorkspaceId:   defaultWorkspaceID,
+		}
+	}
+	require.NoError(t, jobsDB.UpdateJobStatus(context.Background(), []*JobStatusT{
+		status(unprocessed.Jobs[0], Failed.State, "500"),
+		status(unprocessed.Jobs[1], Failed.State, "500"),
+	}, []string{customVal}, []ParameterFilterT{}))
+	require.NoError(t, jobsDB.UpdateJobStatus(context.Background(), []*JobStatusT{
+		status(unprocessed.Jobs[1], Succeeded.State, "200"),
+	}, []string{customVal}, []ParameterFilterT{}))
+
+	dsIndex := (&HandleInspector{Handle: jobsDB}).DSIndicesList()[0]
+
+	t.Run("stats", func(t *testing.T) {
+		stats, err := jobsDB.GetDSStats(context.Background(), dsIndex)
+		require.NoError(t, err)
+		require.Equal(t, &DSStats{
+			JobCountsByStateAndDestination: []JobCountsByStateAndDestination{
+				{Count: 1, State: Failed.State, Destination: customVal},
+				{Count: 1, State: Succeeded.State, Destination: customVal},
+			},
+			ErrorCodeCountsByDestination: []ErrorCodeCountsByDestination{
+				{Count: 1, ErrorCode: "500", Destination: customVal, DestinationID: destinationID},
+			},
+			JobCountByConnections: []JobCountByConnections{
+				{Count: 3, SourceId: "sourceID", DestinationId: destinationID},
+			},
+			LatestJobStatusCounts: []LatestJobStatusCounts{
+				{Count: 1, State: Failed.State, Rank: 1},
+				{Count: 1, State: Succeeded.State, Rank: 1},
+				{Count: 1, State: Failed.State, Rank: 2},
+			},
+			UnprocessedJobCounts: 1,
+		}, stats)
+	})
+
+	t.Run("datasets", func(t *testing.T) {
+		require.Equal(t, prefix+"_jobs_"+dsIndex+"\n", jobsDB.GetDSList())
+
+		metadata, err := jobsDB.GetDSMetadata(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []DSMetadata{{
+			Index:          dsIndex,
+			JobTable:       prefix + "_jobs_" + dsIndex,
+			JobStatusTable: prefix + "_job_status_" + dsIndex,
+			MinJobID:       unprocessed.Jobs[0].JobID,
+			MaxJobID:       unprocessed.Jobs[2].JobID,
+			RowCount:       3,
+		}}, metadata)
+	})
+
+	t.Run("failed jobs", func(t *testing.T) {
+		var buf bytes.Buffer
+		count, err := jobsDB.WriteDSFailedJobs(context.Background(), dsIndex, customVal, &buf)
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+
+		var job JobT
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &job))
+		require.Equal(t, unprocessed.Jobs[0].JobID, job.JobID)
+		require.Equal(t, unprocessed.Jobs[0].UUID, job.UUID)
+		require.JSONEq(t, `{"testKey":"testValue"}`, string(job.EventPayload))
+		require.Equal(t, Failed.State, job.LastJobStatus.JobState)
+		require.Equal(t, "500", job.LastJobStatus.ErrorCode)
+
+		buf.Reset()
+		count, err = jobsDB.WriteDSFailedJobs(context.Background(), dsIndex, "OTHER", &buf)
+		require.NoError(t, err)
+		require.Zero(t, count)
+		require.Empty(t, buf.String())
+
+		_, err = jobsDB.WriteDSFailedJobs(context.Background(), "unknown", "", &buf)
+		require.EqualError(t, err, `dataset "unknown" not found`)
+	})
+
+	t.Run("job timeline", func(t *testing.T) {
+		timeline, err := jobsDB.GetJobTimeline(context.Background(), unprocessed.Jobs[1].JobID)
+		require.NoError(t, err)
+		require.Equal(t, unprocessed.Jobs[1].JobID, timeline.JobID)
+		require.Equal(t, dsIndex, timeline.Index)
+		require.Len(t, timeline.Statuses, 2)
+		require.Equal(t, Failed.State, timeline.Statuses[0].JobState)
+		require.Equal(t, "500", timeline.Statuses[0].ErrorCode)
+		require.Equal(t, Succeeded.State, timeline.Statuses[1].JobState)
+		require.Equal(t, "200", timeline.Statuses[1].ErrorCode)
+		require.False(t, timeline.Statuses[1].ExecTime.Before(timeline.Statuses[0].ExecTime))
+
+		timeline, err = jobsDB.GetJobTimeline(context.Background(), unprocessed.Jobs[2].JobID)
+		require.NoError(t, err)
+		require.Empty(t, timeline.Statuses)
+
+		_, err = jobsDB.GetJobTimeline(context.Background(), -1)
+		require.EqualError(t, err, "job -1 not found")
+	})
+
+	t.Run("job id statuses", func(t *testing.T) {
+		statuses, err := jobsDB.GetJobIDStatuses(context.Background(), []int64{unprocessed.Jobs[1].JobID, unprocessed.Jobs[2].JobID, -1})
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+
+		status := statuses[unprocessed.Jobs[1].JobID]
+		require.Equal(t, dsIndex, status.Index)
+		require.Equal(t, Succeeded.State, status.Status.JobState)
+		require.Equal(t, "200", status.Status.ErrorCode)
+
+		require.Equal(t, dsIndex, statuses[unprocessed.Jobs[2].JobID].Index)
+		require.Nil(t, statuses[unprocessed.Jobs[2].JobID].Status)
+	})
+
+	t.Run("concurrent queries", func(t *testing.T) {
+		c.Set("JobsDB.dsStats.queryConcurrency", 5)
+		defer c.Set("JobsDB.dsStats.queryConcurrency", 1)
+
+		stats, err := jobsDB.GetDSStats(context.Background(), dsIndex)
+		require.NoError(t, err)
+		require.Len(t, stats.JobCountsByStateAndDestination, 2)
+		require.Len(t, stats.ErrorCodeCountsByDestination, 1)
+		require.Len(t, stats.JobCountByConnections, 1)
+		require.Len(t, stats.LatestJobStatusCounts, 3)
+		require.Equal(t, 1, stats.UnprocessedJobCounts)
+	})
+
+	t.Run("unknown dataset", func(t *testing.T) {
+		_, err := jobsDB.GetDSStats(context.Background(), "unknown")
+		require.EqualError(t, err, `dataset "unknown" not found`)
+	})
+
+	t.Run("query timeout", func(t *testing.T) {
+		c.Set("JobsDB.dsStats.queryTimeout", "1ns")
+		defer c.Set("JobsDB.dsStats.queryTimeout", "30s")
+
+		stats, err := jobsDB.GetDSStats(context.Background(), dsIndex)
+		require.Error(t, err)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.ErrorContains(t, err, "unprocessed job counts: timed out after 1ns")
+		require.NotNil(t, stats)
+	})
+
+	t.Run("orphaned job statuses", func(t *testing.T) {
+		counts, err := jobsDB.GetOrphanedJobStatusCounts(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []OrphanedJobStatusCounts{{Index: dsIndex, Count: 0}}, counts)
+
+		// orphaned job statuses can only exist if the foreign key to the job table is not enforced, e.g. after a partial migration
+		tx, err := jobsDB.dbHandle.Begin()
+		require.NoError(t, err)
+		_, err = tx.Exec(`SET LOCAL session_replication_role = replica`)
+		require.NoError(t, err)
+		_, err = tx.Exec(fmt.Sprintf(`INSERT INTO %q (job_id, job_state, attempt) VALUES (-1, 'failed', 1), (-2, 'failed', 1)`, prefix+"_job_status_"+dsIndex))
+		require.NoError(t, err)
+		require.NoError(t, tx.Commit())
+
+		counts, err = jobsDB.GetOrphanedJobStatusCounts(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, []OrphanedJobStatusCounts{{Index: dsIndex, Count: 2}}, counts)
+	})
+}
+
+func TestGetDSStats_UnknownParameters(t *testing.T) {
+	_ = startPostgres(t)
+	prefix := strings.ToLower(rsRand.String(5))
+
+	jobsDB := NewForReadWrite(prefix, WithConfig(config.New()))
+	require.NoError(t, jobsDB.Start())
+	defer jobsDB.TearDown()
+
+	parameters := []string{
+		`{"source_id":"sourceID","destination_id":"destinationID"}`,
+		`{"source_id":"sourceID"}`,
+		`{"source_id":"","destination_id":"destinationID"}`,
+		`{}`,
+	}
+	jobs := make([]*JobT, len(parameters))
+	for i := range jobs {
+		jobs[i] = &JobT{
+			Parameters:   []byte(parameters[i]),
+			EventPayload: []byte(`{"testKey":"testValue"}`),
+			UserID:       "a-292e-4e79-9880-f8009e0ae4a3",
+			UUID:         uuid.New(),
+			CustomVal:    "CUSTOMVAL",
+			EventCount:   1,
+		}
+	}
+	require.NoError(t, jobsDB.Store(context.Background(), jobs))
+
+	dsIndex := (&HandleInspector{Handle: jobsDB}).DSIndicesList()[0]
+
+	stats, err := jobsDB.GetDSStats(context.Background(), dsIndex)
+	require.NoError(t, err)
+	require.Equal(t, []JobCountByConnections{
+		{Count: 1, SourceId: UnknownParameter, DestinationId: UnknownParameter},
+		{Count: 1, SourceId: UnknownParameter, DestinationId: "destinationID"},
+		{Count: 1, SourceId: "sourceID", DestinationId: UnknownParameter},
+		{Count: 1, SourceId: "sourceID", DestinationId: "destinationID"},
+	}, stats.JobCountByConnections)
+}
+
 func TestMaxAgeCleanup(t *testing.T) {
 	_ = startPostgres(t)
 	customVal := "CUSTOMVAL"
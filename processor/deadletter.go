@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"time"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+)
+
+// The failed events of the processor are written to one of two sinks:
+//   - the error DB (proc_error), for the events failing for a reason which may go away, e.g. a tracking plan violation or an event rejected by a transformation,
+//     so that they can be inspected and replayed as part of the regular error handling.
+//   - the dead-letter DB, if any (see WithDeadLetter), for the events whose transformation exhausted its retries, i.e. the transformer couldn't be reached or timed out long enough.
+//     These events are never retried, so they are kept apart with their own retention, along with the reason and the time of their failure.
+//
+// Without a dead-letter DB all failed events are written to the error DB.
+
+// deadLetterReasonRetriesExhausted is the dead-letter reason of the events whose transformation exhausted its retries
+const deadLetterReasonRetriesExhausted = "transformation_retries_exhausted"
+
+// isDeadLetter returns true if the failed job is to be written to the dead-letter DB instead of the error DB
+func (proc *Handle) isDeadLetter(job *jobsdb.JobT) bool {
+	if proc.deadLetterDB == nil {
+		return false
+	}
+	params := gjson.GetManyBytes(job.Parameters, "stage", "status_code")
+	switch params[0].String() {
+	case transformer.UserTransformerStage, transformer.DestTransformerStage:
+	default:
+		return false
+	}
+	switch params[1].Int() {
+	case transformer.TransformerRequestFailure, transformer.TransformerRequestTimeout:
+		return true
+	}
+	return false
+}
+
+// splitDeadLetterJobs splits the failed jobs into the jobs for the error DB and the jobs for the dead-letter DB.
+// The dead-letter jobs are annotated with the reason and the time of their failure (dead_letter parameter).
+func (proc *Handle) splitDeadLetterJobs(jobs []*jobsdb.JobT, failedAt time.Time) (errorJobs, deadLetterJobs []*jobsdb.JobT) {
+	if proc.deadLetterDB == nil {
+		return jobs, nil
+	}
+	for _, job := range jobs {
+		if !proc.isDeadLetter(job) {
+			errorJobs = append(errorJobs, job)
+			continue
+		}
+		params, err := sjson.SetBytes(job.Parameters, "dead_letter", map[string]interface{}{
+			"reason":    deadLetterReasonRetriesExhausted,
+			"failed_at": failedAt.UTC().Format(time.RFC3339Nano),
+		})
+		if err != nil {
+			proc.logger.Errorf("[Processor] Failed to set the dead-letter parameters of job %s: %v", job.UUID, err)
+		} else {
+			job.Parameters = params
+		}
+		deadLetterJobs = append(deadLetterJobs, job)
+	}
+	return errorJobs, deadLetterJobs
+}
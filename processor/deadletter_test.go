@@ -0,0 +1,49 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+)
+
+func TestSplitDeadLetterJobs(t *testing.T) {
+	failedAt := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	newJobs := func() []*jobsdb.JobT {
+		return []*jobsdb.JobT{
+			{Parameters: []byte(`{"stage":"user_transformer","status_code":909}`)},
+			{Parameters: []byte(`{"stage":"dest_transformer","status_code":919}`)},
+			{Parameters: []byte(`{"stage":"dest_transformer","status_code":400}`)},
+			{Parameters: []byte(`{"stage":"trackingPlan_validation","status_code":909}`)},
+		}
+	}
+
+	t.Run("without dead-letter DB", func(t *testing.T) {
+		proc := &Handle{logger: logger.NOP}
+		jobs := newJobs()
+
+		errorJobs, deadLetterJobs := proc.splitDeadLetterJobs(jobs, failedAt)
+		require.Equal(t, jobs, errorJobs)
+		require.Empty(t, deadLetterJobs)
+	})
+
+	t.Run("with dead-letter DB", func(t *testing.T) {
+		proc := &Handle{logger: logger.NOP, deadLetterDB: &jobsdb.Handle{}}
+		jobs := newJobs()
+
+		errorJobs, deadLetterJobs := proc.splitDeadLetterJobs(jobs, failedAt)
+		require.Equal(t, []*jobsdb.JobT{jobs[2], jobs[3]}, errorJobs)
+		require.Len(t, deadLetterJobs, 2)
+		for _, job := range deadLetterJobs {
+			require.Equal(t, deadLetterReasonRetriesExhausted, gjson.GetBytes(job.Parameters, "dead_letter.reason").String())
+			require.Equal(t, "2023-10-01T12:00:00Z", gjson.GetBytes(job.Parameters, "dead_letter.failed_at").String())
+		}
+		require.Equal(t, transformer.UserTransformerStage, gjson.GetBytes(deadLetterJobs[0].Parameters, "stage").String())
+		require.EqualValues(t, transformer.TransformerRequestTimeout, gjson.GetBytes(deadLetterJobs[1].Parameters, "status_code").Int())
+	})
+}
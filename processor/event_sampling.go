@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"math/rand"
+
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+)
+
+// EventSample is an event captured before and after a transformation stage
+type EventSample struct {
+	Stage  string
+	Input  transformer.TransformerEvent
+	Output []transformer.TransformerResponse // both successful and failed responses containing the event
+}
+
+// EventSampleSink receives the sampled events. It is called concurrently from the processor workers, so it needs to be thread-safe.
+type EventSampleSink interface {
+	Sample(sample EventSample)
+}
+
+// eventSampler captures a fraction of the transformed events for debugging
+type eventSampler struct {
+	rate float64
+	sink EventSampleSink
+}
+
+// newEventSampler returns nil if nothing would ever be sampled, so that sampling has no overhead by default
+func newEventSampler(rate float64, sink EventSampleSink) *eventSampler {
+	if rate <= 0 || sink == nil {
+		return nil
+	}
+	return &eventSampler{
+		rate: min(rate, 1),
+		sink: sink,
+	}
+}
+
+// sample emits the sampled input events along with the responses of the stage that contain them
+func (s *eventSampler) sample(stage string, events []transformer.TransformerEvent, response transformer.Response) {
+	if s == nil || len(events) == 0 {
+		return
+	}
+
+	var sampled map[string]*EventSample // messageID -> sample
+	for i := range events {
+		if s.rate < 1 && rand.Float64() >= s.rate { // skipcq: GSC-G404
+			continue
+		}
+		if sampled == nil {
+			sampled = make(map[string]*EventSample)
+		}
+		sampled[events[i].Metadata.MessageID] = &EventSample{
+			Stage: stage,
+			Input: events[i],
+		}
+	}
+	if len(sampled) == 0 {
+		return
+	}
+
+	for _, responses := range [][]transformer.TransformerResponse{response.Events, response.FailedEvents} {
+		for i := range responses {
+			for _, messageID := range responses[i].Metadata.GetMessagesIDs() {
+				if sample, ok := sampled[messageID]; ok {
+					sample.Output = append(sample.Output, responses[i])
+				}
+			}
+		}
+	}
+
+	for i := range events {
+		if sample, ok := sampled[events[i].Metadata.MessageID]; ok {
+			s.sink.Sample(*sample)
+			delete(sampled, events[i].Metadata.MessageID)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package processor
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+)
+
+type memorySampleSink struct {
+	mu      sync.Mutex
+	samples []EventSample
+}
+
+func (m *memorySampleSink) Sample(sample EventSample) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.samples = append(m.samples, sample)
+}
+
+func TestEventSampler(t *testing.T) {
+	events := make([]transformer.TransformerEvent, 0, 100)
+	for i := 0; i < 100; i++ {
+		events = append(events, transformer.TransformerEvent{
+			Metadata: transformer.Metadata{MessageID: strconv.Itoa(i)},
+			Message:  map[string]interface{}{"index": i},
+		})
+	}
+	response := transformer.Response{
+		Events: []transformer.TransformerResponse{
+			{Metadata: transformer.Metadata{MessageID: "0"}, StatusCode: 200},
+			{Metadata: transformer.Metadata{MessageIDs: []string{"1", "2"}}, StatusCode: 200},
+		},
+		FailedEvents: []transformer.TransformerResponse{
+			{Metadata: transformer.Metadata{MessageID: "3"}, StatusCode: 400, Error: "error"},
+		},
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		sink := &memorySampleSink{}
+		require.Nil(t, newEventSampler(0, sink))
+		require.Nil(t, newEventSampler(0.5, nil))
+
+		var s *eventSampler
+		s.sample(transformer.DestTransformerStage, events, response)
+		require.Empty(t, sink.samples)
+	})
+
+	t.Run("sample all", func(t *testing.T) {
+		sink := &memorySampleSink{}
+		s := newEventSampler(2, sink)
+		require.Equal(t, 1.0, s.rate)
+
+		s.sample(transformer.DestTransformerStage, events, response)
+		require.Len(t, sink.samples, len(events))
+		for i, sample := range sink.samples {
+			require.Equal(t, transformer.DestTransformerStage, sample.Stage)
+			require.Equal(t, events[i], sample.Input)
+		}
+		require.Equal(t, response.Events[:1], sink.samples[0].Output)
+		require.Equal(t, response.Events[1:2], sink.samples[1].Output)
+		require.Equal(t, response.Events[1:2], sink.samples[2].Output)
+		require.Equal(t, response.FailedEvents, sink.samples[3].Output)
+		require.Empty(t, sink.samples[4].Output, "dropped events have no output")
+	})
+
+	t.Run("concurrent sampling", func(t *testing.T) {
+		sink := &memorySampleSink{}
+		s := newEventSampler(0.5, sink)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.sample(transformer.UserTransformerStage, events, response)
+			}()
+		}
+		wg.Wait()
+		require.Greater(t, len(sink.samples), 0)
+		require.Less(t, len(sink.samples), 10*len(events))
+	})
+}
@@ -0,0 +1,93 @@
+package processor
+
+import (
+	"context"
+	"sync"
+)
+
+// pendingBatches keeps track of the batches of jobs read from the gateway which are not stored yet.
+// Its zero value is ready to use.
+type pendingBatches struct {
+	mu      sync.Mutex
+	count   int           // number of batches read and not stored yet
+	flushes int           // number of flushes in progress, no new batches are read while there are any
+	stopped bool          // no new batches are read once stopped, until started again
+	drained chan struct{} // closed as soon as count drops to zero
+}
+
+// begin registers a new batch which is about to be read, returning false if no new batches should be read due to a flush or a stop.
+func (p *pendingBatches) begin() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.flushes > 0 || p.stopped {
+		return false
+	}
+	p.count++
+	return true
+}
+
+// end marks a batch as either stored or empty
+func (p *pendingBatches) end() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.count == 0 {
+		return
+	}
+	p.count--
+	if p.count == 0 && p.drained != nil {
+		close(p.drained)
+		p.drained = nil
+	}
+}
+
+// stop prevents any new batch from being read, unlike flush it lasts until start is called
+func (p *pendingBatches) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = true
+}
+
+// start allows new batches to be read again after a stop
+func (p *pendingBatches) start() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopped = false
+}
+
+// pending returns the number of batches read and not stored yet
+func (p *pendingBatches) pending() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.count
+}
+
+// flush pauses the reading of new batches and waits until all pending batches are stored, or the context is done.
+// Reading resumes once flush returns.
+func (p *pendingBatches) flush(ctx context.Context) error {
+	p.mu.Lock()
+	p.flushes++
+	var drained chan struct{}
+	if p.count > 0 {
+		if p.drained == nil {
+			p.drained = make(chan struct{})
+		}
+		drained = p.drained
+	}
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.flushes--
+		p.mu.Unlock()
+	}()
+
+	if drained == nil {
+		return nil
+	}
+	select {
+	case <-drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPendingBatchesFlush(t *testing.T) {
+	t.Run("nothing pending", func(t *testing.T) {
+		var p pendingBatches
+		require.NoError(t, p.flush(context.Background()))
+		require.True(t, p.begin(), "reading should resume after flushing")
+	})
+
+	t.Run("waits for pending batches", func(t *testing.T) {
+		var p pendingBatches
+		require.True(t, p.begin())
+		require.True(t, p.begin())
+
+		flushed := make(chan error)
+		go func() { flushed <- p.flush(context.Background()) }()
+
+		require.Eventually(t, func() bool {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return p.flushes == 1
+		}, time.Second, time.Millisecond)
+		require.False(t, p.begin(), "no new batches should be read while flushing")
+
+		p.end()
+		select {
+		case <-flushed:
+			t.Fatal("flush returned while a batch is still pending")
+		case <-time.After(10 * time.Millisecond):
+		}
+
+		p.end()
+		require.NoError(t, <-flushed)
+		require.True(t, p.begin())
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		var p pendingBatches
+		require.True(t, p.begin())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		require.ErrorIs(t, p.flush(ctx), context.Canceled)
+		require.True(t, p.begin())
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		var p pendingBatches
+		require.True(t, p.begin())
+
+		p.stop()
+		require.False(t, p.begin(), "no new batches should be read once stopped")
+
+		flushed := make(chan error)
+		go func() { flushed <- p.flush(context.Background()) }()
+		p.end()
+		require.NoError(t, <-flushed)
+		require.False(t, p.begin(), "reading should not resume after flushing while stopped")
+
+		p.start()
+		require.True(t, p.begin(), "reading should resume once started again")
+	})
+
+	t.Run("unbalanced end", func(t *testing.T) {
+		var p pendingBatches
+		p.end()
+		require.NoError(t, p.flush(context.Background()))
+	})
+}
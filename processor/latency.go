@@ -0,0 +1,24 @@
+package processor
+
+import (
+	"time"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+// LatencyRecorder is called for every processed event with the time elapsed between its reception and its processing.
+// It is called concurrently from the processor workers, so it needs to be thread-safe.
+type LatencyRecorder func(sourceID, destType string, latency time.Duration)
+
+// recordLatency reports the latency of the event to the recorder, if any.
+// Events whose received at can't be parsed are not reported, since their latency is unknown.
+func (proc *Handle) recordLatency(sourceID, destType, receivedAt string, processedAt time.Time) {
+	if proc.latencyRecorder == nil {
+		return
+	}
+	receivedAtTime, err := time.Parse(misc.RFC3339Milli, receivedAt)
+	if err != nil {
+		return
+	}
+	proc.latencyRecorder(sourceID, destType, processedAt.Sub(receivedAtTime))
+}
@@ -0,0 +1,38 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+func TestRecordLatency(t *testing.T) {
+	processedAt := time.Date(2023, 1, 1, 0, 0, 1, 500_000_000, time.UTC)
+	receivedAt := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Format(misc.RFC3339Milli)
+
+	t.Run("recorded", func(t *testing.T) {
+		type latency struct {
+			sourceID, destType string
+			latency            time.Duration
+		}
+		var latencies []latency
+
+		proc := &Handle{latencyRecorder: func(sourceID, destType string, l time.Duration) {
+			latencies = append(latencies, latency{sourceID: sourceID, destType: destType, latency: l})
+		}}
+		proc.recordLatency("source-1", "WEBHOOK", receivedAt, processedAt)
+		proc.recordLatency("source-1", "WEBHOOK", "invalid", processedAt)
+
+		require.Equal(t, []latency{{sourceID: "source-1", destType: "WEBHOOK", latency: 1500 * time.Millisecond}}, latencies)
+	})
+
+	t.Run("without recorder", func(t *testing.T) {
+		proc := &Handle{}
+		require.NotPanics(t, func() {
+			proc.recordLatency("source-1", "WEBHOOK", receivedAt, processedAt)
+		})
+	})
+}
@@ -64,7 +64,9 @@ func (proc *LifecycleManager) Start() error {
 	var wg sync.WaitGroup
 	proc.waitGroup = &wg
 	metric.Instance.Reset()
+	proc.Handle.pendingBatches.start()
 	if err := proc.Handle.countPendingEvents(currentCtx); err != nil {
+		cancel()
 		return err
 	}
 	wg.Add(1)
@@ -78,12 +80,38 @@ func (proc *LifecycleManager) Start() error {
 }
 
 // Stop stops the processor, this is a blocking call.
+// The processor is stopped in the following order, so that it never writes to the jobsdb handles, nor uses the debuggers, once Stop returns:
+//  1. no new jobs are picked up
+//  2. the jobs already picked up are flushed, waiting up to Processor.shutdownFlushTimeout for them to be stored
+//  3. the processing loops are stopped and waited for, so that the debuggers are no longer used
+//  4. the handle is shut down, stopping its background goroutines
+//
+// The jobsdb handles and the debuggers are owned by the caller (e.g. the debuggers are shared with the routers), hence need to be stopped after Stop returns.
 func (proc *LifecycleManager) Stop() {
+	proc.Handle.pendingBatches.stop()
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), proc.Handle.config.shutdownFlushTimeout.Load())
+	if err := proc.Handle.pendingBatches.flush(flushCtx); err != nil {
+		proc.Handle.logger.Warnf("Flushing the pending jobs before stopping the processor: %v", err)
+	}
+	cancel()
+
 	proc.currentCancel()
 	proc.waitGroup.Wait()
 	proc.Handle.Shutdown()
 }
 
+// Flush waits until all the jobs already read by the processor are stored to the router and batch router DBs, without reading any new jobs meanwhile.
+// It can be called before Stop for a graceful shutdown, and returns immediately if there is nothing pending.
+func (proc *LifecycleManager) Flush(ctx context.Context) error {
+	return proc.Handle.pendingBatches.flush(ctx)
+}
+
+// Status returns a snapshot of the processing activity of the processor, for the admin interface
+func (proc *LifecycleManager) Status() interface{} {
+	return proc.Handle.Status()
+}
+
 func WithFeaturesRetryMaxAttempts(maxAttempts int) func(l *LifecycleManager) {
 	return func(l *LifecycleManager) {
 		l.Handle.config.featuresRetryMaxAttempts = maxAttempts
@@ -123,8 +151,85 @@ func New(ctx context.Context, clearDb *bool, gwDb, rtDb, brtDb, errDbForRead, er
 
 type Opts func(l *LifecycleManager)
 
+// WithBackendConfig overrides the backend config the processor is set up with, instead of backendconfig.DefaultBackendConfig, e.g. for a custom control plane
+func WithBackendConfig(bc backendconfig.BackendConfig) Opts {
+	return func(l *LifecycleManager) {
+		l.BackendConfig = bc
+	}
+}
+
+// WithTransformerRetryClassifier overrides which transformer failures are retried and which abort the events immediately
+func WithTransformerRetryClassifier(classifier transformer.RetryClassifier) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.transformer = transformer.NewTransformer(config.Default, logger.NewLogger().Child("processor"), stats.Default, transformer.WithRetryClassifier(classifier))
+	}
+}
+
+// WithEventSampling emits the events before and after every transformation stage to the sink, for the sampled fraction (0 to 1) of events.
+// Sampling is disabled by default (rate 0).
+func WithEventSampling(rate float64, sink EventSampleSink) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.eventSampler = newEventSampler(rate, sink)
+	}
+}
+
+// WithTransformationFailureSamples keeps the last n destination transformation failures of every destination, along with the events which failed (redacted),
+// reporting them in the status of the processor. It overrides Processor.transformationFailureSamples, failures not being kept by default (n 0).
+func WithTransformationFailureSamples(n int) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.transformationFailures = newTransformationFailures(n)
+	}
+}
+
 func WithAdaptiveLimit(adaptiveLimitFunction func(int64) int64) Opts {
 	return func(l *LifecycleManager) {
 		l.Handle.adaptiveLimit = adaptiveLimitFunction
 	}
 }
+
+// WithMaxBatchBytes bounds the total payload bytes of the jobs picked up in a processing batch, in addition to the jobs count limit.
+// Pickup stops as soon as either limit is reached, yet a single job larger than the limit is still picked up on its own.
+func WithMaxBatchBytes(n int64) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.config.maxBatchBytes = n
+	}
+}
+
+// WithPickupBatchSize overrides the max number of jobs picked up in a processing batch (Processor.maxLoopProcessEvents), e.g. for tuning multiple processors in the same binary differently.
+// The value needs to be positive, otherwise Processor.maxLoopProcessEvents is used.
+// The adaptive limit function only adapts the payload size limit of the batches, so it never changes the pickup batch size.
+func WithPickupBatchSize(n int) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.config.pickupBatchSize = n
+	}
+}
+
+// WithLatencyRecorder reports the time elapsed between the reception and the processing of every processed event, e.g. for SLA reporting
+func WithLatencyRecorder(recorder LatencyRecorder) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.latencyRecorder = recorder
+	}
+}
+
+// WithSourceRateLimiter limits the events of every source picked up in a processing batch to the max returned for the source.
+// Sources for which the limiter returns 0 are not limited.
+func WithSourceRateLimiter(maxEventsPerBatch func(sourceID string) int) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.sourceRateLimiter = maxEventsPerBatch
+	}
+}
+
+// WithRoutingDecider overrides whether a transformed event is written to the router or the batch router DB, instead of deciding based on its destination type
+func WithRoutingDecider(decider RoutingDecider) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.routingDecider = decider
+	}
+}
+
+// WithDeadLetter writes the events whose transformation exhausted its retries to the dead-letter DB, instead of the error DB, so that they can have their own retention.
+// The error DB keeps the rest of the failed events. The dead-letter DB needs to be started and stopped by the caller.
+func WithDeadLetter(db jobsdb.JobsDB) Opts {
+	return func(l *LifecycleManager) {
+		l.Handle.deadLetterDB = db
+	}
+}
@@ -222,10 +222,12 @@ func TestProcessorManager(t *testing.T) {
 		mockRsourcesService,
 		destinationdebugger.NewNoOpService(),
 		transformationdebugger.NewNoOpService(),
+		WithBackendConfig(mockBackendConfig),
 		func(m *LifecycleManager) {
 			m.Handle.config.enablePipelining = false
 			m.Handle.config.featuresRetryMaxAttempts = 0
 		})
+	require.Equal(t, mockBackendConfig, processor.BackendConfig)
 
 	t.Run("jobs are already there in GW DB before processor starts", func(t *testing.T) {
 		require.NoError(t, gwDB.Start())
@@ -247,7 +249,6 @@ func TestProcessorManager(t *testing.T) {
 		mockBackendConfig.EXPECT().WaitForConfig(gomock.Any()).Times(1)
 		processor.Handle.transformerFeatures = json.RawMessage(defaultTransformerFeatures)
 		mockRsourcesService.EXPECT().IncrementStats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), rsources.Stats{Out: 10}).Times(1)
-		processor.BackendConfig = mockBackendConfig
 		processor.Handle.transformer = mockTransformer
 		require.NoError(t, processor.Start())
 		defer processor.Stop()
@@ -286,18 +287,33 @@ func TestProcessorManager(t *testing.T) {
 
 		mockBackendConfig.EXPECT().WaitForConfig(gomock.Any()).Times(1)
 		processor.Handle.transformerFeatures = json.RawMessage(defaultTransformerFeatures)
-		mockRsourcesService.EXPECT().IncrementStats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), rsources.Stats{Out: 10}).Times(1)
+		// the batch is kept pending while storing, until released
+		storing := make(chan struct{})
+		release := make(chan struct{})
+		mockRsourcesService.EXPECT().IncrementStats(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), rsources.Stats{Out: 10}).Times(1).DoAndReturn(
+			func(context.Context, *sql.Tx, string, rsources.JobTargetKey, rsources.Stats) error {
+				close(storing)
+				<-release
+				return nil
+			},
+		)
+
+		shutdownFlushTimeout := 10 * time.Second
+		config.Set("Processor.shutdownFlushTimeout", shutdownFlushTimeout)
+		defer config.Reset()
 
 		require.NoError(t, processor.Start())
 		err = tempDB.Store(context.Background(), genJobs(customVal, jobCountPerDS, eventsPerJob))
 		require.NoError(t, err)
-		unprocessedListEmpty, err = tempDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParams{
-			CustomValFilters: []string{customVal},
-			JobsLimit:        20,
-			ParameterFilters: []jobsdb.ParameterFilterT{},
-		})
-		require.NoError(t, err, "failed to get unprocessed jobs")
-		Eventually(func() int {
+
+		select {
+		case <-storing:
+		case <-time.After(time.Minute):
+			t.Fatal("the jobs were not picked up")
+		}
+		require.Equal(t, 1, processor.Handle.pendingBatches.pending())
+
+		unprocessedGWJobs := func() int {
 			res, err := tempDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParams{
 				CustomValFilters: []string{customVal},
 				JobsLimit:        20,
@@ -305,7 +321,24 @@ func TestProcessorManager(t *testing.T) {
 			})
 			require.NoError(t, err)
 			return len(res.Jobs)
-		}, time.Minute, 100*time.Millisecond).Should(Equal(0))
+		}
+		require.Equal(t, jobCountPerDS, unprocessedGWJobs())
+
+		// stopping waits for the pending batch to be stored
+		time.AfterFunc(time.Second, func() { close(release) })
+		stopStart := time.Now()
 		processor.Stop()
+		require.Less(t, time.Since(stopStart), shutdownFlushTimeout)
+		require.Zero(t, processor.Handle.pendingBatches.pending())
+		require.Zero(t, unprocessedGWJobs(), "the pending batch should be flushed before stopping")
+
+		// nothing is written to the router DB once the processor is stopped
+		rtJobs := func() int {
+			res, err := rtDB.GetUnprocessed(context.Background(), jobsdb.GetQueryParams{JobsLimit: 1000})
+			require.NoError(t, err)
+			return len(res.Jobs)
+		}
+		jobsAfterStop := rtJobs()
+		Consistently(rtJobs, time.Second, 100*time.Millisecond).Should(Equal(jobsAfterStop))
 	})
 }
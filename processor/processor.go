@@ -14,6 +14,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 
@@ -78,6 +79,7 @@ type Handle struct {
 	batchRouterDB jobsdb.JobsDB
 	readErrorDB   jobsdb.JobsDB
 	writeErrorDB  jobsdb.JobsDB
+	deadLetterDB  jobsdb.JobsDB
 	eventSchemaDB jobsdb.JobsDB
 	archivalDB    jobsdb.JobsDB
 
@@ -111,6 +113,9 @@ type Handle struct {
 		isolationMode             isolation.Mode
 		mainLoopTimeout           time.Duration
 		featuresRetryMaxAttempts  int
+		backendConfigWaitTimeout  time.Duration
+		maxBatchBytes             int64
+		pickupBatchSize           int
 		enablePipelining          bool
 		pipelineBufferedItems     int
 		subJobSize                int
@@ -118,6 +123,7 @@ type Handle struct {
 		readLoopSleep             misc.ValueLoader[time.Duration]
 		maxLoopSleep              misc.ValueLoader[time.Duration]
 		storeTimeout              misc.ValueLoader[time.Duration]
+		shutdownFlushTimeout      misc.ValueLoader[time.Duration]
 		maxEventsToProcess        misc.ValueLoader[int]
 		transformBatchSize        misc.ValueLoader[int]
 		userTransformBatchSize    misc.ValueLoader[int]
@@ -134,6 +140,7 @@ type Handle struct {
 		enableEventCount          misc.ValueLoader[bool]
 		transformTimesPQLength    int
 		captureEventNameStats     misc.ValueLoader[bool]
+		captureRoutingStats       misc.ValueLoader[bool]
 		transformerURL            string
 		pollInterval              time.Duration
 		GWCustomVal               string
@@ -145,6 +152,15 @@ type Handle struct {
 
 	adaptiveLimit func(int64) int64
 	storePlocker  kitsync.PartitionLocker
+	eventSampler  *eventSampler
+
+	transformationFailures *transformationFailures
+
+	pendingBatches    pendingBatches
+	cycles            cycleTracker
+	sourceRateLimiter func(sourceID string) int
+	latencyRecorder   LatencyRecorder
+	routingDecider    RoutingDecider
 }
 type processorStats struct {
 	statGatewayDBR                stats.Measurement
@@ -377,6 +393,14 @@ func (proc *Handle) Setup(
 	if proc.adaptiveLimit == nil {
 		proc.adaptiveLimit = func(limit int64) int64 { return limit }
 	}
+	if proc.transformationFailures == nil {
+		proc.transformationFailures = newTransformationFailures(config.GetIntVar(0, 1, "Processor.transformationFailureSamples"))
+	}
+	if n := proc.config.pickupBatchSize; n > 0 {
+		proc.config.maxEventsToProcess = misc.SingleValueLoader(n)
+	} else if n < 0 {
+		proc.logger.Warnf("Ignoring invalid pickup batch size %d, using Processor.maxLoopProcessEvents instead", n)
+	}
 	proc.storePlocker = *kitsync.NewPartitionLocker()
 
 	// Stats
@@ -517,7 +541,12 @@ func (proc *Handle) Start(ctx context.Context) error {
 	// pinger loop
 	g.Go(misc.WithBugsnag(func() error {
 		proc.logger.Info("Starting pinger loop")
-		proc.backendConfig.WaitForConfig(ctx)
+		if err := proc.waitForBackendConfig(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("waiting for backend config: %w", err)
+		}
 		proc.logger.Info("Backend config received")
 
 		// waiting for init group
@@ -584,6 +613,7 @@ func (proc *Handle) Shutdown() {
 func (proc *Handle) loadConfig() {
 	proc.config.mainLoopTimeout = 200 * time.Millisecond
 	proc.config.featuresRetryMaxAttempts = 10
+	proc.config.backendConfigWaitTimeout = config.GetDurationVar(30, time.Second, "Processor.backendConfigWaitTimeout")
 
 	defaultSubJobSize := 2000
 	defaultMaxEventsToProcess := 10000
@@ -623,6 +653,7 @@ func (proc *Handle) loadReloadableConfig(defaultPayloadLimit int64, defaultMaxEv
 	proc.payloadLimit = config.GetReloadableInt64Var(defaultPayloadLimit, 1, "Processor.payloadLimit")
 	proc.config.maxLoopSleep = config.GetReloadableDurationVar(10000, time.Millisecond, "Processor.maxLoopSleep", "Processor.maxLoopSleepInMS")
 	proc.config.storeTimeout = config.GetReloadableDurationVar(5, time.Minute, "Processor.storeTimeout")
+	proc.config.shutdownFlushTimeout = config.GetReloadableDurationVar(30, time.Second, "Processor.shutdownFlushTimeout")
 	proc.config.pingerSleep = config.GetReloadableDurationVar(1000, time.Millisecond, "Processor.pingerSleep")
 	proc.config.readLoopSleep = config.GetReloadableDurationVar(1000, time.Millisecond, "Processor.readLoopSleep")
 	proc.config.transformBatchSize = config.GetReloadableIntVar(100, 1, "Processor.transformBatchSize")
@@ -633,6 +664,7 @@ func (proc *Handle) loadReloadableConfig(defaultPayloadLimit int64, defaultMaxEv
 	proc.config.archivalEnabled = config.GetReloadableBoolVar(true, "archival.Enabled")
 	// Capture event name as a tag in event level stats
 	proc.config.captureEventNameStats = config.GetReloadableBoolVar(false, "Processor.Stats.captureEventName")
+	proc.config.captureRoutingStats = config.GetReloadableBoolVar(true, "Processor.Stats.captureRouting")
 }
 
 // syncTransformerFeatureJson polls the transformer feature json endpoint,
@@ -1997,6 +2029,8 @@ func (proc *Handle) sendQueryRetryStats(attempt int) {
 }
 
 func (proc *Handle) Store(partition string, in *storeMessage) {
+	defer proc.pendingBatches.end()
+
 	if proc.limiter.store != nil {
 		defer proc.limiter.store.BeginWithPriority(partition, proc.getLimiterPriority(partition))()
 	}
@@ -2098,15 +2132,29 @@ func (proc *Handle) Store(partition string, in *storeMessage) {
 		in.procErrorJobs = append(in.procErrorJobs, jobs...)
 	}
 	if len(in.procErrorJobs) > 0 {
-		err := misc.RetryWithNotify(context.Background(), proc.jobsDBCommandTimeout.Load(), proc.jobdDBMaxRetries.Load(), func(ctx context.Context) error {
-			return proc.writeErrorDB.Store(ctx, in.procErrorJobs)
-		}, proc.sendRetryStoreStats)
-		if err != nil {
-			proc.logger.Errorf("Store into proc error table failed with error: %v", err)
-			proc.logger.Errorf("procErrorJobs: %v", in.procErrorJobs)
-			panic(err)
+		procErrorJobs, deadLetterJobs := proc.splitDeadLetterJobs(in.procErrorJobs, time.Now())
+		if len(procErrorJobs) > 0 {
+			err := misc.RetryWithNotify(context.Background(), proc.jobsDBCommandTimeout.Load(), proc.jobdDBMaxRetries.Load(), func(ctx context.Context) error {
+				return proc.writeErrorDB.Store(ctx, procErrorJobs)
+			}, proc.sendRetryStoreStats)
+			if err != nil {
+				proc.logger.Errorf("Store into proc error table failed with error: %v", err)
+				proc.logger.Errorf("procErrorJobs: %v", procErrorJobs)
+				panic(err)
+			}
+			proc.logger.Debug("[Processor] Total jobs written to proc_error: ", len(procErrorJobs))
+		}
+		if len(deadLetterJobs) > 0 {
+			err := misc.RetryWithNotify(context.Background(), proc.jobsDBCommandTimeout.Load(), proc.jobdDBMaxRetries.Load(), func(ctx context.Context) error {
+				return proc.deadLetterDB.Store(ctx, deadLetterJobs)
+			}, proc.sendRetryStoreStats)
+			if err != nil {
+				proc.logger.Errorf("Store into dead-letter table failed with error: %v", err)
+				proc.logger.Errorf("deadLetterJobs: %v", deadLetterJobs)
+				panic(err)
+			}
+			proc.logger.Debug("[Processor] Total jobs written to dead-letter: ", len(deadLetterJobs))
 		}
-		proc.logger.Debug("[Processor] Total jobs written to proc_error: ", len(in.procErrorJobs))
 		proc.recordEventDeliveryStatus(in.procErrorJobsByDestID)
 	}
 
@@ -2165,6 +2213,8 @@ func (proc *Handle) Store(partition string, in *storeMessage) {
 	proc.stats.statRouterDBW.Count(len(destJobs))
 	proc.stats.statBatchRouterDBW.Count(len(batchDestJobs))
 	proc.stats.statProcErrDBW.Count(len(in.procErrorJobs))
+
+	proc.cycles.cycleDone(len(statusList), time.Since(in.start), time.Now())
 }
 
 // getJobCountsByWorkspaceDestType returns the number of jobs per workspace and destination type
@@ -2287,6 +2337,7 @@ func (proc *Handle) transformSrcDest(
 			response = proc.transformer.UserTransform(ctx, eventList, proc.config.userTransformBatchSize.Load())
 			d := time.Since(startedAt)
 			userTransformationStat.transformTime.SendTiming(d)
+			proc.eventSampler.sample(transformer.UserTransformerStage, eventList, response)
 
 			var successMetrics []*types.PUReportedMetric
 			var successCountMap map[string]int64
@@ -2430,6 +2481,8 @@ func (proc *Handle) transformSrcDest(
 
 			destTransformationStat := proc.newDestinationTransformationStat(sourceID, workspaceID, transformAt, destination)
 			destTransformationStat.transformTime.Since(s)
+			proc.eventSampler.sample(transformer.DestTransformerStage, eventsToTransform, response)
+			proc.transformationFailures.capture(destID, eventsToTransform, response, time.Now())
 			transformAt = "processor"
 
 			proc.logger.Debugf("Dest Transform output size %d", len(response.Events))
@@ -2485,6 +2538,7 @@ func (proc *Handle) transformSrcDest(
 	}
 
 	trace.WithRegion(ctx, "MarshalForDB", func() {
+		processedAt := time.Now()
 		// Save the JSON in DB. This is what the router uses
 		for i := range response.Events {
 			destEventJSON, err := jsonfast.Marshal(response.Events[i].Output)
@@ -2556,13 +2610,16 @@ func (proc *Handle) transformSrcDest(
 				EventPayload: destEventJSON,
 				WorkspaceId:  workspaceId,
 			}
-			if slices.Contains(proc.config.batchDestinations, newJob.CustomVal) {
+			proc.recordLatency(sourceID, destType, receivedAt, processedAt)
+
+			if proc.routesToBatchRouter(&newJob) {
 				batchDestJobs = append(batchDestJobs, &newJob)
 			} else {
 				destJobs = append(destJobs, &newJob)
 				routerDestIDs[destID] = struct{}{}
 			}
 		}
+		proc.recordRouting(destType, len(destJobs), len(batchDestJobs))
 	})
 	return transformSrcDestOutput{
 		destJobs:        destJobs,
@@ -2651,7 +2708,22 @@ func ConvertToFilteredTransformerResponse(events []transformer.TransformerEvent,
 	return transformer.Response{Events: responses, FailedEvents: failedEvents}
 }
 
+// payloadSizeLimit returns the limit of the total payload bytes of the jobs read in a batch.
+// If a max batch bytes limit is set, the payload limit can not exceed it.
+func (proc *Handle) payloadSizeLimit() int64 {
+	limit := proc.adaptiveLimit(proc.payloadLimit.Load())
+	if maxBatchBytes := proc.config.maxBatchBytes; maxBatchBytes > 0 && (limit <= 0 || limit > maxBatchBytes) {
+		return maxBatchBytes
+	}
+	return limit
+}
+
 func (proc *Handle) getJobs(partition string) jobsdb.JobsResult {
+	// don't read any new jobs while flushing
+	if !proc.pendingBatches.begin() {
+		return jobsdb.JobsResult{}
+	}
+
 	if proc.limiter.read != nil {
 		defer proc.limiter.read.BeginWithPriority(partition, proc.getLimiterPriority(partition))()
 	}
@@ -2668,7 +2740,7 @@ func (proc *Handle) getJobs(partition string) jobsdb.JobsResult {
 		CustomValFilters: []string{proc.config.GWCustomVal},
 		JobsLimit:        proc.config.maxEventsToProcess.Load(),
 		EventsLimit:      eventCount,
-		PayloadSizeLimit: proc.adaptiveLimit(proc.payloadLimit.Load()),
+		PayloadSizeLimit: proc.payloadSizeLimit(),
 	}
 	proc.isolationStrategy.AugmentQueryParams(partition, &queryParams)
 
@@ -2679,6 +2751,7 @@ func (proc *Handle) getJobs(partition string) jobsdb.JobsResult {
 		proc.logger.Errorf("Failed to get unprocessed jobs from DB. Error: %v", err)
 		panic(err)
 	}
+	unprocessedList = limitJobsPerSource(unprocessedList, proc.sourceRateLimiter)
 
 	totalPayloadBytes := 0
 	for _, job := range unprocessedList.Jobs {
@@ -2706,6 +2779,7 @@ func (proc *Handle) getJobs(partition string) jobsdb.JobsResult {
 
 	// check if there is work to be done
 	if len(unprocessedList.Jobs) == 0 {
+		proc.pendingBatches.end()
 		proc.logger.Debugf("Processor DB Read Complete. No GW Jobs to process.")
 		return unprocessedList
 	}
@@ -2943,11 +3017,42 @@ func (proc *Handle) IncreasePendingEvents(tablePrefix string, stats map[string]m
 	}
 }
 
+// waitForBackendConfig waits for the backend config to become available, waiting up to Processor.backendConfigWaitTimeout per attempt
+// and retrying with an exponential backoff up to featuresRetryMaxAttempts times, so that a transient unavailability of the control plane
+// doesn't fail the start of the processor.
+func (proc *Handle) waitForBackendConfig(ctx context.Context) error {
+	var maxRetries uint64
+	if proc.config.featuresRetryMaxAttempts > 0 {
+		maxRetries = uint64(proc.config.featuresRetryMaxAttempts)
+	}
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.MaxElapsedTime = 0 // bounded by featuresRetryMaxAttempts only
+	return backoff.RetryNotify(
+		func() error {
+			waitCtx, cancel := context.WithTimeout(ctx, proc.config.backendConfigWaitTimeout)
+			defer cancel()
+			proc.backendConfig.WaitForConfig(waitCtx)
+			if ctx.Err() != nil {
+				return backoff.Permanent(ctx.Err())
+			}
+			if waitCtx.Err() != nil {
+				return fmt.Errorf("backend config not available after %s", proc.config.backendConfigWaitTimeout)
+			}
+			return nil
+		},
+		backoff.WithContext(backoff.WithMaxRetries(expBackoff, maxRetries), ctx),
+		func(err error, t time.Duration) {
+			proc.logger.Warnf("Waiting for backend config failed, retrying in %s: %v", t, err)
+		},
+	)
+}
+
 func (proc *Handle) countPendingEvents(ctx context.Context) error {
 	dbs := map[string]jobsdb.JobsDB{"rt": proc.routerDB, "batch_rt": proc.batchRouterDB}
 	jobdDBQueryRequestTimeout := config.GetDurationVar(600, time.Second, "JobsDB.GetPileUpCounts.QueryRequestTimeout", "JobsDB.QueryRequestTimeout")
 	jobdDBMaxRetries := config.GetReloadableIntVar(2, 1, "JobsDB.Processor.MaxRetries", "JobsDB.MaxRetries")
 
+	pileUpCounts := make(map[string]map[string]map[string]int, len(dbs))
 	for tablePrefix, db := range dbs {
 		pileUpStatMap, err := misc.QueryWithRetriesAndNotify(ctx,
 			jobdDBQueryRequestTimeout,
@@ -2961,6 +3066,10 @@ func (proc *Handle) countPendingEvents(ctx context.Context) error {
 		if err != nil {
 			return err
 		}
+		pileUpCounts[tablePrefix] = pileUpStatMap
+	}
+	// the counts are applied only once all of them have been collected, so that a failure doesn't leave some of them counted
+	for tablePrefix, pileUpStatMap := range pileUpCounts {
 		proc.IncreasePendingEvents(tablePrefix, pileUpStatMap)
 	}
 	return nil
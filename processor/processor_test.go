@@ -3,6 +3,7 @@ package processor
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
@@ -29,6 +30,7 @@ import (
 
 	"github.com/rudderlabs/rudder-go-kit/config"
 	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats/metric"
 	"github.com/rudderlabs/rudder-server/admin"
 	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
 	"github.com/rudderlabs/rudder-server/jobsdb"
@@ -40,6 +42,7 @@ import (
 	"github.com/rudderlabs/rudder-server/processor/isolation"
 	"github.com/rudderlabs/rudder-server/processor/transformer"
 	"github.com/rudderlabs/rudder-server/services/fileuploader"
+	"github.com/rudderlabs/rudder-server/services/rmetrics"
 	"github.com/rudderlabs/rudder-server/services/rsources"
 	"github.com/rudderlabs/rudder-server/services/transientsource"
 	"github.com/rudderlabs/rudder-server/utils/misc"
@@ -1101,6 +1104,39 @@ var _ = Describe("Processor", Ordered, func() {
 			defer cancel()
 			Expect(processor.config.asyncInit.WaitContext(ctx)).To(BeNil())
 		})
+
+		It("should keep the configured transformation failure samples", func() {
+			config.Set("Processor.transformationFailureSamples", 2)
+			defer config.Reset()
+
+			mockTransformer := mocksTransformer.NewMockTransformer(c.mockCtrl)
+
+			processor := prepareHandle(NewHandle(mockTransformer))
+
+			c.mockGatewayJobsDB.EXPECT().DeleteExecuting().Times(1)
+
+			processor.Setup(
+				c.mockBackendConfig,
+				c.mockGatewayJobsDB,
+				c.mockRouterJobsDB,
+				c.mockBatchRouterJobsDB,
+				c.mockReadProcErrorsDB,
+				c.mockWriteProcErrorsDB,
+				nil,
+				nil,
+				nil,
+				transientsource.NewEmptyService(),
+				fileuploader.NewDefaultProvider(),
+				c.MockRsourcesService,
+				destinationdebugger.NewNoOpService(),
+				transformationdebugger.NewNoOpService(),
+			)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			Expect(processor.config.asyncInit.WaitContext(ctx)).To(BeNil())
+			Expect(processor.transformationFailures).NotTo(BeNil())
+			Expect(processor.transformationFailures.size).To(Equal(2))
+		})
 	})
 
 	Context("normal operation", func() {
@@ -1147,6 +1183,45 @@ var _ = Describe("Processor", Ordered, func() {
 			Expect(didWork).To(Equal(false))
 		})
 
+		It("should pick up the jobs using the pickup batch size", func() {
+			mockTransformer := mocksTransformer.NewMockTransformer(c.mockCtrl)
+
+			processor := prepareHandle(NewHandle(mockTransformer))
+			processor.config.pickupBatchSize = 10
+
+			processor.Setup(
+				c.mockBackendConfig,
+				c.mockGatewayJobsDB,
+				c.mockRouterJobsDB,
+				c.mockBatchRouterJobsDB,
+				c.mockReadProcErrorsDB,
+				c.mockWriteProcErrorsDB,
+				nil,
+				nil,
+				c.MockReportingI,
+				transientsource.NewEmptyService(),
+				fileuploader.NewDefaultProvider(),
+				c.MockRsourcesService,
+				destinationdebugger.NewNoOpService(),
+				transformationdebugger.NewNoOpService(),
+			)
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			Expect(processor.config.asyncInit.WaitContext(ctx)).To(BeNil())
+
+			c.mockGatewayJobsDB.EXPECT().GetUnprocessed(
+				gomock.Any(),
+				jobsdb.GetQueryParams{
+					CustomValFilters: gatewayCustomVal,
+					JobsLimit:        10,
+					EventsLimit:      10,
+					PayloadSizeLimit: processor.payloadLimit.Load(),
+				}).Return(jobsdb.JobsResult{Jobs: emptyJobsList}, nil).Times(1)
+
+			didWork := processor.handlePendingGatewayJobs("")
+			Expect(didWork).To(Equal(false))
+		})
+
 		It("should process unprocessed jobs to destination without user transformation", func() {
 			messages := map[string]mockEventData{
 				// this message should be delivered only to destination A
@@ -2274,6 +2349,77 @@ var _ = Describe("Processor", Ordered, func() {
 	})
 })
 
+var _ = Describe("Processor start dependencies", func() {
+	var (
+		mockCtrl              *gomock.Controller
+		mockBackendConfig     *mocksBackendConfig.MockBackendConfig
+		mockRouterJobsDB      *mocksJobsDB.MockJobsDB
+		mockBatchRouterJobsDB *mocksJobsDB.MockJobsDB
+	)
+
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		mockBackendConfig = mocksBackendConfig.NewMockBackendConfig(mockCtrl)
+		mockRouterJobsDB = mocksJobsDB.NewMockJobsDB(mockCtrl)
+		mockBatchRouterJobsDB = mocksJobsDB.NewMockJobsDB(mockCtrl)
+	})
+
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	It("should retry waiting for the backend config until it becomes available", func() {
+		processor := NewHandle(mocksTransformer.NewMockTransformer(mockCtrl))
+		processor.logger = logger.NOP
+		processor.backendConfig = mockBackendConfig
+		processor.config.featuresRetryMaxAttempts = 3
+		processor.config.backendConfigWaitTimeout = 10 * time.Millisecond
+
+		gomock.InOrder(
+			mockBackendConfig.EXPECT().WaitForConfig(gomock.Any()).Times(1).Do(func(ctx context.Context) {
+				<-ctx.Done()
+			}),
+			mockBackendConfig.EXPECT().WaitForConfig(gomock.Any()).Times(1),
+		)
+		Expect(processor.waitForBackendConfig(context.Background())).To(Succeed())
+	})
+
+	It("should give up waiting for the backend config after featuresRetryMaxAttempts", func() {
+		processor := NewHandle(mocksTransformer.NewMockTransformer(mockCtrl))
+		processor.logger = logger.NOP
+		processor.backendConfig = mockBackendConfig
+		processor.config.featuresRetryMaxAttempts = 1
+		processor.config.backendConfigWaitTimeout = 10 * time.Millisecond
+
+		mockBackendConfig.EXPECT().WaitForConfig(gomock.Any()).Times(2).Do(func(ctx context.Context) {
+			<-ctx.Done()
+		})
+		Expect(processor.waitForBackendConfig(context.Background())).To(MatchError(ContainSubstring("backend config not available")))
+	})
+
+	It("should not double count the pending events when counting them again after a partial failure", func() {
+		metric.Instance.Reset()
+		defer metric.Instance.Reset()
+		processor := NewHandle(mocksTransformer.NewMockTransformer(mockCtrl))
+		processor.logger = logger.NOP
+		processor.routerDB = mockRouterJobsDB
+		processor.batchRouterDB = mockBatchRouterJobsDB
+
+		mockRouterJobsDB.EXPECT().GetPileUpCounts(gomock.Any()).Return(map[string]map[string]int{sampleWorkspaceID: {"WEBHOOK": 5}}, nil).AnyTimes()
+		gomock.InOrder(
+			mockBatchRouterJobsDB.EXPECT().GetPileUpCounts(gomock.Any()).Return(nil, errors.New("batch_rt unavailable")).Times(1),
+			mockBatchRouterJobsDB.EXPECT().GetPileUpCounts(gomock.Any()).Return(map[string]map[string]int{sampleWorkspaceID: {"S3": 3}}, nil).Times(1),
+		)
+
+		Expect(processor.countPendingEvents(context.Background())).To(MatchError(ContainSubstring("batch_rt unavailable")))
+		Expect(rmetrics.PendingEvents("rt", sampleWorkspaceID, "WEBHOOK").Value()).To(BeZero())
+
+		Expect(processor.countPendingEvents(context.Background())).To(Succeed())
+		Expect(rmetrics.PendingEvents("rt", sampleWorkspaceID, "WEBHOOK").Value()).To(Equal(float64(5)))
+		Expect(rmetrics.PendingEvents("batch_rt", sampleWorkspaceID, "S3").Value()).To(Equal(float64(3)))
+	})
+})
+
 var _ = Describe("Static Function Tests", func() {
 	initProcessor()
 
@@ -4081,3 +4227,30 @@ func TestStoreMessageMerge(t *testing.T) {
 	require.Len(t, merged.dedupKeys, 2, "dedup keys should have 2 elements")
 	require.Equal(t, merged.totalEvents, 2, "total events should be 2")
 }
+
+func TestPayloadSizeLimit(t *testing.T) {
+	newHandle := func(payloadLimit, maxBatchBytes int64) *Handle {
+		proc := &Handle{
+			adaptiveLimit: func(limit int64) int64 { return limit / 2 },
+			payloadLimit:  misc.SingleValueLoader(payloadLimit),
+		}
+		proc.config.maxBatchBytes = maxBatchBytes
+		return proc
+	}
+
+	t.Run("no max batch bytes", func(t *testing.T) {
+		require.EqualValues(t, 50, newHandle(100, 0).payloadSizeLimit())
+	})
+
+	t.Run("max batch bytes below payload limit", func(t *testing.T) {
+		require.EqualValues(t, 10, newHandle(100, 10).payloadSizeLimit())
+	})
+
+	t.Run("max batch bytes above payload limit", func(t *testing.T) {
+		require.EqualValues(t, 50, newHandle(100, 1000).payloadSizeLimit())
+	})
+
+	t.Run("max batch bytes without payload limit", func(t *testing.T) {
+		require.EqualValues(t, 10, newHandle(0, 10).payloadSizeLimit())
+	})
+}
@@ -0,0 +1,56 @@
+package processor
+
+import (
+	"golang.org/x/exp/slices"
+
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+)
+
+// DestinationClass is the class of routers a transformed event is written for
+type DestinationClass int
+
+const (
+	// DefaultDestinationClass routes the event based on its destination type, as if there was no routing decider
+	DefaultDestinationClass DestinationClass = iota
+	// RouterDestinationClass writes the event to the router DB
+	RouterDestinationClass
+	// BatchRouterDestinationClass writes the event to the batch router DB
+	BatchRouterDestinationClass
+)
+
+// RoutingDecider decides whether a transformed event is written to the router or the batch router DB, given its job (whose custom val is the destination type).
+// The destination type needs to be served by the chosen class of routers, otherwise the event is never delivered.
+// It is called concurrently from the processor workers, so it needs to be thread-safe.
+type RoutingDecider func(job *jobsdb.JobT) DestinationClass
+
+// routesToBatchRouter returns true if the job is to be written to the batch router DB.
+// The routing decider, if any, takes precedence over the destination type based rule.
+func (proc *Handle) routesToBatchRouter(job *jobsdb.JobT) bool {
+	if proc.routingDecider != nil {
+		switch proc.routingDecider(job) {
+		case RouterDestinationClass:
+			return false
+		case BatchRouterDestinationClass:
+			return true
+		}
+	}
+	return slices.Contains(proc.config.batchDestinations, job.CustomVal)
+}
+
+// recordRouting counts the transformed events of the destination type written to the router and the batch router DBs (processor.routed_events),
+// so that the split between the two, and destination types routed to the wrong class of routers, can be seen. It can be disabled through Processor.Stats.captureRouting.
+func (proc *Handle) recordRouting(destType string, routerEvents, batchRouterEvents int) {
+	if !proc.config.captureRoutingStats.Load() {
+		return
+	}
+	for module, count := range map[string]int{"router": routerEvents, "batch_router": batchRouterEvents} {
+		if count == 0 {
+			continue
+		}
+		proc.statsFactory.NewTaggedStat("processor.routed_events", stats.CountType, stats.Tags{
+			"destType": destType,
+			"module":   module,
+		}).Count(count)
+	}
+}
@@ -0,0 +1,72 @@
+package processor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-go-kit/stats/memstats"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+func TestRoutesToBatchRouter(t *testing.T) {
+	rtJob := &jobsdb.JobT{CustomVal: "WEBHOOK"}
+	brtJob := &jobsdb.JobT{CustomVal: "S3"}
+
+	t.Run("without routing decider", func(t *testing.T) {
+		proc := &Handle{}
+		proc.config.batchDestinations = []string{"S3"}
+
+		require.False(t, proc.routesToBatchRouter(rtJob))
+		require.True(t, proc.routesToBatchRouter(brtJob))
+	})
+
+	t.Run("with routing decider", func(t *testing.T) {
+		proc := &Handle{routingDecider: func(job *jobsdb.JobT) DestinationClass {
+			switch string(job.EventPayload) {
+			case "router":
+				return RouterDestinationClass
+			case "batchrouter":
+				return BatchRouterDestinationClass
+			default:
+				return DefaultDestinationClass
+			}
+		}}
+		proc.config.batchDestinations = []string{"S3"}
+
+		require.True(t, proc.routesToBatchRouter(&jobsdb.JobT{CustomVal: "WEBHOOK", EventPayload: []byte("batchrouter")}))
+		require.False(t, proc.routesToBatchRouter(&jobsdb.JobT{CustomVal: "S3", EventPayload: []byte("router")}))
+
+		// the destination type based rule applies for the default class
+		require.False(t, proc.routesToBatchRouter(rtJob))
+		require.True(t, proc.routesToBatchRouter(brtJob))
+	})
+}
+
+func TestRecordRouting(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		store := memstats.New()
+		proc := &Handle{statsFactory: store}
+		proc.config.captureRoutingStats = misc.SingleValueLoader(true)
+
+		proc.recordRouting("WEBHOOK", 3, 1)
+		proc.recordRouting("S3", 0, 2)
+
+		require.EqualValues(t, 3, store.Get("processor.routed_events", stats.Tags{"destType": "WEBHOOK", "module": "router"}).LastValue())
+		require.EqualValues(t, 1, store.Get("processor.routed_events", stats.Tags{"destType": "WEBHOOK", "module": "batch_router"}).LastValue())
+		require.EqualValues(t, 2, store.Get("processor.routed_events", stats.Tags{"destType": "S3", "module": "batch_router"}).LastValue())
+		require.Nil(t, store.Get("processor.routed_events", stats.Tags{"destType": "S3", "module": "router"}), "no events routed to the router")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		store := memstats.New()
+		proc := &Handle{statsFactory: store}
+		proc.config.captureRoutingStats = misc.SingleValueLoader(false)
+
+		proc.recordRouting("WEBHOOK", 3, 1)
+
+		require.Nil(t, store.Get("processor.routed_events", stats.Tags{"destType": "WEBHOOK", "module": "router"}))
+	})
+}
@@ -0,0 +1,48 @@
+package processor
+
+import (
+	"github.com/tidwall/gjson"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+)
+
+// limitJobsPerSource keeps at most maxEvents(sourceID) events per source in the batch of jobs, so that a noisy source can't crowd out the others.
+// Since all the jobs of a source after its limit is reached are left out, they are picked up in order by the next batches.
+// The first job of every source is always kept, while sources without a limit (0) are never limited.
+func limitJobsPerSource(result jobsdb.JobsResult, maxEvents func(sourceID string) int) jobsdb.JobsResult {
+	if maxEvents == nil || len(result.Jobs) == 0 {
+		return result
+	}
+
+	eventsPerSource := make(map[string]int)
+	limits := make(map[string]int)
+	limited := jobsdb.JobsResult{
+		Jobs:          make([]*jobsdb.JobT, 0, len(result.Jobs)),
+		LimitsReached: result.LimitsReached,
+	}
+	for _, job := range result.Jobs {
+		sourceID := gjson.GetBytes(job.Parameters, "source_id").String()
+		limit, ok := limits[sourceID]
+		if !ok {
+			limit = maxEvents(sourceID)
+			limits[sourceID] = limit
+		}
+
+		if limit > 0 && eventsPerSource[sourceID] >= limit {
+			// there are more jobs to be picked up
+			limited.LimitsReached = true
+			continue
+		}
+
+		eventCount := max(job.EventCount, 1)
+		eventsPerSource[sourceID] += eventCount
+		limited.Jobs = append(limited.Jobs, job)
+		limited.EventsCount += eventCount
+		limited.PayloadSize += job.PayloadSize
+	}
+
+	if len(limited.Jobs) == len(result.Jobs) {
+		return result
+	}
+	return limited
+}
@@ -0,0 +1,65 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/jobsdb"
+)
+
+func TestLimitJobsPerSource(t *testing.T) {
+	newJob := func(jobID int64, sourceID string, eventCount int) *jobsdb.JobT {
+		return &jobsdb.JobT{
+			JobID:       jobID,
+			Parameters:  []byte(fmt.Sprintf(`{"source_id":%q}`, sourceID)),
+			EventCount:  eventCount,
+			PayloadSize: 10,
+		}
+	}
+	jobIDs := func(result jobsdb.JobsResult) []int64 {
+		return lo.Map(result.Jobs, func(job *jobsdb.JobT, _ int) int64 { return job.JobID })
+	}
+
+	result := jobsdb.JobsResult{
+		Jobs: []*jobsdb.JobT{
+			newJob(1, "noisy", 1),
+			newJob(2, "noisy", 2),
+			newJob(3, "quiet", 1),
+			newJob(4, "noisy", 1),
+			newJob(5, "quiet", 1),
+			newJob(6, "noisy", 1),
+		},
+		EventsCount: 7,
+		PayloadSize: 60,
+	}
+
+	t.Run("no limiter", func(t *testing.T) {
+		require.Equal(t, result, limitJobsPerSource(result, nil))
+	})
+
+	t.Run("no limits", func(t *testing.T) {
+		require.Equal(t, result, limitJobsPerSource(result, func(string) int { return 0 }))
+	})
+
+	t.Run("limited source", func(t *testing.T) {
+		limited := limitJobsPerSource(result, func(sourceID string) int {
+			if sourceID == "noisy" {
+				return 2
+			}
+			return 0
+		})
+		require.Equal(t, []int64{1, 2, 3, 5}, jobIDs(limited))
+		require.True(t, limited.LimitsReached)
+		require.Equal(t, 5, limited.EventsCount)
+		require.EqualValues(t, 40, limited.PayloadSize)
+	})
+
+	t.Run("first job is always kept", func(t *testing.T) {
+		limited := limitJobsPerSource(result, func(string) int { return 1 })
+		require.Equal(t, []int64{1, 3}, jobIDs(limited))
+		require.True(t, limited.LimitsReached)
+	})
+}
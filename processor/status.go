@@ -0,0 +1,57 @@
+package processor
+
+import (
+	"sync"
+	"time"
+)
+
+// Status is a snapshot of the processing activity of the processor, exposed through the admin interface for debugging
+type Status struct {
+	InFlightBatches   int       `json:"inFlightBatches"`   // batches read from the gateway DB which are not stored yet
+	ProcessedJobs     int64     `json:"processedJobs"`     // gateway jobs processed since the processor started
+	LastCycleJobs     int       `json:"lastCycleJobs"`     // gateway jobs processed by the last cycle, from reading to storing them
+	LastCycleDuration string    `json:"lastCycleDuration"` // duration of the last cycle
+	LastCycleAt       time.Time `json:"lastCycleAt"`       // when the last cycle ended, zero if no cycle ended yet
+	Throughput        float64   `json:"throughput"`        // gateway jobs per second of the last cycle
+
+	// TransformationFailures are the last destination transformation failures by destination ID, if kept (see WithTransformationFailureSamples)
+	TransformationFailures map[string][]TransformationFailureSample `json:"transformationFailures,omitempty"`
+}
+
+// cycleTracker keeps track of the processing cycles for the status of the processor
+type cycleTracker struct {
+	mu                sync.RWMutex
+	processedJobs     int64
+	lastCycleJobs     int
+	lastCycleDuration time.Duration
+	lastCycleAt       time.Time
+}
+
+// cycleDone records a cycle which processed the jobs within the duration, ending at the given time
+func (t *cycleTracker) cycleDone(jobs int, duration time.Duration, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.processedJobs += int64(jobs)
+	t.lastCycleJobs = jobs
+	t.lastCycleDuration = duration
+	t.lastCycleAt = at
+}
+
+// Status returns a snapshot of the processing activity of the processor
+func (proc *Handle) Status() interface{} {
+	proc.cycles.mu.RLock()
+	status := Status{
+		ProcessedJobs:     proc.cycles.processedJobs,
+		LastCycleJobs:     proc.cycles.lastCycleJobs,
+		LastCycleDuration: proc.cycles.lastCycleDuration.String(),
+		LastCycleAt:       proc.cycles.lastCycleAt,
+	}
+	if proc.cycles.lastCycleDuration > 0 {
+		status.Throughput = float64(proc.cycles.lastCycleJobs) / proc.cycles.lastCycleDuration.Seconds()
+	}
+	proc.cycles.mu.RUnlock()
+
+	status.InFlightBatches = proc.pendingBatches.pending()
+	status.TransformationFailures = proc.transformationFailures.samples()
+	return status
+}
@@ -0,0 +1,30 @@
+package processor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatus(t *testing.T) {
+	proc := &Handle{}
+	require.Equal(t, Status{LastCycleDuration: "0s"}, proc.Status())
+
+	require.True(t, proc.pendingBatches.begin())
+	require.True(t, proc.pendingBatches.begin())
+	proc.pendingBatches.end()
+
+	at := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	proc.cycles.cycleDone(100, time.Second, at.Add(-time.Minute))
+	proc.cycles.cycleDone(50, 2*time.Second, at)
+
+	require.Equal(t, Status{
+		InFlightBatches:   1,
+		ProcessedJobs:     150,
+		LastCycleJobs:     50,
+		LastCycleDuration: "2s",
+		LastCycleAt:       at,
+		Throughput:        25,
+	}, proc.Status())
+}
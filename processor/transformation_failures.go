@@ -0,0 +1,140 @@
+package processor
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/exp/slices"
+
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+// redactedValue replaces the values of the events kept, since the status of the processor is exposed over the status endpoint
+const redactedValue = "[redacted]"
+
+// unredactedFields are the fields of the events kept as they are, identifying the events without exposing their payloads
+var unredactedFields = []string{"type", "event", "messageId", "channel"}
+
+// TransformationFailureSample is an event which failed the destination transformation, along with the failed response.
+// The values of the event and of the response are redacted, except for unredactedFields, and the destination config is left out.
+type TransformationFailureSample struct {
+	Input    transformer.TransformerEvent    `json:"input"`
+	Output   transformer.TransformerResponse `json:"output"`
+	FailedAt time.Time                       `json:"failedAt"`
+}
+
+// transformationFailures keeps the last destination transformation failures of every destination for debugging,
+// so that failing transformations can be looked into without enabling the debuggers
+type transformationFailures struct {
+	size int
+
+	mu            sync.RWMutex
+	byDestination map[string][]TransformationFailureSample // oldest first
+}
+
+// newTransformationFailures returns nil if no failure would ever be kept, so that capturing failures has no overhead by default
+func newTransformationFailures(size int) *transformationFailures {
+	if size <= 0 {
+		return nil
+	}
+	return &transformationFailures{
+		size:          size,
+		byDestination: make(map[string][]TransformationFailureSample),
+	}
+}
+
+// capture keeps the failed responses of the destination transformation of the events, dropping the oldest failures of the destination beyond the size.
+// Filtered events aren't failures, so they are not kept.
+func (f *transformationFailures) capture(destID string, events []transformer.TransformerEvent, response transformer.Response, at time.Time) {
+	if f == nil || len(response.FailedEvents) == 0 {
+		return
+	}
+
+	inputs := make(map[string]transformer.TransformerEvent, len(events)) // messageID -> event
+	for i := range events {
+		inputs[events[i].Metadata.MessageID] = events[i]
+	}
+
+	var samples []TransformationFailureSample
+	for i := range response.FailedEvents {
+		if response.FailedEvents[i].StatusCode == types.FilterEventCode {
+			continue
+		}
+		output := response.FailedEvents[i]
+		output.Output = redact(output.Output)
+		sample := TransformationFailureSample{Output: output, FailedAt: at}
+		for _, messageID := range response.FailedEvents[i].Metadata.GetMessagesIDs() {
+			if input, ok := inputs[messageID]; ok {
+				input.Message = redact(input.Message)
+				input.Destination.Config = nil
+				sample.Input = input
+				break
+			}
+		}
+		samples = append(samples, sample)
+	}
+	if len(samples) == 0 {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	destSamples := append(f.byDestination[destID], samples...)
+	if excess := len(destSamples) - f.size; excess > 0 {
+		destSamples = append([]TransformationFailureSample(nil), destSamples[excess:]...)
+	}
+	f.byDestination[destID] = destSamples
+}
+
+// samples returns the last failures kept for every destination, keyed by destination ID
+func (f *transformationFailures) samples() map[string][]TransformationFailureSample {
+	if f == nil {
+		return nil
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	samples := make(map[string][]TransformationFailureSample, len(f.byDestination))
+	for destID, destSamples := range f.byDestination {
+		samples[destID] = append([]TransformationFailureSample(nil), destSamples...)
+	}
+	return samples
+}
+
+// redact returns a copy of the event with all the values replaced by redactedValue, except for the top level unredactedFields.
+// The keys are kept, so that the shape of the event can still be looked into.
+func redact(event map[string]interface{}) map[string]interface{} {
+	if event == nil {
+		return nil
+	}
+	redacted := make(map[string]interface{}, len(event))
+	for key, value := range event {
+		if slices.Contains(unredactedFields, key) {
+			redacted[key] = value
+			continue
+		}
+		redacted[key] = redactValue(value)
+	}
+	return redacted
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			redacted[key] = redactValue(value)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i := range v {
+			redacted[i] = redactValue(v[i])
+		}
+		return redacted
+	case nil:
+		return nil
+	default:
+		return redactedValue
+	}
+}
@@ -0,0 +1,129 @@
+package processor
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/processor/transformer"
+	"github.com/rudderlabs/rudder-server/utils/types"
+)
+
+func TestTransformationFailures(t *testing.T) {
+	at := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	events := make([]transformer.TransformerEvent, 0, 5)
+	for i := 0; i < 5; i++ {
+		events = append(events, transformer.TransformerEvent{
+			Metadata: transformer.Metadata{MessageID: strconv.Itoa(i)},
+			Message:  map[string]interface{}{"index": i},
+			Destination: backendconfig.DestinationT{
+				ID:     "dest1",
+				Config: map[string]interface{}{"apiKey": "secret"},
+			},
+		})
+	}
+	// redacted returns the event as kept in the samples
+	redacted := func(i int) transformer.TransformerEvent {
+		event := events[i]
+		event.Message = map[string]interface{}{"index": redactedValue}
+		event.Destination.Config = nil
+		return event
+	}
+	response := transformer.Response{
+		Events: []transformer.TransformerResponse{
+			{Metadata: transformer.Metadata{MessageID: "0"}, StatusCode: 200},
+		},
+		FailedEvents: []transformer.TransformerResponse{
+			{Metadata: transformer.Metadata{MessageID: "1"}, StatusCode: 400, Error: "error 1"},
+			{Metadata: transformer.Metadata{MessageIDs: []string{"2", "3"}}, StatusCode: 500, Error: "error 2"},
+			{Metadata: transformer.Metadata{MessageID: "4"}, StatusCode: types.FilterEventCode, Error: "filtered"},
+		},
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		require.Nil(t, newTransformationFailures(0))
+
+		var f *transformationFailures
+		f.capture("dest1", events, response, at)
+		require.Nil(t, f.samples())
+
+		proc := &Handle{}
+		require.Nil(t, proc.Status().(Status).TransformationFailures)
+	})
+
+	t.Run("captures the failures with their input", func(t *testing.T) {
+		f := newTransformationFailures(10)
+		f.capture("dest1", events, response, at)
+		f.capture("dest2", events, transformer.Response{Events: response.Events}, at)
+
+		require.Equal(t, map[string][]TransformationFailureSample{
+			"dest1": {
+				{Input: redacted(1), Output: response.FailedEvents[0], FailedAt: at},
+				{Input: redacted(2), Output: response.FailedEvents[1], FailedAt: at},
+			},
+		}, f.samples())
+	})
+
+	t.Run("keeps the last failures of every destination", func(t *testing.T) {
+		f := newTransformationFailures(3)
+		f.capture("dest1", events, response, at)
+		f.capture("dest1", events, response, at.Add(time.Minute))
+		f.capture("dest2", events, response, at)
+
+		samples := f.samples()
+		require.Len(t, samples["dest1"], 3)
+		require.Equal(t, TransformationFailureSample{Input: redacted(2), Output: response.FailedEvents[1], FailedAt: at}, samples["dest1"][0])
+		require.Equal(t, at.Add(time.Minute), samples["dest1"][1].FailedAt)
+		require.Equal(t, at.Add(time.Minute), samples["dest1"][2].FailedAt)
+		require.Len(t, samples["dest2"], 2)
+	})
+
+	t.Run("reported in the status", func(t *testing.T) {
+		proc := &Handle{transformationFailures: newTransformationFailures(1)}
+		proc.transformationFailures.capture("dest1", events, response, at)
+
+		require.Equal(t, map[string][]TransformationFailureSample{
+			"dest1": {{Input: redacted(2), Output: response.FailedEvents[1], FailedAt: at}},
+		}, proc.Status().(Status).TransformationFailures)
+	})
+	t.Run("redacts the events", func(t *testing.T) {
+		input := transformer.TransformerEvent{
+			Metadata: transformer.Metadata{MessageID: "1"},
+			Message: map[string]interface{}{
+				"type":      "track",
+				"event":     "Order Completed",
+				"messageId": "1",
+				"userId":    "user@example.com",
+				"context":   map[string]interface{}{"traits": map[string]interface{}{"email": "user@example.com"}},
+				"products":  []interface{}{map[string]interface{}{"price": 10.5}, "gift"},
+				"coupon":    nil,
+			},
+		}
+		failed := transformer.Response{
+			FailedEvents: []transformer.TransformerResponse{
+				{Metadata: transformer.Metadata{MessageID: "1"}, Output: map[string]interface{}{"userId": "user@example.com"}, StatusCode: 400, Error: "error 1"},
+			},
+		}
+
+		f := newTransformationFailures(1)
+		f.capture("dest1", []transformer.TransformerEvent{input}, failed, at)
+
+		sample := f.samples()["dest1"][0]
+		require.Equal(t, map[string]interface{}{
+			"type":      "track",
+			"event":     "Order Completed",
+			"messageId": "1",
+			"userId":    redactedValue,
+			"context":   map[string]interface{}{"traits": map[string]interface{}{"email": redactedValue}},
+			"products":  []interface{}{map[string]interface{}{"price": redactedValue}, redactedValue},
+			"coupon":    nil,
+		}, map[string]interface{}(sample.Input.Message))
+		require.Equal(t, map[string]interface{}{"userId": redactedValue}, sample.Output.Output)
+		require.Equal(t, "error 1", sample.Output.Error)
+		require.Equal(t, "user@example.com", input.Message["userId"], "the event itself is left as it is")
+	})
+}
@@ -149,6 +149,18 @@ func GetVersion() string {
 	return defaultBuildVersion
 }
 
+// RetryClassifier decides whether a transformer request should be retried, given the status code returned by the transformer or the error of the request (status code is 0 then).
+// Requests which are not retried fail all their events with the returned status code, so that they are aborted instead of churning retries.
+type RetryClassifier func(statusCode int, err error) bool
+
+// DefaultRetryClassifier retries request errors and the status codes which don't terminate a job (e.g. 429, 5xx)
+func DefaultRetryClassifier(statusCode int, err error) bool {
+	if err != nil {
+		return true
+	}
+	return !isJobTerminated(statusCode)
+}
+
 type Opt func(*handle)
 
 func WithClient(client *http.Client) Opt {
@@ -157,6 +169,13 @@ func WithClient(client *http.Client) Opt {
 	}
 }
 
+// WithRetryClassifier overrides the classification of transformer requests into retriable and permanent failures
+func WithRetryClassifier(classifier RetryClassifier) Opt {
+	return func(s *handle) {
+		s.retryClassifier = classifier
+	}
+}
+
 // Transformer provides methods to transform events
 type Transformer interface {
 	Transform(ctx context.Context, clientEvents []TransformerEvent, batchSize int) Response
@@ -174,7 +193,8 @@ type handle struct {
 	logger logger.Logger
 	stat   stats.Stats
 
-	client *http.Client
+	client          *http.Client
+	retryClassifier RetryClassifier
 
 	guardConcurrency chan struct{}
 
@@ -451,9 +471,10 @@ func (trans *handle) request(ctx context.Context, url, stage string, data []Tran
 
 func (trans *handle) doPost(ctx context.Context, rawJSON []byte, url, stage string, tags stats.Tags) ([]byte, int) {
 	var (
-		retryCount int
-		resp       *http.Response
-		respData   []byte
+		retryCount   int
+		resp         *http.Response
+		respData     []byte
+		permanentErr bool
 	)
 
 	err := backoff.RetryNotify(
@@ -477,12 +498,16 @@ func (trans *handle) doPost(ctx context.Context, rawJSON []byte, url, stage stri
 			})
 			trans.requestTime(tags, time.Since(requestStartTime))
 			if reqErr != nil {
+				if !trans.retriable(0, reqErr) {
+					permanentErr = true
+					return backoff.Permanent(reqErr)
+				}
 				return reqErr
 			}
 
 			defer func() { httputil.CloseResponse(resp) }()
 
-			if !isJobTerminated(resp.StatusCode) && resp.StatusCode != StatusCPDown {
+			if resp.StatusCode != StatusCPDown && trans.retriable(resp.StatusCode, nil) {
 				return fmt.Errorf("transformer returned status code: %v", resp.StatusCode)
 			}
 
@@ -496,6 +521,9 @@ func (trans *handle) doPost(ctx context.Context, rawJSON []byte, url, stage stri
 		},
 	)
 	if err != nil {
+		if permanentErr {
+			return []byte(fmt.Sprintf("transformer request failed: %s", err)), TransformerRequestFailure
+		}
 		if trans.config.failOnUserTransformTimeout.Load() && stage == UserTransformerStage && os.IsTimeout(err) {
 			return []byte(fmt.Sprintf("transformer request timed out: %s", err)), TransformerRequestTimeout
 		} else if trans.config.failOnError.Load() {
@@ -521,6 +549,13 @@ func (trans *handle) doPost(ctx context.Context, rawJSON []byte, url, stage stri
 	return respData, resp.StatusCode
 }
 
+func (trans *handle) retriable(statusCode int, err error) bool {
+	if trans.retryClassifier == nil {
+		return DefaultRetryClassifier(statusCode, err)
+	}
+	return trans.retryClassifier(statusCode, err)
+}
+
 func (trans *handle) destTransformURL(destType string) string {
 	destinationEndPoint := fmt.Sprintf("%s/v0/destinations/%s", trans.config.destTransformationURL, strings.ToLower(destType))
 
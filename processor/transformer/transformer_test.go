@@ -406,6 +406,7 @@ func TestTransformer(t *testing.T) {
 			expectPanic      bool
 			expectedResponse []TransformerResponse
 			failOnError      bool
+			retryClassifier  RetryClassifier
 		}{
 			{
 				name:            "too many requests",
@@ -457,6 +458,41 @@ func TestTransformer(t *testing.T) {
 				},
 				failOnError: false,
 			},
+			{
+				name:            "permanent error with retry classifier",
+				retries:         3,
+				maxRetryCount:   10,
+				statusCode:      http.StatusInternalServerError,
+				statusError:     "malformed event",
+				expectedRetries: 1,
+				expectPanic:     false,
+				expectedResponse: []TransformerResponse{
+					{
+						Metadata: Metadata{
+							MessageID: msgID,
+						},
+						StatusCode: http.StatusInternalServerError,
+						Error:      response.MakeResponse("malformed event") + "\n",
+					},
+				},
+				failOnError: false,
+				retryClassifier: func(statusCode int, err error) bool {
+					return err != nil || statusCode == http.StatusTooManyRequests
+				},
+			},
+			{
+				name:            "transient error with retry classifier",
+				retries:         3,
+				maxRetryCount:   10,
+				statusCode:      http.StatusTooManyRequests,
+				statusError:     "too many requests",
+				expectedRetries: 4,
+				expectPanic:     true,
+				failOnError:     false,
+				retryClassifier: func(statusCode int, err error) bool {
+					return err != nil || statusCode == http.StatusTooManyRequests
+				},
+			},
 		}
 
 		for _, tc := range testCases {
@@ -483,6 +519,7 @@ func TestTransformer(t *testing.T) {
 				tr.config.maxRetry = misc.SingleValueLoader(tc.retries)
 				tr.config.failOnError = misc.SingleValueLoader(tc.failOnError)
 				tr.cpDownGauge = tr.stat.NewStat("control_plane_down", stats.GaugeType)
+				tr.retryClassifier = tc.retryClassifier
 
 				if tc.expectPanic {
 					require.Panics(t, func() {
@@ -83,6 +83,8 @@ type Handle struct {
 	mainLoopFreq                 misc.ValueLoader[time.Duration]
 	disableEgress                bool
 	toAbortDestinationIDs        misc.ValueLoader[string]
+	toAbortJobStates             misc.ValueLoader[string]
+	staleDrainThreshold          misc.ValueLoader[time.Duration]
 	warehouseServiceMaxRetryTime misc.ValueLoader[time.Duration]
 	transformerURL               string
 	datePrefixOverride           misc.ValueLoader[string]
@@ -385,7 +387,10 @@ func (brt *Handle) upload(provider string, batchJobs *BatchedJobs, isWarehouse b
 	if brt.datePrefixOverride.Load() != "" {
 		datePrefixLayout = brt.datePrefixOverride.Load()
 	} else {
-		dateFormat, _ := brt.dateFormatProvider.GetFormat(brt.logger, uploader, batchJobs.Connection, folderName)
+		dateFormat, err := brt.dateFormatProvider.GetFormat(brt.logger, uploader, batchJobs.Connection, folderName)
+		if err != nil {
+			brt.logger.Warnf("BRT: Detecting the date format for destination %s, falling back to %s: %v", batchJobs.Connection.Destination.ID, dateFormat, err)
+		}
 		datePrefixLayout = dateFormat
 	}
 
@@ -108,7 +108,10 @@ func (brt *Handle) Setup(
 	brt.uploadIntervalMap = map[string]time.Duration{}
 	brt.lastExecTimes = map[string]time.Time{}
 	brt.failingDestinations = map[string]bool{}
-	brt.dateFormatProvider = &storageDateFormatProvider{dateFormatsCache: make(map[string]string)}
+	brt.dateFormatProvider = &storageDateFormatProvider{
+		dateFormatsCache: make(map[string]string),
+		dateSegmentIndex: config.GetIntVar(1, 1, "BatchRouter."+brt.destType+".dateFormatSegmentIndex", "BatchRouter.dateFormatSegmentIndex"),
+	}
 	diagnosisTickerTime := config.GetDurationVar(600, time.Second, "Diagnostics.batchRouterTimePeriod", "Diagnostics.batchRouterTimePeriodInS")
 	brt.diagnosisTicker = time.NewTicker(diagnosisTickerTime)
 	brt.uploadedRawDataJobsCache = make(map[string]map[string]bool)
@@ -199,6 +202,8 @@ func (brt *Handle) setupReloadableVars() {
 	brt.uploadFreq = config.GetReloadableDurationVar(30, time.Second, "BatchRouter.uploadFreqInS", "BatchRouter.uploadFreq")
 	brt.mainLoopFreq = config.GetReloadableDurationVar(30, time.Second, "BatchRouter.mainLoopFreq")
 	brt.toAbortDestinationIDs = config.GetReloadableStringVar("", "BatchRouter.toAbortDestinationIDs")
+	brt.toAbortJobStates = config.GetReloadableStringVar("", "BatchRouter.toAbortJobStates")
+	brt.staleDrainThreshold = config.GetReloadableDurationVar(24, time.Hour, "Router.staleDrainThreshold")
 	brt.warehouseServiceMaxRetryTime = config.GetReloadableDurationVar(3, time.Hour, "BatchRouter.warehouseServiceMaxRetryTime", "BatchRouter.warehouseServiceMaxRetryTimeinHr")
 	brt.datePrefixOverride = config.GetReloadableStringVar("", "BatchRouter.datePrefixOverride")
 	brt.customDatePrefix = config.GetReloadableStringVar("", "BatchRouter.customDatePrefix")
@@ -69,6 +69,7 @@ func getBatchRouterConfigInt(key, destType string, defaultValue int) int {
 type storageDateFormatProvider struct {
 	dateFormatsCacheMu sync.RWMutex
 	dateFormatsCache   map[string]string // (sourceId:destinationId) -> dateFormat
+	dateSegmentIndex   int               // index of the date segment in the keys, relative to the prefix
 }
 
 func (sdfp *storageDateFormatProvider) GetFormat(log logger.Logger, manager filemanager.FileManager, destination *Connection, folderName string) (dateFormat string, err error) {
@@ -125,17 +126,34 @@ func (sdfp *storageDateFormatProvider) GetFormat(log logger.Logger, manager file
 		}
 		key := fileObjects[idx].Key
 		replacedKey := strings.Replace(key, fullPrefix, "", 1)
-		splittedKeys := strings.Split(replacedKey, "/")
-		if len(splittedKeys) > 1 {
-			date := splittedKeys[1]
-			for layout, format := range dateFormatLayouts {
-				_, err = time.Parse(layout, date)
-				if err == nil {
-					dateFormat = format
-					return
-				}
-			}
+		if format, ok := sdfp.dateFormatFromKey(replacedKey); ok {
+			dateFormat = format
+			return dateFormat, nil
 		}
 	}
+	// caching the default format, so that the keys aren't listed again on every upload
+	sdfp.dateFormatsCacheMu.Lock()
+	sdfp.dateFormatsCache[connIdentifier] = dateFormat
+	sdfp.dateFormatsCacheMu.Unlock()
+	err = fmt.Errorf("no date segment found at index %d in keys with prefix %s", sdfp.dateSegmentIndex, fullPrefix)
 	return
 }
+
+// dateFormatFromKey returns the date format of the key (relative to the prefix), looking only at the segment at the configured index,
+// so that date-like folder names elsewhere in the key aren't mistaken for the date segment.
+func (sdfp *storageDateFormatProvider) dateFormatFromKey(key string) (string, bool) {
+	splittedKeys := strings.Split(key, "/")
+	if sdfp.dateSegmentIndex <= 0 || sdfp.dateSegmentIndex >= len(splittedKeys) {
+		return "", false
+	}
+	return parseDateFormat(splittedKeys[sdfp.dateSegmentIndex])
+}
+
+func parseDateFormat(date string) (string, bool) {
+	for layout, format := range dateFormatLayouts {
+		if _, err := time.Parse(layout, date); err == nil {
+			return format, true
+		}
+	}
+	return "", false
+}
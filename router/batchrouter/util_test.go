@@ -0,0 +1,130 @@
+package batchrouter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/filemanager"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+)
+
+type listFileManager struct {
+	mockFileManager
+	prefix string
+	keys   []string
+	listed *int
+}
+
+func (m listFileManager) ListFilesWithPrefix(context.Context, string, string, int64) filemanager.ListSession {
+	*m.listed++
+	return m
+}
+
+func (m listFileManager) Next() ([]*filemanager.FileInfo, error) {
+	fileObjects := make([]*filemanager.FileInfo, 0, len(m.keys))
+	for _, key := range m.keys {
+		fileObjects = append(fileObjects, &filemanager.FileInfo{Key: key})
+	}
+	return fileObjects, nil
+}
+
+func (m listFileManager) Prefix() string {
+	return m.prefix
+}
+
+func TestStorageDateFormatProvider(t *testing.T) {
+	connection := &Connection{
+		Source:      backendconfig.SourceT{ID: "source-id"},
+		Destination: backendconfig.DestinationT{ID: "destination-id"},
+	}
+
+	testCases := []struct {
+		name             string
+		prefix           string
+		keys             []string
+		dateSegmentIndex int
+		expected         string
+		wantErr          bool
+	}{
+		{
+			name:             "date right after the source",
+			keys:             []string{"rudder-logs/source-id/01-02-2023/1.json.gz"},
+			dateSegmentIndex: 1,
+			expected:         "MM-DD-YYYY",
+		},
+		{
+			name:             "date right after the source with config prefix",
+			prefix:           "some/prefix/",
+			keys:             []string{"some/prefix/rudder-logs/source-id/2023-01-02/1.json.gz"},
+			dateSegmentIndex: 1,
+			expected:         "YYYY-MM-DD",
+		},
+		{
+			name:             "date deeper in the key",
+			keys:             []string{"rudder-logs/source-id/extra/level/01-02-2023/1.json.gz"},
+			dateSegmentIndex: 1,
+			expected:         "YYYY-MM-DD",
+			wantErr:          true,
+		},
+		{
+			name:             "date-like folder name before the date segment",
+			keys:             []string{"rudder-logs/source-id/2023-01-02/01-02-2023/1.json.gz"},
+			dateSegmentIndex: 2,
+			expected:         "MM-DD-YYYY",
+		},
+		{
+			name:             "configured segment index",
+			keys:             []string{"rudder-logs/source-id/extra/2023-01-02/1.json.gz"},
+			dateSegmentIndex: 2,
+			expected:         "YYYY-MM-DD",
+		},
+		{
+			name:             "first key without date",
+			keys:             []string{"rudder-logs/source-id/no-date/1.json.gz", "rudder-logs/source-id/01-02-2023/1.json.gz"},
+			dateSegmentIndex: 1,
+			expected:         "MM-DD-YYYY",
+		},
+		{
+			name:             "no files",
+			dateSegmentIndex: 1,
+			expected:         "YYYY-MM-DD",
+		},
+		{
+			name:             "no segment parses as a date",
+			keys:             []string{"rudder-logs/source-id/no-date/1.json.gz"},
+			dateSegmentIndex: 1,
+			expected:         "YYYY-MM-DD",
+			wantErr:          true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			sdfp := &storageDateFormatProvider{
+				dateFormatsCache: make(map[string]string),
+				dateSegmentIndex: tc.dateSegmentIndex,
+			}
+			var listed int
+			fm := listFileManager{prefix: tc.prefix, keys: tc.keys, listed: &listed}
+
+			dateFormat, err := sdfp.GetFormat(logger.NOP, fm, connection, "rudder-logs")
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expected, dateFormat)
+			require.Len(t, sdfp.dateFormatsCache, 1)
+
+			dateFormat, err = sdfp.GetFormat(logger.NOP, fm, connection, "rudder-logs")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, dateFormat)
+			require.Equal(t, 1, listed, "the detected format, or the default on failure, should be cached")
+		})
+	}
+}
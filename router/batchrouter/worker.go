@@ -74,8 +74,12 @@ func (w *worker) processJobAsync(jobsWg *sync.WaitGroup, destinationJobs *Destin
 		drainStatsbyDest := make(map[string]*router_utils.DrainStats)
 
 		jobsBySource := make(map[string][]*jobsdb.JobT)
+		drainer := router_utils.NewDrainer(brt.staleDrainThreshold.Load(), stats.Default)
 		for _, job := range destinationJobs.jobs {
-			if drain, reason := router_utils.ToBeDrained(job, destWithSources.Destination.ID, brt.toAbortDestinationIDs.Load(), destinationsMap); drain {
+			if drain, reason := drainer.ToBeDrained(job, destWithSources.Destination.ID, router_utils.DrainConfig{
+				ToAbortDestinationIDs: brt.toAbortDestinationIDs.Load(),
+				ToAbortJobStates:      brt.toAbortJobStates.Load(),
+			}, destinationsMap); drain {
 				status := jobsdb.JobStatusT{
 					JobID:         job.JobID,
 					AttemptNum:    job.LastJobStatus.AttemptNum + 1,
@@ -1,6 +1,8 @@
 package router
 
 import (
+	"bytes"
+	"container/list"
 	"context"
 	"encoding/json"
 	"errors"
@@ -68,6 +70,8 @@ type Handle struct {
 	drainConcurrencyLimit   int
 	workerInputBufferSize   int
 	saveDestinationResponse bool
+	failedEventsCacheSize   int
+	collapseFailedStatuses  bool
 
 	diagnosisTickerTime time.Duration
 
@@ -86,6 +90,10 @@ type Handle struct {
 	backendConfigInitialized       chan bool
 	responseQ                      chan workerJobStatus
 	throttlingCosts                atomic.Pointer[types.EventTypeThrottlingCost]
+	failedEventsListMu             sync.RWMutex
+	failedEventsList               *list.List // most recent failed job statuses, oldest first
+	statusCountsMu                 sync.RWMutex
+	statusCounts                   map[string]*customValStatusCounts // custom_val -> job statuses since the router started
 	batchInputCountStat            stats.Measurement
 	batchOutputCountStat           stats.Measurement
 	routerTransformInputCountStat  stats.Measurement
@@ -115,6 +123,99 @@ type Handle struct {
 	}
 }
 
+// recentFailedStatus is a recent failed job status, standing for Count consecutive failed statuses with the same state and error
+type recentFailedStatus struct {
+	*jobsdb.JobStatusT
+	Count int `json:"Count"`
+}
+
+// sameFailure indicates if the statuses have the same state and error, regardless of their jobs
+func sameFailure(a, b *jobsdb.JobStatusT) bool {
+	return a.JobState == b.JobState && a.ErrorCode == b.ErrorCode && bytes.Equal(a.ErrorResponse, b.ErrorResponse)
+}
+
+// recordFailedStatus keeps the status in the list of recent failed statuses, evicting the oldest ones beyond the configured capacity.
+// If collapsing is enabled, a status with the same failure as the most recent one replaces it and increments its count instead, so that repeated failures don't evict the distinct ones.
+func (rt *Handle) recordFailedStatus(status *jobsdb.JobStatusT) {
+	rt.failedEventsListMu.Lock()
+	defer rt.failedEventsListMu.Unlock()
+	if rt.collapseFailedStatuses {
+		if last := rt.failedEventsList.Back(); last != nil {
+			if lastStatus := last.Value.(*recentFailedStatus); sameFailure(lastStatus.JobStatusT, status) {
+				lastStatus.JobStatusT = status
+				lastStatus.Count++
+				return
+			}
+		}
+	}
+	rt.failedEventsList.PushBack(&recentFailedStatus{JobStatusT: status, Count: 1})
+	for rt.failedEventsList.Len() > rt.failedEventsCacheSize {
+		rt.failedEventsList.Remove(rt.failedEventsList.Front())
+	}
+}
+
+// customValStatusCounts are the number of job statuses of a custom_val, by outcome
+type customValStatusCounts struct {
+	Succeeded int `json:"succeeded"` // filtered jobs included
+	Failed    int `json:"failed"`
+	Aborted   int `json:"aborted"`
+}
+
+// recordStatusCount counts the status of a job of the custom_val, so that the failures of a specific destination definition can be told apart within the router
+func (rt *Handle) recordStatusCount(customVal, jobState string) {
+	rt.statusCountsMu.Lock()
+	defer rt.statusCountsMu.Unlock()
+	if rt.statusCounts == nil {
+		rt.statusCounts = make(map[string]*customValStatusCounts)
+	}
+	counts, ok := rt.statusCounts[customVal]
+	if !ok {
+		counts = &customValStatusCounts{}
+		rt.statusCounts[customVal] = counts
+	}
+	switch jobState {
+	case jobsdb.Succeeded.State, jobsdb.Filtered.State:
+		counts.Succeeded++
+	case jobsdb.Failed.State:
+		counts.Failed++
+	case jobsdb.Aborted.State:
+		counts.Aborted++
+	}
+}
+
+// Status returns the recent failed job statuses of the router along with the job status counts by custom_val, used for debugging by the admin interface
+func (rt *Handle) Status() interface{} {
+	rt.failedEventsListMu.RLock()
+	var failedStatuses interface{}
+	if rt.collapseFailedStatuses {
+		collapsed := make([]recentFailedStatus, 0, rt.failedEventsList.Len())
+		for e := rt.failedEventsList.Front(); e != nil; e = e.Next() {
+			collapsed = append(collapsed, *e.Value.(*recentFailedStatus))
+		}
+		failedStatuses = collapsed
+	} else {
+		statuses := make([]*jobsdb.JobStatusT, 0, rt.failedEventsList.Len())
+		for e := rt.failedEventsList.Front(); e != nil; e = e.Next() {
+			statuses = append(statuses, e.Value.(*recentFailedStatus).JobStatusT)
+		}
+		failedStatuses = statuses
+	}
+	rt.failedEventsListMu.RUnlock()
+
+	rt.statusCountsMu.RLock()
+	statusCounts := make(map[string]customValStatusCounts, len(rt.statusCounts))
+	for customVal, counts := range rt.statusCounts {
+		statusCounts[customVal] = *counts
+	}
+	rt.statusCountsMu.RUnlock()
+
+	return map[string]interface{}{
+		"destType":      rt.destType,
+		"recent-failed": failedStatuses,
+		"custom-vals":   statusCounts,
+	}
+}
+
 // activePartitions returns the list of active partitions, depending on the active isolation strategy
 func (rt *Handle) activePartitions(ctx context.Context) []string {
 	statTags := map[string]string{"destType": rt.destType}
@@ -336,6 +437,10 @@ func (rt *Handle) commitStatusList(workerJobStatuses *[]workerJobStatus) {
 		// REPORTING - ROUTER - END
 
 		statusList = append(statusList, workerJobStatus.status)
+		rt.recordStatusCount(workerJobStatus.job.CustomVal, workerJobStatus.status.JobState)
+		if workerJobStatus.status.JobState == jobsdb.Failed.State {
+			rt.recordFailedStatus(workerJobStatus.status)
+		}
 
 		// tracking router errors
 		if diagnostics.EnableDestinationFailuresMetric {
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"container/list"
 	"context"
 	"fmt"
 	"sync"
@@ -88,6 +89,9 @@ func (rt *Handle) Setup(
 
 	rt.drainConcurrencyLimit = getRouterConfigInt("drainedConcurrencyLimit", destType, 1)
 	rt.barrierConcurrencyLimit = getRouterConfigInt("barrierConcurrencyLimit", destType, 100)
+	rt.failedEventsCacheSize = getRouterConfigInt("failedEventsCacheSize", destType, 10)
+	rt.collapseFailedStatuses = getRouterConfigBool("collapseFailedStatuses", destType, true)
+	rt.failedEventsList = list.New()
 
 	statTags := stats.Tags{"destType": rt.destType}
 	rt.batchInputCountStat = stats.Default.NewTaggedStat("router_batch_num_input_jobs", stats.CountType, statTags)
@@ -266,6 +270,8 @@ func (rt *Handle) setupReloadableVars() {
 	rt.reloadableConfig.minRetryBackoff = config.GetReloadableDurationVar(10, time.Second, "Router.minRetryBackoff", "Router.minRetryBackoffInS")
 	rt.reloadableConfig.maxRetryBackoff = config.GetReloadableDurationVar(300, time.Second, "Router.maxRetryBackoff", "Router.maxRetryBackoffInS")
 	rt.reloadableConfig.toAbortDestinationIDs = config.GetReloadableStringVar("", "Router.toAbortDestinationIDs")
+	rt.reloadableConfig.toAbortJobStates = config.GetReloadableStringVar("", "Router.toAbortJobStates")
+	rt.reloadableConfig.staleDrainThreshold = config.GetReloadableDurationVar(24, time.Hour, "Router.staleDrainThreshold")
 	rt.reloadableConfig.pickupFlushInterval = config.GetReloadableDurationVar(2, time.Second, "Router.pickupFlushInterval")
 	rt.reloadableConfig.failingJobsPenaltySleep = config.GetReloadableDurationVar(2000, time.Millisecond, "Router.failingJobsPenaltySleep")
 	rt.reloadableConfig.failingJobsPenaltyThreshold = config.GetReloadableFloat64Var(0.6, "Router.failingJobsPenaltyThreshold")
@@ -0,0 +1,420 @@
+package manager
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/samber/lo"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/filemanager"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	routerutils "github.com/rudderlabs/rudder-server/router/utils"
+	"github.com/rudderlabs/rudder-server/utils/filemanagerutil"
+)
+
+const (
+	routerKindRouter      = "router"
+	routerKindBatchRouter = "batchrouter"
+)
+
+// RegisteredRouter describes a router started by the lifecycle manager
+type RegisteredRouter struct {
+	Name            string `json:"name"`
+	DestinationType string `json:"destinationType"`
+	Kind            string `json:"kind"` // router or batchrouter
+}
+
+// statusProvider is implemented by the routers exposing their status for debugging
+type statusProvider interface {
+	Status() interface{}
+}
+
+// datasetsProvider is implemented by the jobsdb of the routers, describing its datasets
+type datasetsProvider interface {
+	GetDSStats(ctx context.Context, dsIndex string) (*jobsdb.DSStats, error)
+	GetDSList() string
+	GetDSMetadata(ctx context.Context) ([]jobsdb.DSMetadata, error)
+	GetOrphanedJobStatusCounts(ctx context.Context) ([]jobsdb.OrphanedJobStatusCounts, error)
+	WriteDSFailedJobs(ctx context.Context, dsIndex, customVal string, w io.Writer) (int, error)
+	GetJobTimeline(ctx context.Context, jobID int64) (*jobsdb.JobTimeline, error)
+	GetJobIDStatuses(ctx context.Context, jobIDs []int64) (map[int64]*jobsdb.JobIDStatus, error)
+}
+
+type registeredHandle struct {
+	router RegisteredRouter
+	status statusProvider // optional
+}
+
+// RouterAdmin exposes the routers started by the lifecycle manager over the admin interface
+type RouterAdmin struct {
+	handlesMu sync.RWMutex
+	handles   map[string]registeredHandle
+
+	datasets datasetsProvider // optional
+
+	// fileManager returns the file manager the failed jobs are exported with
+	fileManager func() (filemanager.FileManager, error)
+}
+
+func newRouterAdmin(datasets datasetsProvider) *RouterAdmin {
+	return &RouterAdmin{
+		handles:     make(map[string]registeredHandle),
+		datasets:    datasets,
+		fileManager: backupsFileManager,
+	}
+}
+
+// backupsFileManager returns a file manager for the object storage of the jobsdb backups
+func backupsFileManager() (filemanager.FileManager, error) {
+	provider := config.GetString("JOBS_BACKUP_STORAGE_PROVIDER", "S3")
+	return filemanager.New(&filemanager.Settings{
+		Provider: provider,
+		Config:   filemanagerutil.GetProviderConfigForBackupsFromEnv(context.Background(), config.Default),
+		Conf:     config.Default,
+	})
+}
+
+var errDatasetsNotAvailable = errors.New("datasets are not available")
+
+func (ra *RouterAdmin) registerRouter(destType, kind string, status statusProvider) {
+	ra.handlesMu.Lock()
+	defer ra.handlesMu.Unlock()
+	ra.handles[kind+"/"+destType] = registeredHandle{
+		router: RegisteredRouter{
+			Name:            destType,
+			DestinationType: destType,
+			Kind:            kind,
+		},
+		status: status,
+	}
+}
+
+func (ra *RouterAdmin) reset() {
+	ra.handlesMu.Lock()
+	defer ra.handlesMu.Unlock()
+	ra.handles = make(map[string]registeredHandle)
+}
+
+// Routers returns the registered routers, sorted by kind and destination type
+func (ra *RouterAdmin) Routers() []RegisteredRouter {
+	ra.handlesMu.RLock()
+	defer ra.handlesMu.RUnlock()
+
+	routers := make([]RegisteredRouter, 0, len(ra.handles))
+	for _, h := range ra.handles {
+		routers = append(routers, h.router)
+	}
+	sort.Slice(routers, func(i, j int) bool {
+		if routers[i].Kind != routers[j].Kind {
+			return routers[i].Kind < routers[j].Kind
+		}
+		return routers[i].DestinationType < routers[j].DestinationType
+	})
+	return routers
+}
+
+// ListRouters returns the registered routers as json.
+// It can be called from rudder-cli using getUDSClient().Call("Router.ListRouters", struct{}{}, &reply)
+func (ra *RouterAdmin) ListRouters(_ struct{}, reply *string) error {
+	formattedOutput, err := json.MarshalIndent(ra.Routers(), "", "  ")
+	if err != nil {
+		return err
+	}
+	*reply = string(formattedOutput)
+	return nil
+}
+
+// Status returns the status of the registered routers, keyed by destination type
+func (ra *RouterAdmin) Status() interface{} {
+	ra.handlesMu.RLock()
+	defer ra.handlesMu.RUnlock()
+
+	statuses := make(map[string]interface{})
+	for _, h := range ra.handles {
+		if h.status != nil {
+			statuses[h.router.DestinationType] = h.status.Status()
+		}
+	}
+	return statuses
+}
+
+// RouterStatus returns the status of the registered routers as json.
+// It can be called from rudder-cli using getUDSClient().Call("Router.RouterStatus", struct{}{}, &reply)
+func (ra *RouterAdmin) RouterStatus(_ struct{}, reply *string) error {
+	formattedOutput, err := json.MarshalIndent(ra.Status(), "", "  ")
+	if err != nil {
+		return err
+	}
+	*reply = string(formattedOutput)
+	return nil
+}
+
+// dsStatsReply is the reply of GetDSStats, the statistics which could be collected along with the error of the ones which couldn't
+type dsStatsReply struct {
+	*jobsdb.DSStats
+	Error string `json:",omitempty"`
+}
+
+// GetDSStats returns the statistics of the router dataset with the given index as json.
+// If only some of the statistics can be collected, they are returned along with the error of the others.
+// It can be called from rudder-cli using getUDSClient().Call("Router.GetDSStats", dsIndex, &reply)
+func (ra *RouterAdmin) GetDSStats(dsIndex string, reply *string) error {
+	if ra.datasets == nil {
+		return errDatasetsNotAvailable
+	}
+	stats, err := ra.datasets.GetDSStats(context.Background(), dsIndex)
+	if stats == nil {
+		return err
+	}
+	statsReply := dsStatsReply{DSStats: stats}
+	if err != nil {
+		statsReply.Error = err.Error()
+	}
+	formattedOutput, err := json.MarshalIndent(statsReply, "", "  ")
+	if err != nil {
+		return err
+	}
+	*reply = string(formattedOutput)
+	return nil
+}
+
+// GetDSList returns the job tables of the router datasets, one per line.
+// It can be called from rudder-cli using getUDSClient().Call("Router.GetDSList", "", &reply)
+func (ra *RouterAdmin) GetDSList(_ string, reply *string) error {
+	if ra.datasets == nil {
+		return errDatasetsNotAvailable
+	}
+	*reply = ra.datasets.GetDSList()
+	return nil
+}
+
+// GetDSMetadata returns the metadata of the router datasets, for tooling needing more than GetDSList
+func (ra *RouterAdmin) GetDSMetadata(_ string, reply *[]jobsdb.DSMetadata) error {
+	if ra.datasets == nil {
+		return errDatasetsNotAvailable
+	}
+	metadata, err := ra.datasets.GetDSMetadata(context.Background())
+	if err != nil {
+		return err
+	}
+	*reply = metadata
+	return nil
+}
+
+// GetOrphanedJobStatusCounts returns the number of job statuses without a corresponding job in every router dataset as json.
+// It can be called from rudder-cli using getUDSClient().Call("Router.GetOrphanedJobStatusCounts", "", &reply)
+func (ra *RouterAdmin) GetOrphanedJobStatusCounts(_ string, reply *string) error {
+	if ra.datasets == nil {
+		return errDatasetsNotAvailable
+	}
+	counts, err := ra.datasets.GetOrphanedJobStatusCounts(context.Background())
+	if err != nil {
+		return err
+	}
+	formattedOutput, err := json.MarshalIndent(counts, "", "  ")
+	if err != nil {
+		return err
+	}
+	*reply = string(formattedOutput)
+	return nil
+}
+
+// GetJobTimeline returns all the statuses of the router job with the given id ordered by exec time as json, for tracing how the job progressed through its states.
+// It can be called from rudder-cli using getUDSClient().Call("Router.GetJobTimeline", jobID, &reply)
+func (ra *RouterAdmin) GetJobTimeline(jobID string, reply *string) error {
+	if ra.datasets == nil {
+		return errDatasetsNotAvailable
+	}
+	id, err := strconv.ParseInt(strings.TrimSpace(jobID), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid job id %q: %w", jobID, err)
+	}
+	timeline, err := ra.datasets.GetJobTimeline(context.Background(), id)
+	if err != nil {
+		return err
+	}
+	formattedOutput, err := json.MarshalIndent(timeline, "", "  ")
+	if err != nil {
+		return err
+	}
+	*reply = string(formattedOutput)
+	return nil
+}
+
+// JobIDStatusResult is the current status of a job looked up through GetJobIDStatuses
+type JobIDStatusResult struct {
+	NotFound bool               `json:"notFound,omitempty"` // no dataset contains the job
+	Index    string             `json:"index,omitempty"`    // index of the dataset of the job
+	Status   *jobsdb.JobStatusT `json:"status,omitempty"`   // last status of the job, nil if it has no status yet
+}
+
+// GetJobIDStatuses returns the current statuses of the router jobs with the given ids in a single call, keyed by job id.
+// The jobs not found in any dataset are marked as not found. Up to Router.maxJobIDStatusesCount jobs can be looked up at once.
+// It can be called from rudder-cli using getUDSClient().Call("Router.GetJobIDStatuses", []int64{1, 2}, &reply)
+func (ra *RouterAdmin) GetJobIDStatuses(jobIDs []int64, reply *map[int64]JobIDStatusResult) error {
+	if ra.datasets == nil {
+		return errDatasetsNotAvailable
+	}
+	jobIDs = lo.Uniq(jobIDs)
+	if len(jobIDs) == 0 {
+		return errors.New("no job ids")
+	}
+	if maxCount := config.GetIntVar(100, 1, "Router.maxJobIDStatusesCount"); len(jobIDs) > maxCount {
+		return fmt.Errorf("too many job ids %d, up to %d jobs can be looked up at once", len(jobIDs), maxCount)
+	}
+
+	statuses, err := ra.datasets.GetJobIDStatuses(context.Background(), jobIDs)
+	if err != nil {
+		return err
+	}
+	results := make(map[int64]JobIDStatusResult, len(jobIDs))
+	for _, jobID := range jobIDs {
+		status, ok := statuses[jobID]
+		if !ok {
+			results[jobID] = JobIDStatusResult{NotFound: true}
+			continue
+		}
+		results[jobID] = JobIDStatusResult{Index: status.Index, Status: status.Status}
+	}
+	*reply = results
+	return nil
+}
+
+// ExportDSFailedJobs exports the failed jobs of a router dataset as a gzipped json lines file to the object storage of the jobsdb backups (JOBS_BACKUP_STORAGE_PROVIDER and JOBS_BACKUP_BUCKET), returning its location.
+// The argument is the index of the dataset, optionally followed by the destination type of the jobs to export, e.g. "1:WEBHOOK".
+// It can be called from rudder-cli using getUDSClient().Call("Router.ExportDSFailedJobs", "1:WEBHOOK", &reply)
+func (ra *RouterAdmin) ExportDSFailedJobs(arg string, reply *string) error {
+	if ra.datasets == nil {
+		return errDatasetsNotAvailable
+	}
+	dsIndex, customVal, _ := strings.Cut(arg, ":")
+
+	ctx := context.Background()
+
+	file, err := os.CreateTemp("", "failed-jobs-*.json.gz")
+	if err != nil {
+		return fmt.Errorf("creating export file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	gzWriter := gzip.NewWriter(file)
+	if _, err := ra.datasets.WriteDSFailedJobs(ctx, dsIndex, customVal, gzWriter); err != nil {
+		return fmt.Errorf("writing failed jobs: %w", err)
+	}
+	if err := gzWriter.Close(); err != nil {
+		return fmt.Errorf("closing export file: %w", err)
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("rewinding export file: %w", err)
+	}
+
+	fm, err := ra.fileManager()
+	if err != nil {
+		return fmt.Errorf("creating file manager: %w", err)
+	}
+	uploaded, err := fm.Upload(ctx, file, "failed-jobs-exports", "rt", dsIndex)
+	if err != nil {
+		return fmt.Errorf("uploading export file: %w", err)
+	}
+	*reply = uploaded.Location
+	return nil
+}
+
+// DrainConfigResult is the outcome of setting a drain config through SetDrainJobsConfigs
+type DrainConfigResult struct {
+	ToAbortDestinationIDs string `json:"toAbortDestinationIDs"`
+	Applied               bool   `json:"applied"`
+	Error                 string `json:"error,omitempty"`
+}
+
+// SetDrainJobsConfig drains the jobs of the destinations of the drain config, in both the routers and the batch routers, replacing their previous drain configs.
+// It can be called from rudder-cli using getUDSClient().Call("Router.SetDrainJobsConfig", routerutils.DrainConfig{ToAbortDestinationIDs: "dest1,dest2"}, &reply)
+func (ra *RouterAdmin) SetDrainJobsConfig(dc routerutils.DrainConfig, reply *string) error {
+	if err := dc.Validate(); err != nil {
+		return fmt.Errorf("invalid drain config: %w", err)
+	}
+	routerutils.SetDrainConfigs([]routerutils.DrainConfig{dc})
+	*reply = fmt.Sprintf("draining the jobs of %s", dc.ToAbortDestinationIDs)
+	return nil
+}
+
+// SetDrainJobsConfigs applies multiple drain configs at once, replying with the outcome of every drain config.
+// All the drain configs are validated before applying any, so none of them is applied if any of them is invalid.
+// It can be called from rudder-cli using getUDSClient().Call("Router.SetDrainJobsConfigs", []routerutils.DrainConfig{...}, &reply)
+func (ra *RouterAdmin) SetDrainJobsConfigs(dcs []routerutils.DrainConfig, reply *[]DrainConfigResult) error {
+	results := make([]DrainConfigResult, 0, len(dcs))
+	valid := true
+	for _, dc := range dcs {
+		result := DrainConfigResult{ToAbortDestinationIDs: dc.ToAbortDestinationIDs}
+		if err := dc.Validate(); err != nil {
+			result.Error = err.Error()
+			valid = false
+		}
+		results = append(results, result)
+	}
+
+	if valid {
+		routerutils.SetDrainConfigs(dcs)
+	}
+	for i := range results {
+		if valid {
+			results[i].Applied = true
+		} else if results[i].Error == "" {
+			results[i].Error = "not applied, since other drain configs are invalid"
+		}
+	}
+	*reply = results
+	return nil
+}
+
+// ClearDrainJobsConfig stops draining the jobs of the destinations with a drain config set through SetDrainJobsConfig(s).
+// The argument is a comma separated list of destination IDs, all the drain configs are cleared if it is empty.
+// It can be called from rudder-cli using getUDSClient().Call("Router.ClearDrainJobsConfig", "dest1,dest2", &reply)
+func (ra *RouterAdmin) ClearDrainJobsConfig(destIDs string, reply *string) error {
+	if strings.TrimSpace(destIDs) == "" {
+		routerutils.ClearDrainConfigs()
+		*reply = "cleared all the drain configs"
+		return nil
+	}
+	var toClear []string
+	for _, destID := range strings.Split(destIDs, ",") {
+		if destID = strings.TrimSpace(destID); destID != "" {
+			toClear = append(toClear, destID)
+		}
+	}
+	if len(toClear) == 0 {
+		return fmt.Errorf("no destination IDs in %q", destIDs)
+	}
+	routerutils.ClearDrainConfigs(toClear...)
+	*reply = fmt.Sprintf("cleared the drain configs of %s", strings.Join(toClear, ","))
+	return nil
+}
+
+// GetDrainJobsConfigs returns the drain configs set through SetDrainJobsConfig(s) along with when they were set, keyed by destination ID.
+// Drains active for longer than Router.staleDrainThreshold are reported by the router_stale_drain_jobs metric for every job they drain,
+// the ones active for longer than Router.drainMaxAge (if set) are flushed.
+func (ra *RouterAdmin) GetDrainJobsConfigs(_ string, reply *map[string]routerutils.DrainConfig) error {
+	*reply = routerutils.DrainConfigs()
+	return nil
+}
+
+// GetDrainJobsConfigsHistory returns the last drain configs set through SetDrainJobsConfig(s), the oldest first, along with when they were cleared or replaced.
+// The history is kept in memory and bounded by Router.drainHistorySize, active drain configs having no ClearedAt timestamp.
+// It can be called from rudder-cli using getUDSClient().Call("Router.GetDrainJobsConfigsHistory", "", &reply)
+func (ra *RouterAdmin) GetDrainJobsConfigsHistory(_ string, reply *[]routerutils.DrainHistoryEntry) error {
+	*reply = routerutils.DrainConfigsHistory()
+	return nil
+}
@@ -0,0 +1,379 @@
+package manager
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/filemanager"
+	"github.com/rudderlabs/rudder-go-kit/filemanager/mock_filemanager"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	routerutils "github.com/rudderlabs/rudder-server/router/utils"
+)
+
+type staticStatus string
+
+func (s staticStatus) Status() interface{} {
+	return string(s)
+}
+
+func TestRouterAdmin(t *testing.T) {
+	ra := newRouterAdmin(nil)
+	require.Empty(t, ra.Routers())
+
+	ra.registerRouter("WEBHOOK", routerKindRouter, staticStatus("webhook status"))
+	ra.registerRouter("S3", routerKindBatchRouter, nil)
+	ra.registerRouter("AM", routerKindRouter, nil)
+	ra.registerRouter("AM", routerKindRouter, nil)
+
+	expected := []RegisteredRouter{
+		{Name: "S3", DestinationType: "S3", Kind: routerKindBatchRouter},
+		{Name: "AM", DestinationType: "AM", Kind: routerKindRouter},
+		{Name: "WEBHOOK", DestinationType: "WEBHOOK", Kind: routerKindRouter},
+	}
+	require.Equal(t, expected, ra.Routers())
+
+	var reply string
+	require.NoError(t, ra.ListRouters(struct{}{}, &reply))
+
+	var routers []RegisteredRouter
+	require.NoError(t, json.Unmarshal([]byte(reply), &routers))
+	require.Equal(t, expected, routers)
+
+	require.Equal(t, map[string]interface{}{"WEBHOOK": "webhook status"}, ra.Status())
+
+	ra.reset()
+	require.Empty(t, ra.Routers())
+}
+
+func TestRouterAdminConcurrentAccess(t *testing.T) {
+	ra := newRouterAdmin(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		destType := fmt.Sprintf("DEST_%d", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			ra.registerRouter(destType, routerKindRouter, staticStatus(destType))
+		}()
+		go func() {
+			defer wg.Done()
+			_ = ra.Status()
+			_ = ra.Routers()
+			var reply string
+			_ = ra.RouterStatus(struct{}{}, &reply)
+		}()
+	}
+	wg.Wait()
+
+	require.Len(t, ra.Routers(), 10)
+	require.Len(t, ra.Status(), 10)
+}
+
+type staticDatasets map[string]*jobsdb.DSStats
+
+func (s staticDatasets) GetDSStats(_ context.Context, dsIndex string) (*jobsdb.DSStats, error) {
+	stats, ok := s[dsIndex]
+	if !ok {
+		return nil, errors.New("dataset not found")
+	}
+	return stats, nil
+}
+
+// partialDatasets only collect some of the statistics of their datasets
+type partialDatasets struct {
+	staticDatasets
+	err error
+}
+
+func (p partialDatasets) GetDSStats(ctx context.Context, dsIndex string) (*jobsdb.DSStats, error) {
+	stats, err := p.staticDatasets.GetDSStats(ctx, dsIndex)
+	if err != nil {
+		return nil, err
+	}
+	return stats, p.err
+}
+
+func (s staticDatasets) GetDSList() string {
+	return "rt_jobs_1\nrt_jobs_2\n"
+}
+
+func (s staticDatasets) GetDSMetadata(context.Context) ([]jobsdb.DSMetadata, error) {
+	return []jobsdb.DSMetadata{
+		{Index: "1", JobTable: "rt_jobs_1", JobStatusTable: "rt_job_status_1", MinJobID: 1, MaxJobID: 10, RowCount: 10},
+		{Index: "2", JobTable: "rt_jobs_2", JobStatusTable: "rt_job_status_2"},
+	}, nil
+}
+
+func (s staticDatasets) GetOrphanedJobStatusCounts(context.Context) ([]jobsdb.OrphanedJobStatusCounts, error) {
+	return []jobsdb.OrphanedJobStatusCounts{{Index: "1", Count: 0}, {Index: "2", Count: 3}}, nil
+}
+
+func (s staticDatasets) WriteDSFailedJobs(_ context.Context, dsIndex, customVal string, w io.Writer) (int, error) {
+	if _, ok := s[dsIndex]; !ok {
+		return 0, errors.New("dataset not found")
+	}
+	_, err := io.WriteString(w, `{"JobID":1,"CustomVal":"`+customVal+`"}`+"\n")
+	return 1, err
+}
+
+func (s staticDatasets) GetJobIDStatuses(_ context.Context, jobIDs []int64) (map[int64]*jobsdb.JobIDStatus, error) {
+	statuses := make(map[int64]*jobsdb.JobIDStatus)
+	for _, jobID := range jobIDs {
+		switch jobID {
+		case 1:
+			statuses[jobID] = &jobsdb.JobIDStatus{JobID: jobID, Index: "1", Status: &jobsdb.JobStatusT{JobID: jobID, JobState: jobsdb.Failed.State, AttemptNum: 1, ErrorCode: "500"}}
+		case 2:
+			statuses[jobID] = &jobsdb.JobIDStatus{JobID: jobID, Index: "2"}
+		}
+	}
+	return statuses, nil
+}
+
+func (s staticDatasets) GetJobTimeline(_ context.Context, jobID int64) (*jobsdb.JobTimeline, error) {
+	if jobID != 1 {
+		return nil, errors.New("job not found")
+	}
+	return &jobsdb.JobTimeline{JobID: jobID, Index: "1", Statuses: []*jobsdb.JobStatusT{
+		{JobID: jobID, JobState: jobsdb.Executing.State, AttemptNum: 1},
+		{JobID: jobID, JobState: jobsdb.Failed.State, AttemptNum: 1, ErrorCode: "500"},
+	}}, nil
+}
+
+func TestRouterAdmin_Datasets(t *testing.T) {
+	t.Run("not available", func(t *testing.T) {
+		ra := newRouterAdmin(nil)
+
+		var reply string
+		require.ErrorIs(t, ra.GetDSStats("1", &reply), errDatasetsNotAvailable)
+		require.ErrorIs(t, ra.GetDSList("", &reply), errDatasetsNotAvailable)
+		require.ErrorIs(t, ra.GetOrphanedJobStatusCounts("", &reply), errDatasetsNotAvailable)
+		require.ErrorIs(t, ra.ExportDSFailedJobs("1", &reply), errDatasetsNotAvailable)
+		require.ErrorIs(t, ra.GetJobTimeline("1", &reply), errDatasetsNotAvailable)
+
+		var statuses map[int64]JobIDStatusResult
+		require.ErrorIs(t, ra.GetJobIDStatuses([]int64{1}, &statuses), errDatasetsNotAvailable)
+
+		var metadata []jobsdb.DSMetadata
+		require.ErrorIs(t, ra.GetDSMetadata("", &metadata), errDatasetsNotAvailable)
+	})
+
+	ra := newRouterAdmin(staticDatasets{
+		"1": {
+			JobCountByConnections: []jobsdb.JobCountByConnections{{Count: 2, SourceId: "source-1", DestinationId: "destination-1"}},
+			UnprocessedJobCounts:  1,
+		},
+	})
+
+	t.Run("GetDSStats", func(t *testing.T) {
+		var reply string
+		require.NoError(t, ra.GetDSStats("1", &reply))
+
+		var stats jobsdb.DSStats
+		require.NoError(t, json.Unmarshal([]byte(reply), &stats))
+		require.Equal(t, 1, stats.UnprocessedJobCounts)
+		require.Equal(t, []jobsdb.JobCountByConnections{{Count: 2, SourceId: "source-1", DestinationId: "destination-1"}}, stats.JobCountByConnections)
+
+		require.EqualError(t, ra.GetDSStats("2", &reply), "dataset not found")
+
+		partial := newRouterAdmin(partialDatasets{
+			staticDatasets: staticDatasets{"1": {UnprocessedJobCounts: 1}},
+			err:            errors.New("latest job status counts: timed out after 1m0s: context deadline exceeded"),
+		})
+		require.NoError(t, partial.GetDSStats("1", &reply))
+
+		var partialStats struct {
+			jobsdb.DSStats
+			Error string
+		}
+		require.NoError(t, json.Unmarshal([]byte(reply), &partialStats))
+		require.Equal(t, 1, partialStats.UnprocessedJobCounts)
+		require.Equal(t, "latest job status counts: timed out after 1m0s: context deadline exceeded", partialStats.Error)
+
+		require.EqualError(t, partial.GetDSStats("2", &reply), "dataset not found")
+	})
+
+	t.Run("GetDSList", func(t *testing.T) {
+		var reply string
+		require.NoError(t, ra.GetDSList("", &reply))
+		require.Equal(t, "rt_jobs_1\nrt_jobs_2\n", reply)
+	})
+
+	t.Run("GetDSMetadata", func(t *testing.T) {
+		var metadata []jobsdb.DSMetadata
+		require.NoError(t, ra.GetDSMetadata("", &metadata))
+		require.Len(t, metadata, 2)
+		require.Equal(t, jobsdb.DSMetadata{Index: "1", JobTable: "rt_jobs_1", JobStatusTable: "rt_job_status_1", MinJobID: 1, MaxJobID: 10, RowCount: 10}, metadata[0])
+	})
+
+	t.Run("ExportDSFailedJobs", func(t *testing.T) {
+		mockFileManager := mock_filemanager.NewMockFileManager(gomock.NewController(t))
+		ra.fileManager = func() (filemanager.FileManager, error) {
+			return mockFileManager, nil
+		}
+
+		var exported string
+		mockFileManager.EXPECT().Upload(gomock.Any(), gomock.Any(), "failed-jobs-exports", "rt", "1").DoAndReturn(
+			func(_ context.Context, file *os.File, _ ...string) (filemanager.UploadedFile, error) {
+				gzReader, err := gzip.NewReader(file)
+				require.NoError(t, err)
+				content, err := io.ReadAll(gzReader)
+				require.NoError(t, err)
+				exported = string(content)
+				return filemanager.UploadedFile{Location: "https://bucket/failed-jobs-exports/rt/1/export.json.gz"}, nil
+			},
+		)
+
+		var reply string
+		require.NoError(t, ra.ExportDSFailedJobs("1:WEBHOOK", &reply))
+		require.Equal(t, "https://bucket/failed-jobs-exports/rt/1/export.json.gz", reply)
+		require.Equal(t, `{"JobID":1,"CustomVal":"WEBHOOK"}`+"\n", exported)
+
+		require.ErrorContains(t, ra.ExportDSFailedJobs("2", &reply), "dataset not found")
+	})
+
+	t.Run("GetJobTimeline", func(t *testing.T) {
+		var reply string
+		require.NoError(t, ra.GetJobTimeline(" 1 ", &reply))
+
+		var timeline jobsdb.JobTimeline
+		require.NoError(t, json.Unmarshal([]byte(reply), &timeline))
+		require.EqualValues(t, 1, timeline.JobID)
+		require.Equal(t, "1", timeline.Index)
+		require.Len(t, timeline.Statuses, 2)
+		require.Equal(t, jobsdb.Failed.State, timeline.Statuses[1].JobState)
+		require.Equal(t, "500", timeline.Statuses[1].ErrorCode)
+
+		require.EqualError(t, ra.GetJobTimeline("2", &reply), "job not found")
+		require.ErrorContains(t, ra.GetJobTimeline("job", &reply), `invalid job id "job"`)
+	})
+
+	t.Run("GetJobIDStatuses", func(t *testing.T) {
+		var statuses map[int64]JobIDStatusResult
+		require.NoError(t, ra.GetJobIDStatuses([]int64{1, 2, 3, 1}, &statuses))
+		require.Equal(t, map[int64]JobIDStatusResult{
+			1: {Index: "1", Status: &jobsdb.JobStatusT{JobID: 1, JobState: jobsdb.Failed.State, AttemptNum: 1, ErrorCode: "500"}},
+			2: {Index: "2"},
+			3: {NotFound: true},
+		}, statuses)
+
+		require.EqualError(t, ra.GetJobIDStatuses(nil, &statuses), "no job ids")
+
+		config.Set("Router.maxJobIDStatusesCount", 2)
+		t.Cleanup(config.Reset)
+		require.EqualError(t, ra.GetJobIDStatuses([]int64{1, 2, 3}, &statuses), "too many job ids 3, up to 2 jobs can be looked up at once")
+	})
+
+	t.Run("GetOrphanedJobStatusCounts", func(t *testing.T) {
+		var reply string
+		require.NoError(t, ra.GetOrphanedJobStatusCounts("", &reply))
+
+		var counts []jobsdb.OrphanedJobStatusCounts
+		require.NoError(t, json.Unmarshal([]byte(reply), &counts))
+		require.Equal(t, []jobsdb.OrphanedJobStatusCounts{{Index: "1", Count: 0}, {Index: "2", Count: 3}}, counts)
+	})
+}
+
+func TestRouterAdminDrainJobsConfigs(t *testing.T) {
+	t.Cleanup(func() { routerutils.ClearDrainConfigs() })
+
+	ra := newRouterAdmin(nil)
+
+	t.Run("SetDrainJobsConfig", func(t *testing.T) {
+		var reply string
+		require.NoError(t, ra.SetDrainJobsConfig(routerutils.DrainConfig{ToAbortDestinationIDs: "dest1"}, &reply))
+		require.ErrorContains(t, ra.SetDrainJobsConfig(routerutils.DrainConfig{}, &reply), "invalid drain config")
+
+		var configs map[string]routerutils.DrainConfig
+		require.NoError(t, ra.GetDrainJobsConfigs("", &configs))
+		require.Equal(t, map[string]routerutils.DrainConfig{"dest1": {ToAbortDestinationIDs: "dest1"}}, withoutSetAt(t, configs))
+	})
+
+	t.Run("SetDrainJobsConfigs with an invalid config applies none", func(t *testing.T) {
+		var results []DrainConfigResult
+		require.NoError(t, ra.SetDrainJobsConfigs([]routerutils.DrainConfig{
+			{ToAbortDestinationIDs: "dest2"},
+			{ToAbortDestinationIDs: "dest3", ToAbortJobStates: "aborted"},
+		}, &results))
+		require.Len(t, results, 2)
+		require.Equal(t, DrainConfigResult{ToAbortDestinationIDs: "dest2", Error: "not applied, since other drain configs are invalid"}, results[0])
+		require.False(t, results[1].Applied)
+		require.Contains(t, results[1].Error, `invalid job state "aborted"`)
+
+		var configs map[string]routerutils.DrainConfig
+		require.NoError(t, ra.GetDrainJobsConfigs("", &configs))
+		require.Equal(t, map[string]routerutils.DrainConfig{"dest1": {ToAbortDestinationIDs: "dest1"}}, withoutSetAt(t, configs))
+	})
+
+	t.Run("SetDrainJobsConfigs", func(t *testing.T) {
+		var results []DrainConfigResult
+		require.NoError(t, ra.SetDrainJobsConfigs([]routerutils.DrainConfig{
+			{ToAbortDestinationIDs: "dest2"},
+			{ToAbortDestinationIDs: "dest3", ToAbortJobStates: "failed"},
+		}, &results))
+		require.Equal(t, []DrainConfigResult{
+			{ToAbortDestinationIDs: "dest2", Applied: true},
+			{ToAbortDestinationIDs: "dest3", Applied: true},
+		}, results)
+
+		var configs map[string]routerutils.DrainConfig
+		require.NoError(t, ra.GetDrainJobsConfigs("", &configs))
+		require.Equal(t, map[string]routerutils.DrainConfig{
+			"dest1": {ToAbortDestinationIDs: "dest1"},
+			"dest2": {ToAbortDestinationIDs: "dest2"},
+			"dest3": {ToAbortDestinationIDs: "dest3", ToAbortJobStates: "failed"},
+		}, withoutSetAt(t, configs))
+	})
+
+	t.Run("ClearDrainJobsConfig", func(t *testing.T) {
+		var reply string
+		require.Error(t, ra.ClearDrainJobsConfig(" , ", &reply))
+		require.NoError(t, ra.ClearDrainJobsConfig("dest1, dest2 ", &reply))
+		require.Equal(t, "cleared the drain configs of dest1,dest2", reply)
+
+		var configs map[string]routerutils.DrainConfig
+		require.NoError(t, ra.GetDrainJobsConfigs("", &configs))
+		require.Equal(t, map[string]routerutils.DrainConfig{"dest3": {ToAbortDestinationIDs: "dest3", ToAbortJobStates: "failed"}}, withoutSetAt(t, configs))
+
+		require.NoError(t, ra.ClearDrainJobsConfig("", &reply))
+		require.NoError(t, ra.GetDrainJobsConfigs("", &configs))
+		require.Empty(t, configs)
+	})
+
+	t.Run("GetDrainJobsConfigsHistory", func(t *testing.T) {
+		var history []routerutils.DrainHistoryEntry
+		require.NoError(t, ra.GetDrainJobsConfigsHistory("", &history))
+		require.Len(t, history, 3)
+		for i, destID := range []string{"dest1", "dest2", "dest3"} {
+			require.Equal(t, destID, history[i].ToAbortDestinationIDs)
+			require.False(t, history[i].SetAt.IsZero(), "drain config of %s should have a timestamp", destID)
+			require.False(t, history[i].ClearedAt.IsZero(), "drain config of %s should be cleared", destID)
+		}
+		require.Equal(t, "failed", history[2].ToAbortJobStates)
+	})
+}
+
+// withoutSetAt returns the drain configs without their SetAt timestamps, requiring all of them to have one
+func withoutSetAt(t testing.TB, configs map[string]routerutils.DrainConfig) map[string]routerutils.DrainConfig {
+	t.Helper()
+
+	withoutSetAt := make(map[string]routerutils.DrainConfig, len(configs))
+	for destID, dc := range configs {
+		require.False(t, dc.SetAt.IsZero(), "drain config of %s should have a timestamp", destID)
+		dc.SetAt = time.Time{}
+		withoutSetAt[destID] = dc
+	}
+	return withoutSetAt
+}
@@ -3,14 +3,17 @@ package manager
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/rudderlabs/rudder-go-kit/config"
 	"github.com/rudderlabs/rudder-go-kit/logger"
 	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
 	"github.com/rudderlabs/rudder-server/router"
 	"github.com/rudderlabs/rudder-server/router/batchrouter"
+	routerutils "github.com/rudderlabs/rudder-server/router/utils"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 )
 
@@ -30,6 +33,7 @@ type LifecycleManager struct {
 	backendConfig backendconfig.BackendConfig
 	currentCancel context.CancelFunc
 	waitGroup     *errgroup.Group
+	admin         *RouterAdmin
 }
 
 // Start starts a Router, this is not a blocking call.
@@ -44,6 +48,10 @@ func (r *LifecycleManager) Start() error {
 		r.monitorDestRouters(currentCtx, r.rt, r.brt)
 		return nil
 	})
+	g.Go(func() error {
+		r.flushExpiredDrains(currentCtx)
+		return nil
+	})
 	return nil
 }
 
@@ -62,6 +70,54 @@ func New(rtFactory *router.Factory, brtFactory *batchrouter.Factory,
 		rt:            rtFactory,
 		brt:           brtFactory,
 		backendConfig: backendConfig,
+		admin:         newRouterAdmin(routerDatasets(rtFactory)),
+	}
+}
+
+// routerDatasets returns the datasets of the router jobsdb, if it describes them
+func routerDatasets(rtFactory *router.Factory) datasetsProvider {
+	if rtFactory == nil {
+		return nil
+	}
+	if datasets, ok := rtFactory.RouterDB.(datasetsProvider); ok {
+		return datasets
+	}
+	return nil
+}
+
+// Admin returns the admin of the routers started by the lifecycle manager
+func (r *LifecycleManager) Admin() *RouterAdmin {
+	return r.admin
+}
+
+// configDrainKeys are the config keys draining destinations, which can't be flushed at runtime
+var configDrainKeys = []string{"Router.toAbortDestinationIDs", "BatchRouter.toAbortDestinationIDs"}
+
+// flushExpiredDrains periodically clears the drain configs set at runtime which are active for longer than Router.drainMaxAge,
+// until the context is cancelled. Flushing a drain is logged as a warning, since the jobs of the destination were discarded all along.
+// The destinations drained through configDrainKeys for longer than Router.drainMaxAge are logged as errors instead, since they need to be removed from the config.
+func (r *LifecycleManager) flushExpiredDrains(ctx context.Context) {
+	trackers := make(map[string]*routerutils.ConfigDrainsTracker, len(configDrainKeys))
+	for _, key := range configDrainKeys {
+		trackers[key] = routerutils.NewConfigDrainsTracker()
+	}
+	for {
+		now := time.Now()
+		for _, dc := range routerutils.FlushExpiredDrainConfigs(now) {
+			r.logger.Warnf("Flushed the drain config of destination %s set at %s, since it was active for longer than Router.drainMaxAge: its jobs (states %q, older than %s) are no longer drained",
+				dc.ToAbortDestinationIDs, dc.SetAt.Format(time.RFC3339), dc.ToAbortJobStates, dc.OlderThan,
+			)
+		}
+		for _, key := range configDrainKeys {
+			for _, dc := range trackers[key].Expired(config.GetStringVar("", key), now) {
+				r.logger.Errorf("The jobs of destination %s have been drained through %s since %s, for longer than Router.drainMaxAge: the drain can't be flushed, remove the destination from %s once it is no longer needed",
+					dc.ToAbortDestinationIDs, key, dc.SetAt.Format(time.RFC3339), key,
+				)
+			}
+		}
+		if err := misc.SleepCtx(ctx, config.GetDurationVar(1, time.Minute, "Router.drainSweepInterval")); err != nil {
+			return
+		}
 	}
 }
 
@@ -124,6 +180,7 @@ loop:
 								brt.Start()
 								cleanup = append(cleanup, brt.Shutdown)
 								dstToBatchRouter[destination.DestinationDefinition.Name] = brt
+								r.admin.registerRouter(destination.DestinationDefinition.Name, routerKindBatchRouter, nil)
 							}
 						} else {
 							_, ok := dstToRouter[destination.DestinationDefinition.Name]
@@ -133,6 +190,7 @@ loop:
 								rt.Start()
 								cleanup = append(cleanup, rt.Shutdown)
 								dstToRouter[destination.DestinationDefinition.Name] = rt
+								r.admin.registerRouter(destination.DestinationDefinition.Name, routerKindRouter, rt)
 							}
 						}
 					}
@@ -150,4 +208,5 @@ loop:
 		})
 	}
 	_ = g.Wait()
+	r.admin.reset()
 }
@@ -1,6 +1,7 @@
 package router
 
 import (
+	"container/list"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -2048,3 +2049,84 @@ func TestAllowRouterAbortAlert(t *testing.T) {
 		})
 	}
 }
+
+func TestFailedEventsList(t *testing.T) {
+	rt := &Handle{
+		destType:              "WEBHOOK",
+		failedEventsCacheSize: 3,
+		failedEventsList:      list.New(),
+	}
+	for i := 1; i <= 5; i++ {
+		rt.recordFailedStatus(&jobsdb.JobStatusT{JobID: int64(i), JobState: jobsdb.Failed.State})
+	}
+
+	status := rt.Status().(map[string]interface{})
+	require.Equal(t, "WEBHOOK", status["destType"])
+
+	failedStatuses := status["recent-failed"].([]*jobsdb.JobStatusT)
+	require.Len(t, failedStatuses, 3)
+	for i, failedStatus := range failedStatuses {
+		require.EqualValues(t, i+3, failedStatus.JobID, "oldest statuses should be evicted first")
+	}
+}
+
+func TestCollapsedFailedEventsList(t *testing.T) {
+	rt := &Handle{
+		destType:               "WEBHOOK",
+		failedEventsCacheSize:  3,
+		failedEventsList:       list.New(),
+		collapseFailedStatuses: true,
+	}
+	failed := func(jobID int64, errorResponse string) *jobsdb.JobStatusT {
+		return &jobsdb.JobStatusT{JobID: jobID, JobState: jobsdb.Failed.State, ErrorCode: "500", ErrorResponse: []byte(errorResponse)}
+	}
+	rt.recordFailedStatus(failed(1, `{"error":"a"}`))
+	for i := 2; i <= 100; i++ {
+		rt.recordFailedStatus(failed(int64(i), `{"error":"b"}`))
+	}
+	rt.recordFailedStatus(failed(101, `{"error":"c"}`))
+	rt.recordFailedStatus(failed(102, `{"error":"b"}`))
+	rt.recordFailedStatus(&jobsdb.JobStatusT{JobID: 103, JobState: jobsdb.Aborted.State, ErrorCode: "500", ErrorResponse: []byte(`{"error":"b"}`)})
+
+	status := rt.Status().(map[string]interface{})
+	failedStatuses := status["recent-failed"].([]recentFailedStatus)
+	require.Len(t, failedStatuses, 3, "repeated failures should not evict the distinct ones")
+
+	require.EqualValues(t, 101, failedStatuses[0].JobID)
+	require.Equal(t, 1, failedStatuses[0].Count)
+	require.EqualValues(t, 102, failedStatuses[1].JobID)
+	require.Equal(t, 1, failedStatuses[1].Count)
+	require.EqualValues(t, 103, failedStatuses[2].JobID)
+	require.Equal(t, jobsdb.Aborted.State, failedStatuses[2].JobState)
+
+	rt.recordFailedStatus(&jobsdb.JobStatusT{JobID: 104, JobState: jobsdb.Aborted.State, ErrorCode: "500", ErrorResponse: []byte(`{"error":"b"}`)})
+	failedStatuses = rt.Status().(map[string]interface{})["recent-failed"].([]recentFailedStatus)
+	require.Len(t, failedStatuses, 3)
+	require.EqualValues(t, 104, failedStatuses[2].JobID, "the most recent status of the repeated failures should be kept")
+	require.Equal(t, 2, failedStatuses[2].Count)
+
+	marshalled, err := json.Marshal(failedStatuses[2])
+	require.NoError(t, err)
+	require.Contains(t, string(marshalled), `"JobID":104`)
+	require.Contains(t, string(marshalled), `"Count":2`)
+}
+
+func TestStatusCountsByCustomVal(t *testing.T) {
+	rt := &Handle{
+		destType:         "WEBHOOK",
+		failedEventsList: list.New(),
+	}
+	require.Empty(t, rt.Status().(map[string]interface{})["custom-vals"])
+
+	for _, state := range []string{jobsdb.Succeeded.State, jobsdb.Filtered.State, jobsdb.Failed.State, jobsdb.Aborted.State} {
+		rt.recordStatusCount("WEBHOOK", state)
+	}
+	rt.recordStatusCount("WEBHOOK_CUSTOM", jobsdb.Failed.State)
+	rt.recordStatusCount("WEBHOOK_CUSTOM", jobsdb.Failed.State)
+
+	status := rt.Status().(map[string]interface{})
+	require.Equal(t, map[string]customValStatusCounts{
+		"WEBHOOK":        {Succeeded: 2, Failed: 1, Aborted: 1},
+		"WEBHOOK_CUSTOM": {Failed: 2},
+	}, status["custom-vals"])
+}
@@ -35,6 +35,18 @@ func (jp *JobParameters) ParseReceivedAtTime() time.Time {
 	return receivedAt
 }
 
+// UnknownAge is the age returned by [JobParameters.Age] when the [ReceivedAt] field is empty or invalid
+const UnknownAge time.Duration = -1
+
+// Age returns how old the job is relative to now, based on the [ReceivedAt] field, or [UnknownAge] if parsing fails
+func (jp *JobParameters) Age(now time.Time) time.Duration {
+	receivedAt := jp.ParseReceivedAtTime()
+	if receivedAt.IsZero() {
+		return UnknownAge
+	}
+	return now.Sub(receivedAt)
+}
+
 type workerJobStatus struct {
 	userID string
 	worker *worker
@@ -87,6 +99,8 @@ type reloadableConfig struct {
 	failingJobsPenaltyThreshold             misc.ValueLoader[float64]
 	failingJobsPenaltySleep                 misc.ValueLoader[time.Duration]
 	toAbortDestinationIDs                   misc.ValueLoader[string]
+	toAbortJobStates                        misc.ValueLoader[string]
+	staleDrainThreshold                     misc.ValueLoader[time.Duration]
 	noOfJobsToBatchInAWorker                misc.ValueLoader[int]
 	jobsDBCommandTimeout                    misc.ValueLoader[time.Duration]
 	jobdDBMaxRetries                        misc.ValueLoader[int]
@@ -32,4 +32,26 @@ func TestJobParameters(t *testing.T) {
 			require.True(t, jp.ParseReceivedAtTime().IsZero(), "an invalid ReceivedAt should return a zero value time")
 		})
 	})
+
+	t.Run("Age", func(t *testing.T) {
+		now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+		t.Run("valid string", func(t *testing.T) {
+			jp := router.JobParameters{
+				ReceivedAt: now.Add(-90 * time.Second).Format(misc.RFC3339Milli),
+			}
+			require.Equal(t, 90*time.Second, jp.Age(now))
+		})
+
+		t.Run("empty string", func(t *testing.T) {
+			var jp router.JobParameters
+			require.Equal(t, router.UnknownAge, jp.Age(now), "an empty ReceivedAt should return an unknown age")
+		})
+
+		t.Run("invalid string", func(t *testing.T) {
+			jp := router.JobParameters{
+				ReceivedAt: "invalid",
+			}
+			require.Equal(t, router.UnknownAge, jp.Age(now), "an invalid ReceivedAt should return an unknown age")
+		})
+	})
 }
@@ -1,14 +1,18 @@
 package utils
 
 import (
+	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"golang.org/x/exp/slices"
 
+	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
 
 	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/stats"
 	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
 	"github.com/rudderlabs/rudder-server/jobsdb"
 	"github.com/rudderlabs/rudder-server/utils/misc"
@@ -47,7 +51,304 @@ func getRetentionTimeForDestination(destID string) time.Duration {
 	return config.GetDurationVar(720, time.Hour, "Router."+destID+".jobRetention", "Router.jobRetention")
 }
 
-func ToBeDrained(job *jobsdb.JobT, destID, toAbortDestinationIDs string, destinationsMap map[string]*DestinationWithSources) (bool, string) {
+func getStaleDrainThreshold() time.Duration {
+	return config.GetDurationVar(24, time.Hour, "Router.staleDrainThreshold")
+}
+
+func getDrainMaxAge() time.Duration {
+	return config.GetDurationVar(0, time.Hour, "Router.drainMaxAge")
+}
+
+func getDrainHistorySize() int {
+	return config.GetIntVar(100, 1, "Router.drainHistorySize")
+}
+
+// DrainConfig configures the jobs to be drained, on top of the expired jobs and the jobs of disabled destinations
+type DrainConfig struct {
+	// ToAbortDestinationIDs is a comma separated list of the destinations whose jobs are drained
+	ToAbortDestinationIDs string
+	// ToAbortJobStates is a comma separated list of the states of the jobs of ToAbortDestinationIDs which are drained.
+	// The state of a job is the one of its last status when picked up (not_picked_yet if it has no status). All states are drained if empty.
+	ToAbortJobStates string
+	// OlderThan limits the jobs of ToAbortDestinationIDs which are drained to the ones received longer than OlderThan ago, e.g. 168h for the jobs older than 7 days.
+	// The received at of a job is the one of its parameters, or its creation time if it has none. Jobs of any age are drained if zero.
+	OlderThan time.Duration
+	// SetAt is when the drain config was set through the admin interface, it is ignored when setting it
+	SetAt time.Time
+}
+
+// drainableJobStates are the states of the jobs which can be drained, i.e. the states of the jobs picked up by the routers
+var drainableJobStates = []string{
+	jobsdb.Unprocessed.State,
+	jobsdb.Failed.State,
+	jobsdb.Executing.State,
+	jobsdb.Waiting.State,
+}
+
+// Validate returns an error if the drain config doesn't configure any destination, or configures job states which can't be drained
+func (dc DrainConfig) Validate() error {
+	if strings.TrimSpace(dc.ToAbortDestinationIDs) == "" {
+		return fmt.Errorf("no destinations to abort")
+	}
+	for _, destID := range strings.Split(dc.ToAbortDestinationIDs, ",") {
+		if strings.TrimSpace(destID) == "" {
+			return fmt.Errorf("empty destination id in %q", dc.ToAbortDestinationIDs)
+		}
+	}
+	if dc.OlderThan < 0 {
+		return fmt.Errorf("invalid age %s, it needs to be positive", dc.OlderThan)
+	}
+	if dc.ToAbortJobStates == "" {
+		return nil
+	}
+	for _, state := range strings.Split(dc.ToAbortJobStates, ",") {
+		if !slices.Contains(drainableJobStates, state) {
+			return fmt.Errorf("invalid job state %q, valid states are %s", state, strings.Join(drainableJobStates, ","))
+		}
+	}
+	return nil
+}
+
+// DrainHistoryEntry is a drain config of a destination set at runtime, along with when it was cleared (or replaced by another drain config)
+type DrainHistoryEntry struct {
+	DrainConfig
+	// ClearedAt is when the drain config was cleared or replaced, it is zero while the drain config is active
+	ClearedAt time.Time
+}
+
+// drainConfigs are the drain configs set at runtime through the admin interface, keyed by destination ID.
+// They apply on top of the drain config of the routers (e.g. Router.toAbortDestinationIDs).
+// The history keeps the last Router.drainHistorySize drain configs set, the oldest first.
+var drainConfigs = struct {
+	mu            sync.RWMutex
+	byDestination map[string]DrainConfig
+	history       []DrainHistoryEntry
+}{byDestination: make(map[string]DrainConfig)}
+
+// recordDrainConfig appends the drain config to the history, dropping the oldest entries beyond the size of the history.
+// It needs to be called with the drain configs lock held.
+func recordDrainConfig(dc DrainConfig) {
+	drainConfigs.history = append(drainConfigs.history, DrainHistoryEntry{DrainConfig: dc})
+	if excess := len(drainConfigs.history) - getDrainHistorySize(); excess > 0 {
+		drainConfigs.history = slices.Delete(drainConfigs.history, 0, excess)
+	}
+}
+
+// recordDrainConfigCleared marks the active drain config of the destination in the history as cleared.
+// It needs to be called with the drain configs lock held.
+func recordDrainConfigCleared(destID string, clearedAt time.Time) {
+	for i := len(drainConfigs.history) - 1; i >= 0; i-- {
+		entry := &drainConfigs.history[i]
+		if entry.ToAbortDestinationIDs == destID && entry.ClearedAt.IsZero() {
+			entry.ClearedAt = clearedAt
+			return
+		}
+	}
+}
+
+// SetDrainConfigs applies the drain configs at once, replacing the drain configs of their destinations.
+// The drain configs are expected to be valid.
+func SetDrainConfigs(dcs []DrainConfig) {
+	SetDrainConfigsAt(dcs, time.Now())
+}
+
+// SetDrainConfigsAt is like SetDrainConfigs, the drain configs being set at setAt instead of now
+func SetDrainConfigsAt(dcs []DrainConfig, setAt time.Time) {
+	drainConfigs.mu.Lock()
+	defer drainConfigs.mu.Unlock()
+	for _, dc := range dcs {
+		for _, destID := range strings.Split(dc.ToAbortDestinationIDs, ",") {
+			destID = strings.TrimSpace(destID)
+			recordDrainConfigCleared(destID, setAt)
+			drainConfigs.byDestination[destID] = DrainConfig{
+				ToAbortDestinationIDs: destID,
+				ToAbortJobStates:      dc.ToAbortJobStates,
+				OlderThan:             dc.OlderThan,
+				SetAt:                 setAt,
+			}
+			recordDrainConfig(drainConfigs.byDestination[destID])
+		}
+	}
+}
+
+// ClearDrainConfigs removes the drain configs of the destinations set at runtime, or all of them if no destination is given
+func ClearDrainConfigs(destIDs ...string) {
+	drainConfigs.mu.Lock()
+	defer drainConfigs.mu.Unlock()
+	clearedAt := time.Now()
+	if len(destIDs) == 0 {
+		for destID := range drainConfigs.byDestination {
+			recordDrainConfigCleared(destID, clearedAt)
+		}
+		drainConfigs.byDestination = make(map[string]DrainConfig)
+		return
+	}
+	for _, destID := range destIDs {
+		if _, ok := drainConfigs.byDestination[destID]; ok {
+			recordDrainConfigCleared(destID, clearedAt)
+		}
+		delete(drainConfigs.byDestination, destID)
+	}
+}
+
+// FlushExpiredDrainConfigs clears the drain configs set at runtime longer than Router.drainMaxAge ago, returning the cleared drain configs sorted by destination ID.
+// It is a safety net against forgotten drains discarding the traffic of their destinations indefinitely, drain configs never expire if Router.drainMaxAge isn't positive.
+func FlushExpiredDrainConfigs(now time.Time) []DrainConfig {
+	maxAge := getDrainMaxAge()
+	if maxAge <= 0 {
+		return nil
+	}
+
+	drainConfigs.mu.Lock()
+	defer drainConfigs.mu.Unlock()
+	var flushed []DrainConfig
+	for destID, dc := range drainConfigs.byDestination {
+		if now.Sub(dc.SetAt) <= maxAge {
+			continue
+		}
+		recordDrainConfigCleared(destID, now)
+		delete(drainConfigs.byDestination, destID)
+		flushed = append(flushed, dc)
+	}
+	slices.SortFunc(flushed, func(a, b DrainConfig) int {
+		return strings.Compare(a.ToAbortDestinationIDs, b.ToAbortDestinationIDs)
+	})
+	return flushed
+}
+
+// ConfigDrainsTracker tracks since when destinations are drained through the config of the routers (e.g. Router.toAbortDestinationIDs).
+// Unlike the drain configs set at runtime, these can't be flushed, so the ones active for longer than Router.drainMaxAge are only reported.
+// Since the config doesn't tell when a destination was added, a destination is tracked from the first time it is seen, i.e. since the last restart at best.
+type ConfigDrainsTracker struct {
+	since      map[string]time.Time
+	reportedAt map[string]time.Time
+}
+
+func NewConfigDrainsTracker() *ConfigDrainsTracker {
+	return &ConfigDrainsTracker{
+		since:      make(map[string]time.Time),
+		reportedAt: make(map[string]time.Time),
+	}
+}
+
+// Expired tracks the destinations of the comma separated toAbortDestinationIDs as of now, returning the ones drained for longer than Router.drainMaxAge sorted by destination ID,
+// their SetAt being when they were first seen. A destination is returned at most once every Router.drainMaxAge, nothing is returned if Router.drainMaxAge isn't positive.
+func (t *ConfigDrainsTracker) Expired(toAbortDestinationIDs string, now time.Time) []DrainConfig {
+	drained := make(map[string]struct{})
+	for _, destID := range strings.Split(toAbortDestinationIDs, ",") {
+		if destID = strings.TrimSpace(destID); destID != "" {
+			drained[destID] = struct{}{}
+		}
+	}
+	for destID := range t.since {
+		if _, ok := drained[destID]; !ok {
+			delete(t.since, destID)
+			delete(t.reportedAt, destID)
+		}
+	}
+	for destID := range drained {
+		if _, ok := t.since[destID]; !ok {
+			t.since[destID] = now
+		}
+	}
+
+	maxAge := getDrainMaxAge()
+	if maxAge <= 0 {
+		return nil
+	}
+	var expired []DrainConfig
+	for destID, since := range t.since {
+		if now.Sub(since) <= maxAge {
+			continue
+		}
+		if reportedAt, ok := t.reportedAt[destID]; ok && now.Sub(reportedAt) < maxAge {
+			continue
+		}
+		t.reportedAt[destID] = now
+		expired = append(expired, DrainConfig{ToAbortDestinationIDs: destID, SetAt: since})
+	}
+	slices.SortFunc(expired, func(a, b DrainConfig) int {
+		return strings.Compare(a.ToAbortDestinationIDs, b.ToAbortDestinationIDs)
+	})
+	return expired
+}
+
+// DrainConfigsHistory returns the last drain configs set at runtime, the oldest first, along with when they were cleared
+func DrainConfigsHistory() []DrainHistoryEntry {
+	drainConfigs.mu.RLock()
+	defer drainConfigs.mu.RUnlock()
+	return slices.Clone(drainConfigs.history)
+}
+
+// DrainConfigs returns the drain configs set at runtime, keyed by destination ID
+func DrainConfigs() map[string]DrainConfig {
+	drainConfigs.mu.RLock()
+	defer drainConfigs.mu.RUnlock()
+	dcs := make(map[string]DrainConfig, len(drainConfigs.byDestination))
+	for destID, dc := range drainConfigs.byDestination {
+		dcs[destID] = dc
+	}
+	return dcs
+}
+
+func drainConfigFor(destID string) (DrainConfig, bool) {
+	drainConfigs.mu.RLock()
+	defer drainConfigs.mu.RUnlock()
+	dc, ok := drainConfigs.byDestination[destID]
+	return dc, ok
+}
+
+// drainsJobState returns true if jobs in the state are to be drained
+func (dc DrainConfig) drainsJobState(state string) bool {
+	if dc.ToAbortJobStates == "" {
+		return true
+	}
+	if state == "" {
+		state = jobsdb.Unprocessed.State
+	}
+	return slices.Contains(strings.Split(dc.ToAbortJobStates, ","), state)
+}
+
+// drainsJobAge returns true if the job is old enough to be drained
+func (dc DrainConfig) drainsJobAge(job *jobsdb.JobT) bool {
+	if dc.OlderThan <= 0 {
+		return true
+	}
+	return time.Since(jobReceivedAt(job)) > dc.OlderThan
+}
+
+// drainsJob returns true if the job of a destination configured to abort is to be drained, given its state and its age
+func (dc DrainConfig) drainsJob(job *jobsdb.JobT) bool {
+	return dc.drainsJobState(job.LastJobStatus.JobState) && dc.drainsJobAge(job)
+}
+
+// jobReceivedAt returns the received at of the parameters of the job, parsed the same way as the router job parameters do, or the creation time of the job if it has none
+func jobReceivedAt(job *jobsdb.JobT) time.Time {
+	receivedAt, err := time.Parse(misc.RFC3339Milli, gjson.GetBytes(job.Parameters, "received_at").String())
+	if err != nil {
+		return job.CreatedAt
+	}
+	return receivedAt
+}
+
+// Drainer decides whether the jobs are to be drained, reporting the ones drained by stale drain configs through the router_stale_drain_jobs metric.
+// Its settings are provided when it is created, so that they are read once for a whole batch of jobs.
+type Drainer struct {
+	staleDrainThreshold time.Duration
+	stats               stats.Stats
+}
+
+// NewDrainer creates a drainer reporting the drain configs set at runtime longer than staleDrainThreshold ago as stale
+func NewDrainer(staleDrainThreshold time.Duration, stat stats.Stats) *Drainer {
+	return &Drainer{staleDrainThreshold: staleDrainThreshold, stats: stat}
+}
+
+// ToBeDrained is the same as Drainer.ToBeDrained, reading Router.staleDrainThreshold on every call
+func ToBeDrained(job *jobsdb.JobT, destID string, drainConfig DrainConfig, destinationsMap map[string]*DestinationWithSources) (bool, string) {
+	return NewDrainer(getStaleDrainThreshold(), stats.Default).ToBeDrained(job, destID, drainConfig, destinationsMap)
+}
+
+func (dr *Drainer) ToBeDrained(job *jobsdb.JobT, destID string, drainConfig DrainConfig, destinationsMap map[string]*DestinationWithSources) (bool, string) {
 	// drain if job is older than the destination's retention time
 	createdAt := job.CreatedAt
 	if time.Since(createdAt) > getRetentionTimeForDestination(destID) {
@@ -58,13 +359,21 @@ func ToBeDrained(job *jobsdb.JobT, destID, toAbortDestinationIDs string, destina
 		return true, "destination is disabled"
 	}
 
-	if toAbortDestinationIDs != "" {
-		abortIDs := strings.Split(toAbortDestinationIDs, ",")
-		if slices.Contains(abortIDs, destID) {
+	if drainConfig.ToAbortDestinationIDs != "" {
+		abortIDs := strings.Split(drainConfig.ToAbortDestinationIDs, ",")
+		if slices.Contains(abortIDs, destID) && drainConfig.drainsJob(job) {
 			return true, "destination configured to abort"
 		}
 	}
 
+	if dc, ok := drainConfigFor(destID); ok && dc.drainsJob(job) {
+		// drain configs set at runtime are easily forgotten, discarding the traffic of the destination long after the outage
+		if time.Since(dc.SetAt) > dr.staleDrainThreshold {
+			dr.stats.NewTaggedStat("router_stale_drain_jobs", stats.CountType, stats.Tags{"destId": destID}).Increment()
+		}
+		return true, "destination configured to abort"
+	}
+
 	return false, ""
 }
 
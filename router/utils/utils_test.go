@@ -0,0 +1,362 @@
+package utils_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-go-kit/stats/memstats"
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/jobsdb"
+	"github.com/rudderlabs/rudder-server/router/utils"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+)
+
+func TestToBeDrained(t *testing.T) {
+	destinationsMap := map[string]*utils.DestinationWithSources{
+		"enabled":  {Destination: backendconfig.DestinationT{ID: "enabled", Enabled: true}},
+		"disabled": {Destination: backendconfig.DestinationT{ID: "disabled", Enabled: false}},
+	}
+	jobInState := func(state string) *jobsdb.JobT {
+		return &jobsdb.JobT{
+			CreatedAt:     time.Now(),
+			LastJobStatus: jobsdb.JobStatusT{JobState: state},
+		}
+	}
+	// jobs are created now, whereas they were received at the given time
+	receivedAt := func(t time.Time) *jobsdb.JobT {
+		return &jobsdb.JobT{
+			CreatedAt:     time.Now(),
+			Parameters:    []byte(fmt.Sprintf(`{"received_at":%q}`, t.Format(misc.RFC3339Milli))),
+			LastJobStatus: jobsdb.JobStatusT{JobState: jobsdb.Failed.State},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		job         *jobsdb.JobT
+		destID      string
+		drainConfig utils.DrainConfig
+		drained     bool
+		reason      string
+	}{
+		{
+			name:    "not drained",
+			job:     jobInState(jobsdb.Failed.State),
+			destID:  "enabled",
+			drained: false,
+		},
+		{
+			name:    "expired job",
+			job:     &jobsdb.JobT{CreatedAt: time.Now().Add(-721 * time.Hour)},
+			destID:  "enabled",
+			drained: true,
+			reason:  "job expired",
+		},
+		{
+			name:        "disabled destination regardless of the states",
+			job:         jobInState(jobsdb.Failed.State),
+			destID:      "disabled",
+			drainConfig: utils.DrainConfig{ToAbortJobStates: jobsdb.Waiting.State},
+			drained:     true,
+			reason:      "destination is disabled",
+		},
+		{
+			name:        "destination configured to abort in any state",
+			job:         jobInState(jobsdb.Executing.State),
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "other,enabled"},
+			drained:     true,
+			reason:      "destination configured to abort",
+		},
+		{
+			name:        "destination configured to abort in matching state",
+			job:         jobInState(jobsdb.Failed.State),
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "enabled", ToAbortJobStates: "waiting,failed"},
+			drained:     true,
+			reason:      "destination configured to abort",
+		},
+		{
+			name:        "destination configured to abort in other state",
+			job:         jobInState(jobsdb.Executing.State),
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "enabled", ToAbortJobStates: "waiting,failed"},
+			drained:     false,
+		},
+		{
+			name:        "unprocessed job configured to abort",
+			job:         jobInState(""),
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "enabled", ToAbortJobStates: jobsdb.Unprocessed.State},
+			drained:     true,
+			reason:      "destination configured to abort",
+		},
+		{
+			name:        "job received before the age configured to abort",
+			job:         receivedAt(time.Now().Add(-8 * 24 * time.Hour)),
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "enabled", OlderThan: 7 * 24 * time.Hour},
+			drained:     true,
+			reason:      "destination configured to abort",
+		},
+		{
+			name:        "job received after the age configured to abort",
+			job:         receivedAt(time.Now().Add(-6 * 24 * time.Hour)),
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "enabled", OlderThan: 7 * 24 * time.Hour},
+			drained:     false,
+		},
+		{
+			name:        "job without received at created before the age configured to abort",
+			job:         &jobsdb.JobT{CreatedAt: time.Now().Add(-2 * time.Hour), Parameters: []byte(`{"received_at":"invalid"}`)},
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "enabled", OlderThan: time.Hour},
+			drained:     true,
+			reason:      "destination configured to abort",
+		},
+		{
+			name:        "old job in other state than the ones configured to abort",
+			job:         receivedAt(time.Now().Add(-8 * 24 * time.Hour)),
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "enabled", ToAbortJobStates: jobsdb.Waiting.State, OlderThan: 7 * 24 * time.Hour},
+			drained:     false,
+		},
+		{
+			name:        "unprocessed job not configured to abort",
+			job:         jobInState(""),
+			destID:      "enabled",
+			drainConfig: utils.DrainConfig{ToAbortDestinationIDs: "enabled", ToAbortJobStates: jobsdb.Failed.State},
+			drained:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			drained, reason := utils.ToBeDrained(tc.job, tc.destID, tc.drainConfig, destinationsMap)
+			require.Equal(t, tc.drained, drained)
+			require.Equal(t, tc.reason, reason)
+		})
+	}
+}
+
+func TestDrainConfigValidate(t *testing.T) {
+	require.NoError(t, utils.DrainConfig{ToAbortDestinationIDs: "dest1,dest2"}.Validate())
+	require.NoError(t, utils.DrainConfig{ToAbortDestinationIDs: "dest1", ToAbortJobStates: "not_picked_yet,failed,executing,waiting"}.Validate())
+
+	require.ErrorContains(t, utils.DrainConfig{}.Validate(), "no destinations to abort")
+	require.ErrorContains(t, utils.DrainConfig{ToAbortDestinationIDs: "dest1,,dest2"}.Validate(), "empty destination id")
+	require.ErrorContains(t, utils.DrainConfig{ToAbortDestinationIDs: "dest1", ToAbortJobStates: "succeeded"}.Validate(), `invalid job state "succeeded"`)
+	require.NoError(t, utils.DrainConfig{ToAbortDestinationIDs: "dest1", OlderThan: time.Hour}.Validate())
+	require.ErrorContains(t, utils.DrainConfig{ToAbortDestinationIDs: "dest1", OlderThan: -time.Hour}.Validate(), "invalid age -1h0m0s")
+}
+
+func TestToBeDrainedWithDrainConfigs(t *testing.T) {
+	t.Cleanup(func() { utils.ClearDrainConfigs() })
+
+	destinationsMap := map[string]*utils.DestinationWithSources{
+		"dest1": {Destination: backendconfig.DestinationT{ID: "dest1", Enabled: true}},
+		"dest2": {Destination: backendconfig.DestinationT{ID: "dest2", Enabled: true}},
+		"dest3": {Destination: backendconfig.DestinationT{ID: "dest3", Enabled: true}},
+	}
+	failedJob := &jobsdb.JobT{CreatedAt: time.Now(), LastJobStatus: jobsdb.JobStatusT{JobState: jobsdb.Failed.State}}
+
+	before := time.Now()
+	utils.SetDrainConfigs([]utils.DrainConfig{
+		{ToAbortDestinationIDs: "dest1,dest2"},
+		{ToAbortDestinationIDs: "dest3", ToAbortJobStates: jobsdb.Waiting.State, SetAt: before.Add(-time.Hour)},
+		{ToAbortDestinationIDs: "dest4", OlderThan: time.Hour},
+	})
+	drainConfigs := utils.DrainConfigs()
+	for destID, dc := range drainConfigs {
+		require.False(t, dc.SetAt.Before(before), "drain config of %s should be set now", destID)
+		require.False(t, dc.SetAt.After(time.Now()), "drain config of %s should be set now", destID)
+		dc.SetAt = time.Time{}
+		drainConfigs[destID] = dc
+	}
+	require.Equal(t, map[string]utils.DrainConfig{
+		"dest1": {ToAbortDestinationIDs: "dest1"},
+		"dest2": {ToAbortDestinationIDs: "dest2"},
+		"dest3": {ToAbortDestinationIDs: "dest3", ToAbortJobStates: jobsdb.Waiting.State},
+		"dest4": {ToAbortDestinationIDs: "dest4", OlderThan: time.Hour},
+	}, drainConfigs)
+
+	for destID, expected := range map[string]bool{"dest1": true, "dest2": true, "dest3": false, "dest4": false} {
+		drained, _ := utils.ToBeDrained(failedJob, destID, utils.DrainConfig{}, destinationsMap)
+		require.Equal(t, expected, drained, destID)
+	}
+
+	utils.ClearDrainConfigs("dest1")
+	drained, _ := utils.ToBeDrained(failedJob, "dest1", utils.DrainConfig{}, destinationsMap)
+	require.False(t, drained)
+	drained, _ = utils.ToBeDrained(failedJob, "dest2", utils.DrainConfig{}, destinationsMap)
+	require.True(t, drained)
+
+	utils.ClearDrainConfigs()
+	require.Empty(t, utils.DrainConfigs())
+}
+
+func TestDrainConfigsHistory(t *testing.T) {
+	t.Cleanup(func() { utils.ClearDrainConfigs() })
+	config.Set("Router.drainHistorySize", 3)
+	t.Cleanup(func() { config.Reset() })
+
+	utils.SetDrainConfigs([]utils.DrainConfig{{ToAbortDestinationIDs: "dest1"}})
+	utils.SetDrainConfigs([]utils.DrainConfig{{ToAbortDestinationIDs: "dest2,dest3", ToAbortJobStates: jobsdb.Failed.State}})
+	utils.SetDrainConfigs([]utils.DrainConfig{{ToAbortDestinationIDs: "dest2", OlderThan: time.Hour}})
+	utils.ClearDrainConfigs("dest3", "unknown")
+
+	history := utils.DrainConfigsHistory()
+	require.Len(t, history, 3, "the oldest drain configs should be dropped")
+	for _, entry := range history {
+		require.False(t, entry.SetAt.IsZero())
+	}
+
+	require.Equal(t, "dest2", history[0].ToAbortDestinationIDs)
+	require.Equal(t, jobsdb.Failed.State, history[0].ToAbortJobStates)
+	require.Equal(t, history[2].SetAt, history[0].ClearedAt, "replaced drain configs should be cleared when replaced")
+
+	require.Equal(t, "dest3", history[1].ToAbortDestinationIDs)
+	require.False(t, history[1].ClearedAt.IsZero())
+
+	require.Equal(t, utils.DrainConfig{ToAbortDestinationIDs: "dest2", OlderThan: time.Hour, SetAt: history[2].SetAt}, history[2].DrainConfig)
+	require.True(t, history[2].ClearedAt.IsZero(), "active drain configs should not be cleared")
+
+	utils.ClearDrainConfigs()
+	history = utils.DrainConfigsHistory()
+	require.False(t, history[2].ClearedAt.IsZero())
+}
+
+func TestToBeDrainedWithStaleDrainConfig(t *testing.T) {
+	t.Cleanup(func() { utils.ClearDrainConfigs() })
+
+	setAt := time.Now().Add(-time.Hour)
+	utils.SetDrainConfigsAt([]utils.DrainConfig{{ToAbortDestinationIDs: "dest1"}}, setAt)
+
+	staleDrainJobs := func(statsStore *memstats.Store) float64 {
+		m := statsStore.Get("router_stale_drain_jobs", stats.Tags{"destId": "dest1"})
+		if m == nil {
+			return 0
+		}
+		return m.LastValue()
+	}
+
+	t.Run("not stale", func(t *testing.T) {
+		statsStore := memstats.New()
+		drainer := utils.NewDrainer(2*time.Hour, statsStore)
+
+		drained, reason := drainer.ToBeDrained(&jobsdb.JobT{CreatedAt: time.Now()}, "dest1", utils.DrainConfig{}, nil)
+		require.True(t, drained)
+		require.Equal(t, "destination configured to abort", reason)
+		require.Zero(t, staleDrainJobs(statsStore))
+	})
+
+	t.Run("stale", func(t *testing.T) {
+		statsStore := memstats.New()
+		drainer := utils.NewDrainer(time.Minute, statsStore)
+
+		// stale drain configs keep draining the jobs, they are only reported
+		drained, reason := drainer.ToBeDrained(&jobsdb.JobT{CreatedAt: time.Now()}, "dest1", utils.DrainConfig{}, nil)
+		require.True(t, drained)
+		require.Equal(t, "destination configured to abort", reason)
+		require.EqualValues(t, 1, staleDrainJobs(statsStore))
+
+		drained, _ = drainer.ToBeDrained(&jobsdb.JobT{CreatedAt: time.Now()}, "dest2", utils.DrainConfig{}, nil)
+		require.False(t, drained)
+		require.Nil(t, statsStore.Get("router_stale_drain_jobs", stats.Tags{"destId": "dest2"}), "jobs not drained should not be reported")
+	})
+}
+
+func TestFlushExpiredDrainConfigs(t *testing.T) {
+	t.Cleanup(func() { utils.ClearDrainConfigs() })
+	t.Cleanup(func() { config.Reset() })
+
+	utils.SetDrainConfigs([]utils.DrainConfig{{ToAbortDestinationIDs: "dest2,dest1"}})
+	setAt := utils.DrainConfigs()["dest1"].SetAt
+
+	t.Run("disabled by default", func(t *testing.T) {
+		require.Empty(t, utils.FlushExpiredDrainConfigs(setAt.Add(24*365*time.Hour)))
+		require.Len(t, utils.DrainConfigs(), 2)
+	})
+
+	config.Set("Router.drainMaxAge", "1h")
+
+	t.Run("not expired", func(t *testing.T) {
+		require.Empty(t, utils.FlushExpiredDrainConfigs(setAt.Add(time.Hour)))
+		require.Len(t, utils.DrainConfigs(), 2)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		dest3SetAt := setAt.Add(time.Minute)
+		utils.SetDrainConfigsAt([]utils.DrainConfig{{ToAbortDestinationIDs: "dest3"}}, dest3SetAt)
+		now := dest3SetAt.Add(time.Hour)
+
+		flushed := utils.FlushExpiredDrainConfigs(now)
+		require.Equal(t, []utils.DrainConfig{
+			{ToAbortDestinationIDs: "dest1", SetAt: setAt},
+			{ToAbortDestinationIDs: "dest2", SetAt: setAt},
+		}, flushed)
+		require.Equal(t, map[string]utils.DrainConfig{"dest3": {ToAbortDestinationIDs: "dest3", SetAt: dest3SetAt}}, utils.DrainConfigs())
+
+		drained, _ := utils.ToBeDrained(&jobsdb.JobT{CreatedAt: time.Now()}, "dest1", utils.DrainConfig{}, nil)
+		require.False(t, drained, "flushed drain configs should no longer drain the jobs")
+
+		for _, entry := range utils.DrainConfigsHistory() {
+			if entry.SetAt.Equal(setAt) {
+				require.Equal(t, now, entry.ClearedAt)
+			}
+		}
+	})
+}
+
+func TestConfigDrainsTracker(t *testing.T) {
+	t.Cleanup(func() { config.Reset() })
+
+	since := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("disabled by default", func(t *testing.T) {
+		tracker := utils.NewConfigDrainsTracker()
+		require.Empty(t, tracker.Expired("dest1", since))
+		require.Empty(t, tracker.Expired("dest1", since.Add(24*365*time.Hour)))
+	})
+
+	config.Set("Router.drainMaxAge", "1h")
+
+	t.Run("expired once per max age", func(t *testing.T) {
+		tracker := utils.NewConfigDrainsTracker()
+		require.Empty(t, tracker.Expired("dest2, dest1", since))
+		require.Empty(t, tracker.Expired("dest2,dest1,dest3", since.Add(30*time.Minute)))
+		require.Empty(t, tracker.Expired("dest2,dest1,dest3", since.Add(time.Hour)))
+
+		require.Equal(t, []utils.DrainConfig{
+			{ToAbortDestinationIDs: "dest1", SetAt: since},
+			{ToAbortDestinationIDs: "dest2", SetAt: since},
+		}, tracker.Expired("dest2,dest1,dest3", since.Add(time.Hour+time.Minute)))
+		require.Empty(t, tracker.Expired("dest2,dest1,dest3", since.Add(time.Hour+2*time.Minute)), "already reported")
+
+		require.Equal(t, []utils.DrainConfig{
+			{ToAbortDestinationIDs: "dest3", SetAt: since.Add(30 * time.Minute)},
+		}, tracker.Expired("dest2,dest1,dest3", since.Add(time.Hour+31*time.Minute)))
+
+		require.Equal(t, []utils.DrainConfig{
+			{ToAbortDestinationIDs: "dest1", SetAt: since},
+			{ToAbortDestinationIDs: "dest2", SetAt: since},
+		}, tracker.Expired("dest2,dest1,dest3", since.Add(2*time.Hour+time.Minute)), "reported again after the max age")
+	})
+
+	t.Run("removed destinations are tracked again", func(t *testing.T) {
+		tracker := utils.NewConfigDrainsTracker()
+		require.Empty(t, tracker.Expired("dest1", since))
+		require.Empty(t, tracker.Expired("", since.Add(time.Hour)))
+		require.Empty(t, tracker.Expired("dest1", since.Add(2*time.Hour)), "tracked again since it was added back")
+		require.Equal(t, []utils.DrainConfig{
+			{ToAbortDestinationIDs: "dest1", SetAt: since.Add(2 * time.Hour)},
+		}, tracker.Expired("dest1", since.Add(3*time.Hour+time.Minute)))
+	})
+}
@@ -90,7 +90,11 @@ func (w *worker) workLoop() {
 				panic(fmt.Errorf("unmarshalling of job parameters failed for job %d (%s): %w", job.JobID, string(job.Parameters), err))
 			}
 			w.rt.destinationsMapMu.RLock()
-			abort, abortReason := routerutils.ToBeDrained(job, parameters.DestinationID, w.rt.reloadableConfig.toAbortDestinationIDs.Load(), w.rt.destinationsMap)
+			drainer := routerutils.NewDrainer(w.rt.reloadableConfig.staleDrainThreshold.Load(), stats.Default)
+			abort, abortReason := drainer.ToBeDrained(job, parameters.DestinationID, routerutils.DrainConfig{
+				ToAbortDestinationIDs: w.rt.reloadableConfig.toAbortDestinationIDs.Load(),
+				ToAbortJobStates:      w.rt.reloadableConfig.toAbortJobStates.Load(),
+			}, w.rt.destinationsMap)
 			abortTag := abortReason
 			w.rt.destinationsMapMu.RUnlock()
 			if !abort {
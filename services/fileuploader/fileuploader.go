@@ -162,7 +162,7 @@ func (*defaultProvider) GetStoragePreferences(_ string) (backendconfig.StoragePr
 func getDefaultBucket(ctx context.Context, provider string) backendconfig.StorageBucket {
 	return backendconfig.StorageBucket{
 		Type:   provider,
-		Config: filemanager.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(ctx, provider, config.Default)),
+		Config: filemanagerutil.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(ctx, provider, config.Default)),
 	}
 }
 
@@ -0,0 +1,8 @@
+--
+-- wh_async_job_pauses
+--
+
+CREATE TABLE IF NOT EXISTS wh_async_job_pauses (
+    destination_id character varying(64) PRIMARY KEY,
+    created_at timestamp without time zone NOT NULL
+);
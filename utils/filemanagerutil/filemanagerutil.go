@@ -9,12 +9,22 @@ import (
 )
 
 func GetProviderConfigForBackupsFromEnv(ctx context.Context, config *config.Config) map[string]interface{} {
-	return filemanager.GetProviderConfigFromEnv(ProviderConfigOpts(ctx,
+	return GetProviderConfigFromEnv(ProviderConfigOpts(ctx,
 		config.GetString("JOBS_BACKUP_STORAGE_PROVIDER", "S3"),
 		config,
 	))
 }
 
+// GetProviderConfigFromEnv returns the provider config built from env, as filemanager.GetProviderConfigFromEnv does,
+// along with the custom endpoint of GCS (GCS_ENDPOINT, e.g. an emulator or a private endpoint) when one is provided
+func GetProviderConfigFromEnv(opts filemanager.ProviderConfigOpts) map[string]interface{} {
+	providerConfig := filemanager.GetProviderConfigFromEnv(opts)
+	if opts.Provider == "GCS" && opts.Config.IsSet("GCS_ENDPOINT") {
+		providerConfig["endPoint"] = opts.Config.GetString("GCS_ENDPOINT", "")
+	}
+	return providerConfig
+}
+
 func ProviderConfigOpts(ctx context.Context, provider string, config *config.Config) filemanager.ProviderConfigOpts {
 	return filemanager.ProviderConfigOpts{
 		Provider: provider,
@@ -0,0 +1,47 @@
+package filemanagerutil_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-server/utils/filemanagerutil"
+)
+
+func TestGetProviderConfigFromEnv(t *testing.T) {
+	t.Run("GCS without endpoint", func(t *testing.T) {
+		c := config.New()
+		c.Set("JOBS_BACKUP_BUCKET", "backups")
+
+		providerConfig := filemanagerutil.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(context.Background(), "GCS", c))
+		require.Equal(t, "backups", providerConfig["bucketName"])
+		require.NotContains(t, providerConfig, "endPoint")
+	})
+
+	t.Run("GCS with endpoint", func(t *testing.T) {
+		c := config.New()
+		c.Set("GCS_ENDPOINT", "http://localhost:4443/storage/v1/")
+
+		providerConfig := filemanagerutil.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(context.Background(), "GCS", c))
+		require.Equal(t, "http://localhost:4443/storage/v1/", providerConfig["endPoint"])
+	})
+
+	t.Run("endpoint only applies to GCS", func(t *testing.T) {
+		c := config.New()
+		c.Set("GCS_ENDPOINT", "http://localhost:4443/storage/v1/")
+
+		providerConfig := filemanagerutil.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(context.Background(), "AZURE_BLOB", c))
+		require.NotContains(t, providerConfig, "endPoint")
+	})
+
+	t.Run("backups", func(t *testing.T) {
+		c := config.New()
+		c.Set("JOBS_BACKUP_STORAGE_PROVIDER", "GCS")
+		c.Set("GCS_ENDPOINT", "http://localhost:4443/storage/v1/")
+
+		providerConfig := filemanagerutil.GetProviderConfigForBackupsFromEnv(context.Background(), c)
+		require.Equal(t, "http://localhost:4443/storage/v1/", providerConfig["endPoint"])
+	})
+}
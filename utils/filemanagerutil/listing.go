@@ -0,0 +1,72 @@
+package filemanagerutil
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rudderlabs/rudder-go-kit/filemanager"
+)
+
+// ErrListingTruncated is returned by the list sessions of the file managers returned by WithMaxListedObjects once they have listed the maximum number of objects
+// while there are more objects with the prefix, i.e. the listing is incomplete.
+var ErrListingTruncated = errors.New("listing truncated by the maximum number of listed objects")
+
+// WithMaxListedObjects returns the file manager, with its list sessions returning up to maxObjects objects in total, however many times Next is called.
+// Once the ceiling is reached, Next returns ErrListingTruncated if there are more objects with the prefix, so that callers know they haven't seen all of them.
+// A maxObjects of zero or less returns the file manager as is.
+//
+// The maxItems of ListFilesWithPrefix is the number of objects returned by every call to Next. Since the file managers return no objects at all
+// for a maxItems of zero or less (the listing stops right away instead of listing everything), such a maxItems is replaced by maxObjects.
+// A maxItems larger than maxObjects is capped by it.
+func WithMaxListedObjects(fm filemanager.FileManager, maxObjects int64) filemanager.FileManager {
+	if maxObjects <= 0 {
+		return fm
+	}
+	return &maxListedObjectsFileManager{FileManager: fm, maxObjects: maxObjects}
+}
+
+type maxListedObjectsFileManager struct {
+	filemanager.FileManager
+	maxObjects int64
+}
+
+func (m *maxListedObjectsFileManager) ListFilesWithPrefix(ctx context.Context, startAfter, prefix string, maxItems int64) filemanager.ListSession {
+	if maxItems <= 0 || maxItems > m.maxObjects {
+		maxItems = m.maxObjects
+	}
+	return &maxListedObjectsListSession{
+		session:   m.FileManager.ListFilesWithPrefix(ctx, startAfter, prefix, maxItems),
+		remaining: m.maxObjects,
+	}
+}
+
+type maxListedObjectsListSession struct {
+	session   filemanager.ListSession
+	remaining int64
+	truncated bool
+}
+
+func (s *maxListedObjectsListSession) Next() ([]*filemanager.FileInfo, error) {
+	if s.truncated {
+		return nil, ErrListingTruncated
+	}
+
+	fileObjects, err := s.session.Next()
+	if err != nil {
+		return nil, err
+	}
+	if s.remaining == 0 {
+		// the ceiling was reached exactly by the previous call, the listing is complete only if there are no more objects
+		if len(fileObjects) > 0 {
+			s.truncated = true
+			return nil, ErrListingTruncated
+		}
+		return nil, nil
+	}
+	if int64(len(fileObjects)) > s.remaining {
+		fileObjects = fileObjects[:s.remaining]
+		s.truncated = true
+	}
+	s.remaining -= int64(len(fileObjects))
+	return fileObjects, nil
+}
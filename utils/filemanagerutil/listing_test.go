@@ -0,0 +1,97 @@
+package filemanagerutil_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/filemanager"
+	"github.com/rudderlabs/rudder-go-kit/filemanager/mock_filemanager"
+	"github.com/rudderlabs/rudder-server/utils/filemanagerutil"
+)
+
+// pagedListSession returns the objects in pages of pageSize objects
+type pagedListSession struct {
+	objects  []*filemanager.FileInfo
+	pageSize int64
+}
+
+func (s *pagedListSession) Next() ([]*filemanager.FileInfo, error) {
+	n := min(s.pageSize, int64(len(s.objects)))
+	page := s.objects[:n]
+	s.objects = s.objects[n:]
+	return page, nil
+}
+
+func TestWithMaxListedObjects(t *testing.T) {
+	newFileManager := func(t *testing.T, objects int) *mock_filemanager.MockFileManager {
+		fm := mock_filemanager.NewMockFileManager(gomock.NewController(t))
+		fm.EXPECT().ListFilesWithPrefix(gomock.Any(), "", "prefix", gomock.Any()).DoAndReturn(
+			func(_ context.Context, _, _ string, maxItems int64) filemanager.ListSession {
+				session := &pagedListSession{pageSize: maxItems}
+				for i := 0; i < objects; i++ {
+					session.objects = append(session.objects, &filemanager.FileInfo{Key: fmt.Sprintf("prefix/%d", i)})
+				}
+				return session
+			},
+		).AnyTimes()
+		return fm
+	}
+
+	// list returns the number of objects listed until the session is done or fails
+	list := func(session filemanager.ListSession) (int, error) {
+		var listed int
+		for {
+			fileObjects, err := session.Next()
+			if err != nil {
+				return listed, err
+			}
+			if len(fileObjects) == 0 {
+				return listed, nil
+			}
+			listed += len(fileObjects)
+		}
+	}
+
+	testCases := []struct {
+		name           string
+		objects        int
+		maxObjects     int64
+		maxItems       int64
+		expectedListed int
+		expectedErr    error
+	}{
+		{name: "below the ceiling", objects: 5, maxObjects: 10, maxItems: 2, expectedListed: 5},
+		{name: "exactly the ceiling", objects: 10, maxObjects: 10, maxItems: 5, expectedListed: 10},
+		{name: "above the ceiling", objects: 15, maxObjects: 10, maxItems: 4, expectedListed: 10, expectedErr: filemanagerutil.ErrListingTruncated},
+		{name: "above the ceiling at a page boundary", objects: 15, maxObjects: 10, maxItems: 5, expectedListed: 10, expectedErr: filemanagerutil.ErrListingTruncated},
+		{name: "zero max items", objects: 15, maxObjects: 10, maxItems: 0, expectedListed: 10, expectedErr: filemanagerutil.ErrListingTruncated},
+		{name: "max items above the ceiling", objects: 15, maxObjects: 10, maxItems: 100, expectedListed: 10, expectedErr: filemanagerutil.ErrListingTruncated},
+		{name: "no ceiling", objects: 15, maxObjects: 0, maxItems: 4, expectedListed: 15},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fm := filemanagerutil.WithMaxListedObjects(newFileManager(t, tc.objects), tc.maxObjects)
+
+			listed, err := list(fm.ListFilesWithPrefix(context.Background(), "", "prefix", tc.maxItems))
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedListed, listed)
+		})
+	}
+
+	t.Run("iterator", func(t *testing.T) {
+		fm := filemanagerutil.WithMaxListedObjects(newFileManager(t, 15), 10)
+
+		var listed int
+		iter := filemanager.IterateFilesWithPrefix(context.Background(), "prefix", "", 3, fm)
+		for iter.Next() {
+			listed++
+		}
+		require.ErrorIs(t, iter.Err(), filemanagerutil.ErrListingTruncated)
+		require.Equal(t, 10, listed)
+	})
+}
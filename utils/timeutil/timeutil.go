@@ -1,7 +1,6 @@
 package timeutil
 
 import (
-	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
@@ -34,7 +33,5 @@ func Now() time.Time {
 
 // GetElapsedMinsInThisDay() returns no of minutes elapsed in this day for the time provided
 func GetElapsedMinsInThisDay(currentTime time.Time) int {
-	hour := currentTime.Hour()
-	minute := currentTime.Minute()
-	return MinsOfDay(fmt.Sprintf("%d:%d", hour, minute))
+	return currentTime.Hour()*60 + currentTime.Minute()
 }
@@ -2,6 +2,7 @@ package admin
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -11,6 +12,7 @@ import (
 	"github.com/rudderlabs/rudder-go-kit/stats"
 	"github.com/rudderlabs/rudder-server/warehouse/integrations/manager"
 	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	"github.com/rudderlabs/rudder-server/warehouse/jobs"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
 	"github.com/rudderlabs/rudder-server/warehouse/validations"
 )
@@ -25,6 +27,11 @@ type ConfigurationTestInput struct {
 	DestID string
 }
 
+type TriggerWarehouseUploadInput struct {
+	WarehouseIdentifier string // e.g. POSTGRES:sourceID:destinationID
+	Off                 bool
+}
+
 type ConfigurationTestOutput struct {
 	Valid bool
 	Error string
@@ -33,6 +40,8 @@ type ConfigurationTestOutput struct {
 type Admin struct {
 	csf    connectionSourcesFetcher
 	suas   startUploadAlwaysSetter
+	suaw   startUploadAlwaysWarehouses
+	ajp    asyncJobsPauser
 	logger logger.Logger
 }
 
@@ -44,14 +53,30 @@ type startUploadAlwaysSetter interface {
 	Store(bool)
 }
 
+type startUploadAlwaysWarehouses interface {
+	Add(identifier string)
+	Remove(identifier string)
+	List() []string
+}
+
+type asyncJobsPauser interface {
+	PauseDestination(ctx context.Context, destinationID string) error
+	ResumeDestination(ctx context.Context, destinationID string) error
+	PausedDestinations(ctx context.Context) ([]string, error)
+}
+
 func New(
 	csf connectionSourcesFetcher,
 	suas startUploadAlwaysSetter,
+	suaw startUploadAlwaysWarehouses,
+	ajp asyncJobsPauser,
 	logger logger.Logger,
 ) *Admin {
 	return &Admin{
 		csf:    csf,
 		suas:   suas,
+		suaw:   suaw,
+		ajp:    ajp,
 		logger: logger.Child("admin"),
 	}
 }
@@ -67,6 +92,28 @@ func (a *Admin) TriggerUpload(off bool, reply *string) error {
 	return nil
 }
 
+// TriggerWarehouseUpload sets uploads to start without delay for a single warehouse, unlike TriggerUpload which does it for all the warehouses
+func (a *Admin) TriggerWarehouseUpload(s TriggerWarehouseUploadInput, reply *string) error {
+	identifier := strings.TrimSpace(s.WarehouseIdentifier)
+	if identifier == "" {
+		return errors.New("please specify the warehouse identifier to trigger uploads for")
+	}
+	if s.Off {
+		a.suaw.Remove(identifier)
+		*reply = fmt.Sprintf("Turned off explicit upload triggers for warehouse %s.\nIts uploads will continue to be done as per schedule in control plane.", identifier)
+	} else {
+		a.suaw.Add(identifier)
+		*reply = fmt.Sprintf("Successfully set uploads to start always without delay for warehouse %s.\nRun same command with -o flag to turn off explicit triggers.", identifier)
+	}
+	return nil
+}
+
+// TriggeredWarehouseUploads lists the warehouses for which uploads are set to start without delay through TriggerWarehouseUpload
+func (a *Admin) TriggeredWarehouseUploads(_ string, reply *[]string) error {
+	*reply = a.suaw.List()
+	return nil
+}
+
 // Query the underlying warehouse
 func (a *Admin) Query(s QueryInput, reply *warehouseutils.QueryResult) error {
 	if strings.TrimSpace(s.DestID) == "" {
@@ -138,3 +185,88 @@ func (a *Admin) ConfigurationTest(s ConfigurationTestInput, reply *Configuration
 	reply.Error = res.Error
 	return nil
 }
+
+// PauseAsyncJobs pauses the async jobs of the destination, keeping the pending ones queued
+func (a *Admin) PauseAsyncJobs(destID string, reply *string) error {
+	if strings.TrimSpace(destID) == "" {
+		return errors.New("please specify the destination ID to pause the async jobs for")
+	}
+	if err := a.ajp.PauseDestination(context.TODO(), destID); err != nil {
+		return err
+	}
+	*reply = fmt.Sprintf("Paused async jobs for destination %s", destID)
+	return nil
+}
+
+// ResumeAsyncJobs resumes the async jobs of a paused destination
+func (a *Admin) ResumeAsyncJobs(destID string, reply *string) error {
+	if strings.TrimSpace(destID) == "" {
+		return errors.New("please specify the destination ID to resume the async jobs for")
+	}
+	if err := a.ajp.ResumeDestination(context.TODO(), destID); err != nil {
+		return err
+	}
+	*reply = fmt.Sprintf("Resumed async jobs for destination %s", destID)
+	return nil
+}
+
+// PausedAsyncJobs lists the destinations for which the async jobs are paused
+func (a *Admin) PausedAsyncJobs(_ string, reply *[]string) error {
+	destIDs, err := a.ajp.PausedDestinations(context.TODO())
+	if err != nil {
+		return err
+	}
+	*reply = destIDs
+	return nil
+}
+
+// EstimateJob estimates the number of records affected by the async job, without changing any state
+func (a *Admin) EstimateJob(payload jobs.AsyncJobPayload, reply *int64) error {
+	if strings.TrimSpace(payload.DestinationID) == "" || strings.TrimSpace(payload.SourceID) == "" {
+		return errors.New("please specify the source ID and destination ID of the async job")
+	}
+	if payload.AsyncJobType != jobs.AsyncJobTypeDeleteByJobRunID {
+		return fmt.Errorf("estimating async job type %q is not supported", payload.AsyncJobType)
+	}
+
+	srcMap, ok := a.csf.ConnectionSourcesMap(payload.DestinationID)
+	if !ok {
+		return fmt.Errorf("please specify a valid and existing destinationID: %s", payload.DestinationID)
+	}
+	warehouse, ok := srcMap[payload.SourceID]
+	if !ok {
+		return errors.New("please specify a valid (sourceID, destination ID) pair")
+	}
+
+	var metadata warehouseutils.DeleteByMetaData
+	if err := json.Unmarshal(payload.MetaData, &metadata); err != nil {
+		return fmt.Errorf("unmarshalling metadata of async job: %w", err)
+	}
+
+	whManager, err := manager.New(warehouse.Type, config.Default, logger.NOP, stats.Default)
+	if err != nil {
+		return err
+	}
+	estimator, ok := whManager.(manager.DeleteByEstimator)
+	if !ok {
+		return fmt.Errorf("estimating async jobs is not supported for destination type %s", warehouse.Type)
+	}
+	whManager.SetConnectionTimeout(warehouseutils.GetConnectionTimeout(
+		warehouse.Type, warehouse.Destination.ID,
+	))
+
+	ctx := context.TODO()
+	if err := whManager.Setup(ctx, warehouse, &jobs.WhAsyncJob{}); err != nil {
+		return err
+	}
+	defer whManager.Cleanup(ctx)
+
+	a.logger.Infof(`[WH Admin]: Estimating async job for table %s in warehouse: %s:%s`, payload.TableName, warehouse.Type, warehouse.Destination.ID)
+	*reply, err = estimator.EstimateDeleteBy(ctx, []string{payload.TableName}, warehouseutils.DeleteByParams{
+		SourceId:  payload.SourceID,
+		TaskRunId: metadata.TaskRunId,
+		JobRunId:  metadata.JobRunId,
+		StartTime: metadata.StartTime,
+	})
+	return err
+}
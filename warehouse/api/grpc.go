@@ -776,7 +776,7 @@ func (g *GRPC) validateObjectStorage(ctx context.Context, request validateObject
 // overrideWithEnv overrides the config keys in the fileManager settings
 // with fallback values pulled from env. Only supported for S3 for now.
 func overrideWithEnv(ctx context.Context, settings *filemanager.Settings) {
-	envConfig := filemanager.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(
+	envConfig := filemanagerutil.GetProviderConfigFromEnv(filemanagerutil.ProviderConfigOpts(
 		ctx,
 		settings.Provider,
 		config.Default,
@@ -69,6 +69,7 @@ type App struct {
 	sourcesManager     *jobs.AsyncJobWh
 	admin              *whadmin.Admin
 	triggerStore       *sync.Map
+	routerOpts         []router.Opt
 
 	appName string
 
@@ -91,6 +92,15 @@ type App struct {
 	}
 }
 
+type Opt func(a *App)
+
+// WithUploadObserver registers an observer for the upload lifecycle events of all the warehouse routers
+func WithUploadObserver(observer router.UploadObserver) Opt {
+	return func(a *App) {
+		a.routerOpts = append(a.routerOpts, router.WithUploadObserver(observer))
+	}
+}
+
 func New(
 	app app.App,
 	conf *config.Config,
@@ -98,6 +108,7 @@ func New(
 	statsFactory stats.Stats,
 	bcConfig backendconfig.BackendConfig,
 	fileManagerFactory filemanager.Factory,
+	opts ...Opt,
 ) *App {
 	a := &App{
 		app:                app,
@@ -124,6 +135,9 @@ func New(
 
 	a.appName = misc.DefaultString("rudder-server").OnError(os.Hostname())
 
+	for _, opt := range opts {
+		opt(a)
+	}
 	return a
 }
 
@@ -175,6 +189,7 @@ func (a *App) Setup(ctx context.Context) error {
 		ctx,
 		a.db,
 		a.notifier,
+		a.statsFactory,
 	)
 	jobs.WithConfig(a.sourcesManager, a.conf)
 
@@ -206,6 +221,8 @@ func (a *App) Setup(ctx context.Context) error {
 	a.admin = whadmin.New(
 		a.bcManager,
 		&router.StartUploadAlways,
+		router.StartUploadAlwaysWarehouses,
+		a.sourcesManager,
 		a.logger,
 	)
 
@@ -483,6 +500,7 @@ func (a *App) onConfigDataEvent(
 					a.bcManager,
 					a.encodingFactory,
 					a.triggerStore,
+					a.routerOpts...,
 				)
 				if err != nil {
 					return fmt.Errorf("setup warehouse %q: %w", destination.DestinationDefinition.Name, err)
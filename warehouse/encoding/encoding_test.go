@@ -4,6 +4,7 @@ import (
 	"compress/gzip"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -380,3 +381,69 @@ func TestReaderLoader(t *testing.T) {
 		})
 	})
 }
+
+func TestParquetEventReader(t *testing.T) {
+	columns := []string{"active", "id", "missing", "name", "received_at", "score"}
+
+	t.Run("gzip compressed", func(t *testing.T) {
+		r, err := encoding.NewParquetEventReader("testdata/load.gzip.parquet")
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, r.Close()) })
+
+		var records [][]string
+		for {
+			record, err := r.Read(columns)
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			records = append(records, record)
+		}
+		require.Equal(t, [][]string{
+			{"true", "1", "", "alice", "2023-10-01T12:30:45.123456Z", "1.5"},
+			{"false", "2", "", "bob", "", ""},
+			{"", "3", "", "", "2023-10-01T12:30:45.123456Z", "2.25"},
+		}, records)
+	})
+
+	t.Run("written by the load file writer", func(t *testing.T) {
+		outputFilePath := t.TempDir() + "/" + uuid.New().String() + ".parquet"
+		schema := model.TableSchema{
+			"id":          "int",
+			"received_at": "datetime",
+		}
+
+		ef := encoding.NewFactory(config.Default)
+		writer, err := ef.NewLoadFileWriter(warehouseutils.LoadFileTypeParquet, outputFilePath, schema, warehouseutils.S3Datalake)
+		require.NoError(t, err)
+		for i := 0; i < 1500; i++ {
+			require.NoError(t, writer.WriteRow([]interface{}{int64(i), nil}))
+		}
+		require.NoError(t, writer.Close())
+
+		r, err := encoding.NewParquetEventReader(outputFilePath)
+		require.NoError(t, err)
+		t.Cleanup(func() { require.NoError(t, r.Close()) })
+
+		for i := 0; i < 1500; i++ {
+			record, err := r.Read([]string{"id", "received_at"})
+			require.NoError(t, err)
+			require.Equal(t, []string{strconv.Itoa(i), ""}, record)
+		}
+		_, err = r.Read([]string{"id", "received_at"})
+		require.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("not a parquet file", func(t *testing.T) {
+		fileName := t.TempDir() + "/load.parquet"
+		require.NoError(t, os.WriteFile(fileName, []byte("id,name\n1,alice\n"), 0o600))
+
+		_, err := encoding.NewParquetEventReader(fileName)
+		require.Error(t, err)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := encoding.NewParquetEventReader(t.TempDir() + "/missing.parquet")
+		require.Error(t, err)
+	})
+}
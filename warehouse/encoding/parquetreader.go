@@ -0,0 +1,168 @@
+package encoding
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/common"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/reader"
+	"github.com/xitongsys/parquet-go/source"
+)
+
+// parquetReadBatchSize is the number of rows read at once from every column of a parquet load file
+const parquetReadBatchSize = 1000
+
+// ParquetEventReader is an EventReader of a parquet load file, which needs to be closed once read
+type ParquetEventReader interface {
+	EventReader
+	Close() error
+}
+
+type parquetReader struct {
+	file   source.ParquetFile
+	reader *reader.ParquetReader
+	rows   int64 // rows not read yet
+
+	columns        []string
+	convertedTypes []parquet.ConvertedType // converted types of the columns, -1 if they have none
+	batch          [][]interface{}         // values of the current batch, by column
+	size           int                     // number of rows of the current batch
+	index          int                     // index of the next row of the current batch
+}
+
+// NewParquetEventReader returns a reader of the records of the (local) parquet load file, whatever the compression codec of the file.
+// The columns of the records are mapped by name to the columns of the file, the columns missing from the file being read as empty (NULL) values.
+// Values are converted to strings the same way they are written to the CSV load files, so that they go through the same type coercion:
+// booleans as true or false, numbers in decimal notation, timestamps in RFC3339 and NULLs as empty strings.
+// The column names need to be the same for every call to Read.
+func NewParquetEventReader(fileName string) (ParquetEventReader, error) {
+	file, err := local.NewLocalFileReader(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("opening parquet file %s: %w", fileName, err)
+	}
+
+	pr, err := reader.NewParquetColumnReader(file, 1)
+	if err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("reading parquet file %s: %w", fileName, err)
+	}
+
+	return &parquetReader{
+		file:   file,
+		reader: pr,
+		rows:   pr.GetNumRows(),
+	}, nil
+}
+
+func (p *parquetReader) Read(columnNames []string) ([]string, error) {
+	if p.index >= p.size {
+		if p.rows <= 0 {
+			return []string{}, io.EOF
+		}
+		if err := p.readBatch(columnNames); err != nil {
+			return []string{}, err
+		}
+	}
+
+	record := make([]string, 0, len(p.columns))
+	for i, column := range p.columns {
+		value, err := stringValue(p.convertedTypes[i], p.batch[i][p.index])
+		if err != nil {
+			return []string{}, fmt.Errorf("column %s: %w", column, err)
+		}
+		record = append(record, value)
+	}
+	p.index++
+	return record, nil
+}
+
+// readBatch reads the next batch of rows of all the columns
+func (p *parquetReader) readBatch(columnNames []string) error {
+	n := min(p.rows, parquetReadBatchSize)
+
+	p.columns = columnNames
+	p.convertedTypes = make([]parquet.ConvertedType, len(columnNames))
+	p.batch = make([][]interface{}, len(columnNames))
+	for i, column := range columnNames {
+		path, ok := p.columnPath(column)
+		if !ok {
+			p.convertedTypes[i] = -1
+			p.batch[i] = make([]interface{}, n)
+			continue
+		}
+		p.convertedTypes[i] = p.convertedType(path)
+		values, _, _, err := p.reader.ReadColumnByPath(path, n)
+		if err != nil {
+			return fmt.Errorf("reading column %s: %w", column, err)
+		}
+		if int64(len(values)) != n {
+			return fmt.Errorf("reading column %s: expected %d values, got %d", column, n, len(values))
+		}
+		p.batch[i] = values
+	}
+	p.rows -= n
+	p.size, p.index = int(n), 0
+	return nil
+}
+
+// columnPath returns the path of the column in the parquet schema, if the file has the column
+func (p *parquetReader) columnPath(column string) (string, bool) {
+	path := common.PathToStr([]string{p.reader.SchemaHandler.GetRootExName(), column})
+	inPath, err := p.reader.SchemaHandler.ConvertToInPathStr(path)
+	if err != nil {
+		return "", false
+	}
+	return inPath, true
+}
+
+// stringValue converts the value of a column with the converted type into its string representation in the CSV load files
+func stringValue(convertedType parquet.ConvertedType, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int32:
+		if convertedType == parquet.ConvertedType_DATE {
+			return time.Unix(int64(v)*24*60*60, 0).UTC().Format(time.RFC3339), nil
+		}
+		return strconv.FormatInt(int64(v), 10), nil
+	case int64:
+		switch convertedType {
+		case parquet.ConvertedType_TIMESTAMP_MICROS:
+			return time.UnixMicro(v).UTC().Format(time.RFC3339Nano), nil
+		case parquet.ConvertedType_TIMESTAMP_MILLIS:
+			return time.UnixMilli(v).UTC().Format(time.RFC3339Nano), nil
+		}
+		return strconv.FormatInt(v, 10), nil
+	default:
+		return cast.ToStringE(v)
+	}
+}
+
+// convertedType returns the converted type of the column with the path, or -1 if it has none
+func (p *parquetReader) convertedType(path string) parquet.ConvertedType {
+	index, ok := p.reader.SchemaHandler.MapIndex[path]
+	if !ok {
+		return -1
+	}
+	element := p.reader.SchemaHandler.SchemaElements[index]
+	if !element.IsSetConvertedType() {
+		return -1
+	}
+	return element.GetConvertedType()
+}
+
+func (p *parquetReader) Close() error {
+	p.reader.ReadStop()
+	return p.file.Close()
+}
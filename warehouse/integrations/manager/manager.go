@@ -57,6 +57,41 @@ type WarehouseOperations interface {
 	WarehouseDelete
 }
 
+// CapabilitiesReporter is implemented by the integrations reporting the features they support
+type CapabilitiesReporter interface {
+	Capabilities() types.Capabilities
+}
+
+// CapabilitiesOf returns the features supported by the integration.
+// The integrations not reporting their capabilities are assumed to support all the features.
+func CapabilitiesOf(m Manager) types.Capabilities {
+	if reporter, ok := m.(CapabilitiesReporter); ok {
+		return reporter.Capabilities()
+	}
+	return types.Capabilities{
+		Merge:        true,
+		DeleteByJobs: true,
+		BulkCopy:     true,
+	}
+}
+
+// DeleteByEstimator is implemented by the integrations which can estimate the records affected by DeleteBy, without changing any state
+type DeleteByEstimator interface {
+	EstimateDeleteBy(ctx context.Context, tableNames []string, params warehouseutils.DeleteByParams) (int64, error)
+}
+
+// StorageEgressVerifier is implemented by the integrations which can make the warehouse read an object from the object storage,
+// verifying that the warehouse itself (and not only RudderStack) can reach the object storage
+type StorageEgressVerifier interface {
+	VerifyStorageEgress(ctx context.Context, objectName string) error
+}
+
+// StaleStagingTablesDropper is implemented by the integrations which can drop the staging tables left behind by crashed loads,
+// opening their own connection to the warehouse, without running any upload
+type StaleStagingTablesDropper interface {
+	DropStaleStagingTables(ctx context.Context, warehouse model.Warehouse, olderThan time.Duration) (int, error)
+}
+
 // New is a Factory function that returns a Manager of a given destination-type
 func New(destType string, conf *config.Config, logger logger.Logger, stats stats.Stats) (Manager, error) {
 	switch destType {
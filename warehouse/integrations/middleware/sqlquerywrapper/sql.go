@@ -156,7 +156,7 @@ func (db *DB) ExecContext(ctx context.Context, query string, args ...interface{}
 	ctx, cancel := queryContextWithTimeout(ctx, db.queryTimeout)
 	defer cancel()
 	result, err := db.DB.ExecContext(ctx, query, args...)
-	db.logQuery(query, startedAt)()
+	db.logQuery(ctx, query, startedAt)()
 	return result, err
 }
 
@@ -170,19 +170,19 @@ func (db *DB) QueryContext(ctx context.Context, query string, args ...interface{
 	rows, err := db.DB.QueryContext(ctx, query, args...)
 	if err != nil {
 		defer cancel()
-		defer db.logQuery(query, startedAt)()
+		defer db.logQuery(ctx, query, startedAt)()
 		return nil, err
 	}
 	if err := rows.Err(); err != nil {
 		cancel()
-		db.logQuery(query, startedAt)()
+		db.logQuery(ctx, query, startedAt)()
 		func() { _ = rows.Close() }()
 		return nil, err
 	}
 	return &Rows{
 		Rows:       rows,
 		CancelFunc: cancel,
-		logQ:       db.logQuery(query, startedAt),
+		logQ:       db.logQuery(ctx, query, startedAt),
 	}, err
 }
 
@@ -196,7 +196,7 @@ func (db *DB) QueryRowContext(ctx context.Context, query string, args ...interfa
 	return &Row{
 		Row:        db.DB.QueryRowContext(ctx, query, args...),
 		CancelFunc: cancel,
-		logQ:       db.logQuery(query, startedAt),
+		logQ:       db.logQuery(ctx, query, startedAt),
 	}
 }
 
@@ -221,7 +221,7 @@ func (db *DB) WithTx(ctx context.Context, fn func(*Tx) error) error {
 	return tx.Commit()
 }
 
-func (db *DB) logQuery(query string, since time.Time) logQ {
+func (db *DB) logQuery(ctx context.Context, query string, since time.Time) logQ {
 	return func() {
 		var (
 			sanitizedQuery string
@@ -237,6 +237,11 @@ func (db *DB) logQuery(query string, since time.Time) logQ {
 			keysAndValues = append(keysAndValues, db.keysAndValues...)
 		}
 
+		if recorder, ok := ctx.Value(queryRecorderKey{}).(QueryRecorder); ok {
+			createLogData()
+			recorder(sanitizedQuery, db.since(since))
+		}
+
 		if db.stats != nil {
 			var expected bool
 			tags := make(stats.Tags, len(db.keysAndValues)/2+1)
@@ -271,6 +276,16 @@ func (db *DB) logQuery(query string, since time.Time) logQ {
 
 type logQ func()
 
+// QueryRecorder receives every query executed with a context carrying it, with its secrets replaced
+type QueryRecorder func(query string, elapsed time.Duration)
+
+type queryRecorderKey struct{}
+
+// WithQueryRecorder returns a context recording the queries executed with it, e.g. to audit the queries run for a specific operation
+func WithQueryRecorder(ctx context.Context, recorder QueryRecorder) context.Context {
+	return context.WithValue(ctx, queryRecorderKey{}, recorder)
+}
+
 // Begin starts a transaction.
 //
 // Use BeginTx to pass context and options to the underlying driver.
@@ -297,7 +312,7 @@ func (tx *Tx) ExecContext(ctx context.Context, query string, args ...interface{}
 	ctx, cancel := queryContextWithTimeout(ctx, tx.db.queryTimeout)
 	defer cancel()
 	result, err := tx.Tx.ExecContext(ctx, query, args...)
-	tx.db.logQuery(query, startedAt)()
+	tx.db.logQuery(ctx, query, startedAt)()
 	return result, err
 }
 
@@ -311,19 +326,19 @@ func (tx *Tx) QueryContext(ctx context.Context, query string, args ...interface{
 	rows, err := tx.Tx.QueryContext(ctx, query, args...)
 	if err != nil {
 		defer cancel()
-		defer tx.db.logQuery(query, startedAt)()
+		defer tx.db.logQuery(ctx, query, startedAt)()
 		return nil, err
 	}
 	if err := rows.Err(); err != nil {
 		cancel()
-		tx.db.logQuery(query, startedAt)()
+		tx.db.logQuery(ctx, query, startedAt)()
 		func() { _ = rows.Close() }()
 		return nil, err
 	}
 	return &Rows{
 		Rows:       rows,
 		CancelFunc: cancel,
-		logQ:       tx.db.logQuery(query, startedAt),
+		logQ:       tx.db.logQuery(ctx, query, startedAt),
 	}, err
 }
 
@@ -337,7 +352,7 @@ func (tx *Tx) QueryRowContext(ctx context.Context, query string, args ...interfa
 	return &Row{
 		Row:        tx.Tx.QueryRowContext(ctx, query, args...),
 		CancelFunc: cancel,
-		logQ:       tx.db.logQuery(query, startedAt),
+		logQ:       tx.db.logQuery(ctx, query, startedAt),
 	}
 }
 
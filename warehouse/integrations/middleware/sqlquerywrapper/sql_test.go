@@ -478,3 +478,47 @@ func TestWithStats(t *testing.T) {
 	})
 	require.NotNilf(t, measurement, "measurement should not be nil")
 }
+
+func TestWithQueryRecorder(t *testing.T) {
+	t.Parallel()
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pgResource, err := resource.SetupPostgres(pool, t)
+	require.NoError(t, err)
+
+	qw := New(
+		pgResource.DB,
+		WithSecretsRegex(map[string]string{
+			"'secret'": "'***'",
+		}),
+	)
+	qw.since = func(time.Time) time.Duration {
+		return time.Second
+	}
+
+	var recorded []string
+	ctx := WithQueryRecorder(context.Background(), func(query string, elapsed time.Duration) {
+		require.Equal(t, time.Second, elapsed)
+		recorded = append(recorded, query)
+	})
+
+	_, err = qw.ExecContext(ctx, "SELECT 'secret';")
+	require.NoError(t, err)
+
+	rows, err := qw.QueryContext(ctx, "SELECT 2;")
+	require.NoError(t, err)
+	require.NoError(t, rows.Close())
+
+	err = qw.WithTx(ctx, func(tx *Tx) error {
+		_, err := tx.ExecContext(ctx, "SELECT 3;")
+		return err
+	})
+	require.NoError(t, err)
+
+	_, err = qw.ExecContext(context.Background(), "SELECT 4;")
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"SELECT '***';", "SELECT 2;", "SELECT 3;"}, recorded)
+}
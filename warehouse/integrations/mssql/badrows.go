@@ -0,0 +1,149 @@
+package mssql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/samber/lo"
+
+	sqlmw "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// ErrBadRowsThresholdExceeded is returned when loading a table if more rows of its load files can't be loaded than tolerated
+var ErrBadRowsThresholdExceeded = errors.New("bad rows threshold exceeded")
+
+// badRowsThreshold is the number of bad rows tolerated while loading a table, either as a number of rows or as a percentage of the rows of the load files.
+// The bad rows are routed to the discards table instead of failing the load, as long as either of the thresholds isn't exceeded.
+// Without any threshold (the default) the first bad row fails the load.
+type badRowsThreshold struct {
+	rows    int
+	percent float64
+}
+
+func (t badRowsThreshold) enabled() bool {
+	return t.rows > 0 || t.percent > 0
+}
+
+// exceeded returns true if the bad rows out of the total rows read are not tolerated
+func (t badRowsThreshold) exceeded(badRows, totalRows int) bool {
+	if badRows == 0 {
+		return false
+	}
+	if t.rows > 0 && badRows <= t.rows {
+		return false
+	}
+	if t.percent > 0 && totalRows > 0 && float64(badRows)*100/float64(totalRows) <= t.percent {
+		return false
+	}
+	return true
+}
+
+// exceededEarly returns true if the bad rows are not tolerated whatever the number of rows left to read, i.e. only the number of rows threshold is enabled and it is exceeded
+func (t badRowsThreshold) exceededEarly(badRows int) bool {
+	return t.percent <= 0 && t.rows > 0 && badRows > t.rows
+}
+
+// badRow is a row of a load file which can't be loaded, e.g. because its number of columns doesn't match the schema of the upload
+type badRow struct {
+	fileName string
+	line     int
+	record   []string
+	reason   string
+}
+
+// badRows collects the bad rows found while loading the staging table of a table
+type badRows struct {
+	threshold badRowsThreshold
+	rows      []badRow
+	totalRows int
+}
+
+// add records the bad row, returning the error failing the load if the bad row isn't tolerated
+func (b *badRows) add(row badRow, err error) error {
+	if !b.threshold.enabled() {
+		return err
+	}
+	b.rows = append(b.rows, row)
+	if b.threshold.exceededEarly(len(b.rows)) {
+		return fmt.Errorf("%w: %d bad rows: %w", ErrBadRowsThresholdExceeded, len(b.rows), err)
+	}
+	return nil
+}
+
+// check returns an error if the bad rows found are not tolerated, once all the rows are read
+func (b *badRows) check() error {
+	if !b.threshold.exceeded(len(b.rows), b.totalRows) {
+		return nil
+	}
+	return fmt.Errorf("%w: %d bad rows out of %d rows", ErrBadRowsThresholdExceeded, len(b.rows), b.totalRows)
+}
+
+// loadBadRowsIntoDiscards inserts a discard for every bad row of the table, the whole raw row being the value of the discard.
+// The discards table is created if the upload has no discards of its own.
+// It runs in the transaction of the load, so that the discards are only kept if the load succeeds.
+func (ms *MSSQL) loadBadRowsIntoDiscards(ctx context.Context, txn *sqlmw.Tx, tableName string, rows []badRow) error {
+	schema := lo.Assign(model.TableSchema{}, warehouseutils.DiscardsSchema)
+	discardsSchema := ms.Uploader.GetTableSchemaInWarehouse(warehouseutils.DiscardsTable)
+	for _, column := range []string{"is_null", "reason"} {
+		if _, ok := discardsSchema[column]; ok {
+			schema[column] = warehouseutils.DiscardsExtraColumns[column]
+		}
+	}
+	if _, err := txn.ExecContext(ctx, ms.GenerateCreateTableSQL(warehouseutils.DiscardsTable, schema)); err != nil {
+		return fmt.Errorf("creating discards table: %w", err)
+	}
+	if sqlStatement := ms.generateCreateClusteredIndexSQL(warehouseutils.DiscardsTable, schema); sqlStatement != "" {
+		if _, err := txn.ExecContext(ctx, sqlStatement); err != nil {
+			return fmt.Errorf("creating discards clustered index: %w", err)
+		}
+	}
+
+	sortedColumnKeys := warehouseutils.SortColumnKeysFromColumnMap(schema)
+
+	stmt, err := txn.PrepareContext(ctx, mssql.CopyIn(ms.quoteTable(warehouseutils.DiscardsTable), mssql.BulkOptions{}, sortedColumnKeys...))
+	if err != nil {
+		return fmt.Errorf("preparing copyIn statement: %w", err)
+	}
+
+	now := time.Now().UTC()
+	for _, row := range rows {
+		discard := map[string]any{
+			"table_name":   tableName,
+			"row_id":       fmt.Sprintf("%s:%d", filepath.Base(row.fileName), row.line),
+			"column_name":  "",
+			"column_value": truncateRunes(strings.Join(row.record, string(ms.config.csvDialect.delimiter)), stringLengthLimit),
+			"received_at":  now,
+			"uuid_ts":      now,
+			"reason":       row.reason,
+			"is_null":      false,
+		}
+		if _, err = stmt.ExecContext(ctx, lo.Map(sortedColumnKeys, func(column string, _ int) any {
+			return discard[column]
+		})...); err != nil {
+			return fmt.Errorf("exec statement error: %w", err)
+		}
+	}
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("executing copyIn statement: %w", err)
+	}
+	return nil
+}
+
+// truncateRunes truncates the value to at most limit bytes, without splitting a multi-byte character
+func truncateRunes(value string, limit int) string {
+	if len(value) <= limit {
+		return value
+	}
+	for limit > 0 && !utf8.RuneStart(value[limit]) {
+		limit--
+	}
+	return value[:limit]
+}
@@ -0,0 +1,119 @@
+package mssql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+type recordingStagingWriter struct {
+	rows [][]interface{}
+}
+
+func (w *recordingStagingWriter) write(_ context.Context, values []interface{}) error {
+	w.rows = append(w.rows, values)
+	return nil
+}
+
+func (*recordingStagingWriter) flush(context.Context) error {
+	return nil
+}
+
+func TestBadRowsThreshold(t *testing.T) {
+	testCases := []struct {
+		name      string
+		threshold badRowsThreshold
+		badRows   int
+		totalRows int
+		exceeded  bool
+	}{
+		{name: "no bad rows", threshold: badRowsThreshold{}, totalRows: 10},
+		{name: "disabled", threshold: badRowsThreshold{}, badRows: 1, totalRows: 10, exceeded: true},
+		{name: "within rows", threshold: badRowsThreshold{rows: 2}, badRows: 2, totalRows: 10},
+		{name: "above rows", threshold: badRowsThreshold{rows: 2}, badRows: 3, totalRows: 10, exceeded: true},
+		{name: "within percent", threshold: badRowsThreshold{percent: 20}, badRows: 2, totalRows: 10},
+		{name: "above percent", threshold: badRowsThreshold{percent: 20}, badRows: 3, totalRows: 10, exceeded: true},
+		{name: "above rows within percent", threshold: badRowsThreshold{rows: 1, percent: 50}, badRows: 3, totalRows: 10},
+		{name: "above both", threshold: badRowsThreshold{rows: 1, percent: 10}, badRows: 3, totalRows: 10, exceeded: true},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.exceeded, tc.threshold.exceeded(tc.badRows, tc.totalRows))
+		})
+	}
+
+	t.Run("exceeded early", func(t *testing.T) {
+		require.True(t, badRowsThreshold{rows: 1}.exceededEarly(2))
+		require.False(t, badRowsThreshold{rows: 1}.exceededEarly(1))
+		require.False(t, badRowsThreshold{rows: 1, percent: 50}.exceededEarly(2), "the percentage is only known once all the rows are read")
+		require.False(t, badRowsThreshold{}.exceededEarly(2))
+	})
+}
+
+func TestLoadDataIntoStagingTableBadRows(t *testing.T) {
+	schema := model.TableSchema{
+		"id":   model.StringDataType,
+		"name": model.StringDataType,
+	}
+	sortedColumnKeys := []string{"id", "name"}
+
+	load := func(t *testing.T, threshold badRowsThreshold, content string) (*recordingStagingWriter, *badRows, error) {
+		t.Helper()
+
+		ms := New(config.New(), logger.NOP, stats.Default)
+		w := &recordingStagingWriter{}
+		bad := &badRows{threshold: threshold}
+		err := ms.loadDataIntoStagingTable(context.Background(), logger.NOP, w,
+			[]string{writeGzipFile(t, "1.csv.gz", content)}, sortedColumnKeys,
+			schema, rowsOptions{}, bad,
+		)
+		return w, bad, err
+	}
+
+	t.Run("no tolerance", func(t *testing.T) {
+		_, _, err := load(t, badRowsThreshold{}, "1,a\n2\n3,c\n")
+		require.ErrorContains(t, err, "mismatch in number of columns")
+		require.NotErrorIs(t, err, ErrBadRowsThresholdExceeded)
+	})
+
+	t.Run("tolerated", func(t *testing.T) {
+		w, bad, err := load(t, badRowsThreshold{rows: 1}, "1,a\n2\n3,c\n")
+		require.NoError(t, err)
+		require.Equal(t, [][]interface{}{{"1", "a"}, {"3", "c"}}, w.rows)
+		require.Len(t, bad.rows, 1)
+		require.Equal(t, 2, bad.rows[0].line)
+		require.Equal(t, []string{"2"}, bad.rows[0].record)
+		require.Equal(t, "column count mismatch", bad.rows[0].reason)
+		require.Equal(t, 3, bad.totalRows)
+	})
+
+	t.Run("rows threshold exceeded", func(t *testing.T) {
+		_, _, err := load(t, badRowsThreshold{rows: 1}, "1,a\n2\n3,c,c\n4,d\n")
+		require.ErrorIs(t, err, ErrBadRowsThresholdExceeded)
+	})
+
+	t.Run("percent threshold exceeded", func(t *testing.T) {
+		_, bad, err := load(t, badRowsThreshold{percent: 25}, "1,a\n2\n3,c,c\n4,d\n")
+		require.ErrorIs(t, err, ErrBadRowsThresholdExceeded)
+		require.EqualError(t, err, "bad rows threshold exceeded: 2 bad rows out of 4 rows")
+		require.Len(t, bad.rows, 2)
+	})
+}
+
+func TestTruncateRunes(t *testing.T) {
+	require.Equal(t, "hello", truncateRunes("hello", 10))
+	require.Equal(t, "hel", truncateRunes("hello", 3))
+	require.Equal(t, "aé", truncateRunes("aéb", 3))
+	require.Equal(t, "a", truncateRunes("aéb", 2), "the limit falls in the middle of é")
+	require.Equal(t, "", truncateRunes("日本", 2))
+	require.Equal(t, "日", truncateRunes("日本", 5))
+}
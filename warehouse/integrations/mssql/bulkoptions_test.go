@@ -0,0 +1,67 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+)
+
+func TestStagingBulkOptions(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		conf                 map[string]any
+		columns              int
+		expectedRowsPerBatch int
+		expectedKBPerBatch   int
+	}{
+		{
+			name:                 "narrow table",
+			columns:              10,
+			expectedRowsPerBatch: 210,
+		},
+		{
+			name:                 "wide table",
+			columns:              1000,
+			expectedRowsPerBatch: 2,
+		},
+		{
+			name:                 "wider than the parameters limit",
+			columns:              3000,
+			expectedRowsPerBatch: 1,
+		},
+		{
+			name:                 "configured parameters per batch",
+			conf:                 map[string]any{"Warehouse.mssql.stagingParametersPerBatch": 10000},
+			columns:              100,
+			expectedRowsPerBatch: 100,
+		},
+		{
+			name:                 "configured rows per batch",
+			conf:                 map[string]any{"Warehouse.mssql.stagingRowsPerBatch": 500, "Warehouse.mssql.stagingKilobytesPerBatch": 64},
+			columns:              1000,
+			expectedRowsPerBatch: 500,
+			expectedKBPerBatch:   64,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			c := config.New()
+			for k, v := range tc.conf {
+				c.Set(k, v)
+			}
+			ms := New(c, logger.NOP, stats.Default)
+
+			options := ms.stagingBulkOptions(tc.columns)
+			require.Equal(t, tc.expectedRowsPerBatch, options.RowsPerBatch)
+			require.Equal(t, tc.expectedKBPerBatch, options.KilobytesPerBatch)
+			require.False(t, options.CheckConstraints)
+		})
+	}
+}
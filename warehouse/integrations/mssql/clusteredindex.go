@@ -0,0 +1,58 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// clusteredIndexColumnSetting is the destination setting for the datetime column (e.g. received_at) the tables are clustered by when they are created
+const clusteredIndexColumnSetting = "clusteredIndexColumn"
+
+// clusteredIndexColumn returns the column the table is clustered by, or an empty string if the table is not clustered.
+// The column needs to be a datetime column of the schema of the table, otherwise the table is created without a clustered index.
+func (ms *MSSQL) clusteredIndexColumn(tableName string, schema model.TableSchema) string {
+	column := strings.TrimSpace(warehouseutils.GetConfigValue(clusteredIndexColumnSetting, ms.Warehouse))
+	if column == "" {
+		return ""
+	}
+
+	dataType, ok := schema[column]
+	if !ok {
+		ms.logger.Warnf("MSSQL: not clustering table %s of destination %s by %s, since it is not a column of the table", tableName, ms.Warehouse.Destination.ID, column)
+		return ""
+	}
+	if dataType != model.DateTimeDataType {
+		ms.logger.Warnf("MSSQL: not clustering table %s of destination %s by %s, since it is a %s column instead of a datetime one", tableName, ms.Warehouse.Destination.ID, column, dataType)
+		return ""
+	}
+	return column
+}
+
+// clusteredIndexName returns the name of the clustered index of the table on the column. Index names only need to be unique within the table.
+func clusteredIndexName(column string) string {
+	name := "cix_" + column
+	if len(name) > tableNameLimit {
+		name = name[:tableNameLimit]
+	}
+	return name
+}
+
+// generateCreateClusteredIndexSQL returns the statement creating the clustered index of the table, if the table is clustered and doesn't have a clustered index yet.
+func (ms *MSSQL) generateCreateClusteredIndexSQL(tableName string, schema model.TableSchema) string {
+	column := ms.clusteredIndexColumn(tableName, schema)
+	if column == "" {
+		return ""
+	}
+
+	name := ms.quoteTable(tableName)
+	return fmt.Sprintf(`IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE object_id = OBJECT_ID(%[1]s) AND type = 1)
+	CREATE CLUSTERED INDEX %[2]s ON %[3]s ( %[4]s )`,
+		quoteString(name),
+		ms.quoteIdentifier(clusteredIndexName(column)),
+		name,
+		ms.quoteIdentifier(column),
+	)
+}
@@ -0,0 +1,83 @@
+package mssql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestClusteredIndex(t *testing.T) {
+	newMSSQL := func(destConfig map[string]any) *MSSQL {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		ms.Namespace = "namespace"
+		ms.Warehouse = model.Warehouse{
+			Destination: backendconfig.DestinationT{
+				ID:     "test_destination_id",
+				Config: destConfig,
+			},
+		}
+		return ms
+	}
+
+	schema := model.TableSchema{
+		"id":          model.StringDataType,
+		"received_at": model.DateTimeDataType,
+	}
+
+	t.Run("clustered index column", func(t *testing.T) {
+		testCases := []struct {
+			name           string
+			destConfig     map[string]any
+			expectedColumn string
+		}{
+			{
+				name:       "disabled",
+				destConfig: map[string]any{},
+			},
+			{
+				name:           "datetime column",
+				destConfig:     map[string]any{clusteredIndexColumnSetting: " received_at "},
+				expectedColumn: "received_at",
+			},
+			{
+				name:       "not a column of the table",
+				destConfig: map[string]any{clusteredIndexColumnSetting: "sent_at"},
+			},
+			{
+				name:       "not a datetime column",
+				destConfig: map[string]any{clusteredIndexColumnSetting: "id"},
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expectedColumn, newMSSQL(tc.destConfig).clusteredIndexColumn("tracks", schema))
+			})
+		}
+	})
+
+	t.Run("create clustered index", func(t *testing.T) {
+		ms := newMSSQL(map[string]any{clusteredIndexColumnSetting: "received_at"})
+
+		require.Equal(t, `IF NOT EXISTS (SELECT 1 FROM sys.indexes WHERE object_id = OBJECT_ID(N'"namespace"."tracks"') AND type = 1)
+	CREATE CLUSTERED INDEX "cix_received_at" ON "namespace"."tracks" ( "received_at" )`, ms.generateCreateClusteredIndexSQL("tracks", schema))
+	})
+
+	t.Run("not clustered", func(t *testing.T) {
+		require.Empty(t, newMSSQL(map[string]any{}).generateCreateClusteredIndexSQL("tracks", schema))
+		require.Empty(t, newMSSQL(map[string]any{clusteredIndexColumnSetting: "sent_at"}).generateCreateClusteredIndexSQL("tracks", schema))
+	})
+
+	t.Run("index name limit", func(t *testing.T) {
+		require.Equal(t, "cix_received_at", clusteredIndexName("received_at"))
+		require.Len(t, clusteredIndexName(strings.Repeat("a", 200)), tableNameLimit)
+	})
+}
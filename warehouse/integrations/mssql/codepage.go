@@ -0,0 +1,86 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// utf8CodePage is the code page of the UTF-8 collations (SQL Server 2019 onwards)
+const utf8CodePage = 65001
+
+// codePages are the code pages of the non-unicode collations the strings can be encoded with
+var codePages = map[int]*charmap.Charmap{
+	437:  charmap.CodePage437,
+	850:  charmap.CodePage850,
+	874:  charmap.Windows874,
+	1250: charmap.Windows1250,
+	1251: charmap.Windows1251,
+	1252: charmap.Windows1252,
+	1253: charmap.Windows1253,
+	1254: charmap.Windows1254,
+	1255: charmap.Windows1255,
+	1256: charmap.Windows1256,
+	1257: charmap.Windows1257,
+	1258: charmap.Windows1258,
+}
+
+// nonUnicodeColumnsSQL returns the non-unicode (char, varchar and text) columns of the table, with the code page of their collation
+const nonUnicodeColumnsSQL = `SELECT c.name, COLLATIONPROPERTY(c.collation_name, 'CodePage')
+	FROM sys.columns c JOIN sys.types t ON c.user_type_id = t.user_type_id
+	WHERE c.object_id = OBJECT_ID(@table) AND t.name IN ('char', 'varchar', 'text')`
+
+// nonUnicodeColumns returns the code pages of the non-unicode columns of the table, keyed by column.
+// The strings of these columns are sent as bytes of their code page, instead of the UTF-16 the nvarchar columns expect.
+func (ms *MSSQL) nonUnicodeColumns(ctx context.Context, tableName string) (map[string]int, error) {
+	rows, err := ms.DB.QueryContext(ctx, nonUnicodeColumnsSQL, sql.Named("table", ms.quoteTable(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("querying non-unicode columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	columns := make(map[string]int)
+	for rows.Next() {
+		var (
+			column   string
+			codePage sql.NullInt64
+		)
+		if err := rows.Scan(&column, &codePage); err != nil {
+			return nil, fmt.Errorf("scanning non-unicode columns: %w", err)
+		}
+		if _, ok := codePages[int(codePage.Int64)]; !ok && codePage.Int64 != utf8CodePage {
+			ms.logger.Warnf("MSSQL: encoding the strings of column %s of table %s as unicode, since its code page %d is not supported", column, tableName, codePage.Int64)
+			continue
+		}
+		columns[column] = int(codePage.Int64)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating non-unicode columns: %w", err)
+	}
+	return columns, nil
+}
+
+// encodeNonUnicode encodes the string for a non-unicode column with the code page of its collation.
+// Characters which can't be represented in the code page are replaced by '?', the same as SQL Server does.
+// Strings for UTF-8 collations are sent as they are.
+func encodeNonUnicode(value string, codePage int) (interface{}, error) {
+	if codePage == utf8CodePage {
+		return value, nil
+	}
+	cm, ok := codePages[codePage]
+	if !ok {
+		return nil, fmt.Errorf("unsupported code page %d", codePage)
+	}
+
+	encoded := make([]byte, 0, len(value))
+	for _, r := range value {
+		b, ok := cm.EncodeRune(r)
+		if !ok {
+			b = '?'
+		}
+		encoded = append(encoded, b)
+	}
+	return encoded, nil
+}
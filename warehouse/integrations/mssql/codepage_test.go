@@ -0,0 +1,58 @@
+package mssql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestEncodeNonUnicode(t *testing.T) {
+	t.Run("windows-1252", func(t *testing.T) {
+		encoded, err := encodeNonUnicode("tést", 1252)
+		require.NoError(t, err)
+		require.Equal(t, []byte{0x74, 0xe9, 0x73, 0x74}, encoded)
+	})
+
+	t.Run("unsupported characters", func(t *testing.T) {
+		encoded, err := encodeNonUnicode("t€st 日本", 1252)
+		require.NoError(t, err)
+		require.Equal(t, []byte("t\x80st ??"), encoded)
+	})
+
+	t.Run("utf-8", func(t *testing.T) {
+		encoded, err := encodeNonUnicode("tést", utf8CodePage)
+		require.NoError(t, err)
+		require.Equal(t, "tést", encoded)
+	})
+
+	t.Run("unsupported code page", func(t *testing.T) {
+		_, err := encodeNonUnicode("tést", 932)
+		require.EqualError(t, err, "unsupported code page 932")
+	})
+}
+
+func TestReadRowsNonUnicode(t *testing.T) {
+	schema := model.TableSchema{
+		"name":     model.StringDataType,
+		"nickname": model.StringDataType,
+		"count":    model.IntDataType,
+	}
+
+	ms := New(config.New(), logger.NOP, stats.Default)
+	rows := ms.readRows(context.Background(), []string{writeGzipFile(t, "1.csv.gz", "1,tést,tést\n")}, schema, rowsOptions{
+		nonUnicode: map[string]int{"name": 1252, "count": 1252},
+	})
+	defer func() { _ = rows.Close() }()
+
+	require.True(t, rows.Next())
+	require.Equal(t, []interface{}{1, []byte{0x74, 0xe9, 0x73, 0x74}, []byte{0x74, 0x0, 0xe9, 0x0, 0x73, 0x0, 0x74, 0x0}}, rows.Row().Values)
+	require.False(t, rows.Next())
+	require.NoError(t, rows.Err())
+}
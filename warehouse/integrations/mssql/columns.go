@@ -0,0 +1,66 @@
+package mssql
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/rudderlabs/rudder-server/warehouse/integrations/types"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+// caseInsensitiveCollisions returns the groups of columns which only differ by case.
+// With a case-insensitive collation (the default), MSSQL treats them as the same column.
+// Both the groups and the columns within them are sorted.
+func caseInsensitiveCollisions(schema model.TableSchema) [][]string {
+	groups := lo.GroupBy(lo.Keys(schema), strings.ToLower)
+
+	var collisions [][]string
+	for _, columns := range groups {
+		if len(columns) < 2 {
+			continue
+		}
+		sort.Strings(columns)
+		collisions = append(collisions, columns)
+	}
+	sort.Slice(collisions, func(i, j int) bool {
+		return collisions[i][0] < collisions[j][0]
+	})
+	return collisions
+}
+
+// validateColumnNames returns an error identifying the columns of the table which collide case-insensitively
+func validateColumnNames(tableName string, schema model.TableSchema) error {
+	collisions := caseInsensitiveCollisions(schema)
+	if len(collisions) == 0 {
+		return nil
+	}
+
+	formatted := lo.Map(collisions, func(columns []string, _ int) string {
+		return "(" + strings.Join(columns, ", ") + ")"
+	})
+	return fmt.Errorf("columns of table %s differing only by case: %s", tableName, strings.Join(formatted, ", "))
+}
+
+// schemaMismatches returns the columns of the upload whose data type differs from the one in the warehouse, sorted by column.
+// Columns missing from the warehouse aren't mismatches, since they are added before loading.
+func schemaMismatches(schemaInUpload, schemaInWarehouse model.TableSchema) []types.SchemaMismatch {
+	var mismatches []types.SchemaMismatch
+	for column, uploadDataType := range schemaInUpload {
+		warehouseDataType, ok := schemaInWarehouse[column]
+		if !ok || warehouseDataType == uploadDataType {
+			continue
+		}
+		mismatches = append(mismatches, types.SchemaMismatch{
+			Column:            column,
+			UploadDataType:    uploadDataType,
+			WarehouseDataType: warehouseDataType,
+		})
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Column < mismatches[j].Column
+	})
+	return mismatches
+}
@@ -0,0 +1,55 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-server/warehouse/integrations/types"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestCaseInsensitiveCollisions(t *testing.T) {
+	t.Run("no collisions", func(t *testing.T) {
+		schema := model.TableSchema{"id": "string", "name": "string", "received_at": "datetime"}
+		require.Empty(t, caseInsensitiveCollisions(schema))
+		require.NoError(t, validateColumnNames("test_table", schema))
+	})
+
+	t.Run("collisions", func(t *testing.T) {
+		schema := model.TableSchema{
+			"id":          "string",
+			"Id":          "string",
+			"ID":          "int",
+			"name":        "string",
+			"Received_At": "datetime",
+			"received_at": "datetime",
+		}
+		require.Equal(t, [][]string{{"ID", "Id", "id"}, {"Received_At", "received_at"}}, caseInsensitiveCollisions(schema))
+		require.EqualError(t, validateColumnNames("test_table", schema), "columns of table test_table differing only by case: (ID, Id, id), (Received_At, received_at)")
+	})
+}
+
+func TestSchemaMismatches(t *testing.T) {
+	schemaInUpload := model.TableSchema{
+		"id":          "string",
+		"received_at": "datetime",
+		"test_int":    "int",
+		"test_float":  "float",
+		"test_new":    "string",
+	}
+	schemaInWarehouse := model.TableSchema{
+		"id":          "string",
+		"received_at": "datetime",
+		"test_int":    "string",
+		"test_float":  "int",
+		"test_other":  "boolean",
+	}
+
+	require.Equal(t, []types.SchemaMismatch{
+		{Column: "test_float", UploadDataType: "float", WarehouseDataType: "int"},
+		{Column: "test_int", UploadDataType: "int", WarehouseDataType: "string"},
+	}, schemaMismatches(schemaInUpload, schemaInWarehouse))
+	require.Empty(t, schemaMismatches(schemaInUpload, schemaInUpload))
+	require.Empty(t, schemaMismatches(schemaInUpload, nil))
+}
@@ -0,0 +1,82 @@
+package mssql
+
+import (
+	"encoding/csv"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// csvDialect describes how the load files are encoded.
+// encoding/csv always uses '"' as the quote character, so the quote character itself is not configurable.
+type csvDialect struct {
+	delimiter  rune
+	nullToken  string
+	hasHeader  bool
+	lazyQuotes bool
+}
+
+var defaultCSVDialect = csvDialect{
+	delimiter: ',',
+}
+
+// parseDelimiter returns the delimiter rune for the configured value, supporting escaped tabs (e.g. `\t`).
+func parseDelimiter(delimiter string) (rune, bool) {
+	switch delimiter {
+	case `\t`, "tab":
+		return '\t', true
+	case "":
+		return defaultCSVDialect.delimiter, true
+	}
+
+	r, size := utf8.DecodeRuneInString(delimiter)
+	if size != len(delimiter) || !validDelimiter(r) {
+		return 0, false
+	}
+	return r, true
+}
+
+// validDelimiter mirrors the validation done by encoding/csv
+func validDelimiter(r rune) bool {
+	return r != 0 && r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+}
+
+func (d csvDialect) newReader(r io.Reader) *csv.Reader {
+	csvReader := csv.NewReader(r)
+	csvReader.Comma = d.delimiter
+	csvReader.LazyQuotes = d.lazyQuotes
+	// the number of fields is validated against the schema of the upload while loading, so that the records with a wrong number of fields can be told apart as bad rows
+	csvReader.FieldsPerRecord = -1
+	return csvReader
+}
+
+// rawLine returns the record the way it is written in a load file with the dialect, e.g. to point to it in the errors
+func (d csvDialect) rawLine(record []string) string {
+	var sb strings.Builder
+	w := csv.NewWriter(&sb)
+	w.Comma = d.delimiter
+	_ = w.Write(record)
+	w.Flush()
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// isNull returns true if the value read from the load file represents a NULL
+func (d csvDialect) isNull(value string) bool {
+	if strings.TrimSpace(value) == "" {
+		return true
+	}
+	return d.nullToken != "" && value == d.nullToken
+}
+
+// values converts the record into values, replacing the null values with nil
+func (d csvDialect) values(record []string) []interface{} {
+	values := make([]interface{}, 0, len(record))
+	for _, value := range record {
+		if d.isNull(value) {
+			values = append(values, nil)
+		} else {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,82 @@
+package mssql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestCSVDialect(t *testing.T) {
+	t.Run("delimiter", func(t *testing.T) {
+		testCases := []struct {
+			delimiter string
+			expected  rune
+			valid     bool
+		}{
+			{delimiter: "", expected: ',', valid: true},
+			{delimiter: ",", expected: ',', valid: true},
+			{delimiter: `\t`, expected: '\t', valid: true},
+			{delimiter: "tab", expected: '\t', valid: true},
+			{delimiter: "|", expected: '|', valid: true},
+			{delimiter: `"`, valid: false},
+			{delimiter: "\n", valid: false},
+			{delimiter: "||", valid: false},
+		}
+		for _, tc := range testCases {
+			r, ok := parseDelimiter(tc.delimiter)
+			require.Equal(t, tc.valid, ok, tc.delimiter)
+			require.Equal(t, tc.expected, r, tc.delimiter)
+		}
+	})
+
+	t.Run("default dialect", func(t *testing.T) {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		require.Equal(t, defaultCSVDialect, ms.config.csvDialect)
+
+		record, err := ms.config.csvDialect.newReader(strings.NewReader(`1,"a ""quoted"", value", ,2020-01-01T00:00:00Z` + "\n")).Read()
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"1", `a "quoted", value`, nil, "2020-01-01T00:00:00Z"}, ms.config.csvDialect.values(record))
+	})
+
+	t.Run("tab separated with null token", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.mssql.loadFile.delimiter", `\t`)
+		c.Set("Warehouse.mssql.loadFile.nullToken", `\N`)
+
+		ms := New(c, logger.NOP, stats.Default)
+		require.Equal(t, '\t', ms.config.csvDialect.delimiter)
+
+		record, err := ms.config.csvDialect.newReader(strings.NewReader("1\t\"tab\tseparated\"\t\\N\ttrue\n")).Read()
+		require.NoError(t, err)
+
+		values := ms.config.csvDialect.values(record)
+		require.Equal(t, []interface{}{"1", "tab\tseparated", nil, "true"}, values)
+
+		dataTypes := []string{model.IntDataType, model.StringDataType, model.DateTimeDataType, model.BooleanDataType}
+		expected := []interface{}{int64(1), "tab\tseparated", nil, true}
+		for i, value := range values {
+			if value == nil {
+				require.Nil(t, expected[i])
+				continue
+			}
+			processedValue, err := ms.ProcessColumnValue(value.(string), dataTypes[i])
+			require.NoError(t, err)
+			require.EqualValues(t, expected[i], processedValue)
+		}
+	})
+
+	t.Run("invalid delimiter falls back to default", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.mssql.loadFile.delimiter", `"`)
+
+		ms := New(c, logger.NOP, stats.Default)
+		require.Equal(t, defaultCSVDialect.delimiter, ms.config.csvDialect.delimiter)
+	})
+}
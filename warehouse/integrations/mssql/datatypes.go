@@ -0,0 +1,133 @@
+package mssql
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// dataTypeOverridesSetting is the destination setting overriding the column types used for the rudder data types.
+// It is a JSON object of rudder data type to MSSQL column type, e.g. {"string":"nvarchar(max)"}
+const dataTypeOverridesSetting = "dataTypeOverrides"
+
+// datetimeTypeSetting is the destination setting for the column type used for datetimes: datetime, datetime2(n) or datetimeoffset(n)
+const datetimeTypeSetting = "datetimeType"
+
+// defaultDatetimeType keeps microseconds, which is the precision of the events
+const defaultDatetimeType = "datetime2(6)"
+
+var datetimeTypeRegexp = regexp.MustCompile(`^(datetime2|datetimeoffset)(?:\(([0-7])\))?$`)
+
+// datetimeType describes the column type used for datetimes, and therefore how the datetime values are converted before being loaded
+type datetimeType struct {
+	columnType string
+	precision  int // number of digits of the fractional seconds
+	withOffset bool
+}
+
+// parseDatetimeType parses the supported datetime column types. The precision defaults to 7 for datetime2 and datetimeoffset, same as MSSQL.
+// The legacy datetime type is rounded by MSSQL to increments of .000, .003 or .007 seconds, so only milliseconds are kept.
+func parseDatetimeType(columnType string) (datetimeType, bool) {
+	if columnType == "datetime" {
+		return datetimeType{columnType: columnType, precision: 3}, true
+	}
+
+	matches := datetimeTypeRegexp.FindStringSubmatch(columnType)
+	if matches == nil {
+		return datetimeType{}, false
+	}
+
+	precision := 7
+	if matches[2] != "" {
+		precision, _ = strconv.Atoi(matches[2])
+	}
+	return datetimeType{
+		columnType: columnType,
+		precision:  precision,
+		withOffset: matches[1] == "datetimeoffset",
+	}, true
+}
+
+// convert converts the datetime to be loaded into the column type. Types without an offset store the datetime in UTC.
+func (d datetimeType) convert(t time.Time) time.Time {
+	if !d.withOffset {
+		t = t.UTC()
+	}
+	return t.Truncate(time.Duration(math.Pow10(9 - d.precision)))
+}
+
+// resetDataTypes sets the column types back to the defaults, which aren't specific to any destination
+func (ms *MSSQL) resetDataTypes() {
+	ms.config.datetimeType = ms.config.defaultDatetimeType
+	ms.dataTypesMap = lo.Assign(ms.config.defaultDataTypesMap)
+}
+
+// applyDataTypes sets the column types to the ones configured for the destination, starting from the defaults,
+// so that the column types configured for a destination don't carry over to the next one set up with the same instance
+func (ms *MSSQL) applyDataTypes() {
+	ms.resetDataTypes()
+	ms.applyDatetimeType()
+	ms.applyDataTypeOverrides()
+}
+
+// applyDatetimeType sets the column type used for datetimes to the one configured for the destination
+func (ms *MSSQL) applyDatetimeType() {
+	value := warehouseutils.GetConfigValue(datetimeTypeSetting, ms.Warehouse)
+	if value == "" {
+		return
+	}
+
+	dtType, ok := parseDatetimeType(value)
+	if !ok {
+		ms.logger.Warnf("MSSQL: invalid datetime type %q for destination %s, using %q", value, ms.Warehouse.Destination.ID, ms.config.datetimeType.columnType)
+		return
+	}
+	ms.config.datetimeType = dtType
+	ms.dataTypesMap = lo.Assign(ms.dataTypesMap, map[string]string{
+		model.DateTimeDataType: dtType.columnType,
+	})
+}
+
+// DataTypesMapping returns the MSSQL column type used by CreateTable for every rudder data type
+func (ms *MSSQL) DataTypesMapping() map[string]string {
+	return lo.Assign(ms.dataTypesMap)
+}
+
+// applyDataTypeOverrides overrides the column types with the ones configured for the destination.
+// Overrides for unknown rudder data types are ignored, so that only the supported data types can be mapped.
+func (ms *MSSQL) applyDataTypeOverrides() {
+	value := warehouseutils.GetConfigValue(dataTypeOverridesSetting, ms.Warehouse)
+	if value == "" {
+		return
+	}
+
+	var overrides map[string]string
+	if err := json.Unmarshal([]byte(value), &overrides); err != nil {
+		ms.logger.Warnf("MSSQL: invalid data type overrides %q for destination %s: %v", value, ms.Warehouse.Destination.ID, err)
+		return
+	}
+
+	dataTypesMap := lo.Assign(ms.dataTypesMap)
+	for dataType, columnType := range overrides {
+		if _, ok := dataTypesMap[dataType]; !ok || columnType == "" {
+			ms.logger.Warnf("MSSQL: ignoring data type override %q: %q for destination %s", dataType, columnType, ms.Warehouse.Destination.ID)
+			continue
+		}
+		dataTypesMap[dataType] = columnType
+	}
+	ms.dataTypesMap = dataTypesMap
+
+	// datetimes are converted according to the overridden type, or passed as they are if the type isn't a supported datetime type
+	if dtType, ok := parseDatetimeType(dataTypesMap[model.DateTimeDataType]); ok {
+		ms.config.datetimeType = dtType
+	} else {
+		ms.config.datetimeType = datetimeType{columnType: dataTypesMap[model.DateTimeDataType], precision: 9, withOffset: true}
+	}
+}
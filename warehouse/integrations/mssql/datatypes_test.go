@@ -0,0 +1,224 @@
+package mssql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestDataTypesMapping(t *testing.T) {
+	t.Run("every data type has a mapping", func(t *testing.T) {
+		ms := New(config.New(), logger.NOP, stats.Default)
+
+		mapping := ms.DataTypesMapping()
+		for _, dataType := range []string{
+			model.StringDataType,
+			model.BooleanDataType,
+			model.IntDataType,
+			model.FloatDataType,
+			model.DecimalDataType,
+			model.JSONDataType,
+			model.DateTimeDataType,
+		} {
+			require.NotEmpty(t, mapping[dataType], dataType)
+		}
+		require.Equal(t, "nvarchar(512)", mapping[model.StringDataType])
+		require.Equal(t, "datetime2(6)", mapping[model.DateTimeDataType])
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		ms := New(config.New(), logger.NOP, stats.Default)
+
+		mapping := ms.DataTypesMapping()
+		mapping[model.StringDataType] = "nvarchar(max)"
+		require.Equal(t, "nvarchar(512)", ms.DataTypesMapping()[model.StringDataType])
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		testCases := []struct {
+			name      string
+			overrides string
+			expected  map[string]string
+		}{
+			{
+				name:      "no overrides",
+				overrides: "",
+				expected:  map[string]string{model.StringDataType: "nvarchar(512)", model.JSONDataType: "jsonb"},
+			},
+			{
+				name:      "valid overrides",
+				overrides: `{"string":"nvarchar(max)","json":"nvarchar(max)"}`,
+				expected:  map[string]string{model.StringDataType: "nvarchar(max)", model.JSONDataType: "nvarchar(max)"},
+			},
+			{
+				name:      "unknown data types are ignored",
+				overrides: `{"unknown":"nvarchar(max)","string":""}`,
+				expected:  map[string]string{model.StringDataType: "nvarchar(512)", "unknown": ""},
+			},
+			{
+				name:      "invalid overrides",
+				overrides: `{"string":`,
+				expected:  map[string]string{model.StringDataType: "nvarchar(512)"},
+			},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				ms := New(config.New(), logger.NOP, stats.Default)
+				ms.Warehouse = model.Warehouse{
+					Destination: backendconfig.DestinationT{
+						Config: map[string]interface{}{
+							dataTypeOverridesSetting: tc.overrides,
+						},
+					},
+				}
+				ms.applyDataTypes()
+
+				mapping := ms.DataTypesMapping()
+				for dataType, columnType := range tc.expected {
+					require.Equal(t, columnType, mapping[dataType], dataType)
+				}
+			})
+		}
+	})
+
+	t.Run("overrides don't carry over to the next destination", func(t *testing.T) {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		ms.Warehouse = model.Warehouse{
+			Destination: backendconfig.DestinationT{
+				Config: map[string]interface{}{
+					dataTypeOverridesSetting: `{"string":"nvarchar(max)","datetime":"datetimeoffset(3)"}`,
+				},
+			},
+		}
+		ms.applyDataTypes()
+		require.Equal(t, "nvarchar(max)", ms.DataTypesMapping()[model.StringDataType])
+		require.Equal(t, "datetimeoffset(3)", ms.DataTypesMapping()[model.DateTimeDataType])
+		require.True(t, ms.config.datetimeType.withOffset)
+
+		ms.Warehouse = model.Warehouse{}
+		ms.applyDataTypes()
+		require.Equal(t, "nvarchar(512)", ms.DataTypesMapping()[model.StringDataType])
+		require.Equal(t, "datetime2(6)", ms.DataTypesMapping()[model.DateTimeDataType])
+		require.Equal(t, datetimeType{columnType: "datetime2(6)", precision: 6}, ms.config.datetimeType)
+		require.Equal(t, "nvarchar(512)", rudderDataTypesMapToMssql[model.StringDataType])
+	})
+
+	t.Run("datetime type", func(t *testing.T) {
+		testCases := []struct {
+			columnType string
+			expected   datetimeType
+			valid      bool
+		}{
+			{columnType: "datetime", expected: datetimeType{columnType: "datetime", precision: 3}, valid: true},
+			{columnType: "datetime2", expected: datetimeType{columnType: "datetime2", precision: 7}, valid: true},
+			{columnType: "datetime2(6)", expected: datetimeType{columnType: "datetime2(6)", precision: 6}, valid: true},
+			{columnType: "datetime2(0)", expected: datetimeType{columnType: "datetime2(0)", precision: 0}, valid: true},
+			{columnType: "datetimeoffset", expected: datetimeType{columnType: "datetimeoffset", precision: 7, withOffset: true}, valid: true},
+			{columnType: "datetimeoffset(3)", expected: datetimeType{columnType: "datetimeoffset(3)", precision: 3, withOffset: true}, valid: true},
+			{columnType: "datetime2(8)", valid: false},
+			{columnType: "date", valid: false},
+			{columnType: "", valid: false},
+		}
+		for _, tc := range testCases {
+			dtType, ok := parseDatetimeType(tc.columnType)
+			require.Equal(t, tc.valid, ok, tc.columnType)
+			require.Equal(t, tc.expected, dtType, tc.columnType)
+		}
+	})
+
+	t.Run("datetime type from config", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.mssql.datetimeType", "datetimeoffset(7)")
+
+		ms := New(c, logger.NOP, stats.Default)
+		require.Equal(t, "datetimeoffset(7)", ms.DataTypesMapping()[model.DateTimeDataType])
+
+		c.Set("Warehouse.mssql.datetimeType", "invalid")
+
+		ms = New(c, logger.NOP, stats.Default)
+		require.Equal(t, defaultDatetimeType, ms.DataTypesMapping()[model.DateTimeDataType])
+	})
+
+	t.Run("datetime type for destination", func(t *testing.T) {
+		testCases := []struct {
+			name         string
+			datetimeType string
+			overrides    string
+			expected     string
+			value        string
+			expectedTime time.Time
+		}{
+			{
+				name:         "default keeps microseconds in UTC",
+				expected:     "datetime2(6)",
+				value:        "2020-01-01T05:30:00.123456789+05:30",
+				expectedTime: time.Date(2020, time.January, 1, 0, 0, 0, 123456000, time.UTC),
+			},
+			{
+				name:         "datetime keeps milliseconds",
+				datetimeType: "datetime",
+				expected:     "datetime",
+				value:        "2020-01-01T00:00:00.123456Z",
+				expectedTime: time.Date(2020, time.January, 1, 0, 0, 0, 123000000, time.UTC),
+			},
+			{
+				name:         "datetimeoffset keeps the offset",
+				datetimeType: "datetimeoffset(7)",
+				expected:     "datetimeoffset(7)",
+				value:        "2020-01-01T05:30:00.123456789+05:30",
+				expectedTime: time.Date(2020, time.January, 1, 5, 30, 0, 123456700, time.FixedZone("", 5*60*60+30*60)),
+			},
+			{
+				name:         "invalid datetime type",
+				datetimeType: "date",
+				expected:     "datetime2(6)",
+				value:        "2020-01-01T00:00:00.123456789Z",
+				expectedTime: time.Date(2020, time.January, 1, 0, 0, 0, 123456000, time.UTC),
+			},
+			{
+				name:         "data type overrides take precedence",
+				datetimeType: "datetime",
+				overrides:    `{"datetime":"datetime2(3)"}`,
+				expected:     "datetime2(3)",
+				value:        "2020-01-01T00:00:00.123456789Z",
+				expectedTime: time.Date(2020, time.January, 1, 0, 0, 0, 123000000, time.UTC),
+			},
+		}
+
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				ms := New(config.New(), logger.NOP, stats.Default)
+				ms.Warehouse = model.Warehouse{
+					Destination: backendconfig.DestinationT{
+						Config: map[string]interface{}{
+							datetimeTypeSetting:      tc.datetimeType,
+							dataTypeOverridesSetting: tc.overrides,
+						},
+					},
+				}
+				ms.applyDataTypes()
+				require.Equal(t, tc.expected, ms.DataTypesMapping()[model.DateTimeDataType])
+
+				value, err := ms.ProcessColumnValue(tc.value, model.DateTimeDataType)
+				require.NoError(t, err)
+				require.True(t, tc.expectedTime.Equal(value.(time.Time)), "expected %s, got %s", tc.expectedTime, value)
+				_, expectedOffset := tc.expectedTime.Zone()
+				_, offset := value.(time.Time).Zone()
+				require.Equal(t, expectedOffset, offset)
+			})
+		}
+	})
+}
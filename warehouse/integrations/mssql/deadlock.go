@@ -0,0 +1,73 @@
+package mssql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+
+	"github.com/rudderlabs/rudder-go-kit/logger"
+
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/warehouse/logfield"
+)
+
+// deadlockErrorNumber is the number of the error returned to the transaction chosen as the deadlock victim
+const deadlockErrorNumber = 1205
+
+// validDeadlockPriority returns true for the priorities supported by SET DEADLOCK_PRIORITY, i.e. LOW, NORMAL, HIGH or an integer from -10 to 10
+func validDeadlockPriority(priority string) bool {
+	switch strings.ToUpper(priority) {
+	case "LOW", "NORMAL", "HIGH":
+		return true
+	}
+	n, err := strconv.Atoi(priority)
+	return err == nil && n >= -10 && n <= 10
+}
+
+// sessionSettingsStmt returns the statement applying the lock settings to the session of the load, or an empty string if there are none.
+// The settings don't leak into other loads, since the driver resets the session whenever a pooled connection is reused.
+func (ms *MSSQL) sessionSettingsStmt() string {
+	var settings []string
+	if ms.config.lockTimeout > 0 {
+		settings = append(settings, fmt.Sprintf(`SET LOCK_TIMEOUT %d;`, ms.config.lockTimeout.Milliseconds()))
+	}
+	if ms.config.deadlockPriority != "" {
+		settings = append(settings, fmt.Sprintf(`SET DEADLOCK_PRIORITY %s;`, strings.ToUpper(ms.config.deadlockPriority)))
+	}
+	return strings.Join(settings, " ")
+}
+
+func isDeadlock(err error) bool {
+	var mssqlErr mssql.Error
+	return errors.As(err, &mssqlErr) && mssqlErr.Number == deadlockErrorNumber
+}
+
+// retryOnDeadlock runs the operation, retrying it with an exponential backoff as long as it is chosen as a deadlock victim, up to the configured number of retries.
+// Every retry is taken from the retry budget of the upload, giving up once it is exhausted.
+func (ms *MSSQL) retryOnDeadlock(ctx context.Context, log logger.Logger, operation func() error) error {
+	backoff := ms.config.deadlockRetryBackoff
+	for attempt := 0; ; attempt++ {
+		err := operation()
+		if err == nil || !isDeadlock(err) || attempt >= ms.config.deadlockMaxRetries {
+			return err
+		}
+		if !ms.retryBudget.take() {
+			return fmt.Errorf("%w: %w", errRetryBudgetExhausted, err)
+		}
+
+		log.Warnw("chosen as deadlock victim, retrying",
+			"attempt", attempt+1,
+			"backoff", backoff,
+			"remainingRetryBudget", ms.retryBudget.remaining(),
+			logfield.Error, err.Error(),
+		)
+		if err := misc.SleepCtx(ctx, backoff); err != nil {
+			return fmt.Errorf("waiting to retry after deadlock: %w", err)
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,138 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	mssql "github.com/denisenkom/go-mssqldb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+)
+
+func TestSessionSettings(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		require.Empty(t, ms.sessionSettingsStmt())
+	})
+
+	t.Run("lock timeout and deadlock priority", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.mssql.lockTimeout", "5s")
+		c.Set("Warehouse.mssql.deadlockPriority", "low")
+
+		ms := New(c, logger.NOP, stats.Default)
+		require.Equal(t, `SET LOCK_TIMEOUT 5000; SET DEADLOCK_PRIORITY LOW;`, ms.sessionSettingsStmt())
+	})
+
+	t.Run("invalid deadlock priority", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.mssql.deadlockPriority", "11")
+
+		ms := New(c, logger.NOP, stats.Default)
+		require.Empty(t, ms.sessionSettingsStmt())
+	})
+
+	t.Run("deadlock priorities", func(t *testing.T) {
+		for _, priority := range []string{"LOW", "normal", "High", "-10", "0", "10"} {
+			require.True(t, validDeadlockPriority(priority), priority)
+		}
+		for _, priority := range []string{"", "medium", "-11", "11", "1.5"} {
+			require.False(t, validDeadlockPriority(priority), priority)
+		}
+	})
+}
+
+func TestRetryOnDeadlock(t *testing.T) {
+	deadlockErr := fmt.Errorf("merge into load table: %w", mssql.Error{Number: deadlockErrorNumber, Message: "Transaction was deadlocked"})
+
+	newMSSQL := func(maxRetries int) *MSSQL {
+		c := config.New()
+		c.Set("Warehouse.mssql.deadlockMaxRetries", maxRetries)
+		c.Set("Warehouse.mssql.deadlockRetryBackoff", "1ms")
+		return New(c, logger.NOP, stats.Default)
+	}
+
+	// contendedLoad simulates a load chosen as the deadlock victim the first deadlocks times
+	contendedLoad := func(deadlocks int, attempts *int) func() error {
+		return func() error {
+			*attempts++
+			if *attempts <= deadlocks {
+				return deadlockErr
+			}
+			return nil
+		}
+	}
+
+	t.Run("succeeds after deadlocks", func(t *testing.T) {
+		var attempts int
+		err := newMSSQL(3).retryOnDeadlock(context.Background(), logger.NOP, contendedLoad(2, &attempts))
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		var attempts int
+		err := newMSSQL(2).retryOnDeadlock(context.Background(), logger.NOP, contendedLoad(5, &attempts))
+		require.ErrorIs(t, err, deadlockErr)
+		require.Equal(t, 3, attempts)
+	})
+
+	t.Run("no retries", func(t *testing.T) {
+		var attempts int
+		err := newMSSQL(0).retryOnDeadlock(context.Background(), logger.NOP, contendedLoad(1, &attempts))
+		require.ErrorIs(t, err, deadlockErr)
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("retry budget exhausted", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.mssql.deadlockMaxRetries", 5)
+		c.Set("Warehouse.mssql.deadlockRetryBackoff", "1ms")
+		c.Set("Warehouse.mssql.uploadRetryBudget", 3)
+		ms := New(c, logger.NOP, stats.Default)
+
+		var attempts int
+		err := ms.retryOnDeadlock(context.Background(), logger.NOP, contendedLoad(2, &attempts))
+		require.NoError(t, err)
+		require.Equal(t, 3, attempts)
+
+		// the retries of the upload are shared by its operations
+		attempts = 0
+		err = ms.retryOnDeadlock(context.Background(), logger.NOP, contendedLoad(2, &attempts))
+		require.ErrorIs(t, err, errRetryBudgetExhausted)
+		require.ErrorIs(t, err, deadlockErr)
+		require.Equal(t, 2, attempts)
+		require.Zero(t, ms.retryBudget.remaining())
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		lockTimeoutErr := mssql.Error{Number: 1222, Message: "Lock request time out period exceeded"}
+
+		var attempts int
+		err := newMSSQL(3).retryOnDeadlock(context.Background(), logger.NOP, func() error {
+			attempts++
+			return lockTimeoutErr
+		})
+		require.Equal(t, lockTimeoutErr, err)
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("context cancelled while waiting", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.mssql.deadlockRetryBackoff", "1h")
+		ms := New(c, logger.NOP, stats.Default)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		var attempts int
+		err := ms.retryOnDeadlock(ctx, logger.NOP, contendedLoad(1, &attempts))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 1, attempts)
+	})
+}
@@ -0,0 +1,96 @@
+package mssql
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Bounds of the durations of the connection settings, the driver only supports whole seconds
+const (
+	minDialTimeout = time.Second
+	maxDialTimeout = 5 * time.Minute
+	minKeepAlive   = time.Second
+	maxKeepAlive   = time.Hour
+)
+
+// DSN builds the connection string of a SQL Server database.
+// The credentials and the query parameters are escaped, so they can contain any character (e.g. passwords with '@', ':', '/' or '?').
+type DSN struct {
+	Host string
+	// Port is left out of the connection string if zero, for the port of the Instance to be resolved through the SQL Server Browser service
+	Port int
+	// Instance is the name of the SQL Server instance, if not the default one
+	Instance               string
+	User                   string
+	Password               string
+	Database               string
+	Encrypt                string
+	TrustServerCertificate bool
+	DialTimeout            time.Duration
+	// KeepAlive is the interval of the TCP keep-alive probes detecting stalled connections
+	KeepAlive time.Duration
+}
+
+// String returns the connection string in the URL format supported by the driver
+func (d DSN) String() string {
+	query := url.Values{}
+	query.Add("database", d.Database)
+	if d.Encrypt != "" {
+		query.Add("encrypt", d.Encrypt)
+	}
+	if d.TrustServerCertificate {
+		query.Add("TrustServerCertificate", "true")
+	}
+	if d.DialTimeout > 0 {
+		query.Add("dial timeout", strconv.Itoa(int(d.DialTimeout/time.Second)))
+	}
+	if d.KeepAlive > 0 {
+		query.Add("keepAlive", strconv.Itoa(int(d.KeepAlive/time.Second)))
+	}
+
+	u := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(d.User, d.Password),
+		Host:     d.Host,
+		RawQuery: query.Encode(),
+	}
+	if d.Port > 0 {
+		u.Host = net.JoinHostPort(d.Host, strconv.Itoa(d.Port))
+	}
+	if d.Instance != "" {
+		u.Path = "/" + d.Instance
+	}
+	return u.String()
+}
+
+// parseConnectionDuration parses the duration of a connection setting, either a Go duration (e.g. 30s) or a number of seconds.
+// It returns 0 if the setting isn't set, for the driver default to be used.
+func parseConnectionDuration(setting, value string, minValue, maxValue time.Duration) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, atoiErr := strconv.Atoi(value)
+		if atoiErr != nil {
+			return 0, fmt.Errorf("invalid %s %q: neither a duration nor a number of seconds", setting, value)
+		}
+		d = time.Duration(seconds) * time.Second
+	}
+	if d < minValue || d > maxValue {
+		return 0, fmt.Errorf("invalid %s %q: must be between %s and %s", setting, value, minValue, maxValue)
+	}
+	return d, nil
+}
+
+// splitInstance splits the host configured as host\instance (e.g. db.example.com\SQLEXPRESS) into the host and the instance name, if any
+func splitInstance(host string) (string, string) {
+	host, instance, _ := strings.Cut(host, `\`)
+	return host, instance
+}
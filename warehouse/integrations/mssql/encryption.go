@@ -0,0 +1,67 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+// ErrColumnEncryptionNotSupported is returned when loading a table whose columns are protected with Always Encrypted.
+//
+// The driver neither encrypts the parameters of the connection nor supports AllowEncryptedValueModifications for bulk copies,
+// so neither plain values nor pre-encrypted ones can be loaded into encrypted columns, and merging can't match on them.
+// Setup fails if column encryption is enabled in the destination config, before connecting,
+// and loads fail with the encrypted columns, before downloading the load files, instead of failing opaquely with an operand type clash.
+// Encrypted columns of the table which aren't part of the upload (e.g. populated by another process) are left as they are.
+var ErrColumnEncryptionNotSupported = errors.New("always encrypted columns are not supported")
+
+// encryptedColumnsSQL returns the columns of the table which are protected with Always Encrypted (SQL Server 2016 onwards)
+const encryptedColumnsSQL = `SELECT name FROM sys.columns WHERE object_id = OBJECT_ID(@table) AND encryption_type IS NOT NULL`
+
+// encryptedColumns returns the columns of the table protected with Always Encrypted
+func (ms *MSSQL) encryptedColumns(ctx context.Context, tableName string) ([]string, error) {
+	rows, err := ms.DB.QueryContext(ctx, encryptedColumnsSQL, sql.Named("table", ms.quoteTable(tableName)))
+	if err != nil {
+		return nil, fmt.Errorf("querying encrypted columns: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, fmt.Errorf("scanning encrypted columns: %w", err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating encrypted columns: %w", err)
+	}
+	return columns, nil
+}
+
+// validateEncryptedColumns returns an error identifying the columns of the upload which are encrypted in the table.
+// Column names are compared case-insensitively, as the default collation does.
+func validateEncryptedColumns(tableName string, schema model.TableSchema, encrypted []string) error {
+	loaded := make(map[string]string, len(schema))
+	for column := range schema {
+		loaded[strings.ToLower(column)] = column
+	}
+
+	var columns []string
+	for _, column := range encrypted {
+		if c, ok := loaded[strings.ToLower(column)]; ok {
+			columns = append(columns, c)
+		}
+	}
+	if len(columns) == 0 {
+		return nil
+	}
+	sort.Strings(columns)
+	return fmt.Errorf("%w: columns of table %s: %s", ErrColumnEncryptionNotSupported, tableName, strings.Join(columns, ", "))
+}
@@ -0,0 +1,53 @@
+package mssql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestValidateEncryptedColumns(t *testing.T) {
+	schema := model.TableSchema{
+		"id":    model.StringDataType,
+		"email": model.StringDataType,
+		"Phone": model.StringDataType,
+	}
+
+	t.Run("no encrypted columns", func(t *testing.T) {
+		require.NoError(t, validateEncryptedColumns("users", schema, nil))
+	})
+
+	t.Run("encrypted columns not in the upload", func(t *testing.T) {
+		require.NoError(t, validateEncryptedColumns("users", schema, []string{"ssn"}))
+	})
+
+	t.Run("encrypted columns in the upload", func(t *testing.T) {
+		err := validateEncryptedColumns("users", schema, []string{"phone", "email", "ssn"})
+		require.ErrorIs(t, err, ErrColumnEncryptionNotSupported)
+		require.EqualError(t, err, "always encrypted columns are not supported: columns of table users: Phone, email")
+	})
+}
+
+func TestSetupColumnEncryption(t *testing.T) {
+	ms := New(config.New(), logger.NOP, stats.Default)
+
+	err := ms.Setup(context.Background(), model.Warehouse{
+		Destination: backendconfig.DestinationT{
+			Config: map[string]any{
+				"host":             "unreachable",
+				"columnEncryption": true,
+			},
+		},
+	}, nil)
+	require.ErrorIs(t, err, ErrColumnEncryptionNotSupported)
+	require.EqualError(t, err, "always encrypted columns are not supported: columnEncryption can't be enabled")
+	require.Nil(t, ms.DB, "no connection is opened")
+}
@@ -0,0 +1,196 @@
+package mssql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"golang.org/x/exp/maps"
+
+	"github.com/rudderlabs/rudder-server/warehouse/integrations/types"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	"github.com/rudderlabs/rudder-server/warehouse/logfield"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// secretSettingRegex matches the destination settings holding secrets (e.g. password, secretAccessKey, accountKey, sasToken or credentials),
+// which are left out of the load cases
+var secretSettingRegex = regexp.MustCompile(`(?i)password|secret|token|credentials|^(accessKey|accountKey|privateKey)$`)
+
+// LoadCase is a failed load of a table captured with its exact inputs, so that the failure can be reproduced by replaying it against another MSSQL instance.
+// Load cases of LoadTable and LoadUserTables are captured in Warehouse.mssql.loadCaseDir, if set. The secrets of the destination config are left out of them.
+type LoadCase struct {
+	Warehouse model.Warehouse           `json:"warehouse"`
+	TableName string                    `json:"tableName"`
+	LoadFiles []warehouseutils.LoadFile `json:"loadFiles"`
+	// UploadSchema is the schema of the table in the upload
+	UploadSchema model.TableSchema `json:"uploadSchema"`
+	// WarehouseSchema are the schemas of the table and of the discards table in the warehouse
+	WarehouseSchema           model.Schema `json:"warehouseSchema"`
+	LoadFileType              string       `json:"loadFileType"`
+	UseRudderStorage          bool         `json:"useRudderStorage"`
+	ShouldOnDedupUseNewRecord bool         `json:"shouldOnDedupUseNewRecord"`
+	CanAppend                 bool         `json:"canAppend"`
+	Error                     string       `json:"error"`
+	CapturedAt                time.Time    `json:"capturedAt"`
+}
+
+// captureLoadCase writes the load case of the table which failed to load with the error to the load cases directory, returning its path
+func (ms *MSSQL) captureLoadCase(ctx context.Context, tableName string, loadErr error) (string, error) {
+	loadFiles, err := ms.Uploader.GetLoadFilesMetadata(ctx, warehouseutils.GetLoadFilesOptions{Table: tableName})
+	if err != nil {
+		return "", fmt.Errorf("getting load files: %w", err)
+	}
+
+	warehouse := ms.Warehouse
+	warehouse.Destination.Config = maps.Clone(warehouse.Destination.Config)
+	maps.DeleteFunc(warehouse.Destination.Config, func(setting string, _ interface{}) bool {
+		return secretSettingRegex.MatchString(setting)
+	})
+
+	lc := LoadCase{
+		Warehouse:    warehouse,
+		TableName:    tableName,
+		LoadFiles:    loadFiles,
+		UploadSchema: ms.Uploader.GetTableSchemaInUpload(tableName),
+		WarehouseSchema: model.Schema{
+			tableName:                    ms.Uploader.GetTableSchemaInWarehouse(tableName),
+			warehouseutils.DiscardsTable: ms.Uploader.GetTableSchemaInWarehouse(warehouseutils.DiscardsTable),
+		},
+		LoadFileType:              ms.Uploader.GetLoadFileType(),
+		UseRudderStorage:          ms.Uploader.UseRudderStorage(),
+		ShouldOnDedupUseNewRecord: ms.Uploader.ShouldOnDedupUseNewRecord(),
+		CanAppend:                 ms.Uploader.CanAppend(),
+		Error:                     loadErr.Error(),
+		CapturedAt:                time.Now().UTC(),
+	}
+	content, err := json.MarshalIndent(lc, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("marshalling load case: %w", err)
+	}
+
+	if err := os.MkdirAll(ms.config.loadCaseDir, os.ModePerm); err != nil {
+		return "", fmt.Errorf("creating load cases directory: %w", err)
+	}
+	path := filepath.Join(ms.config.loadCaseDir, fmt.Sprintf("%s_%s_%s_%d.json",
+		warehouse.Destination.ID, warehouse.Namespace, tableName, lc.CapturedAt.UnixNano(),
+	))
+	if err := os.WriteFile(path, content, 0o600); err != nil {
+		return "", fmt.Errorf("writing load case: %w", err)
+	}
+	return path, nil
+}
+
+// captureFailedLoad captures the load case of the table which failed to load, if load cases are to be captured.
+// Failing to capture the load case is only logged, the load failing anyway.
+func (ms *MSSQL) captureFailedLoad(ctx context.Context, tableName string, loadErr error) {
+	if ms.config.loadCaseDir == "" || loadErr == nil {
+		return
+	}
+
+	log := ms.logger.With(
+		logfield.DestinationID, ms.Warehouse.Destination.ID,
+		logfield.Namespace, ms.Namespace,
+		logfield.TableName, tableName,
+	)
+	path, err := ms.captureLoadCase(ctx, tableName, loadErr)
+	if err != nil {
+		log.Warnw("capturing load case", logfield.Error, err.Error())
+		return
+	}
+	log.Infow("captured load case", "path", path)
+}
+
+// ReadLoadCase reads the load case captured in the file
+func ReadLoadCase(path string) (LoadCase, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return LoadCase{}, fmt.Errorf("reading load case: %w", err)
+	}
+	var lc LoadCase
+	if err := json.Unmarshal(content, &lc); err != nil {
+		return LoadCase{}, fmt.Errorf("unmarshalling load case: %w", err)
+	}
+	return lc, nil
+}
+
+// ReplayLoadCase loads the table of the load case again, into the MSSQL instance of the destination config.
+// The destination config overrides the captured one, e.g. with the credentials of a test instance and the secrets of the object storage left out of the load case.
+// The namespace and the table are created if missing, as the upload did before loading the table.
+func (ms *MSSQL) ReplayLoadCase(ctx context.Context, lc LoadCase, destinationConfig map[string]interface{}) (*types.LoadTableStats, error) {
+	warehouse := lc.Warehouse
+	warehouse.Destination.Config = maps.Clone(warehouse.Destination.Config)
+	if warehouse.Destination.Config == nil {
+		warehouse.Destination.Config = make(map[string]interface{})
+	}
+	maps.Copy(warehouse.Destination.Config, destinationConfig)
+
+	if err := ms.Setup(ctx, warehouse, &loadCaseUploader{lc: lc}); err != nil {
+		return nil, fmt.Errorf("setting up: %w", err)
+	}
+	defer ms.Cleanup(ctx)
+
+	if err := ms.CreateSchema(ctx); err != nil {
+		return nil, fmt.Errorf("creating schema: %w", err)
+	}
+	tableSchema := lc.WarehouseSchema[lc.TableName]
+	if len(tableSchema) == 0 {
+		tableSchema = lc.UploadSchema
+	}
+	if err := ms.CreateTable(ctx, lc.TableName, tableSchema); err != nil {
+		return nil, fmt.Errorf("creating table: %w", err)
+	}
+	return ms.LoadTable(ctx, lc.TableName)
+}
+
+// loadCaseUploader provides the inputs of the load of a load case, as the upload did when the load case was captured
+type loadCaseUploader struct {
+	lc LoadCase
+}
+
+func (*loadCaseUploader) IsWarehouseSchemaEmpty() bool                          { return false }
+func (*loadCaseUploader) GetLocalSchema(context.Context) (model.Schema, error)  { return nil, nil }
+func (*loadCaseUploader) UpdateLocalSchema(context.Context, model.Schema) error { return nil }
+func (*loadCaseUploader) GetLoadFileGenStartTIme() time.Time                    { return time.Time{} }
+func (*loadCaseUploader) GetFirstLastEvent() (time.Time, time.Time)             { return time.Time{}, time.Time{} }
+func (u *loadCaseUploader) ShouldOnDedupUseNewRecord() bool                     { return u.lc.ShouldOnDedupUseNewRecord }
+func (u *loadCaseUploader) UseRudderStorage() bool                              { return u.lc.UseRudderStorage }
+func (u *loadCaseUploader) GetLoadFileType() string                             { return u.lc.LoadFileType }
+func (u *loadCaseUploader) CanAppend() bool                                     { return u.lc.CanAppend }
+
+func (u *loadCaseUploader) GetTableSchemaInWarehouse(tableName string) model.TableSchema {
+	return u.lc.WarehouseSchema[tableName]
+}
+
+func (u *loadCaseUploader) GetTableSchemaInUpload(tableName string) model.TableSchema {
+	if tableName != u.lc.TableName {
+		return nil
+	}
+	return u.lc.UploadSchema
+}
+
+func (u *loadCaseUploader) GetLoadFilesMetadata(_ context.Context, options warehouseutils.GetLoadFilesOptions) ([]warehouseutils.LoadFile, error) {
+	if options.Table != u.lc.TableName {
+		return nil, nil
+	}
+	return u.lc.LoadFiles, nil
+}
+
+func (u *loadCaseUploader) GetSampleLoadFileLocation(_ context.Context, tableName string) (string, error) {
+	if tableName != u.lc.TableName || len(u.lc.LoadFiles) == 0 {
+		return "", fmt.Errorf("no load files for table %s", tableName)
+	}
+	return u.lc.LoadFiles[0].Location, nil
+}
+
+func (u *loadCaseUploader) GetSingleLoadFile(ctx context.Context, tableName string) (warehouseutils.LoadFile, error) {
+	location, err := u.GetSampleLoadFileLocation(ctx, tableName)
+	if err != nil {
+		return warehouseutils.LoadFile{}, err
+	}
+	return warehouseutils.LoadFile{Location: location}, nil
+}
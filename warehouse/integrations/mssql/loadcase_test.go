@@ -0,0 +1,191 @@
+package mssql
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	mockuploader "github.com/rudderlabs/rudder-server/warehouse/internal/mocks/utils"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestLoadCase(t *testing.T) {
+	const tableName = "tracks"
+
+	var (
+		loadFiles       = []warehouseutils.LoadFile{{Location: "https://bucket/rudder-warehouse-load-objects/tracks/load.csv.gz", Metadata: []byte(`{"total_rows":10}`)}}
+		uploadSchema    = model.TableSchema{"id": "string", "received_at": "datetime"}
+		warehouseSchema = model.TableSchema{"id": "string"}
+		discardsSchema  = model.TableSchema{"column_name": "string", "column_value": "string"}
+		loadErr         = errors.New("bulk load: mssql: String or binary data would be truncated")
+	)
+
+	newMSSQL := func(t *testing.T, loadCaseDir string) *MSSQL {
+		c := config.New()
+		c.Set("Warehouse.mssql.loadCaseDir", loadCaseDir)
+
+		ctrl := gomock.NewController(t)
+		uploader := mockuploader.NewMockUploader(ctrl)
+		uploader.EXPECT().GetLoadFilesMetadata(gomock.Any(), warehouseutils.GetLoadFilesOptions{Table: tableName}).Return(loadFiles, nil).AnyTimes()
+		uploader.EXPECT().GetTableSchemaInUpload(tableName).Return(uploadSchema).AnyTimes()
+		uploader.EXPECT().GetTableSchemaInWarehouse(tableName).Return(warehouseSchema).AnyTimes()
+		uploader.EXPECT().GetTableSchemaInWarehouse(warehouseutils.DiscardsTable).Return(discardsSchema).AnyTimes()
+		uploader.EXPECT().GetLoadFileType().Return(warehouseutils.LoadFileTypeCsv).AnyTimes()
+		uploader.EXPECT().UseRudderStorage().Return(false).AnyTimes()
+		uploader.EXPECT().ShouldOnDedupUseNewRecord().Return(true).AnyTimes()
+		uploader.EXPECT().CanAppend().Return(false).AnyTimes()
+
+		ms := New(c, logger.NOP, stats.Default)
+		ms.Namespace = "test_namespace"
+		ms.Uploader = uploader
+		ms.Warehouse = model.Warehouse{
+			Namespace: "test_namespace",
+			Destination: backendconfig.DestinationT{
+				ID: "test_destination_id",
+				Config: map[string]interface{}{
+					"host":            "mssql.example.com",
+					"password":        "secret-password",
+					"secretAccessKey": "secret-access-key",
+					"accessKeyID":     "access-key-id",
+					"mergeKeys":       map[string]interface{}{tableName: []interface{}{"id"}},
+				},
+			},
+		}
+		return ms
+	}
+
+	t.Run("disabled by default", func(t *testing.T) {
+		dir := t.TempDir()
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(dir)) // an empty load cases directory would be the working directory
+		t.Cleanup(func() { require.NoError(t, os.Chdir(wd)) })
+
+		ms := newMSSQL(t, "")
+		ms.captureFailedLoad(context.Background(), tableName, loadErr)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries, "no load case should be written")
+	})
+
+	t.Run("capture and read", func(t *testing.T) {
+		dir := t.TempDir()
+		ms := newMSSQL(t, dir)
+
+		ms.captureFailedLoad(context.Background(), tableName, nil)
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Empty(t, entries, "successful loads should not be captured")
+
+		path, err := ms.captureLoadCase(context.Background(), tableName, loadErr)
+		require.NoError(t, err)
+		require.FileExists(t, path)
+
+		lc, err := ReadLoadCase(path)
+		require.NoError(t, err)
+		require.Equal(t, tableName, lc.TableName)
+		requireLoadFiles(t, loadFiles, lc.LoadFiles)
+		require.Equal(t, uploadSchema, lc.UploadSchema)
+		require.Equal(t, model.Schema{tableName: warehouseSchema, warehouseutils.DiscardsTable: discardsSchema}, lc.WarehouseSchema)
+		require.Equal(t, warehouseutils.LoadFileTypeCsv, lc.LoadFileType)
+		require.True(t, lc.ShouldOnDedupUseNewRecord)
+		require.Equal(t, loadErr.Error(), lc.Error)
+		require.Equal(t, "test_destination_id", lc.Warehouse.Destination.ID)
+		require.Equal(t, "test_namespace", lc.Warehouse.Namespace)
+		require.Equal(t, map[string]interface{}{
+			"host":        "mssql.example.com",
+			"accessKeyID": "access-key-id",
+			"mergeKeys":   map[string]interface{}{tableName: []interface{}{"id"}},
+		}, lc.Warehouse.Destination.Config, "secrets should be left out of the load case")
+		require.Equal(t, "secret-password", ms.Warehouse.Destination.Config["password"], "the destination config should not be modified")
+	})
+
+	t.Run("capture failed user tables", func(t *testing.T) {
+		dir := t.TempDir()
+		c := config.New()
+		c.Set("Warehouse.mssql.loadCaseDir", dir)
+
+		// columns differing only by case fail the load of the identifies before connecting
+		identifiesSchema := model.TableSchema{"id": "string", "ID": "string"}
+
+		ctrl := gomock.NewController(t)
+		uploader := mockuploader.NewMockUploader(ctrl)
+		uploader.EXPECT().GetLoadFilesMetadata(gomock.Any(), warehouseutils.GetLoadFilesOptions{Table: warehouseutils.IdentifiesTable}).Return(loadFiles, nil).AnyTimes()
+		uploader.EXPECT().GetTableSchemaInUpload(warehouseutils.IdentifiesTable).Return(identifiesSchema).AnyTimes()
+		uploader.EXPECT().GetTableSchemaInWarehouse(gomock.Any()).Return(nil).AnyTimes()
+		uploader.EXPECT().GetLoadFileType().Return(warehouseutils.LoadFileTypeCsv).AnyTimes()
+		uploader.EXPECT().UseRudderStorage().Return(false).AnyTimes()
+		uploader.EXPECT().ShouldOnDedupUseNewRecord().Return(false).AnyTimes()
+		uploader.EXPECT().CanAppend().Return(false).AnyTimes()
+
+		ms := New(c, logger.NOP, stats.Default)
+		ms.Namespace = "test_namespace"
+		ms.Uploader = uploader
+		ms.Warehouse = model.Warehouse{Namespace: "test_namespace", Destination: backendconfig.DestinationT{ID: "test_destination_id"}}
+
+		errorMap := ms.LoadUserTables(context.Background())
+		require.Error(t, errorMap[warehouseutils.IdentifiesTable])
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+
+		lc, err := ReadLoadCase(filepath.Join(dir, entries[0].Name()))
+		require.NoError(t, err)
+		require.Equal(t, warehouseutils.IdentifiesTable, lc.TableName)
+		require.Equal(t, identifiesSchema, lc.UploadSchema)
+		require.Equal(t, errorMap[warehouseutils.IdentifiesTable].Error(), lc.Error)
+	})
+
+	t.Run("replay uploader", func(t *testing.T) {
+		path, err := newMSSQL(t, t.TempDir()).captureLoadCase(context.Background(), tableName, loadErr)
+		require.NoError(t, err)
+		lc, err := ReadLoadCase(path)
+		require.NoError(t, err)
+
+		u := &loadCaseUploader{lc: lc}
+		files, err := u.GetLoadFilesMetadata(context.Background(), warehouseutils.GetLoadFilesOptions{Table: tableName})
+		require.NoError(t, err)
+		requireLoadFiles(t, loadFiles, files)
+		files, err = u.GetLoadFilesMetadata(context.Background(), warehouseutils.GetLoadFilesOptions{Table: "pages"})
+		require.NoError(t, err)
+		require.Empty(t, files)
+
+		require.Equal(t, uploadSchema, u.GetTableSchemaInUpload(tableName))
+		require.Nil(t, u.GetTableSchemaInUpload("pages"))
+		require.Equal(t, warehouseSchema, u.GetTableSchemaInWarehouse(tableName))
+		require.Equal(t, discardsSchema, u.GetTableSchemaInWarehouse(warehouseutils.DiscardsTable))
+		require.Equal(t, warehouseutils.LoadFileTypeCsv, u.GetLoadFileType())
+		require.True(t, u.ShouldOnDedupUseNewRecord())
+
+		location, err := u.GetSampleLoadFileLocation(context.Background(), tableName)
+		require.NoError(t, err)
+		require.Equal(t, loadFiles[0].Location, location)
+	})
+
+	t.Run("read invalid load case", func(t *testing.T) {
+		_, err := ReadLoadCase(t.TempDir() + "/missing.json")
+		require.ErrorContains(t, err, "reading load case")
+	})
+}
+
+// requireLoadFiles asserts the load files are the expected ones, their metadata being indented in the load cases
+func requireLoadFiles(t *testing.T, expected, actual []warehouseutils.LoadFile) {
+	t.Helper()
+	require.Len(t, actual, len(expected))
+	for i := range expected {
+		require.Equal(t, expected[i].Location, actual[i].Location)
+		require.JSONEq(t, string(expected[i].Metadata), string(actual[i].Metadata))
+	}
+}
@@ -0,0 +1,169 @@
+package mssql
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rudderlabs/rudder-server/warehouse/encoding"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// loadFilesReader reads the records of multiple gzipped load files as a single stream of records.
+// Every load file is decompressed and parsed on its own, so that file boundaries (gzip trailers, missing trailing newlines, headers) never leak into the records.
+// Parquet load files (.parquet) are read as well, their columns being mapped by name to the columns of the records.
+type loadFilesReader struct {
+	fileNames []string
+	dialect   csvDialect
+	columns   []string
+
+	index         int
+	file          *os.File
+	gzipReader    *gzip.Reader
+	csvReader     *csv.Reader
+	parquetReader encoding.ParquetEventReader
+	records       int
+}
+
+// newLoadFilesReader returns a reader of the load files, whose records have the columns (sorted by name) in the case of parquet load files
+func newLoadFilesReader(fileNames []string, dialect csvDialect, columns []string) *loadFilesReader {
+	return &loadFilesReader{
+		fileNames: fileNames,
+		dialect:   dialect,
+		columns:   columns,
+		index:     -1,
+	}
+}
+
+// isParquetLoadFile returns true if the load file is a parquet one, based on its extension
+func isParquetLoadFile(fileName string) bool {
+	return strings.HasSuffix(fileName, "."+warehouseutils.GetLoadFileFormat(warehouseutils.LoadFileTypeParquet))
+}
+
+// Read returns the next record across all the load files.
+// It returns io.EOF once all the load files are read. Any other error identifies the load file which failed.
+func (r *loadFilesReader) Read() ([]string, error) {
+	for {
+		if r.csvReader == nil && r.parquetReader == nil {
+			if err := r.next(); err != nil {
+				return nil, err
+			}
+		}
+
+		record, err := r.readRecord()
+		if errors.Is(err, io.EOF) {
+			if err := r.closeCurrent(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading file %s: %w", r.FileName(), err)
+		}
+		r.records++
+		return record, nil
+	}
+}
+
+func (r *loadFilesReader) readRecord() ([]string, error) {
+	if r.parquetReader != nil {
+		return r.parquetReader.Read(r.columns)
+	}
+	return r.csvReader.Read()
+}
+
+// FileName returns the name of the load file currently being read
+func (r *loadFilesReader) FileName() string {
+	if r.index < 0 || r.index >= len(r.fileNames) {
+		return ""
+	}
+	return r.fileNames[r.index]
+}
+
+// RecordNumber returns the number of the last record read within the load file currently being read, starting at 1 (the header excluded)
+func (r *loadFilesReader) RecordNumber() int {
+	return r.records
+}
+
+// Line returns the line of the last record read within the load file currently being read, starting at 1 (the header included).
+// Parquet load files have no lines, so the record number is returned instead.
+func (r *loadFilesReader) Line() int {
+	if r.parquetReader != nil {
+		return r.records
+	}
+	if r.csvReader == nil {
+		return 0
+	}
+	line, _ := r.csvReader.FieldPos(0)
+	return line
+}
+
+// next opens the next load file, skipping its header if the dialect has one
+func (r *loadFilesReader) next() error {
+	r.index++
+	if r.index >= len(r.fileNames) {
+		return io.EOF
+	}
+
+	fileName := r.fileNames[r.index]
+	r.records = 0
+
+	if isParquetLoadFile(fileName) {
+		parquetReader, err := encoding.NewParquetEventReader(fileName)
+		if err != nil {
+			return err
+		}
+		r.parquetReader = parquetReader
+		return nil
+	}
+
+	file, err := os.Open(fileName)
+	if err != nil {
+		return fmt.Errorf("opening file %s: %w", fileName, err)
+	}
+	r.file = file
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		_ = r.closeCurrent()
+		return fmt.Errorf("reading file %s: %w", fileName, err)
+	}
+	r.gzipReader = gzipReader
+	r.csvReader = r.dialect.newReader(gzipReader)
+
+	if r.dialect.hasHeader {
+		if _, err := r.csvReader.Read(); err != nil && !errors.Is(err, io.EOF) {
+			_ = r.closeCurrent()
+			return fmt.Errorf("reading header of file %s: %w", fileName, err)
+		}
+	}
+	return nil
+}
+
+func (r *loadFilesReader) closeCurrent() error {
+	var gzipErr, fileErr, parquetErr error
+	if r.gzipReader != nil {
+		gzipErr = r.gzipReader.Close()
+	}
+	if r.file != nil {
+		fileErr = r.file.Close()
+	}
+	if r.parquetReader != nil {
+		parquetErr = r.parquetReader.Close()
+	}
+	r.gzipReader, r.file, r.csvReader, r.parquetReader = nil, nil, nil, nil
+
+	if err := errors.Join(gzipErr, fileErr, parquetErr); err != nil {
+		return fmt.Errorf("closing file %s: %w", r.FileName(), err)
+	}
+	return nil
+}
+
+// Close closes the load file currently being read, if any
+func (r *loadFilesReader) Close() error {
+	return r.closeCurrent()
+}
@@ -0,0 +1,163 @@
+package mssql
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeGzipFile(t testing.TB, name, content string) string {
+	t.Helper()
+
+	fileName := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(fileName)
+	require.NoError(t, err)
+
+	gw := gzip.NewWriter(f)
+	_, err = gw.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, gw.Close())
+	require.NoError(t, f.Close())
+	return fileName
+}
+
+func readAll(t testing.TB, r *loadFilesReader) ([][]string, error) {
+	t.Helper()
+
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
+
+func TestLoadFilesReader(t *testing.T) {
+	t.Run("multiple files", func(t *testing.T) {
+		fileNames := []string{
+			writeGzipFile(t, "1.csv.gz", "1,a\n2,b\n"),
+			writeGzipFile(t, "2.csv.gz", ""),
+			writeGzipFile(t, "3.csv.gz", "3,c"), // without trailing newline
+			writeGzipFile(t, "4.csv.gz", "4,d\n"),
+		}
+
+		r := newLoadFilesReader(fileNames, defaultCSVDialect, nil)
+		defer func() { _ = r.Close() }()
+
+		records, err := readAll(t, r)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"1", "a"}, {"2", "b"}, {"3", "c"}, {"4", "d"}}, records)
+	})
+
+	t.Run("with header", func(t *testing.T) {
+		fileNames := []string{
+			writeGzipFile(t, "1.csv.gz", "id,val\n1,a\n"),
+			writeGzipFile(t, "2.csv.gz", "id,val\n"),
+			writeGzipFile(t, "3.csv.gz", "id,val\n2,b\n"),
+		}
+
+		dialect := defaultCSVDialect
+		dialect.hasHeader = true
+
+		r := newLoadFilesReader(fileNames, dialect, nil)
+		defer func() { _ = r.Close() }()
+
+		records, err := readAll(t, r)
+		require.NoError(t, err)
+		require.Equal(t, [][]string{{"1", "a"}, {"2", "b"}}, records)
+	})
+
+	t.Run("no files", func(t *testing.T) {
+		r := newLoadFilesReader(nil, defaultCSVDialect, nil)
+		defer func() { _ = r.Close() }()
+
+		records, err := readAll(t, r)
+		require.NoError(t, err)
+		require.Empty(t, records)
+	})
+
+	t.Run("errors identify the file", func(t *testing.T) {
+		notGzipped := filepath.Join(t.TempDir(), "not-gzipped.csv.gz")
+		require.NoError(t, os.WriteFile(notGzipped, []byte("1,a\n"), 0o600))
+
+		fileNames := []string{
+			writeGzipFile(t, "1.csv.gz", "1,a\n"),
+			notGzipped,
+		}
+
+		r := newLoadFilesReader(fileNames, defaultCSVDialect, nil)
+		defer func() { _ = r.Close() }()
+
+		records, err := readAll(t, r)
+		require.Error(t, err)
+		require.ErrorContains(t, err, notGzipped)
+		require.Equal(t, [][]string{{"1", "a"}}, records)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		missing := filepath.Join(t.TempDir(), "missing.csv.gz")
+
+		r := newLoadFilesReader([]string{missing}, defaultCSVDialect, nil)
+		defer func() { _ = r.Close() }()
+
+		_, err := readAll(t, r)
+		require.ErrorContains(t, err, missing)
+	})
+}
+
+func TestLoadFilesReaderRecordNumber(t *testing.T) {
+	fileNames := []string{
+		writeGzipFile(t, "1.csv.gz", "1,a\n2\n"),
+		writeGzipFile(t, "2.csv.gz", "3,c,c\n"),
+	}
+
+	r := newLoadFilesReader(fileNames, defaultCSVDialect, nil)
+	defer func() { _ = r.Close() }()
+
+	var numbers []int
+	for {
+		_, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err, "records with a different number of fields are read as well")
+		numbers = append(numbers, r.RecordNumber())
+	}
+	require.Equal(t, []int{1, 2, 1}, numbers)
+}
+
+func TestLoadFilesReaderLine(t *testing.T) {
+	dialect := defaultCSVDialect
+	dialect.hasHeader = true
+
+	fileNames := []string{
+		writeGzipFile(t, "1.csv.gz", "id,label\n1,\"multi\nline\"\n2,b\n"),
+		writeGzipFile(t, "2.csv.gz", "id,label\n3,c\n"),
+	}
+
+	r := newLoadFilesReader(fileNames, dialect, nil)
+	defer func() { _ = r.Close() }()
+
+	var lines, numbers []int
+	for {
+		_, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		lines = append(lines, r.Line())
+		numbers = append(numbers, r.RecordNumber())
+	}
+	require.Equal(t, []int{2, 4, 2}, lines, "lines include the header and the line breaks within the values")
+	require.Equal(t, []int{1, 2, 1}, numbers)
+}
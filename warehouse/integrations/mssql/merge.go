@@ -0,0 +1,191 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+
+	sqlmw "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// Destination settings controlling how the rows of the staging table are merged into the load table
+const (
+	mergeBehaviorSetting      = "mergeBehavior"
+	mergeUpdateColumnsSetting = "mergeUpdateColumns" // comma separated list of columns
+)
+
+const (
+	// mergeBehaviorUpdate updates the matched rows with the rows of the staging table, and inserts the rows which don't match (upsert). This is the default.
+	mergeBehaviorUpdate = "update"
+	// mergeBehaviorIgnore only inserts the rows which don't match, the matched rows are never updated.
+	mergeBehaviorIgnore = "ignore"
+	// mergeBehaviorUpdateColumns only updates the configured columns of the matched rows, and inserts the rows which don't match.
+	mergeBehaviorUpdateColumns = "updateColumns"
+	// mergeBehaviorReplace deletes the matched rows and inserts all the rows of the staging table. It is only used internally for the discards table.
+	mergeBehaviorReplace = "replace"
+)
+
+type mergeConfig struct {
+	behavior      string
+	updateColumns []string
+}
+
+// mergeConfigFor returns the merge behaviour configured for the destination
+func (ms *MSSQL) mergeConfigFor() mergeConfig {
+	behavior := warehouseutils.GetConfigValue(mergeBehaviorSetting, ms.Warehouse)
+	switch behavior {
+	case "", mergeBehaviorUpdate:
+		return mergeConfig{behavior: mergeBehaviorUpdate}
+	case mergeBehaviorIgnore:
+		return mergeConfig{behavior: mergeBehaviorIgnore}
+	case mergeBehaviorUpdateColumns:
+		updateColumns := lo.Compact(lo.Map(
+			strings.Split(warehouseutils.GetConfigValue(mergeUpdateColumnsSetting, ms.Warehouse), ","),
+			func(column string, _ int) string { return strings.TrimSpace(column) },
+		))
+		if len(updateColumns) == 0 {
+			ms.logger.Warnf("MSSQL: no columns configured for merge behavior %q of destination %s, using %q", behavior, ms.Warehouse.Destination.ID, mergeBehaviorUpdate)
+			return mergeConfig{behavior: mergeBehaviorUpdate}
+		}
+		return mergeConfig{behavior: mergeBehaviorUpdateColumns, updateColumns: updateColumns}
+	default:
+		ms.logger.Warnf("MSSQL: invalid merge behavior %q for destination %s, using %q", behavior, ms.Warehouse.Destination.ID, mergeBehaviorUpdate)
+		return mergeConfig{behavior: mergeBehaviorUpdate}
+	}
+}
+
+// mergeIntoLoadTable merges the rows of the staging table into the load table, returning the number of rows inserted and updated
+func (ms *MSSQL) mergeIntoLoadTable(
+	ctx context.Context,
+	txn *sqlmw.Tx,
+	tableName string,
+	quotedStagingTableName string,
+	sortedColumnKeys []string,
+) (rowsInserted, rowsUpdated int64, err error) {
+	merge := ms.mergeConfigFor()
+	if tableName == warehouseutils.DiscardsTable {
+		// discards are identified by the rows and the columns they belong to, so they are always replaced
+		merge = mergeConfig{behavior: mergeBehaviorReplace}
+	}
+
+	mergeKey := ms.mergeKey(tableName, sortedColumnKeys)
+	if len(mergeKey) == 0 {
+		// without a merge key the rows can neither be deduplicated nor matched, so they are appended
+		rowsInserted, err = ms.insertIntoLoadTable(ctx, txn, tableName, quotedStagingTableName, sortedColumnKeys, nil, false)
+		if err != nil {
+			return 0, 0, fmt.Errorf("insert into: %w", err)
+		}
+		return rowsInserted, 0, nil
+	}
+
+	switch merge.behavior {
+	case mergeBehaviorIgnore:
+		rowsInserted, err = ms.insertIntoLoadTable(ctx, txn, tableName, quotedStagingTableName, sortedColumnKeys, mergeKey, true)
+		if err != nil {
+			return 0, 0, fmt.Errorf("insert into: %w", err)
+		}
+		return rowsInserted, 0, nil
+	case mergeBehaviorUpdateColumns:
+		updateColumns := lo.Intersect(sortedColumnKeys, merge.updateColumns)
+		if len(updateColumns) > 0 {
+			rowsUpdated, err = ms.updateLoadTable(ctx, txn, tableName, quotedStagingTableName, sortedColumnKeys, mergeKey, updateColumns)
+			if err != nil {
+				return 0, 0, fmt.Errorf("update load table: %w", err)
+			}
+		}
+		rowsInserted, err = ms.insertIntoLoadTable(ctx, txn, tableName, quotedStagingTableName, sortedColumnKeys, mergeKey, true)
+		if err != nil {
+			return 0, 0, fmt.Errorf("insert into: %w", err)
+		}
+		return rowsInserted, rowsUpdated, nil
+	case mergeBehaviorReplace:
+		rowsDeleted, err := ms.deleteFromLoadTable(ctx, txn, tableName, quotedStagingTableName)
+		if err != nil {
+			return 0, 0, fmt.Errorf("delete from load table: %w", err)
+		}
+		rowsInserted, err = ms.insertIntoLoadTable(ctx, txn, tableName, quotedStagingTableName, sortedColumnKeys, mergeKey, false)
+		if err != nil {
+			return 0, 0, fmt.Errorf("insert into: %w", err)
+		}
+		return rowsInserted - rowsDeleted, rowsDeleted, nil
+	default:
+		// the matched rows are updated in place instead of being replaced, so that the columns which only exist in the warehouse are left untouched
+		if updateColumns := lo.Without(sortedColumnKeys, mergeKey...); len(updateColumns) > 0 {
+			rowsUpdated, err = ms.updateLoadTable(ctx, txn, tableName, quotedStagingTableName, sortedColumnKeys, mergeKey, updateColumns)
+			if err != nil {
+				return 0, 0, fmt.Errorf("update load table: %w", err)
+			}
+		}
+		rowsInserted, err = ms.insertIntoLoadTable(ctx, txn, tableName, quotedStagingTableName, sortedColumnKeys, mergeKey, true)
+		if err != nil {
+			return 0, 0, fmt.Errorf("insert into: %w", err)
+		}
+		return rowsInserted, rowsUpdated, nil
+	}
+}
+
+// dedupOrderBy returns the ordering used for picking the row to keep among the rows of the staging table sharing the same key.
+// The latest row wins. Rows received at the same time are ordered by all their columns, so that the same row is always picked, whatever the physical order of the staging table.
+func (ms *MSSQL) dedupOrderBy(sortedColumnKeys []string) string {
+	orderBy := []string{"received_at DESC"}
+	for _, column := range sortedColumnKeys {
+		if column == "received_at" {
+			continue
+		}
+		orderBy = append(orderBy, ms.quoteIdentifier(column))
+	}
+	return strings.Join(orderBy, ", ")
+}
+
+// updateLoadTable updates the columns of the rows in the load table matching the (deduplicated) rows of the staging table
+func (ms *MSSQL) updateLoadTable(
+	ctx context.Context,
+	txn *sqlmw.Tx,
+	tableName string,
+	quotedStagingTableName string,
+	sortedColumnKeys []string,
+	mergeKey []string,
+	updateColumns []string,
+) (int64, error) {
+	setClause := strings.Join(lo.Map(updateColumns, func(column string, _ int) string {
+		return fmt.Sprintf(`_target.%[1]s = _source.%[1]s`, ms.quoteIdentifier(column))
+	}), ", ")
+
+	updateStmt := fmt.Sprintf(`
+		UPDATE
+		  _target
+		SET
+		  %[1]s
+		FROM
+		  %[2]s AS _target
+		  INNER JOIN (
+			SELECT
+			  *,
+			  ROW_NUMBER() OVER (
+				PARTITION BY %[4]s
+				ORDER BY
+				  %[5]s
+			  ) AS _rudder_staging_row_number
+			FROM
+			  %[3]s
+		  ) AS _source ON %[6]s
+		WHERE
+		  _source._rudder_staging_row_number = 1;`,
+		setClause,
+		ms.quoteTable(tableName),
+		quotedStagingTableName,
+		ms.quoteAndJoinByComma(mergeKey),
+		ms.dedupOrderBy(sortedColumnKeys),
+		ms.joinMergeKey(mergeKey, "_source", "_target"),
+	)
+
+	ms.logMergeStatement(tableName, "update", updateStmt)
+	r, err := txn.ExecContext(ctx, updateStmt)
+	if err != nil {
+		return 0, fmt.Errorf("updating main table: %w", err)
+	}
+	return r.RowsAffected()
+}
@@ -0,0 +1,173 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestMergeConfig(t *testing.T) {
+	testCases := []struct {
+		name          string
+		destConfig    map[string]any
+		expectedMerge mergeConfig
+	}{
+		{
+			name:          "default",
+			destConfig:    map[string]any{},
+			expectedMerge: mergeConfig{behavior: mergeBehaviorUpdate},
+		},
+		{
+			name:          "update",
+			destConfig:    map[string]any{mergeBehaviorSetting: "update"},
+			expectedMerge: mergeConfig{behavior: mergeBehaviorUpdate},
+		},
+		{
+			name:          "ignore",
+			destConfig:    map[string]any{mergeBehaviorSetting: "ignore"},
+			expectedMerge: mergeConfig{behavior: mergeBehaviorIgnore},
+		},
+		{
+			name:          "update columns",
+			destConfig:    map[string]any{mergeBehaviorSetting: "updateColumns", mergeUpdateColumnsSetting: " test_int, ,test_string"},
+			expectedMerge: mergeConfig{behavior: mergeBehaviorUpdateColumns, updateColumns: []string{"test_int", "test_string"}},
+		},
+		{
+			name:          "update columns without columns",
+			destConfig:    map[string]any{mergeBehaviorSetting: "updateColumns"},
+			expectedMerge: mergeConfig{behavior: mergeBehaviorUpdate},
+		},
+		{
+			name:          "invalid",
+			destConfig:    map[string]any{mergeBehaviorSetting: "invalid"},
+			expectedMerge: mergeConfig{behavior: mergeBehaviorUpdate},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ms := New(config.New(), logger.NOP, stats.Default)
+			ms.Warehouse = model.Warehouse{
+				Destination: backendconfig.DestinationT{
+					ID:     "test_destination_id",
+					Config: tc.destConfig,
+				},
+			}
+			require.Equal(t, tc.expectedMerge, ms.mergeConfigFor())
+		})
+	}
+}
+
+func TestDedupOrderBy(t *testing.T) {
+	ms := New(config.New(), logger.NOP, stats.Default)
+	require.Equal(t, `received_at DESC, "id", "test_int", "test_string"`, ms.dedupOrderBy([]string{"id", "received_at", "test_int", "test_string"}))
+	require.Equal(t, `received_at DESC`, ms.dedupOrderBy([]string{"received_at"}))
+
+	c := config.New()
+	c.Set("Warehouse.mssql.identifierQuoteStrategy", "brackets")
+
+	ms = New(c, logger.NOP, stats.Default)
+	require.Equal(t, `received_at DESC, [id]`, ms.dedupOrderBy([]string{"id", "received_at"}))
+}
+
+func TestMergeKey(t *testing.T) {
+	columns := []string{"context_sources_task_id", "id", "received_at", "record_id"}
+
+	testCases := []struct {
+		name        string
+		tableName   string
+		columns     []string
+		destConfig  map[string]any
+		expectedKey []string
+	}{
+		{
+			name:        "default",
+			tableName:   "google_sheet",
+			columns:     columns,
+			destConfig:  map[string]any{},
+			expectedKey: []string{"id"},
+		},
+		{
+			name:        "discards",
+			tableName:   "rudder_discards",
+			columns:     []string{"column_name", "row_id", "table_name"},
+			destConfig:  map[string]any{mergeKeysSetting: map[string]any{"rudder_discards": []any{}}},
+			expectedKey: []string{"row_id", "column_name", "table_name"},
+		},
+		{
+			name:       "no id",
+			tableName:  "google_sheet",
+			columns:    []string{"received_at", "record_id"},
+			destConfig: map[string]any{},
+		},
+		{
+			name:        "composite key",
+			tableName:   "google_sheet",
+			columns:     columns,
+			destConfig:  map[string]any{mergeKeysSetting: map[string]any{"google_sheet": []any{"record_id", " context_sources_task_id", "record_id"}}},
+			expectedKey: []string{"record_id", "context_sources_task_id"},
+		},
+		{
+			name:        "comma separated composite key",
+			tableName:   "google_sheet",
+			columns:     columns,
+			destConfig:  map[string]any{mergeKeysSetting: map[string]any{"google_sheet": "record_id, context_sources_task_id"}},
+			expectedKey: []string{"record_id", "context_sources_task_id"},
+		},
+		{
+			name:        "key of another table",
+			tableName:   "google_sheet",
+			columns:     columns,
+			destConfig:  map[string]any{mergeKeysSetting: map[string]any{"tracks": []any{"record_id"}}},
+			expectedKey: []string{"id"},
+		},
+		{
+			name:       "dedup disabled",
+			tableName:  "google_sheet",
+			columns:    columns,
+			destConfig: map[string]any{mergeKeysSetting: map[string]any{"google_sheet": []any{}}},
+		},
+		{
+			name:       "key not in upload",
+			tableName:  "google_sheet",
+			columns:    columns,
+			destConfig: map[string]any{mergeKeysSetting: map[string]any{"google_sheet": []any{"record_id", "sheet_id"}}},
+		},
+		{
+			name:        "invalid key",
+			tableName:   "google_sheet",
+			columns:     columns,
+			destConfig:  map[string]any{mergeKeysSetting: map[string]any{"google_sheet": 1}},
+			expectedKey: []string{"id"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ms := New(config.New(), logger.NOP, stats.Default)
+			ms.Warehouse = model.Warehouse{
+				Destination: backendconfig.DestinationT{
+					ID:     "test_destination_id",
+					Config: tc.destConfig,
+				},
+			}
+			require.Equal(t, tc.expectedKey, ms.mergeKey(tc.tableName, tc.columns))
+		})
+	}
+
+	t.Run("join", func(t *testing.T) {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		require.Equal(t, `_source."record_id" = _target."record_id" AND _source."context_sources_task_id" = _target."context_sources_task_id"`,
+			ms.joinMergeKey([]string{"record_id", "context_sources_task_id"}, "_source", "_target"),
+		)
+	})
+}
@@ -0,0 +1,33 @@
+package mssql
+
+import (
+	"regexp"
+
+	"github.com/rudderlabs/rudder-server/warehouse/logfield"
+)
+
+// stringLiteralRegex matches the (nvarchar or varchar) string literals of a statement, with their escaped quotes
+var stringLiteralRegex = regexp.MustCompile(`N?'(?:[^']|'')*'`)
+
+// redactLiterals replaces the string literals of the statement, so that only its structure remains
+func redactLiterals(stmt string) string {
+	return stringLiteralRegex.ReplaceAllString(stmt, "'<redacted>'")
+}
+
+// logMergeStatement logs the statement merging the staging table into the load table at debug level, if Warehouse.mssql.logMergeStatements is enabled.
+// The rows are merged from the staging table, so the statements only contain identifiers (the match condition and the updated columns).
+// String literals are redacted nonetheless, so that no value is ever logged.
+func (ms *MSSQL) logMergeStatement(tableName, operation, stmt string) {
+	if !ms.config.logMergeStatements {
+		return
+	}
+	ms.logger.Debugw("merge statement",
+		logfield.SourceID, ms.Warehouse.Source.ID,
+		logfield.DestinationID, ms.Warehouse.Destination.ID,
+		logfield.WorkspaceID, ms.Warehouse.WorkspaceID,
+		logfield.Namespace, ms.Namespace,
+		logfield.TableName, tableName,
+		"operation", operation,
+		"statement", redactLiterals(stmt),
+	)
+}
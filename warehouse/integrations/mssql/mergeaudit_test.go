@@ -0,0 +1,52 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	mock_logger "github.com/rudderlabs/rudder-server/mocks/utils/logger"
+)
+
+func TestRedactLiterals(t *testing.T) {
+	require.Equal(t,
+		`EXEC sp_rename '<redacted>', '<redacted>'; SELECT "a" FROM "ns"."t"`,
+		redactLiterals(`EXEC sp_rename N'"ns"."t"', N'it''s'; SELECT "a" FROM "ns"."t"`),
+	)
+	require.Equal(t, `INSERT INTO "ns"."t" ("a") SELECT "a" FROM "ns"."s";`, redactLiterals(`INSERT INTO "ns"."t" ("a") SELECT "a" FROM "ns"."s";`))
+}
+
+func TestLogMergeStatement(t *testing.T) {
+	stmt := `UPDATE _target SET _target."a" = _source."a" WHERE _source."b" = N'value'`
+
+	t.Run("disabled", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockLogger := mock_logger.NewMockLogger(ctrl)
+		mockLogger.EXPECT().Child(gomock.Any()).Return(mockLogger).AnyTimes()
+		mockLogger.EXPECT().Debugw(gomock.Any(), gomock.Any()).Times(0)
+
+		ms := New(config.New(), mockLogger, stats.Default)
+		ms.logMergeStatement("tracks", "update", stmt)
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.mssql.logMergeStatements", true)
+
+		ctrl := gomock.NewController(t)
+		mockLogger := mock_logger.NewMockLogger(ctrl)
+		mockLogger.EXPECT().Child(gomock.Any()).Return(mockLogger).AnyTimes()
+		mockLogger.EXPECT().Debugw("merge statement", gomock.Any()).Times(1).Do(func(_ string, kvs ...interface{}) {
+			require.Contains(t, kvs, "update")
+			require.Contains(t, kvs, `UPDATE _target SET _target."a" = _source."a" WHERE _source."b" = '<redacted>'`)
+			require.NotContains(t, kvs, stmt)
+		})
+
+		ms := New(c, mockLogger, stats.Default)
+		ms.logMergeStatement("tracks", "update", stmt)
+	})
+}
@@ -0,0 +1,80 @@
+package mssql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// mergeKeysSetting is the destination setting mapping tables to the columns identifying their rows, overriding id (or the key of the table).
+// The columns are either a list or a comma separated string. No columns disable the deduplication for the table, whose rows are then appended.
+//
+//	"mergeKeys": {"google_sheet": ["record_id", "context_sources_task_id"], "audit_log": []}
+const mergeKeysSetting = "mergeKeys"
+
+// mergeKey returns the columns identifying the rows of the table, which are used for deduplicating the rows of the staging table and matching them to the rows of the load table.
+// No columns are returned if the table has no suitable key in the upload, in which case the rows are appended without deduplication:
+// for tables loaded by source jobs (async path), the rows of the previous job runs are then only cleaned up by deleting them by job run (Warehouse.mssql.enableDeleteByJobs).
+// Rows with NULL in any of the key columns never match the rows of the load table, so they are always inserted.
+func (ms *MSSQL) mergeKey(tableName string, sortedColumnKeys []string) []string {
+	if tableName == warehouseutils.DiscardsTable {
+		return []string{"row_id", "column_name", "table_name"}
+	}
+
+	key := []string{"id"}
+	if column, ok := primaryKeyMap[tableName]; ok {
+		key = []string{column}
+	}
+	if configured, ok := ms.configuredMergeKey(tableName); ok {
+		if len(configured) == 0 {
+			return nil
+		}
+		key = configured
+	}
+
+	if missing := lo.Without(key, sortedColumnKeys...); len(missing) > 0 {
+		ms.logger.Warnf("MSSQL: appending the rows of table %s of destination %s without deduplication, since the merge key columns %s are not part of the upload",
+			tableName, ms.Warehouse.Destination.ID, strings.Join(missing, ", "),
+		)
+		return nil
+	}
+	return key
+}
+
+// configuredMergeKey returns the merge key configured for the table, if any
+func (ms *MSSQL) configuredMergeKey(tableName string) ([]string, bool) {
+	value, ok := warehouseutils.GetConfigValueAsMap(mergeKeysSetting, ms.Warehouse.Destination.Config)[tableName]
+	if !ok {
+		return nil, false
+	}
+
+	var columns []string
+	switch v := value.(type) {
+	case string:
+		columns = strings.Split(v, ",")
+	case []any:
+		for _, column := range v {
+			if s, ok := column.(string); ok {
+				columns = append(columns, s)
+			}
+		}
+	case []string:
+		columns = v
+	default:
+		ms.logger.Warnf("MSSQL: invalid merge key %v for table %s of destination %s, using the default one", value, tableName, ms.Warehouse.Destination.ID)
+		return nil, false
+	}
+	return lo.Uniq(lo.Compact(lo.Map(columns, func(column string, _ int) string {
+		return strings.TrimSpace(column)
+	}))), true
+}
+
+// joinMergeKey returns the condition matching the rows of the two aliases on the merge key
+func (ms *MSSQL) joinMergeKey(key []string, left, right string) string {
+	return strings.Join(lo.Map(key, func(column string, _ int) string {
+		return fmt.Sprintf(`%[2]s.%[1]s = %[3]s.%[1]s`, ms.quoteIdentifier(column), left, right)
+	}), " AND ")
+}
@@ -1,17 +1,14 @@
 package mssql
 
 import (
-	"compress/gzip"
 	"context"
 	"database/sql"
-	"encoding/csv"
 	"errors"
 	"fmt"
 	"io"
-	"net"
-	"net/url"
-	"os"
+	"math/big"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -46,23 +43,49 @@ const (
 	password = "password"
 	port     = "port"
 	sslMode  = "sslMode"
+	// instanceName is the named instance to connect to, resolved into its port through the SQL Server Browser service if no port is configured.
+	// It can also be configured as part of the host, as host\instance.
+	instanceName = "instanceName"
+	// dialTimeout and keepAlive are durations, either Go ones (e.g. 30s) or a number of seconds. The driver defaults are used if they aren't set.
+	dialTimeout = "dialTimeout"
+	keepAlive   = "keepAlive"
+	// columnEncryption enables Always Encrypted on the connection, which the driver doesn't support, hence Setup fails with ErrColumnEncryptionNotSupported if it is enabled
+	columnEncryption = "columnEncryption"
 )
 
 const (
 	stringLengthLimit = 512
 	provider          = warehouseutils.MSSQL
 	tableNameLimit    = 127
+	// temporary table names are padded by SQL Server to make them unique across sessions, hence the lower limit (including #)
+	tempTableNameLimit = 116
+)
+
+const (
+	defaultDecimalPrecision = 38
+	defaultDecimalScale     = 10
+	maxDecimalPrecision     = 38
+
+	// maxParametersPerStatement is the maximum number of parameters of a statement in SQL Server
+	maxParametersPerStatement = 2100
 )
 
 var rudderDataTypesMapToMssql = map[string]string{
 	"int":      "bigint",
 	"float":    "decimal(28,10)",
+	"decimal":  decimalDataType(defaultDecimalPrecision, defaultDecimalScale),
 	"string":   "nvarchar(512)",
-	"datetime": "datetimeoffset",
+	"datetime": defaultDatetimeType,
 	"boolean":  "bit",
 	"json":     "jsonb",
 }
 
+// defaultDatetimeFallbackLayouts are used for parsing datetimes without an offset
+var defaultDatetimeFallbackLayouts = []string{
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
 var mssqlDataTypesMapToRudder = map[string]string{
 	"integer":                  "int",
 	"smallint":                 "int",
@@ -81,6 +104,7 @@ var mssqlDataTypesMapToRudder = map[string]string{
 	"char":                     "string",
 	"datetimeoffset":           "datetime",
 	"date":                     "datetime",
+	"datetime":                 "datetime",
 	"datetime2":                "datetime",
 	"timestamp with time zone": "datetime",
 	"timestamp":                "datetime",
@@ -100,21 +124,54 @@ type MSSQL struct {
 	stats  stats.Stats
 	logger logger.Logger
 
+	// retryBudget is shared by the retries of all the operations of the upload
+	retryBudget *retryBudget
+
 	config struct {
 		enableDeleteByJobs          bool
+		reportSchemaMismatches      bool
 		numWorkersDownloadLoadFiles int
+		loadFileDownloadRetries     int
+		loadFileDownloadBackoff     time.Duration
 		slowQueryThreshold          time.Duration
+		csvDialect                  csvDialect
+		datetimeFallbackLayouts     []string
+		datetimeDefaultLocation     *time.Location
+		datetimeType                datetimeType
+		defaultDatetimeType         datetimeType
+		defaultDataTypesMap         map[string]string
+		decimalPrecision            int
+		decimalScale                int
+		stagingRowsPerBatch         int
+		stagingKilobytesPerBatch    int
+		stagingParametersPerBatch   int
+		stagingCommitRows           int
+		useTempStagingTables        bool
+		lockTimeout                 time.Duration
+		deadlockPriority            string
+		deadlockMaxRetries          int
+		deadlockRetryBackoff        time.Duration
+		uploadRetryBudget           int
+		badRowsThreshold            badRowsThreshold
+		verifyRowCounts             bool
+		logMergeStatements          bool
+		loadCaseDir                 string
 	}
+
+	dataTypesMap map[string]string
+	quote        quoteFunc
 }
 
 type credentials struct {
-	host     string
-	database string
-	user     string
-	password string
-	port     string
-	sslMode  string
-	timeout  time.Duration
+	host      string
+	database  string
+	user      string
+	password  string
+	port      string
+	instance  string
+	sslMode   string
+	timeout   time.Duration
+	keepAlive time.Duration
 }
 
 var primaryKeyMap = map[string]string{
@@ -123,11 +180,9 @@ var primaryKeyMap = map[string]string{
 	warehouseutils.DiscardsTable:   "row_id",
 }
 
-var partitionKeyMap = map[string]string{
-	warehouseutils.UsersTable:      "id",
-	warehouseutils.IdentifiesTable: "id",
-	warehouseutils.DiscardsTable:   "row_id, column_name, table_name",
-}
+// ErrLoadFileNotFound is returned when loading a table if its load files are missing from the object storage or can't be read, wrapping the underlying cause.
+// It tells the object storage issues apart from the SQL errors, and is the same error as the one of the load files missing when verified before the upload.
+var ErrLoadFileNotFound = downloader.ErrLoadFileNotFound
 
 var errorsMappings = []model.JobError{
 	{
@@ -142,8 +197,80 @@ func New(conf *config.Config, log logger.Logger, stats stats.Stats) *MSSQL {
 		logger: log.Child("integrations").Child("mssql"),
 	}
 	ms.config.enableDeleteByJobs = conf.GetBool("Warehouse.mssql.enableDeleteByJobs", false)
+	ms.config.reportSchemaMismatches = conf.GetBool("Warehouse.mssql.reportSchemaMismatches", false)
 	ms.config.numWorkersDownloadLoadFiles = conf.GetInt("Warehouse.mssql.numWorkersDownloadLoadFiles", 1)
+	ms.config.loadFileDownloadRetries = conf.GetInt("Warehouse.mssql.loadFileDownloadRetries", 3)
+	ms.config.loadFileDownloadBackoff = conf.GetDuration("Warehouse.mssql.loadFileDownloadBackoff", 1, time.Second)
 	ms.config.slowQueryThreshold = conf.GetDuration("Warehouse.mssql.slowQueryThreshold", 5, time.Minute)
+	ms.config.csvDialect = csvDialect{
+		nullToken:  conf.GetString("Warehouse.mssql.loadFile.nullToken", defaultCSVDialect.nullToken),
+		hasHeader:  conf.GetBool("Warehouse.mssql.loadFile.hasHeader", defaultCSVDialect.hasHeader),
+		lazyQuotes: conf.GetBool("Warehouse.mssql.loadFile.lazyQuotes", defaultCSVDialect.lazyQuotes),
+	}
+	quoteStrategy := conf.GetString("Warehouse.mssql.identifierQuoteStrategy", quoteStrategyDoubleQuotes)
+	if quote, ok := quoteStrategies[quoteStrategy]; ok {
+		ms.quote = quote
+	} else {
+		ms.logger.Warnf("MSSQL: invalid identifier quote strategy %q, using %q", quoteStrategy, quoteStrategyDoubleQuotes)
+		ms.quote = quoteWithDoubleQuotes
+	}
+	ms.config.stagingRowsPerBatch = conf.GetInt("Warehouse.mssql.stagingRowsPerBatch", 0)
+	ms.config.stagingKilobytesPerBatch = conf.GetInt("Warehouse.mssql.stagingKilobytesPerBatch", 0)
+	ms.config.stagingParametersPerBatch = conf.GetInt("Warehouse.mssql.stagingParametersPerBatch", maxParametersPerStatement)
+	ms.config.stagingCommitRows = conf.GetInt("Warehouse.mssql.stagingCommitRows", 0)
+	ms.config.useTempStagingTables = conf.GetBool("Warehouse.mssql.useTempStagingTables", false)
+	ms.config.lockTimeout = conf.GetDuration("Warehouse.mssql.lockTimeout", 0, time.Millisecond)
+	ms.config.deadlockPriority = conf.GetString("Warehouse.mssql.deadlockPriority", "")
+	if ms.config.deadlockPriority != "" && !validDeadlockPriority(ms.config.deadlockPriority) {
+		ms.logger.Warnf("MSSQL: invalid deadlock priority %q, using the server default", ms.config.deadlockPriority)
+		ms.config.deadlockPriority = ""
+	}
+	ms.config.deadlockMaxRetries = conf.GetInt("Warehouse.mssql.deadlockMaxRetries", 3)
+	ms.config.deadlockRetryBackoff = conf.GetDuration("Warehouse.mssql.deadlockRetryBackoff", 1, time.Second)
+	ms.config.uploadRetryBudget = conf.GetInt("Warehouse.mssql.uploadRetryBudget", 0)
+	ms.retryBudget = newRetryBudget(ms.config.uploadRetryBudget)
+	ms.config.badRowsThreshold = badRowsThreshold{
+		rows:    conf.GetInt("Warehouse.mssql.badRowsThreshold", 0),
+		percent: conf.GetFloat64("Warehouse.mssql.badRowsThresholdPercent", 0),
+	}
+	ms.config.verifyRowCounts = conf.GetBool("Warehouse.mssql.verifyRowCounts", false)
+	ms.config.logMergeStatements = conf.GetBool("Warehouse.mssql.logMergeStatements", false)
+	ms.config.loadCaseDir = conf.GetString("Warehouse.mssql.loadCaseDir", "")
+	ms.config.decimalPrecision = conf.GetInt("Warehouse.mssql.decimalPrecision", defaultDecimalPrecision)
+	ms.config.decimalScale = conf.GetInt("Warehouse.mssql.decimalScale", defaultDecimalScale)
+	if !validDecimalPrecisionAndScale(ms.config.decimalPrecision, ms.config.decimalScale) {
+		ms.logger.Warnf("MSSQL: invalid decimal precision %d and scale %d, using default precision %d and scale %d",
+			ms.config.decimalPrecision, ms.config.decimalScale, defaultDecimalPrecision, defaultDecimalScale,
+		)
+		ms.config.decimalPrecision, ms.config.decimalScale = defaultDecimalPrecision, defaultDecimalScale
+	}
+	datetimeColumnType := conf.GetString("Warehouse.mssql.datetimeType", defaultDatetimeType)
+	dtType, ok := parseDatetimeType(datetimeColumnType)
+	if !ok {
+		ms.logger.Warnf("MSSQL: invalid datetime type %q, using %q", datetimeColumnType, defaultDatetimeType)
+		dtType, _ = parseDatetimeType(defaultDatetimeType)
+	}
+	ms.config.defaultDatetimeType = dtType
+	ms.config.defaultDataTypesMap = lo.Assign(rudderDataTypesMapToMssql, map[string]string{
+		model.DecimalDataType:  decimalDataType(ms.config.decimalPrecision, ms.config.decimalScale),
+		model.DateTimeDataType: dtType.columnType,
+	})
+	ms.resetDataTypes()
+	ms.config.datetimeFallbackLayouts = conf.GetStringSlice("Warehouse.mssql.datetimeFallbackLayouts", defaultDatetimeFallbackLayouts)
+	timezone := conf.GetString("Warehouse.mssql.datetimeDefaultTimezone", "UTC")
+	if loc, err := time.LoadLocation(timezone); err == nil {
+		ms.config.datetimeDefaultLocation = loc
+	} else {
+		ms.logger.Warnf("MSSQL: invalid datetime default timezone %q, using UTC: %v", timezone, err)
+		ms.config.datetimeDefaultLocation = time.UTC
+	}
+	delimiter := conf.GetString("Warehouse.mssql.loadFile.delimiter", string(defaultCSVDialect.delimiter))
+	if r, ok := parseDelimiter(delimiter); ok {
+		ms.config.csvDialect.delimiter = r
+	} else {
+		ms.logger.Warnf("MSSQL: invalid load file delimiter %q, using default %q", delimiter, defaultCSVDialect.delimiter)
+		ms.config.csvDialect.delimiter = defaultCSVDialect.delimiter
+	}
 
 	return ms
 }
@@ -154,29 +281,17 @@ func New(conf *config.Config, log logger.Logger, stats stats.Stats) *MSSQL {
 // If forceSSL is set to true or false, it works alongside with TrustServerCertificate=true
 // more about combinations in here: https://docs.microsoft.com/en-us/sql/connect/odbc/linux-mac/connection-string-keywords-and-data-source-names-dsns?view=sql-server-ver15
 func (ms *MSSQL) connect() (*sqlmw.DB, error) {
-	cred := ms.connectionCredentials()
-
-	port, err := strconv.Atoi(cred.port)
+	cred, err := ms.connectionCredentials()
 	if err != nil {
-		return nil, fmt.Errorf("invalid port %q: %w", cred.port, err)
-	}
-
-	query := url.Values{}
-	query.Add("database", cred.database)
-	query.Add("encrypt", cred.sslMode)
-	query.Add("TrustServerCertificate", "true")
-	if cred.timeout > 0 {
-		query.Add("dial timeout", fmt.Sprintf("%d", cred.timeout/time.Second))
+		return nil, err
 	}
 
-	connUrl := &url.URL{
-		Scheme:   "sqlserver",
-		User:     url.UserPassword(cred.user, cred.password),
-		Host:     net.JoinHostPort(cred.host, strconv.Itoa(port)),
-		RawQuery: query.Encode(),
+	dsn, err := cred.dsn()
+	if err != nil {
+		return nil, err
 	}
 
-	db, err := sql.Open("sqlserver", connUrl.String())
+	db, err := sql.Open("sqlserver", dsn.String())
 	if err != nil {
 		return nil, fmt.Errorf("opening connection: %w", err)
 	}
@@ -199,16 +314,65 @@ func (ms *MSSQL) connect() (*sqlmw.DB, error) {
 	return middleware, nil
 }
 
-func (ms *MSSQL) connectionCredentials() *credentials {
-	return &credentials{
+func (ms *MSSQL) connectionCredentials() (*credentials, error) {
+	cred := &credentials{
 		host:     warehouseutils.GetConfigValue(host, ms.Warehouse),
 		database: warehouseutils.GetConfigValue(dbName, ms.Warehouse),
 		user:     warehouseutils.GetConfigValue(user, ms.Warehouse),
 		password: warehouseutils.GetConfigValue(password, ms.Warehouse),
-		port:     warehouseutils.GetConfigValue(port, ms.Warehouse),
+		port:     strings.TrimSpace(warehouseutils.GetConfigValue(port, ms.Warehouse)),
+		instance: strings.TrimSpace(warehouseutils.GetConfigValue(instanceName, ms.Warehouse)),
 		sslMode:  warehouseutils.GetConfigValue(sslMode, ms.Warehouse),
 		timeout:  ms.connectTimeout,
 	}
+	if h, instance := splitInstance(cred.host); instance != "" {
+		cred.host = h
+		if cred.instance == "" {
+			cred.instance = instance
+		}
+	}
+
+	timeout, err := parseConnectionDuration(dialTimeout, warehouseutils.GetConfigValue(dialTimeout, ms.Warehouse), minDialTimeout, maxDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if timeout > 0 {
+		cred.timeout = timeout
+	}
+	if cred.keepAlive, err = parseConnectionDuration(keepAlive, warehouseutils.GetConfigValue(keepAlive, ms.Warehouse), minKeepAlive, maxKeepAlive); err != nil {
+		return nil, err
+	}
+	return cred, nil
+}
+
+// dsn returns the connection string of the credentials.
+// Either the port or the instance name is needed: without a port, the port of the instance is resolved through the SQL Server Browser service.
+func (cred *credentials) dsn() (DSN, error) {
+	dsn := DSN{
+		Host:                   cred.host,
+		Instance:               cred.instance,
+		User:                   cred.user,
+		Password:               cred.password,
+		Database:               cred.database,
+		Encrypt:                cred.sslMode,
+		TrustServerCertificate: true,
+		DialTimeout:            cred.timeout,
+		KeepAlive:              cred.keepAlive,
+	}
+
+	if cred.port == "" {
+		if cred.instance == "" {
+			return DSN{}, errors.New("either the port or the instance name is needed")
+		}
+		return dsn, nil
+	}
+
+	port, err := strconv.Atoi(cred.port)
+	if err != nil {
+		return DSN{}, fmt.Errorf("invalid port %q: %w", cred.port, err)
+	}
+	dsn.Port = port
+	return dsn, nil
 }
 
 func ColumnsWithDataTypes(columns model.TableSchema, prefix string) string {
@@ -218,32 +382,66 @@ func ColumnsWithDataTypes(columns model.TableSchema, prefix string) string {
 	return strings.Join(formattedColumns, ",")
 }
 
+func (ms *MSSQL) columnsWithDataTypes(columns model.TableSchema, prefix string) string {
+	names := lo.Keys(columns)
+	sort.Strings(names)
+
+	formattedColumns := lo.Map(names, func(name string, _ int) string {
+		return fmt.Sprintf(`%s %s`, ms.quoteIdentifier(prefix+name), ms.dataTypesMap[columns[name]])
+	})
+	return strings.Join(formattedColumns, ",")
+}
+
+func decimalDataType(precision, scale int) string {
+	return fmt.Sprintf("decimal(%d,%d)", precision, scale)
+}
+
+func validDecimalPrecisionAndScale(precision, scale int) bool {
+	return precision >= 1 && precision <= maxDecimalPrecision && scale >= 0 && scale <= precision
+}
+
 func (*MSSQL) IsEmpty(context.Context, model.Warehouse) (empty bool, err error) {
 	return
 }
 
+// Capabilities returns the features supported by MSSQL. Deleting the rows of the previous source job runs needs Warehouse.mssql.enableDeleteByJobs.
+func (ms *MSSQL) Capabilities() types.Capabilities {
+	return types.Capabilities{
+		Merge:        true,
+		DeleteByJobs: ms.config.enableDeleteByJobs,
+		BulkCopy:     true,
+	}
+}
+
+// deleteByCondition matches the records which are deleted by DeleteBy
+const deleteByCondition = `context_sources_job_run_id <> @jobrunid AND
+		context_sources_task_run_id <> @taskrunid AND
+		context_source_id = @sourceid AND
+		received_at < @starttime`
+
+func deleteByArgs(params warehouseutils.DeleteByParams) []any {
+	return []any{
+		sql.Named("jobrunid", params.JobRunId),
+		sql.Named("taskrunid", params.TaskRunId),
+		sql.Named("sourceid", params.SourceId),
+		sql.Named("starttime", params.StartTime),
+	}
+}
+
 func (ms *MSSQL) DeleteBy(ctx context.Context, tableNames []string, params warehouseutils.DeleteByParams) (err error) {
 	for _, tb := range tableNames {
 		ms.logger.Infof("MSSQL: Cleaning up the table %q ", tb)
-		sqlStatement := fmt.Sprintf(`DELETE FROM "%[1]s"."%[2]s" WHERE
-		context_sources_job_run_id <> @jobrunid AND
-		context_sources_task_run_id <> @taskrunid AND
-		context_source_id = @sourceid AND
-		received_at < @starttime`,
-			ms.Namespace,
-			tb,
+		sqlStatement := fmt.Sprintf(`DELETE FROM %[1]s WHERE
+		%[2]s`,
+			ms.quoteTable(tb),
+			deleteByCondition,
 		)
 
 		ms.logger.Debugf("MSSQL: Deleting rows in table in mysql for MSSQL:%s ", ms.Warehouse.Destination.ID)
 		ms.logger.Infof("MSSQL: Executing the statement %v", sqlStatement)
 
 		if ms.config.enableDeleteByJobs {
-			_, err = ms.DB.ExecContext(ctx, sqlStatement,
-				sql.Named("jobrunid", params.JobRunId),
-				sql.Named("taskrunid", params.TaskRunId),
-				sql.Named("sourceid", params.SourceId),
-				sql.Named("starttime", params.StartTime),
-			)
+			_, err = ms.DB.ExecContext(ctx, sqlStatement, deleteByArgs(params)...)
 			if err != nil {
 				ms.logger.Errorf("Error %s", err)
 				return err
@@ -254,12 +452,45 @@ func (ms *MSSQL) DeleteBy(ctx context.Context, tableNames []string, params wareh
 	return nil
 }
 
+// EstimateDeleteBy returns the number of records DeleteBy would delete from the tables, without deleting them.
+// Nothing is deleted unless Warehouse.mssql.enableDeleteByJobs is set, so the estimate is 0 then.
+func (ms *MSSQL) EstimateDeleteBy(ctx context.Context, tableNames []string, params warehouseutils.DeleteByParams) (int64, error) {
+	if !ms.config.enableDeleteByJobs {
+		return 0, nil
+	}
+	var total int64
+	for _, tb := range tableNames {
+		sqlStatement := fmt.Sprintf(`SELECT COUNT_BIG(*) FROM %[1]s WHERE
+		%[2]s`,
+			ms.quoteTable(tb),
+			deleteByCondition,
+		)
+
+		var count int64
+		if err := ms.DB.QueryRowContext(ctx, sqlStatement, deleteByArgs(params)...).Scan(&count); err != nil {
+			return 0, fmt.Errorf("estimating delete by for table %s: %w", tb, err)
+		}
+		total += count
+	}
+	return total, nil
+}
+
 func (ms *MSSQL) loadTable(
 	ctx context.Context,
 	tableName string,
 	tableSchemaInUpload model.TableSchema,
 	skipTempTableDelete bool,
-) (*types.LoadTableStats, string, error) {
+) (_ *types.LoadTableStats, _ string, err error) {
+	startTime := time.Now()
+	defer func() {
+		statTags := ms.loadTableStatTags(tableName)
+		statTags["status"] = loadTableSucceeded
+		if err != nil {
+			statTags["status"] = loadTableFailed
+		}
+		ms.stats.NewTaggedStat(loadTableDurationStat, stats.TimerType, statTags).Since(startTime)
+	}()
+
 	log := ms.logger.With(
 		logfield.SourceID, ms.Warehouse.Source.ID,
 		logfield.SourceType, ms.Warehouse.Source.SourceDefinition.Name,
@@ -271,242 +502,510 @@ func (ms *MSSQL) loadTable(
 	)
 	log.Infow("started loading")
 
+	// the surrogate key is assigned by the DB, both in the staging table (copied from the load table) and in the load table
+	tableSchemaInUpload = ms.withoutSurrogateKey(tableName, tableSchemaInUpload)
+
+	// columns differing only by case are the same column for MSSQL, so fail early identifying them instead of failing opaquely while loading
+	if err := validateColumnNames(tableName, tableSchemaInUpload); err != nil {
+		return nil, "", fmt.Errorf("validating column names: %w", err)
+	}
+
+	// the check runs before downloading the load files, so that no download is wasted.
+	// It is skipped if it can't be run (e.g. before SQL Server 2016, which has no Always Encrypted), the load then fails or succeeds as before
+	if encrypted, err := ms.encryptedColumns(ctx, tableName); err != nil {
+		log.Warnw("checking for encrypted columns", logfield.Error, err)
+	} else if err := validateEncryptedColumns(tableName, tableSchemaInUpload, encrypted); err != nil {
+		return nil, "", fmt.Errorf("validating encrypted columns: %w", err)
+	}
+
+	// mismatching values are still tolerated (loaded as NULL), the mismatches are only reported so that the types can be fixed upstream
+	var mismatches []types.SchemaMismatch
+	if ms.config.reportSchemaMismatches {
+		mismatches = schemaMismatches(tableSchemaInUpload, ms.Uploader.GetTableSchemaInWarehouse(tableName))
+		for _, mismatch := range mismatches {
+			log.Warnw("mismatch in schema",
+				logfield.ColumnName, mismatch.Column,
+				logfield.ColumnType, mismatch.UploadDataType,
+				"warehouseColumnType", mismatch.WarehouseDataType,
+			)
+		}
+	}
+
 	fileNames, err := ms.LoadFileDownLoader.Download(ctx, tableName)
 	if err != nil {
+		if ctx.Err() == nil && downloader.IsLoadFileNotFound(err) {
+			return nil, "", fmt.Errorf("downloading load files: %w: %w", ErrLoadFileNotFound, err)
+		}
 		return nil, "", fmt.Errorf("downloading load files: %w", err)
 	}
 	defer func() {
 		misc.RemoveFilePaths(fileNames...)
 	}()
 
-	stagingTableName := warehouseutils.StagingTableName(
-		provider,
-		tableName,
-		tableNameLimit,
-	)
+	// a table without load files is a clean no-op, no staging table is created either
+	if len(fileNames) == 0 {
+		log.Infow("no load files to load")
+		ms.stats.NewTaggedStat(loadTableNoLoadFilesStat, stats.CountType, ms.loadTableStatTags(tableName)).Increment()
+		return &types.LoadTableStats{SchemaMismatches: mismatches}, "", nil
+	}
+
+	// without the types of the columns, the strings are encoded for nvarchar columns, as before
+	nonUnicode, nonUnicodeErr := ms.nonUnicodeColumns(ctx, tableName)
+	if nonUnicodeErr != nil {
+		log.Warnw("checking for non-unicode columns", logfield.Error, nonUnicodeErr)
+	}
+	opts := rowsOptions{
+		nonUnicode: nonUnicode,
+		transforms: ms.valueTransformsFor(tableName),
+	}
+
+	// Session scoped temporary tables can only be used if the staging table is not needed after the load,
+	// since the statements of the load use the connection of the transaction.
+	// Neither can they be used if the staging table is loaded in chunks, since every chunk is committed in its own transaction.
+	loadStagingInChunks := ms.config.stagingCommitRows > 0
+	useTempStagingTable := ms.config.useTempStagingTables && !skipTempTableDelete && !loadStagingInChunks
+
+	var stagingTableName, quotedStagingTableName string
+	if useTempStagingTable {
+		stagingTableName = "#" + warehouseutils.StagingTableName(
+			provider,
+			tableName,
+			tempTableNameLimit-1,
+		)
+		quotedStagingTableName = ms.quoteIdentifier(stagingTableName)
+	} else {
+		stagingTableName = warehouseutils.StagingTableName(
+			provider,
+			tableName,
+			tableNameLimit,
+		)
+		quotedStagingTableName = ms.quoteStagingTable(stagingTableName)
+	}
 
 	// The use of prepared statements for creating temporary tables is not suitable in this context.
 	// Temporary tables in SQL Server have a limited scope and are automatically purged after the transaction commits.
-	// Therefore, creating normal tables is chosen as an alternative.
+	// Therefore, creating normal tables is chosen as an alternative, unless temporary staging tables are enabled.
+	// Temporary staging tables are created within the transaction of the load instead, so that they are only visible to it.
 	//
 	// For more information on this behavior:
 	// - See the discussion at https://github.com/denisenkom/go-mssqldb/issues/149 regarding prepared statements.
 	// - Refer to Microsoft's documentation on temporary tables at
 	//   https://docs.microsoft.com/en-us/previous-versions/sql/sql-server-2008-r2/ms175528(v=sql.105)?redirectedfrom=MSDN.
-	log.Debugw("creating staging table")
 	createStagingTableStmt := fmt.Sprintf(`
 		SELECT
-		  TOP 0 * INTO %[1]s.%[2]s
+		  TOP 0 * INTO %[1]s
 		FROM
-		  %[1]s.%[3]s;`,
-		ms.Namespace,
-		stagingTableName,
-		tableName,
+		  %[2]s;`,
+		quotedStagingTableName,
+		ms.quoteTable(tableName),
 	)
-	if _, err = ms.DB.ExecContext(ctx, createStagingTableStmt); err != nil {
-		return nil, "", fmt.Errorf("creating temporary table: %w", err)
-	}
-
-	if !skipTempTableDelete {
-		defer func() {
-			ms.dropStagingTable(ctx, stagingTableName)
-		}()
-	}
+	if !useTempStagingTable {
+		log.Debugw("creating staging table")
+		if _, err = ms.DB.ExecContext(ctx, createStagingTableStmt); err != nil {
+			return nil, "", fmt.Errorf("creating temporary table: %w", err)
+		}
 
-	txn, err := ms.DB.BeginTx(ctx, &sql.TxOptions{})
-	if err != nil {
-		return nil, "", fmt.Errorf("begin transaction: %w", err)
-	}
-	defer func() {
-		if err != nil {
-			_ = txn.Rollback()
+		if !skipTempTableDelete {
+			defer func() {
+				// staging table should be dropped even if the load was cancelled
+				ms.dropStagingTable(context.WithoutCancel(ctx), stagingTableName)
+			}()
 		}
-	}()
+	}
 
 	sortedColumnKeys := warehouseutils.SortColumnKeysFromColumnMap(
 		tableSchemaInUpload,
 	)
 
-	log.Debugw("creating prepared stmt for loading data")
-	copyInStmt := mssql.CopyIn(ms.Namespace+"."+stagingTableName, mssql.BulkOptions{CheckConstraints: false},
-		sortedColumnKeys...,
-	)
-	stmt, err := txn.PrepareContext(ctx, copyInStmt)
-	if err != nil {
-		return nil, "", fmt.Errorf("preparing copyIn statement: %w", err)
-	}
+	// a deadlock victim's transaction is rolled back as a whole, so the staging table is loaded again when retrying
+	var bad *badRows
+	loadInTransaction := func() (rowsInserted, rowsUpdated int64, err error) {
+		// the bad rows are collected again when retrying, since the staging table is loaded again
+		bad = &badRows{threshold: ms.config.badRowsThreshold}
 
-	log.Infow("loading data into staging table")
-	for _, fileName := range fileNames {
-		err = ms.loadDataIntoStagingTable(
-			ctx, log, stmt,
-			fileName, sortedColumnKeys,
-			tableSchemaInUpload,
+		copyInStmt := mssql.CopyIn(quotedStagingTableName, ms.stagingBulkOptions(len(sortedColumnKeys)),
+			sortedColumnKeys...,
 		)
+
+		if loadStagingInChunks {
+			log.Infow("loading data into staging table in chunks", "chunkSize", ms.config.stagingCommitRows)
+			err = ms.loadDataIntoStagingTableInChunks(
+				ctx, log, tableName,
+				quotedStagingTableName, copyInStmt,
+				fileNames, sortedColumnKeys,
+				tableSchemaInUpload, opts, bad,
+			)
+			if err != nil {
+				return 0, 0, fmt.Errorf("loading data into staging table in chunks: %w", err)
+			}
+		}
+
+		txn, err := ms.DB.BeginTx(ctx, &sql.TxOptions{})
 		if err != nil {
-			return nil, "", fmt.Errorf("loading data into staging table: %w", err)
+			return 0, 0, fmt.Errorf("begin transaction: %w", err)
 		}
-	}
-	if _, err = stmt.ExecContext(ctx); err != nil {
-		return nil, "", fmt.Errorf("executing copyIn statement: %w", err)
-	}
+		defer func() {
+			if err != nil {
+				_ = txn.Rollback()
+			}
+		}()
 
-	log.Infow("deleting from load table")
-	rowsDeleted, err := ms.deleteFromLoadTable(
-		ctx, txn, tableName,
-		stagingTableName,
-	)
-	if err != nil {
-		return nil, "", fmt.Errorf("delete from load table: %w", err)
+		if sessionSettingsStmt := ms.sessionSettingsStmt(); sessionSettingsStmt != "" {
+			log.Debugw("applying session settings")
+			if _, err = txn.ExecContext(ctx, sessionSettingsStmt); err != nil {
+				return 0, 0, fmt.Errorf("applying session settings: %w", err)
+			}
+		}
+
+		if useTempStagingTable {
+			log.Debugw("creating temporary staging table")
+			if _, err = txn.ExecContext(ctx, createStagingTableStmt); err != nil {
+				return 0, 0, fmt.Errorf("creating temporary table: %w", err)
+			}
+		}
+
+		if !loadStagingInChunks {
+			log.Debugw("creating prepared stmt for loading data")
+			stmt, err := txn.PrepareContext(ctx, copyInStmt)
+			if err != nil {
+				return 0, 0, fmt.Errorf("preparing copyIn statement: %w", err)
+			}
+
+			log.Infow("loading data into staging table")
+			w := &txnStagingWriter{stmt: stmt}
+			err = ms.loadDataIntoStagingTable(
+				ctx, log, w,
+				fileNames, sortedColumnKeys,
+				tableSchemaInUpload, opts, bad,
+			)
+			if err != nil {
+				return 0, 0, fmt.Errorf("loading data into staging table: %w", err)
+			}
+			if err = w.flush(ctx); err != nil {
+				return 0, 0, err
+			}
+		}
+
+		// the row counts are only used for cross-checking the rows reported as inserted, they never fail the load
+		var rowsBefore int64
+		if ms.config.verifyRowCounts {
+			if rowsBefore, err = ms.countRows(ctx, txn, tableName); err != nil {
+				return 0, 0, err
+			}
+		}
+
+		swapped := ms.swapLoadTable(tableName)
+		if swapped {
+			log.Infow("swapping load table")
+			rowsInserted, err = ms.swapIntoLoadTable(
+				ctx, txn, tableName,
+				quotedStagingTableName, sortedColumnKeys,
+			)
+			if err != nil {
+				return 0, 0, fmt.Errorf("swap into load table: %w", err)
+			}
+		} else {
+			log.Infow("merging into load table")
+			rowsInserted, rowsUpdated, err = ms.mergeIntoLoadTable(
+				ctx, txn, tableName,
+				quotedStagingTableName, sortedColumnKeys,
+			)
+			if err != nil {
+				return 0, 0, fmt.Errorf("merge into load table: %w", err)
+			}
+		}
+
+		if ms.config.verifyRowCounts {
+			rowsAfter, err := ms.countRows(ctx, txn, tableName)
+			if err != nil {
+				return 0, 0, err
+			}
+			if expected := expectedRowCount(rowsBefore, rowsInserted, swapped); rowsAfter != expected {
+				log.Warnw("discrepancy between the rows reported and the row count of the table",
+					"rowsBefore", rowsBefore,
+					"rowsAfter", rowsAfter,
+					"expectedRowsAfter", expected,
+					"rowsInserted", rowsInserted,
+					"rowsUpdated", rowsUpdated,
+				)
+				ms.stats.NewTaggedStat(loadTableRowCountDiscrepanciesStat, stats.CountType, ms.loadTableStatTags(tableName)).Increment()
+			}
+		}
+
+		if len(bad.rows) > 0 {
+			log.Warnw("routing bad rows to discards", "badRows", len(bad.rows))
+			if err = ms.loadBadRowsIntoDiscards(ctx, txn, tableName, bad.rows); err != nil {
+				return 0, 0, fmt.Errorf("loading bad rows into discards: %w", err)
+			}
+		}
+
+		if useTempStagingTable {
+			// the connection outlives the transaction in the pool, so the temporary staging table is dropped explicitly
+			log.Debugw("dropping temporary staging table")
+			if _, err = txn.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s;`, quotedStagingTableName)); err != nil {
+				return 0, 0, fmt.Errorf("dropping temporary table: %w", err)
+			}
+		}
+
+		log.Debugw("committing transaction")
+		if err = txn.Commit(); err != nil {
+			return 0, 0, fmt.Errorf("commit transaction: %w", err)
+		}
+		return rowsInserted, rowsUpdated, nil
 	}
 
-	log.Infow("inserting into load table")
-	rowsInserted, err := ms.insertIntoLoadTable(
-		ctx, txn, tableName,
-		stagingTableName, sortedColumnKeys,
+	var (
+		rowsInserted, rowsUpdated int64
+		attempts                  int
+		statTags                  = ms.loadTableStatTags(tableName)
 	)
+	err = ms.retryOnDeadlock(ctx, log, func() error {
+		attempts++
+		var loadErr error
+		rowsInserted, rowsUpdated, loadErr = loadInTransaction()
+		return loadErr
+	})
+	ms.stats.NewTaggedStat(loadTableDeadlockRetriesStat, stats.CountType, statTags).Count(attempts - 1)
 	if err != nil {
-		return nil, "", fmt.Errorf("insert into: %w", err)
+		return nil, "", err
 	}
+	ms.stats.NewTaggedStat(loadTableRowsInsertedStat, stats.CountType, statTags).Count(int(rowsInserted))
+	ms.stats.NewTaggedStat(loadTableRowsUpdatedStat, stats.CountType, statTags).Count(int(rowsUpdated))
 
-	log.Debugw("committing transaction")
-	if err = txn.Commit(); err != nil {
-		return nil, "", fmt.Errorf("commit transaction: %w", err)
+	if len(bad.rows) > 0 {
+		ms.stats.NewTaggedStat(loadTableBadRowsStat, stats.CountType, statTags).Count(len(bad.rows))
 	}
 
 	log.Infow("completed loading")
 
 	return &types.LoadTableStats{
-		RowsInserted: rowsInserted - rowsDeleted,
-		RowsUpdated:  rowsDeleted,
+		RowsInserted:     rowsInserted,
+		RowsUpdated:      rowsUpdated,
+		BadRows:          int64(len(bad.rows)),
+		SchemaMismatches: mismatches,
 	}, stagingTableName, nil
 }
 
-func (ms *MSSQL) loadDataIntoStagingTable(
+// stagingBulkOptions returns the options for loading data into the staging table.
+// Staging tables are loaded using bulk copy, which streams the rows instead of binding them as parameters.
+// Still, RowsPerBatch defaults to the number of rows fitting in Warehouse.mssql.stagingParametersPerBatch values (the 2100 parameters limit of SQL Server by default),
+// so that the batches of wide tables are kept as small as if their values were bound as parameters. KilobytesPerBatch is only sent if configured (> 0).
+func (ms *MSSQL) stagingBulkOptions(columns int) mssql.BulkOptions {
+	rowsPerBatch := ms.config.stagingRowsPerBatch
+	if rowsPerBatch <= 0 && columns > 0 {
+		rowsPerBatch = max(ms.config.stagingParametersPerBatch/columns, 1)
+	}
+	return mssql.BulkOptions{
+		CheckConstraints:  false,
+		RowsPerBatch:      max(rowsPerBatch, 0),
+		KilobytesPerBatch: max(ms.config.stagingKilobytesPerBatch, 0),
+	}
+}
+
+// loadDataIntoStagingTableInChunks loads the staging table committing every chunk of rows in its own transaction.
+// The staging table is emptied first, since a load retried after a deadlock would otherwise load the rows of the committed chunks again.
+func (ms *MSSQL) loadDataIntoStagingTableInChunks(
 	ctx context.Context,
 	log logger.Logger,
-	stmt *sql.Stmt,
-	fileName string,
+	tableName string,
+	quotedStagingTableName string,
+	copyInStmt string,
+	fileNames []string,
 	sortedColumnKeys []string,
 	tableSchemaInUpload model.TableSchema,
+	opts rowsOptions,
+	bad *badRows,
 ) error {
-	gzipFile, err := os.Open(fileName)
-	if err != nil {
-		return fmt.Errorf("opening file: %w", err)
+	if _, err := ms.DB.ExecContext(ctx, fmt.Sprintf(`TRUNCATE TABLE %s;`, quotedStagingTableName)); err != nil {
+		return fmt.Errorf("truncating staging table: %w", err)
 	}
-	defer func() {
-		_ = gzipFile.Close()
-	}()
 
-	gzipReader, err := gzip.NewReader(gzipFile)
+	w := &chunkedStagingWriter{
+		ms:         ms,
+		copyInStmt: copyInStmt,
+		chunkSize:  ms.config.stagingCommitRows,
+	}
+	defer w.rollback()
+
+	err := ms.loadDataIntoStagingTable(
+		ctx, log, w,
+		fileNames, sortedColumnKeys,
+		tableSchemaInUpload, opts, bad,
+	)
 	if err != nil {
-		return fmt.Errorf("reading file: %w", err)
+		return err
+	}
+	if err = w.flush(ctx); err != nil {
+		return err
 	}
+
+	ms.stats.NewTaggedStat(loadTableStagingChunksStat, stats.CountType, ms.loadTableStatTags(tableName)).Count(w.chunks)
+	return nil
+}
+
+func (ms *MSSQL) loadDataIntoStagingTable(
+	ctx context.Context,
+	log logger.Logger,
+	w stagingWriter,
+	fileNames []string,
+	sortedColumnKeys []string,
+	tableSchemaInUpload model.TableSchema,
+	opts rowsOptions,
+	bad *badRows,
+) error {
+	rows := ms.readRows(ctx, fileNames, tableSchemaInUpload, opts)
 	defer func() {
-		_ = gzipReader.Close()
+		_ = rows.Close()
 	}()
 
-	csvReader := csv.NewReader(gzipReader)
-
-	for {
-		var record []string
-		record, err = csvReader.Read()
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				break
+	// the transaction is rolled back by the caller if reading the rows is aborted
+	for rows.Next() {
+		row := rows.Row()
+		bad.totalRows++
+		if row.Err != nil {
+			br := badRow{
+				fileName: row.FileName,
+				line:     row.RecordNumber,
+				record:   row.Record,
+				reason:   warehouseutils.DiscardReasonColumnCountMismatch,
 			}
-			return fmt.Errorf("reading file: %w", err)
-		}
-		if len(sortedColumnKeys) != len(record) {
-			return fmt.Errorf("mismatch in number of columns: actual count: %d, expected count: %d",
-				len(record),
-				len(sortedColumnKeys),
-			)
-		}
-
-		recordInterface := make([]interface{}, 0, len(record))
-		for _, value := range record {
-			if strings.TrimSpace(value) == "" {
-				recordInterface = append(recordInterface, nil)
-			} else {
-				recordInterface = append(recordInterface, value)
+			if err := bad.add(br, row.Err); err != nil {
+				return err
 			}
+			continue
 		}
 
-		finalColumnValues := make([]interface{}, 0, len(record))
-		for index, value := range recordInterface {
-			valueType := tableSchemaInUpload[sortedColumnKeys[index]]
+		for _, invalid := range row.InvalidValues {
+			log.Warnw("mismatch in datatype",
+				logfield.ColumnType, invalid.DataType,
+				logfield.ColumnName, invalid.Column,
+				logfield.ColumnValue, invalid.Value,
+				logfield.Error, invalid.Err,
+			)
+		}
+		for index, value := range ms.config.csvDialect.values(row.Record) {
 			if value == nil {
 				log.Warnw("found nil value",
-					logfield.ColumnType, valueType,
-					logfield.ColumnName, sortedColumnKeys[index],
-				)
-
-				finalColumnValues = append(finalColumnValues, nil)
-				continue
-			}
-
-			processedVal, err := ms.ProcessColumnValue(
-				value.(string),
-				valueType,
-			)
-			if err != nil {
-				log.Warnw("mismatch in datatype",
-					logfield.ColumnType, valueType,
+					logfield.ColumnType, tableSchemaInUpload[sortedColumnKeys[index]],
 					logfield.ColumnName, sortedColumnKeys[index],
-					logfield.ColumnValue, value,
-					logfield.Error, err,
 				)
-				finalColumnValues = append(finalColumnValues, nil)
-			} else {
-				finalColumnValues = append(finalColumnValues, processedVal)
 			}
 		}
 
-		_, err = stmt.ExecContext(ctx, finalColumnValues...)
+		err := w.write(ctx, row.Values)
 		if err != nil {
 			return fmt.Errorf("exec statement error: %w", err)
 		}
 	}
-	return nil
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return bad.check()
 }
 
-func (as *MSSQL) ProcessColumnValue(
+// ProcessColumnValue converts the value read from the load file into the value to be inserted for the given data type.
+// Values representing NULL (empty values or the configured null token) are returned as nil for every data type, so that they are inserted as NULL rather than as zero values.
+func (ms *MSSQL) ProcessColumnValue(
 	value string,
 	valueType string,
 ) (interface{}, error) {
+	if ms.config.csvDialect.isNull(value) {
+		return nil, nil
+	}
+
 	switch valueType {
 	case model.IntDataType:
 		return strconv.Atoi(value)
 	case model.FloatDataType:
 		return strconv.ParseFloat(value, 64)
+	case model.DecimalDataType:
+		return ms.parseDecimal(value)
 	case model.DateTimeDataType:
-		return time.Parse(time.RFC3339, value)
+		t, err := ms.parseDatetime(value)
+		if err != nil {
+			return nil, err
+		}
+		return ms.config.datetimeType.convert(t), nil
 	case model.BooleanDataType:
 		return strconv.ParseBool(value)
 	case model.StringDataType:
-		if len(value) > stringLengthLimit {
-			value = value[:stringLengthLimit]
-		}
-		if !hasDiacritics(value) {
-			return value, nil
-		} else {
-			byteArr := str2ucs2(value)
-			if len(byteArr) > stringLengthLimit {
-				byteArr = byteArr[:stringLengthLimit]
-			}
-			return byteArr, nil
-		}
+		return encodeString(value), nil
 	default:
 		return value, nil
 	}
 }
 
+// convertColumnValue converts the value like ProcessColumnValue does, except that strings are returned as they are (not truncated nor encoded), see encodeString
+func (ms *MSSQL) convertColumnValue(value, valueType string) (interface{}, error) {
+	if valueType == model.StringDataType {
+		if ms.config.csvDialect.isNull(value) {
+			return nil, nil
+		}
+		return value, nil
+	}
+	return ms.ProcessColumnValue(value, valueType)
+}
+
+// encodeString truncates the string to the length of the string columns, and encodes it for nvarchar columns if it has diacritics
+func encodeString(value string) interface{} {
+	value = truncateString(value)
+	if !hasDiacritics(value) {
+		return value
+	}
+	byteArr := str2ucs2(value)
+	if len(byteArr) > stringLengthLimit {
+		byteArr = byteArr[:stringLengthLimit]
+	}
+	return byteArr
+}
+
+// parseDatetime parses the value as RFC3339. If that fails, the fallback layouts are tried,
+// interpreting values without an offset in the configured default timezone.
+func (ms *MSSQL) parseDatetime(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err == nil {
+		return t, nil
+	}
+
+	for _, layout := range ms.config.datetimeFallbackLayouts {
+		if t, fallbackErr := time.ParseInLocation(layout, value, ms.config.datetimeDefaultLocation); fallbackErr == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// parseDecimal parses the value without going through float64, so that no precision is lost.
+// The value is returned as a string rounded to the configured scale, which is what the bulk copy expects for decimal columns.
+func (ms *MSSQL) parseDecimal(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if strings.Contains(value, "/") {
+		return "", fmt.Errorf("invalid decimal %q", value)
+	}
+
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return "", fmt.Errorf("invalid decimal %q", value)
+	}
+
+	formatted := r.FloatString(ms.config.decimalScale)
+
+	integerDigits := strings.TrimPrefix(formatted, "-")
+	if i := strings.Index(integerDigits, "."); i >= 0 {
+		integerDigits = integerDigits[:i]
+	}
+	integerDigits = strings.TrimLeft(integerDigits, "0")
+	if len(integerDigits) > ms.config.decimalPrecision-ms.config.decimalScale {
+		return "", fmt.Errorf("decimal %q out of range for precision %d and scale %d", value, ms.config.decimalPrecision, ms.config.decimalScale)
+	}
+	return formatted, nil
+}
+
 func (ms *MSSQL) deleteFromLoadTable(
 	ctx context.Context,
 	txn *sqlmw.Tx,
 	tableName string,
-	stagingTableName string,
+	quotedStagingTableName string,
 ) (int64, error) {
 	primaryKey := "id"
 	if column, ok := primaryKeyMap[tableName]; ok {
@@ -515,30 +1014,29 @@ func (ms *MSSQL) deleteFromLoadTable(
 
 	var additionalDeleteStmtClause string
 	if tableName == warehouseutils.DiscardsTable {
-		additionalDeleteStmtClause = fmt.Sprintf(`AND _source.%[3]s = %[1]q.%[2]q.%[3]q AND _source.%[4]s = %[1]q.%[2]q.%[4]q`,
-			ms.Namespace,
-			tableName,
-			"table_name",
-			"column_name",
+		additionalDeleteStmtClause = fmt.Sprintf(`AND _source.%[2]s = %[1]s.%[2]s AND _source.%[3]s = %[1]s.%[3]s`,
+			ms.quoteTable(tableName),
+			ms.quoteIdentifier("table_name"),
+			ms.quoteIdentifier("column_name"),
 		)
 	}
 
 	deleteStmt := fmt.Sprintf(`
 		DELETE FROM
-		  %[1]q.%[2]q
+		  %[1]s
 		FROM
-		  %[1]q.%[3]q AS _source
+		  %[2]s AS _source
 		WHERE
 		  (
-			_source.%[4]s = %[1]q.%[2]q.%[4]q %[5]s
+			_source.%[3]s = %[1]s.%[3]s %[4]s
 		  );`,
-		ms.Namespace,
-		tableName,
-		stagingTableName,
-		primaryKey,
+		ms.quoteTable(tableName),
+		quotedStagingTableName,
+		ms.quoteIdentifier(primaryKey),
 		additionalDeleteStmtClause,
 	)
 
+	ms.logMergeStatement(tableName, "delete", deleteStmt)
 	r, err := txn.ExecContext(ctx, deleteStmt)
 	if err != nil {
 		return 0, fmt.Errorf("deleting from main table: %w", err)
@@ -550,43 +1048,72 @@ func (ms *MSSQL) insertIntoLoadTable(
 	ctx context.Context,
 	txn *sqlmw.Tx,
 	tableName string,
-	stagingTableName string,
+	quotedStagingTableName string,
 	sortedColumnKeys []string,
+	mergeKey []string,
+	onlyNew bool,
 ) (int64, error) {
-	partitionKey := "id"
-	if column, ok := partitionKeyMap[tableName]; ok {
-		partitionKey = column
-	}
-
-	quotedColumnNames := warehouseutils.DoubleQuoteAndJoinByComma(
+	quotedColumnNames := ms.quoteAndJoinByComma(
 		sortedColumnKeys,
 	)
 
+	// without a merge key, all the rows of the staging table are appended
+	if len(mergeKey) == 0 {
+		insertStmt := fmt.Sprintf(`
+		INSERT INTO %[1]s (%[2]s)
+		SELECT
+		  %[2]s
+		FROM
+		  %[3]s;`,
+			ms.quoteTable(tableName),
+			quotedColumnNames,
+			quotedStagingTableName,
+		)
+
+		ms.logMergeStatement(tableName, "insert", insertStmt)
+		r, err := txn.ExecContext(ctx, insertStmt)
+		if err != nil {
+			return 0, fmt.Errorf("inserting into main table: %w", err)
+		}
+		return r.RowsAffected()
+	}
+
+	// only the rows which don't exist in the load table are inserted
+	var additionalInsertStmtClause string
+	if onlyNew {
+		additionalInsertStmtClause = fmt.Sprintf(`AND NOT EXISTS (SELECT 1 FROM %[1]s AS _target WHERE %[2]s)`,
+			ms.quoteTable(tableName),
+			ms.joinMergeKey(mergeKey, "_target", "_"),
+		)
+	}
+
 	insertStmt := fmt.Sprintf(`
-		INSERT INTO %[1]q.%[2]q (%[3]s)
+		INSERT INTO %[1]s (%[2]s)
 		SELECT
-		  %[3]s
+		  %[2]s
 		FROM
 		  (
 			SELECT
 			  *,
 			  ROW_NUMBER() OVER (
-				PARTITION BY %[5]s
+				PARTITION BY %[4]s
 				ORDER BY
-				  received_at DESC
+				  %[6]s
 			  ) AS _rudder_staging_row_number
 			FROM
-			  %[1]q.%[4]q
+			  %[3]s
 		  ) AS _
 		WHERE
-		  _rudder_staging_row_number = 1;`,
-		ms.Namespace,
-		tableName,
+		  _rudder_staging_row_number = 1 %[5]s;`,
+		ms.quoteTable(tableName),
 		quotedColumnNames,
-		stagingTableName,
-		partitionKey,
+		quotedStagingTableName,
+		ms.quoteAndJoinByComma(mergeKey),
+		additionalInsertStmtClause,
+		ms.dedupOrderBy(sortedColumnKeys),
 	)
 
+	ms.logMergeStatement(tableName, "insert", insertStmt)
 	r, err := txn.ExecContext(ctx, insertStmt)
 	if err != nil {
 		return 0, fmt.Errorf("inserting into main table: %w", err)
@@ -605,6 +1132,14 @@ func str2ucs2(s string) []byte {
 	return ucs2
 }
 
+// truncateString truncates the string to the length of the string columns
+func truncateString(value string) string {
+	if len(value) > stringLengthLimit {
+		return value[:stringLengthLimit]
+	}
+	return value
+}
+
 func hasDiacritics(str string) bool {
 	for _, x := range str {
 		if utf8.RuneLen(x) > 1 {
@@ -627,6 +1162,10 @@ func (ms *MSSQL) loadUserTables(ctx context.Context) (errorMap map[string]error)
 		return
 	}
 	errorMap[warehouseutils.UsersTable] = nil
+	// the users are only derived from the identifies, so there are none to load without identifies
+	if identifyStagingTable == "" {
+		return
+	}
 
 	unionStagingTableName := warehouseutils.StagingTableName(provider, "users_identifies_union", tableNameLimit)
 	stagingTableName := warehouseutils.StagingTableName(provider, warehouseutils.UsersTable, tableNameLimit)
@@ -634,38 +1173,43 @@ func (ms *MSSQL) loadUserTables(ctx context.Context) (errorMap map[string]error)
 	defer ms.dropStagingTable(ctx, unionStagingTableName)
 	defer ms.dropStagingTable(ctx, identifyStagingTable)
 
+	quotedUnionStagingTableName := ms.quoteStagingTable(unionStagingTableName)
+	quotedID := ms.quoteIdentifier("id")
+	quotedUserID := ms.quoteIdentifier("user_id")
+
 	userColMap := ms.Uploader.GetTableSchemaInWarehouse(warehouseutils.UsersTable)
 	var userColNames, firstValProps []string
 	for colName := range userColMap {
 		if colName == "id" {
 			continue
 		}
-		userColNames = append(userColNames, fmt.Sprintf(`%q`, colName))
+		quotedColName := ms.quoteIdentifier(colName)
+		userColNames = append(userColNames, quotedColName)
 		caseSubQuery := fmt.Sprintf(`case
 						  when (exists(select 1)) then (
-						  	select "%[1]s" from %[2]s
-						  	where x.id = %[2]s.id
-							  and "%[1]s" is not null
-							  order by received_at desc
+						  	select %[1]s from %[2]s
+						  	where x.%[3]s = %[2]s.%[3]s
+							  and %[1]s is not null
+							  order by %[4]s desc
 						  	OFFSET 0 ROWS
 							FETCH NEXT 1 ROWS ONLY)
-						  end as "%[1]s"`, colName, ms.Namespace+"."+unionStagingTableName)
+						  end as %[1]s`, quotedColName, quotedUnionStagingTableName, quotedID, ms.quoteIdentifier("received_at"))
 
 		// IGNORE NULLS only supported in Azure SQL edge, in which case the query can be shortened to below
 		// https://docs.microsoft.com/en-us/sql/t-sql/functions/first-value-transact-sql?view=sql-server-ver15
-		// caseSubQuery := fmt.Sprintf(`FIRST_VALUE(%[1]s) IGNORE NULLS OVER (PARTITION BY id ORDER BY received_at DESC ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) AS "%[1]s"`, colName)
+		// caseSubQuery := fmt.Sprintf(`FIRST_VALUE(%[1]s) IGNORE NULLS OVER (PARTITION BY id ORDER BY received_at DESC ROWS BETWEEN UNBOUNDED PRECEDING AND UNBOUNDED FOLLOWING) AS %[1]s`, quotedColName)
 		firstValProps = append(firstValProps, caseSubQuery)
 	}
 
 	// TODO: skipped top level temporary table for now
 	sqlStatement := fmt.Sprintf(`SELECT * into %[5]s FROM
 												((
-													SELECT id, %[4]s FROM %[2]s WHERE id in (SELECT user_id FROM %[3]s WHERE user_id IS NOT NULL)
+													SELECT %[1]s, %[4]s FROM %[2]s WHERE %[1]s in (SELECT %[6]s FROM %[3]s WHERE %[6]s IS NOT NULL)
 												) UNION
 												(
-													SELECT user_id, %[4]s FROM %[3]s  WHERE user_id IS NOT NULL
+													SELECT %[6]s, %[4]s FROM %[3]s  WHERE %[6]s IS NOT NULL
 												)) a
-											`, ms.Namespace, ms.Namespace+"."+warehouseutils.UsersTable, ms.Namespace+"."+identifyStagingTable, strings.Join(userColNames, ","), ms.Namespace+"."+unionStagingTableName)
+											`, quotedID, ms.quoteTable(warehouseutils.UsersTable), ms.quoteStagingTable(identifyStagingTable), strings.Join(userColNames, ","), quotedUnionStagingTableName, quotedUserID)
 
 	ms.logger.Debugf("MSSQL: Creating staging table for union of users table with identify staging table: %s\n", sqlStatement)
 	_, err = ms.DB.ExecContext(ctx, sqlStatement)
@@ -677,13 +1221,14 @@ func (ms *MSSQL) loadUserTables(ctx context.Context) (errorMap map[string]error)
 	sqlStatement = fmt.Sprintf(`SELECT * INTO %[1]s FROM (SELECT DISTINCT * FROM
 										(
 											SELECT
-											x.id, %[2]s
+											x.%[4]s, %[2]s
 											FROM %[3]s as x
 										) as xyz
 									) a`,
-		ms.Namespace+"."+stagingTableName,
+		ms.quoteStagingTable(stagingTableName),
 		strings.Join(firstValProps, ","),
-		ms.Namespace+"."+unionStagingTableName,
+		quotedUnionStagingTableName,
+		quotedID,
 	)
 
 	ms.logger.Debugf("MSSQL: Creating staging table for users: %s\n", sqlStatement)
@@ -701,8 +1246,7 @@ func (ms *MSSQL) loadUserTables(ctx context.Context) (errorMap map[string]error)
 		return
 	}
 
-	primaryKey := "id"
-	sqlStatement = fmt.Sprintf(`DELETE FROM %[1]s."%[2]s" FROM %[3]s _source where (_source.%[4]s = %[1]s.%[2]s.%[4]s)`, ms.Namespace, warehouseutils.UsersTable, ms.Namespace+"."+stagingTableName, primaryKey)
+	sqlStatement = fmt.Sprintf(`DELETE FROM %[1]s FROM %[2]s _source where (_source.%[3]s = %[1]s.%[3]s)`, ms.quoteTable(warehouseutils.UsersTable), ms.quoteStagingTable(stagingTableName), quotedID)
 	ms.logger.Infof("MSSQL: Dedup records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
 	_, err = tx.ExecContext(ctx, sqlStatement)
 	if err != nil {
@@ -712,7 +1256,7 @@ func (ms *MSSQL) loadUserTables(ctx context.Context) (errorMap map[string]error)
 		return
 	}
 
-	sqlStatement = fmt.Sprintf(`INSERT INTO "%[1]s"."%[2]s" (%[4]s) SELECT %[4]s FROM  %[3]s`, ms.Namespace, warehouseutils.UsersTable, ms.Namespace+"."+stagingTableName, strings.Join(append([]string{"id"}, userColNames...), ","))
+	sqlStatement = fmt.Sprintf(`INSERT INTO %[1]s (%[3]s) SELECT %[3]s FROM  %[2]s`, ms.quoteTable(warehouseutils.UsersTable), ms.quoteStagingTable(stagingTableName), strings.Join(append([]string{quotedID}, userColNames...), ","))
 	ms.logger.Infof("MSSQL: Inserting records for table:%s using staging table: %s\n", warehouseutils.UsersTable, sqlStatement)
 	_, err = tx.ExecContext(ctx, sqlStatement)
 
@@ -733,11 +1277,55 @@ func (ms *MSSQL) loadUserTables(ctx context.Context) (errorMap map[string]error)
 	return
 }
 
+// CreateSchema creates the namespace, and the schema of the staging tables if it is a separate one
 func (ms *MSSQL) CreateSchema(ctx context.Context) (err error) {
-	sqlStatement := fmt.Sprintf(`IF NOT EXISTS ( SELECT  * FROM  sys.schemas WHERE   name = N'%s' )
-    EXEC('CREATE SCHEMA [%s]');`, ms.Namespace, ms.Namespace)
+	if err = warehouseutils.ValidateNamespace(provider, ms.Namespace); err != nil {
+		return fmt.Errorf("validating namespace: %w", err)
+	}
+	if err = ms.createSchema(ctx, ms.Namespace); err != nil {
+		return err
+	}
+
+	if stagingNamespace := ms.stagingNamespace(); stagingNamespace != ms.Namespace {
+		if err = warehouseutils.ValidateNamespace(provider, stagingNamespace); err != nil {
+			return fmt.Errorf("validating staging schema: %w", err)
+		}
+		if err = ms.createSchema(ctx, stagingNamespace); err != nil {
+			return fmt.Errorf("creating staging schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// schemaExists returns true if the schema exists, whatever the privileges of the user on it
+func (ms *MSSQL) schemaExists(ctx context.Context, schema string) (exists bool, err error) {
+	sqlStatement := `SELECT CASE WHEN EXISTS (SELECT 1 FROM sys.schemas WHERE name = @schema) THEN 1 ELSE 0 END;`
+	err = ms.DB.QueryRowContext(ctx, sqlStatement, sql.Named("schema", schema)).Scan(&exists)
+	return
+}
+
+// createSchema creates the schema if it doesn't exist yet.
+// The existence of the schema is checked first, so that users without the CREATE SCHEMA privilege can still load into pre-provisioned schemas.
+func (ms *MSSQL) createSchema(ctx context.Context, schema string) (err error) {
+	schemaExists, err := ms.schemaExists(ctx, schema)
+	if err != nil {
+		return fmt.Errorf("checking if schema %s exists: %w", schema, err)
+	}
+	if schemaExists {
+		ms.logger.Infof("MSSQL: Skipping creating schema %s for MSSQL:%s since it already exists", schema, ms.Warehouse.Destination.ID)
+		return nil
+	}
+
+	sqlStatement := fmt.Sprintf(`IF NOT EXISTS ( SELECT  * FROM  sys.schemas WHERE   name = %s )
+    EXEC(%s);`,
+		quoteString(schema),
+		quoteString("CREATE SCHEMA "+ms.quoteIdentifier(schema)),
+	)
 	ms.logger.Infof("MSSQL: Creating schema name in mssql for MSSQL:%s : %v", ms.Warehouse.Destination.ID, sqlStatement)
-	_, err = ms.DB.ExecContext(ctx, sqlStatement)
+	err = ms.retryOnDeadlock(ctx, ms.logger.With(logfield.Namespace, schema), func() error {
+		_, err := ms.DB.ExecContext(ctx, sqlStatement)
+		return err
+	})
 	if errors.Is(err, io.EOF) {
 		return nil
 	}
@@ -746,31 +1334,60 @@ func (ms *MSSQL) CreateSchema(ctx context.Context) (err error) {
 
 func (ms *MSSQL) dropStagingTable(ctx context.Context, stagingTableName string) {
 	ms.logger.Infof("MSSQL: dropping table %+v\n", stagingTableName)
-	_, err := ms.DB.ExecContext(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, ms.Namespace+"."+stagingTableName))
+	_, err := ms.DB.ExecContext(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s`, ms.quoteStagingTable(stagingTableName)))
 	if err != nil {
-		ms.logger.Errorf("MSSQL:  Error dropping staging table %s in mssql: %v", ms.Namespace+"."+stagingTableName, err)
+		ms.logger.Errorf("MSSQL:  Error dropping staging table %s in mssql: %v", ms.stagingNamespace()+"."+stagingTableName, err)
 	}
 }
 
-func (ms *MSSQL) createTable(ctx context.Context, name string, columns model.TableSchema) (err error) {
-	sqlStatement := fmt.Sprintf(`IF  NOT EXISTS (SELECT 1 FROM sys.objects WHERE object_id = OBJECT_ID(N'%[1]s') AND type = N'U')
-	CREATE TABLE %[1]s ( %v )`, name, ColumnsWithDataTypes(columns, ""))
+// GenerateCreateTableSQL returns the statement used by CreateTable to create the table, without executing it.
+// The columns are sorted by name, so that the statement is the same for the same schema. The surrogate key column, if any, comes first.
+func (ms *MSSQL) GenerateCreateTableSQL(tableName string, schema model.TableSchema) string {
+	name := ms.quoteTable(tableName)
+	columns := ms.columnsWithDataTypes(schema, "")
+	if surrogateKey := ms.surrogateKeyDefinition(tableName, schema); surrogateKey != "" {
+		columns = surrogateKey + "," + columns
+	}
+	return fmt.Sprintf(`IF  NOT EXISTS (SELECT 1 FROM sys.objects WHERE object_id = OBJECT_ID(%[1]s) AND type = N'U')
+	CREATE TABLE %[2]s ( %[3]v )`, quoteString(name), name, columns)
+}
+
+func (ms *MSSQL) createTable(ctx context.Context, tableName string, columns model.TableSchema) (err error) {
+	sqlStatement := ms.GenerateCreateTableSQL(tableName, columns)
+
+	log := ms.logger.With(logfield.TableName, tableName)
+	exec := func(sqlStatement string) error {
+		return ms.retryOnDeadlock(ctx, log, func() error {
+			_, err := ms.DB.ExecContext(ctx, sqlStatement)
+			return err
+		})
+	}
 
 	ms.logger.Infof("MSSQL: Creating table in mssql for MSSQL:%s : %v", ms.Warehouse.Destination.ID, sqlStatement)
-	_, err = ms.DB.ExecContext(ctx, sqlStatement)
+	if err = exec(sqlStatement); err != nil {
+		return
+	}
+
+	if sqlStatement = ms.generateCreateClusteredIndexSQL(tableName, columns); sqlStatement == "" {
+		return
+	}
+	ms.logger.Infof("MSSQL: Creating clustered index in mssql for MSSQL:%s : %v", ms.Warehouse.Destination.ID, sqlStatement)
+	if err = exec(sqlStatement); err != nil {
+		return fmt.Errorf("creating clustered index: %w", err)
+	}
 	return
 }
 
 func (ms *MSSQL) CreateTable(ctx context.Context, tableName string, columnMap model.TableSchema) (err error) {
 	// Search paths doesn't exist unlike Postgres, default is dbo. Hence, use namespace wherever possible
-	err = ms.createTable(ctx, ms.Namespace+"."+tableName, columnMap)
+	err = ms.createTable(ctx, tableName, columnMap)
 	return err
 }
 
 func (ms *MSSQL) DropTable(ctx context.Context, tableName string) (err error) {
-	sqlStatement := `DROP TABLE "%[1]s"."%[2]s"`
+	sqlStatement := fmt.Sprintf(`DROP TABLE %s`, ms.quoteTable(tableName))
 	ms.logger.Infof("AZ: Dropping table in synapse for AZ:%s : %v", ms.Warehouse.Destination.ID, sqlStatement)
-	_, err = ms.DB.ExecContext(ctx, fmt.Sprintf(sqlStatement, ms.Namespace, tableName))
+	_, err = ms.DB.ExecContext(ctx, sqlStatement)
 	return
 }
 
@@ -788,25 +1405,23 @@ func (ms *MSSQL) AddColumns(ctx context.Context, tableName string, columnsInfo [
 			  FROM
 				SYS.COLUMNS
 			  WHERE
-				OBJECT_ID = OBJECT_ID(N'%[1]s.%[2]s')
-				AND name = '%[3]s'
+				OBJECT_ID = OBJECT_ID(%[1]s)
+				AND name = %[2]s
 			)`,
-			ms.Namespace,
-			tableName,
-			columnsInfo[0].Name,
+			quoteString(ms.quoteTable(tableName)),
+			quoteString(columnsInfo[0].Name),
 		))
 	}
 
 	queryBuilder.WriteString(fmt.Sprintf(`
 		ALTER TABLE
-		  %s.%s
+		  %s
 		ADD`,
-		ms.Namespace,
-		tableName,
+		ms.quoteTable(tableName),
 	))
 
 	for _, columnInfo := range columnsInfo {
-		queryBuilder.WriteString(fmt.Sprintf(` %q %s,`, columnInfo.Name, rudderDataTypesMapToMssql[columnInfo.Type]))
+		queryBuilder.WriteString(fmt.Sprintf(` %s %s,`, ms.quoteIdentifier(columnInfo.Name), ms.dataTypesMap[columnInfo.Type]))
 	}
 
 	query = strings.TrimSuffix(queryBuilder.String(), ",")
@@ -834,11 +1449,22 @@ func (ms *MSSQL) TestConnection(ctx context.Context, _ model.Warehouse) error {
 }
 
 func (ms *MSSQL) Setup(_ context.Context, warehouse model.Warehouse, uploader warehouseutils.Uploader) (err error) {
+	if warehouseutils.ReadAsBool(columnEncryption, warehouse.Destination.Config) {
+		return fmt.Errorf("%w: %s can't be enabled", ErrColumnEncryptionNotSupported, columnEncryption)
+	}
+
 	ms.Warehouse = warehouse
 	ms.Namespace = warehouse.Namespace
 	ms.Uploader = uploader
 	ms.ObjectStorage = warehouseutils.ObjectStorageType(warehouseutils.MSSQL, warehouse.Destination.Config, ms.Uploader.UseRudderStorage())
-	ms.LoadFileDownLoader = downloader.NewDownloader(&warehouse, uploader, ms.config.numWorkersDownloadLoadFiles)
+	ms.retryBudget = newRetryBudget(ms.config.uploadRetryBudget)
+	// object storage failures (e.g. 5xx) are retried on their own, the deadlocks of the load are retried separately.
+	// Both are taken from the same retry budget, so that a flaky destination can't multiply the attempts of the upload.
+	ms.LoadFileDownLoader = downloader.NewDownloader(&warehouse, uploader, ms.config.numWorkersDownloadLoadFiles,
+		downloader.WithRetries(ms.config.loadFileDownloadRetries, ms.config.loadFileDownloadBackoff),
+		downloader.WithRetryBudget(ms.takeDownloadRetry),
+	)
+	ms.applyDataTypes()
 
 	if ms.DB, err = ms.connect(); err != nil {
 		return fmt.Errorf("connecting to mssql: %w", err)
@@ -851,6 +1477,14 @@ func (ms *MSSQL) CrashRecover(ctx context.Context) {
 }
 
 func (ms *MSSQL) dropDanglingStagingTables(ctx context.Context) {
+	ms.dropDanglingStagingTablesIn(ctx, ms.Namespace)
+	if stagingNamespace := ms.stagingNamespace(); stagingNamespace != ms.Namespace {
+		ms.dropDanglingStagingTablesIn(ctx, stagingNamespace)
+	}
+}
+
+// dropDanglingStagingTablesIn drops the staging tables left behind in the schema, e.g. by a crash
+func (ms *MSSQL) dropDanglingStagingTablesIn(ctx context.Context, schema string) {
 	sqlStatement := fmt.Sprintf(`
 		select
 		  table_name
@@ -860,7 +1494,7 @@ func (ms *MSSQL) dropDanglingStagingTables(ctx context.Context) {
 		  table_schema = '%s'
 		  AND table_name like '%s';
 	`,
-		ms.Namespace,
+		schema,
 		fmt.Sprintf(`%s%%`, warehouseutils.StagingTablePrefix(provider)),
 	)
 	rows, err := ms.DB.QueryContext(ctx, sqlStatement)
@@ -884,7 +1518,7 @@ func (ms *MSSQL) dropDanglingStagingTables(ctx context.Context) {
 	}
 	ms.logger.Infof("WH: MSSQL: Dropping dangling staging tables: %+v  %+v\n", len(stagingTableNames), stagingTableNames)
 	for _, stagingTableName := range stagingTableNames {
-		_, err := ms.DB.ExecContext(ctx, fmt.Sprintf(`DROP TABLE "%[1]s"."%[2]s"`, ms.Namespace, stagingTableName))
+		_, err := ms.DB.ExecContext(ctx, fmt.Sprintf(`DROP TABLE %s.%s`, ms.quoteIdentifier(schema), ms.quoteIdentifier(stagingTableName)))
 		if err != nil {
 			ms.logger.Errorf("WH: MSSQL:  Error dropping dangling staging table: %s in redshift: %v\n", stagingTableName, err)
 		}
@@ -900,7 +1534,9 @@ func (ms *MSSQL) FetchSchema(ctx context.Context) (model.Schema, model.Schema, e
 		SELECT
 			table_name,
 			column_name,
-			data_type
+			data_type,
+			numeric_precision,
+			numeric_scale
 		FROM
 			INFORMATION_SCHEMA.COLUMNS
 		WHERE
@@ -919,16 +1555,21 @@ func (ms *MSSQL) FetchSchema(ctx context.Context) (model.Schema, model.Schema, e
 	defer func() { _ = rows.Close() }()
 
 	for rows.Next() {
-		var tableName, columnName, columnType string
+		var (
+			tableName, columnName, columnType string
+			numericPrecision, numericScale    sql.NullInt64
+		)
 
-		if err := rows.Scan(&tableName, &columnName, &columnType); err != nil {
+		if err := rows.Scan(&tableName, &columnName, &columnType, &numericPrecision, &numericScale); err != nil {
 			return nil, nil, fmt.Errorf("scanning schema: %w", err)
 		}
 
 		if _, ok := schema[tableName]; !ok {
 			schema[tableName] = make(model.TableSchema)
 		}
-		if datatype, ok := mssqlDataTypesMapToRudder[columnType]; ok {
+		if ms.isDecimalColumn(columnType, numericPrecision, numericScale) {
+			schema[tableName][columnName] = model.DecimalDataType
+		} else if datatype, ok := mssqlDataTypesMapToRudder[columnType]; ok {
 			schema[tableName][columnName] = datatype
 		} else {
 			if _, ok := unrecognizedSchema[tableName]; !ok {
@@ -946,8 +1587,25 @@ func (ms *MSSQL) FetchSchema(ctx context.Context) (model.Schema, model.Schema, e
 	return schema, unrecognizedSchema, nil
 }
 
+// isDecimalColumn returns true if the column was created for the decimal data type.
+// decimal columns not matching the configured precision and scale are treated as floats.
+func (ms *MSSQL) isDecimalColumn(columnType string, precision, scale sql.NullInt64) bool {
+	if columnType != "decimal" && columnType != "numeric" {
+		return false
+	}
+	if !precision.Valid || !scale.Valid {
+		return false
+	}
+	return ms.dataTypesMap[model.DecimalDataType] != ms.dataTypesMap[model.FloatDataType] &&
+		ms.dataTypesMap[model.DecimalDataType] == decimalDataType(int(precision.Int64), int(scale.Int64))
+}
+
 func (ms *MSSQL) LoadUserTables(ctx context.Context) map[string]error {
-	return ms.loadUserTables(ctx)
+	errorMap := ms.loadUserTables(ctx)
+	for tableName, err := range errorMap {
+		ms.captureFailedLoad(ctx, tableName, err)
+	}
+	return errorMap
 }
 
 func (ms *MSSQL) LoadTable(ctx context.Context, tableName string) (*types.LoadTableStats, error) {
@@ -957,6 +1615,7 @@ func (ms *MSSQL) LoadTable(ctx context.Context, tableName string) (*types.LoadTa
 		ms.Uploader.GetTableSchemaInUpload(tableName),
 		false,
 	)
+	ms.captureFailedLoad(ctx, tableName, err)
 	return loadTableStat, err
 }
 
@@ -998,10 +1657,9 @@ func (ms *MSSQL) Connect(_ context.Context, warehouse model.Warehouse) (client.C
 }
 
 func (ms *MSSQL) LoadTestTable(ctx context.Context, _, tableName string, payloadMap map[string]interface{}, _ string) (err error) {
-	sqlStatement := fmt.Sprintf(`INSERT INTO %q.%q (%v) VALUES (%s)`,
-		ms.Namespace,
-		tableName,
-		fmt.Sprintf(`%q, %q`, "id", "val"),
+	sqlStatement := fmt.Sprintf(`INSERT INTO %s (%s) VALUES (%s)`,
+		ms.quoteTable(tableName),
+		ms.quoteAndJoinByComma([]string{"id", "val"}),
 		fmt.Sprintf(`'%d', '%s'`, payloadMap["id"], payloadMap["val"]),
 	)
 	_, err = ms.DB.ExecContext(ctx, sqlStatement)
@@ -0,0 +1,53 @@
+package mssql
+
+import (
+	"strings"
+)
+
+const (
+	quoteStrategyDoubleQuotes = "doubleQuotes"
+	quoteStrategyBrackets     = "brackets"
+)
+
+// quoteFunc quotes an identifier (schema, table or column name), escaping the quote characters it contains
+type quoteFunc func(identifier string) string
+
+var quoteStrategies = map[string]quoteFunc{
+	quoteStrategyDoubleQuotes: quoteWithDoubleQuotes,
+	quoteStrategyBrackets:     quoteWithBrackets,
+}
+
+// quoteWithDoubleQuotes quotes the identifier as a delimited identifier, escaping embedded double quotes by doubling them.
+// It requires QUOTED_IDENTIFIER to be ON, which is the default for the driver.
+func quoteWithDoubleQuotes(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// quoteWithBrackets quotes the identifier using brackets, escaping embedded closing brackets by doubling them. Same as QUOTENAME.
+func quoteWithBrackets(identifier string) string {
+	return "[" + strings.ReplaceAll(identifier, "]", "]]") + "]"
+}
+
+// quoteIdentifier quotes the identifier using the configured strategy
+func (ms *MSSQL) quoteIdentifier(identifier string) string {
+	return ms.quote(identifier)
+}
+
+// quoteTable returns the quoted name of the table in the namespace
+func (ms *MSSQL) quoteTable(tableName string) string {
+	return ms.quote(ms.Namespace) + "." + ms.quote(tableName)
+}
+
+// quoteAndJoinByComma quotes the identifiers and joins them by comma
+func (ms *MSSQL) quoteAndJoinByComma(identifiers []string) string {
+	quoted := make([]string, 0, len(identifiers))
+	for _, identifier := range identifiers {
+		quoted = append(quoted, ms.quote(identifier))
+	}
+	return strings.Join(quoted, ",")
+}
+
+// quoteString quotes the value as an nvarchar string literal
+func quoteString(value string) string {
+	return "N'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
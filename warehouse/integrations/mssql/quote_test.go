@@ -0,0 +1,59 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	testCases := []struct {
+		name         string
+		identifier   string
+		doubleQuotes string
+		brackets     string
+	}{
+		{name: "plain", identifier: "test_table", doubleQuotes: `"test_table"`, brackets: `[test_table]`},
+		{name: "reserved word", identifier: "select", doubleQuotes: `"select"`, brackets: `[select]`},
+		{name: "with spaces", identifier: "order by", doubleQuotes: `"order by"`, brackets: `[order by]`},
+		{name: "with brackets", identifier: "test]table[", doubleQuotes: `"test]table["`, brackets: `[test]]table[]`},
+		{name: "with double quotes", identifier: `test"table`, doubleQuotes: `"test""table"`, brackets: `[test"table]`},
+		{name: "with single quotes", identifier: `test'table`, doubleQuotes: `"test'table"`, brackets: `[test'table]`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.doubleQuotes, quoteWithDoubleQuotes(tc.identifier))
+			require.Equal(t, tc.brackets, quoteWithBrackets(tc.identifier))
+		})
+	}
+
+	t.Run("strategy", func(t *testing.T) {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		ms.Namespace = "namespace"
+		require.Equal(t, `"namespace"."select"`, ms.quoteTable("select"))
+		require.Equal(t, `"id","order"`, ms.quoteAndJoinByComma([]string{"id", "order"}))
+
+		c := config.New()
+		c.Set("Warehouse.mssql.identifierQuoteStrategy", quoteStrategyBrackets)
+		ms = New(c, logger.NOP, stats.Default)
+		ms.Namespace = "namespace"
+		require.Equal(t, `[namespace].[select]`, ms.quoteTable("select"))
+		require.Equal(t, `[id],[order]`, ms.quoteAndJoinByComma([]string{"id", "order"}))
+
+		c = config.New()
+		c.Set("Warehouse.mssql.identifierQuoteStrategy", "unknown")
+		ms = New(c, logger.NOP, stats.Default)
+		ms.Namespace = "namespace"
+		require.Equal(t, `"namespace"."select"`, ms.quoteTable("select"))
+	})
+
+	t.Run("string", func(t *testing.T) {
+		require.Equal(t, `N'test'`, quoteString("test"))
+		require.Equal(t, `N'test''s'`, quoteString("test's"))
+	})
+}
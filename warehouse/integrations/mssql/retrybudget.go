@@ -0,0 +1,64 @@
+package mssql
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rudderlabs/rudder-server/warehouse/logfield"
+)
+
+// errRetryBudgetExhausted is returned instead of retrying an operation once the retry budget of the upload is spent
+var errRetryBudgetExhausted = errors.New("retry budget of the upload exhausted")
+
+// retryBudget bounds the retries of all the operations of an upload combined, i.e. the deadlock retries of the DDL and the loads and the retries of the load file downloads.
+// A limit of zero or less means the retries are only bounded by the settings of each operation.
+type retryBudget struct {
+	mu    sync.Mutex
+	limit int
+	used  int
+}
+
+func newRetryBudget(limit int) *retryBudget {
+	return &retryBudget{limit: limit}
+}
+
+// take spends one retry of the budget, returning false if there is none left
+func (b *retryBudget) take() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit <= 0 {
+		return true
+	}
+	if b.used >= b.limit {
+		return false
+	}
+	b.used++
+	return true
+}
+
+// remaining returns the number of retries left, or -1 if the budget is unlimited
+func (b *retryBudget) remaining() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.limit <= 0 {
+		return -1
+	}
+	return b.limit - b.used
+}
+
+// takeDownloadRetry spends one retry of the budget on the download of a load file, returning false if there is none left
+func (ms *MSSQL) takeDownloadRetry() bool {
+	if !ms.retryBudget.take() {
+		ms.logger.Warnw("not retrying the download of the load file, since the retry budget of the upload is exhausted",
+			logfield.DestinationID, ms.Warehouse.Destination.ID,
+		)
+		return false
+	}
+	ms.logger.Warnw("retrying the download of the load file",
+		logfield.DestinationID, ms.Warehouse.Destination.ID,
+		"remainingRetryBudget", ms.retryBudget.remaining(),
+	)
+	return true
+}
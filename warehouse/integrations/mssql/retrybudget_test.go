@@ -0,0 +1,50 @@
+package mssql
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryBudget(t *testing.T) {
+	t.Run("unlimited", func(t *testing.T) {
+		b := newRetryBudget(0)
+		for i := 0; i < 100; i++ {
+			require.True(t, b.take())
+		}
+		require.Equal(t, -1, b.remaining())
+	})
+
+	t.Run("limited", func(t *testing.T) {
+		b := newRetryBudget(2)
+		require.Equal(t, 2, b.remaining())
+		require.True(t, b.take())
+		require.True(t, b.take())
+		require.False(t, b.take())
+		require.Zero(t, b.remaining())
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		b := newRetryBudget(10)
+
+		var (
+			wg    sync.WaitGroup
+			mu    sync.Mutex
+			taken int
+		)
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if b.take() {
+					mu.Lock()
+					taken++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+		require.Equal(t, 10, taken)
+	})
+}
@@ -0,0 +1,27 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+
+	sqlmw "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
+)
+
+// countRows returns the number of rows of the load table, as seen by the transaction of the load
+func (ms *MSSQL) countRows(ctx context.Context, txn *sqlmw.Tx, tableName string) (int64, error) {
+	var count int64
+	if err := txn.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT_BIG(*) FROM %s;`, ms.quoteTable(tableName))).Scan(&count); err != nil {
+		return 0, fmt.Errorf("counting rows of table %s: %w", tableName, err)
+	}
+	return count, nil
+}
+
+// expectedRowCount returns the number of rows the load table should have after the load, given the number of rows before and the rows reported as inserted.
+// A swapped load table only contains the rows inserted, whereas a merged one grows by the rows inserted (net of the rows deleted when replacing).
+// Updated rows never change the number of rows.
+func expectedRowCount(rowsBefore, rowsInserted int64, swapped bool) int64 {
+	if swapped {
+		return rowsInserted
+	}
+	return rowsBefore + rowsInserted
+}
@@ -0,0 +1,13 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectedRowCount(t *testing.T) {
+	require.EqualValues(t, 15, expectedRowCount(10, 5, false))
+	require.EqualValues(t, 8, expectedRowCount(10, -2, false), "replacing can delete more rows than it inserts")
+	require.EqualValues(t, 5, expectedRowCount(10, 5, true))
+}
@@ -0,0 +1,208 @@
+package mssql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// Row is a record of a load file, coerced into the values loaded into the staging table
+type Row struct {
+	FileName string
+	// RecordNumber is the number of the record within the load file, starting at 1 (the header excluded)
+	RecordNumber int
+	// Line is the line of the record within the load file, starting at 1 (the header included)
+	Line int
+	// Record is the raw record of the load file
+	Record []string
+	// Values are the values loaded, in the order of Rows.Columns. Invalid values are loaded as NULL, hence are nil as well.
+	Values []interface{}
+	// InvalidValues are the values which couldn't be converted into the data type of their column
+	InvalidValues []InvalidValue
+	// Err is set if the whole row can't be loaded, e.g. because of a mismatch in the number of columns, in which case there are no values.
+	// It is a *ParseError, identifying the record within the load file.
+	Err error
+}
+
+// ParseError is the error of a record of a load file which can't be loaded, pointing to the record so that it can be found in the data
+type ParseError struct {
+	FileName string
+	// Line is the line of the record within the load file, starting at 1 (the header included)
+	Line int
+	// RawLine is the record, the way it is written in the load file
+	RawLine string
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line %d of file %s: %v: %q", e.Line, e.FileName, e.Err, e.RawLine)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidValue is a value of a load file which doesn't match the data type of its column
+type InvalidValue struct {
+	Column   string
+	DataType string
+	Value    string
+	Err      error
+}
+
+// Rows iterates over the rows of load files, independently of loading them:
+//
+//	rows := ms.ReadRows(ctx, fileName, schema)
+//	defer rows.Close()
+//	for rows.Next() {
+//		row := rows.Row()
+//		...
+//	}
+//	if err := rows.Err(); err != nil {
+//		...
+//	}
+type Rows struct {
+	ms      *MSSQL
+	ctx     context.Context
+	reader  *loadFilesReader
+	columns []string
+	schema  model.TableSchema
+	opts    rowsOptions
+
+	row Row
+	err error
+}
+
+// ReadRows returns the rows of the (local, gzipped or parquet) load file of a table with the schema, coerced the same way as when loading the table.
+// The load file is read using the CSV dialect configured, so the values are the ones which would be loaded with the same configuration.
+func (ms *MSSQL) ReadRows(ctx context.Context, loadFile string, schema model.TableSchema) *Rows {
+	return ms.readRows(ctx, []string{loadFile}, schema, rowsOptions{})
+}
+
+// readRows returns the rows of the load files, converted with the options
+func (ms *MSSQL) readRows(ctx context.Context, fileNames []string, schema model.TableSchema, opts rowsOptions) *Rows {
+	columns := warehouseutils.SortColumnKeysFromColumnMap(schema)
+	return &Rows{
+		ms:      ms,
+		ctx:     ctx,
+		reader:  newLoadFilesReader(fileNames, ms.config.csvDialect, columns),
+		columns: columns,
+		schema:  schema,
+		opts:    opts,
+	}
+}
+
+// Columns returns the columns of the values of the rows, sorted by name
+func (r *Rows) Columns() []string {
+	return r.columns
+}
+
+// rowsOptions controls how the values of the rows are converted, on top of their data types
+type rowsOptions struct {
+	// nonUnicode are the code pages of the non-unicode columns, keyed by column
+	nonUnicode map[string]int
+	// transforms are the transforms of the values of the columns, keyed by column
+	transforms map[string]valueTransform
+}
+
+// Next reads the next row, returning false once all the rows are read or if reading failed, see Err
+func (r *Rows) Next() bool {
+	if r.err != nil {
+		return false
+	}
+	// abort reading as soon as the context is cancelled
+	if err := r.ctx.Err(); err != nil {
+		r.err = fmt.Errorf("reading rows aborted: %w", err)
+		return false
+	}
+
+	record, err := r.reader.Read()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			r.err = err
+		}
+		return false
+	}
+
+	r.row = Row{
+		FileName:     r.reader.FileName(),
+		RecordNumber: r.reader.RecordNumber(),
+		Line:         r.reader.Line(),
+		Record:       record,
+	}
+	if len(r.columns) != len(record) {
+		r.row.Err = &ParseError{
+			FileName: r.row.FileName,
+			Line:     r.row.Line,
+			RawLine:  r.ms.config.csvDialect.rawLine(record),
+			Err:      fmt.Errorf("mismatch in number of columns: actual count: %d, expected count: %d", len(record), len(r.columns)),
+		}
+		return true
+	}
+
+	values := r.ms.config.csvDialect.values(record)
+	r.row.Values = make([]interface{}, 0, len(values))
+	for index, value := range values {
+		if value == nil {
+			r.row.Values = append(r.row.Values, nil)
+			continue
+		}
+
+		column := r.columns[index]
+		processedVal, err := r.processValue(column, value.(string))
+		if err != nil {
+			r.row.InvalidValues = append(r.row.InvalidValues, InvalidValue{
+				Column:   column,
+				DataType: r.schema[column],
+				Value:    value.(string),
+				Err:      err,
+			})
+			processedVal = nil
+		}
+		r.row.Values = append(r.row.Values, processedVal)
+	}
+	return true
+}
+
+// processValue converts the value into the data type of the column, then transforms it, before encoding it if it is a string
+func (r *Rows) processValue(column, value string) (interface{}, error) {
+	dataType := r.schema[column]
+	processedVal, err := r.ms.convertColumnValue(value, dataType)
+	if err != nil || processedVal == nil {
+		return processedVal, err
+	}
+
+	if transform, ok := r.opts.transforms[column]; ok {
+		if processedVal, err = applyTransform(transform, processedVal); err != nil || processedVal == nil {
+			return processedVal, err
+		}
+	}
+
+	s, ok := processedVal.(string)
+	if !ok || dataType != model.StringDataType {
+		return processedVal, nil
+	}
+	if codePage, ok := r.opts.nonUnicode[column]; ok {
+		return encodeNonUnicode(truncateString(s), codePage)
+	}
+	return encodeString(s), nil
+}
+
+// Row returns the row read by the last call to Next
+func (r *Rows) Row() Row {
+	return r.row
+}
+
+// Err returns the error which stopped reading the rows, if any. Errors of single rows are reported by the rows themselves.
+func (r *Rows) Err() error {
+	return r.err
+}
+
+// Close closes the load file currently being read, if any
+func (r *Rows) Close() error {
+	return r.reader.Close()
+}
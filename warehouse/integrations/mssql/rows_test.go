@@ -0,0 +1,131 @@
+package mssql
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	"github.com/rudderlabs/rudder-server/warehouse/encoding"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestReadRows(t *testing.T) {
+	schema := model.TableSchema{
+		"id":      model.StringDataType,
+		"count":   model.IntDataType,
+		"enabled": model.BooleanDataType,
+	}
+
+	readAllRows := func(t *testing.T, ctx context.Context, content string) ([]Row, error) {
+		t.Helper()
+
+		ms := New(config.New(), logger.NOP, stats.Default)
+		rows := ms.ReadRows(ctx, writeGzipFile(t, "1.csv.gz", content), schema)
+		defer func() { _ = rows.Close() }()
+
+		require.Equal(t, []string{"count", "enabled", "id"}, rows.Columns())
+
+		var all []Row
+		for rows.Next() {
+			all = append(all, rows.Row())
+		}
+		return all, rows.Err()
+	}
+
+	t.Run("coerced values", func(t *testing.T) {
+		rows, err := readAllRows(t, context.Background(), "1,true,a\n,false,b\n")
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, []interface{}{1, true, "a"}, rows[0].Values)
+		require.Equal(t, 1, rows[0].RecordNumber)
+		require.Equal(t, []interface{}{nil, false, "b"}, rows[1].Values)
+		require.Equal(t, 2, rows[1].RecordNumber)
+		require.Empty(t, rows[1].InvalidValues)
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		rows, err := readAllRows(t, context.Background(), "one,true,a\n")
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.NoError(t, rows[0].Err)
+		require.Equal(t, []interface{}{nil, true, "a"}, rows[0].Values)
+		require.Len(t, rows[0].InvalidValues, 1)
+		require.Equal(t, "count", rows[0].InvalidValues[0].Column)
+		require.Equal(t, model.IntDataType, rows[0].InvalidValues[0].DataType)
+		require.Equal(t, "one", rows[0].InvalidValues[0].Value)
+		require.Error(t, rows[0].InvalidValues[0].Err)
+	})
+
+	t.Run("mismatch in number of columns", func(t *testing.T) {
+		rows, err := readAllRows(t, context.Background(), "1,a\n2,true,b\n")
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.ErrorContains(t, rows[0].Err, "mismatch in number of columns")
+		require.Nil(t, rows[0].Values)
+		require.Equal(t, []string{"1", "a"}, rows[0].Record)
+		require.NoError(t, rows[1].Err)
+
+		var parseErr *ParseError
+		require.ErrorAs(t, rows[0].Err, &parseErr)
+		require.Equal(t, 1, parseErr.Line)
+		require.Equal(t, "1,a", parseErr.RawLine)
+		require.Equal(t, rows[0].FileName, parseErr.FileName)
+		require.EqualError(t, rows[0].Err, `line 1 of file `+rows[0].FileName+`: mismatch in number of columns: actual count: 2, expected count: 3: "1,a"`)
+	})
+
+	t.Run("mismatch in number of columns after quoted line breaks", func(t *testing.T) {
+		rows, err := readAllRows(t, context.Background(), "1,true,\"multi\nline\"\n2,\"b,c\"\n")
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.NoError(t, rows[0].Err)
+
+		var parseErr *ParseError
+		require.ErrorAs(t, rows[1].Err, &parseErr)
+		require.Equal(t, 3, parseErr.Line)
+		require.Equal(t, 3, rows[1].Line)
+		require.Equal(t, 2, rows[1].RecordNumber)
+		require.Equal(t, `2,"b,c"`, parseErr.RawLine)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		rows, err := readAllRows(t, ctx, "1,true,a\n")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Empty(t, rows)
+	})
+
+	t.Run("parquet load file", func(t *testing.T) {
+		fileName := filepath.Join(t.TempDir(), "1.parquet")
+		w, err := encoding.NewFactory(config.New()).NewLoadFileWriter(warehouseutils.LoadFileTypeParquet, fileName, schema, warehouseutils.RS)
+		require.NoError(t, err)
+		require.NoError(t, w.WriteRow([]interface{}{int64(1), true, "a"}))
+		require.NoError(t, w.WriteRow([]interface{}{nil, false, "b"}))
+		require.NoError(t, w.WriteRow([]interface{}{int64(3), nil, nil}))
+		require.NoError(t, w.Close())
+
+		ms := New(config.New(), logger.NOP, stats.Default)
+		rows := ms.ReadRows(context.Background(), fileName, schema)
+		defer func() { _ = rows.Close() }()
+
+		var all []Row
+		for rows.Next() {
+			all = append(all, rows.Row())
+		}
+		require.NoError(t, rows.Err())
+		require.Len(t, all, 3)
+		require.Equal(t, []interface{}{1, true, "a"}, all[0].Values)
+		require.Equal(t, []interface{}{nil, false, "b"}, all[1].Values)
+		require.Equal(t, []interface{}{3, nil, nil}, all[2].Values)
+		require.Equal(t, 3, all[2].RecordNumber)
+		require.Equal(t, 3, all[2].Line)
+	})
+}
@@ -0,0 +1,113 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	sqlmw "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
+)
+
+// stagingWriter writes the rows of the load files into the staging table
+type stagingWriter interface {
+	// write buffers the values of a row into the bulk copy
+	write(ctx context.Context, values []interface{}) error
+	// flush sends the buffered rows to the staging table
+	flush(ctx context.Context) error
+}
+
+// txnStagingWriter writes all the rows into the staging table within the transaction of the load
+type txnStagingWriter struct {
+	stmt *sql.Stmt
+}
+
+func (w *txnStagingWriter) write(ctx context.Context, values []interface{}) error {
+	_, err := w.stmt.ExecContext(ctx, values...)
+	return err
+}
+
+func (w *txnStagingWriter) flush(ctx context.Context) error {
+	if _, err := w.stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("executing copyIn statement: %w", err)
+	}
+	return nil
+}
+
+// chunkedStagingWriter writes the rows into the staging table in chunks of rows, committing every chunk in its own transaction.
+// This keeps the transactions (and the transaction log) of large loads small, whereas the merge into the load table still happens once, after all the chunks are committed.
+type chunkedStagingWriter struct {
+	ms         *MSSQL
+	copyInStmt string
+	chunkSize  int
+
+	txn    *sqlmw.Tx
+	stmt   *sql.Stmt
+	rows   int
+	chunks int
+}
+
+func (w *chunkedStagingWriter) write(ctx context.Context, values []interface{}) error {
+	if w.txn == nil {
+		if err := w.begin(ctx); err != nil {
+			return err
+		}
+	}
+	if _, err := w.stmt.ExecContext(ctx, values...); err != nil {
+		return err
+	}
+
+	w.rows++
+	if w.rows >= w.chunkSize {
+		return w.commit(ctx)
+	}
+	return nil
+}
+
+func (w *chunkedStagingWriter) flush(ctx context.Context) error {
+	return w.commit(ctx)
+}
+
+func (w *chunkedStagingWriter) begin(ctx context.Context) error {
+	txn, err := w.ms.DB.BeginTx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return fmt.Errorf("begin staging chunk transaction: %w", err)
+	}
+	if sessionSettingsStmt := w.ms.sessionSettingsStmt(); sessionSettingsStmt != "" {
+		if _, err = txn.ExecContext(ctx, sessionSettingsStmt); err != nil {
+			_ = txn.Rollback()
+			return fmt.Errorf("applying session settings: %w", err)
+		}
+	}
+	stmt, err := txn.PrepareContext(ctx, w.copyInStmt)
+	if err != nil {
+		_ = txn.Rollback()
+		return fmt.Errorf("preparing copyIn statement: %w", err)
+	}
+	w.txn, w.stmt, w.rows = txn, stmt, 0
+	return nil
+}
+
+// commit sends the rows of the current chunk to the staging table and commits them, if a chunk was started
+func (w *chunkedStagingWriter) commit(ctx context.Context) error {
+	if w.txn == nil {
+		return nil
+	}
+	if _, err := w.stmt.ExecContext(ctx); err != nil {
+		return fmt.Errorf("executing copyIn statement: %w", err)
+	}
+	if err := w.txn.Commit(); err != nil {
+		return fmt.Errorf("commit staging chunk transaction: %w", err)
+	}
+	w.txn, w.stmt = nil, nil
+	w.chunks++
+	return nil
+}
+
+// rollback rolls back the chunk which was not committed yet, if any. The chunks already committed are left in the staging table.
+func (w *chunkedStagingWriter) rollback() {
+	if w.txn == nil {
+		return
+	}
+	_ = w.txn.Rollback()
+	w.txn, w.stmt = nil, nil
+}
@@ -0,0 +1,25 @@
+package mssql
+
+import (
+	"strings"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// stagingSchemaSetting is the destination setting for the schema the staging tables are created in, e.g. a write-heavy schema isolated from the analytics one.
+// By default the staging tables are created in the namespace, next to the tables they are loaded into.
+// Temporary staging tables always live in tempdb, whatever the schema.
+const stagingSchemaSetting = "stagingSchema"
+
+// stagingNamespace returns the schema of the staging tables
+func (ms *MSSQL) stagingNamespace() string {
+	if schema := strings.TrimSpace(warehouseutils.GetConfigValue(stagingSchemaSetting, ms.Warehouse)); schema != "" {
+		return schema
+	}
+	return ms.Namespace
+}
+
+// quoteStagingTable returns the quoted name of the staging table in the staging schema
+func (ms *MSSQL) quoteStagingTable(stagingTableName string) string {
+	return ms.quote(ms.stagingNamespace()) + "." + ms.quote(stagingTableName)
+}
@@ -0,0 +1,41 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestStagingNamespace(t *testing.T) {
+	newMSSQL := func(destConfig map[string]any) *MSSQL {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		ms.Namespace = "analytics"
+		ms.Warehouse = model.Warehouse{
+			Destination: backendconfig.DestinationT{
+				ID:     "test_destination_id",
+				Config: destConfig,
+			},
+		}
+		return ms
+	}
+
+	t.Run("default", func(t *testing.T) {
+		ms := newMSSQL(map[string]any{})
+		require.Equal(t, "analytics", ms.stagingNamespace())
+		require.Equal(t, `"analytics"."rudder_staging_tracks"`, ms.quoteStagingTable("rudder_staging_tracks"))
+	})
+
+	t.Run("separate staging schema", func(t *testing.T) {
+		ms := newMSSQL(map[string]any{stagingSchemaSetting: " staging "})
+		require.Equal(t, "staging", ms.stagingNamespace())
+		require.Equal(t, `"staging"."rudder_staging_tracks"`, ms.quoteStagingTable("rudder_staging_tracks"))
+		require.Equal(t, `"analytics"."tracks"`, ms.quoteTable("tracks"))
+	})
+}
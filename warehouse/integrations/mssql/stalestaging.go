@@ -0,0 +1,86 @@
+package mssql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// staleStagingTablesSQL returns the staging tables of the schema created longer than @olderThan seconds ago.
+// Both the staging tables of the uploads and the ones of the destination validations are matched.
+const staleStagingTablesSQL = `SELECT t.name
+	FROM sys.tables t JOIN sys.schemas s ON t.schema_id = s.schema_id
+	WHERE s.name = @schema
+	  AND (t.name LIKE @stagingPrefix OR t.name LIKE @testStagingPrefix)
+	  AND t.create_date < DATEADD(SECOND, -@olderThan, GETDATE())`
+
+// likePrefix returns the LIKE pattern matching the names starting with the prefix, escaping the wildcards it contains (e.g. the underscores)
+func likePrefix(prefix string) string {
+	return strings.NewReplacer("[", "[[]", "%", "[%]", "_", "[_]").Replace(prefix) + "%"
+}
+
+// DropStaleStagingTables drops the staging tables of the warehouse created longer than olderThan ago, returning how many were dropped.
+// The staging tables are left behind when a load crashes before cleaning them up, olderThan is expected to be longer than any load.
+// It opens its own connection, closed once done, without dropping the other staging tables as Cleanup does.
+func (ms *MSSQL) DropStaleStagingTables(ctx context.Context, warehouse model.Warehouse, olderThan time.Duration) (int, error) {
+	ms.Warehouse = warehouse
+	ms.Namespace = warehouse.Namespace
+
+	db, err := ms.connect()
+	if err != nil {
+		return 0, fmt.Errorf("connecting to mssql: %w", err)
+	}
+	ms.DB = db
+	defer func() {
+		_ = db.Close()
+		ms.DB = nil
+	}()
+
+	schemas := []string{ms.Namespace}
+	if stagingNamespace := ms.stagingNamespace(); stagingNamespace != ms.Namespace {
+		schemas = append(schemas, stagingNamespace)
+	}
+
+	var dropped int
+	for _, schema := range schemas {
+		tableNames, err := ms.staleStagingTables(ctx, schema, olderThan)
+		if err != nil {
+			return dropped, fmt.Errorf("listing stale staging tables of schema %s: %w", schema, err)
+		}
+		for _, tableName := range tableNames {
+			if _, err := ms.DB.ExecContext(ctx, fmt.Sprintf(`DROP TABLE IF EXISTS %s.%s`, ms.quote(schema), ms.quote(tableName))); err != nil {
+				return dropped, fmt.Errorf("dropping stale staging table %s.%s: %w", schema, tableName, err)
+			}
+			dropped++
+		}
+	}
+	return dropped, nil
+}
+
+func (ms *MSSQL) staleStagingTables(ctx context.Context, schema string, olderThan time.Duration) ([]string, error) {
+	rows, err := ms.DB.QueryContext(ctx, staleStagingTablesSQL,
+		sql.Named("schema", schema),
+		sql.Named("stagingPrefix", likePrefix(warehouseutils.StagingTablePrefix(provider))),
+		sql.Named("testStagingPrefix", likePrefix(warehouseutils.CTStagingTablePrefix)),
+		sql.Named("olderThan", int64(olderThan.Seconds())),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	var tableNames []string
+	for rows.Next() {
+		var tableName string
+		if err := rows.Scan(&tableName); err != nil {
+			return nil, err
+		}
+		tableNames = append(tableNames, tableName)
+	}
+	return tableNames, rows.Err()
+}
@@ -0,0 +1,15 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestLikePrefix(t *testing.T) {
+	require.Equal(t, "rudder[_]staging[_]%", likePrefix(warehouseutils.StagingTablePrefix(provider)))
+	require.Equal(t, "setup[_]test[_]staging%", likePrefix(warehouseutils.CTStagingTablePrefix))
+	require.Equal(t, "a[%]b[[]c]%", likePrefix("a%b[c]"))
+}
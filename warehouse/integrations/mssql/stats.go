@@ -0,0 +1,45 @@
+package mssql
+
+import (
+	"github.com/rudderlabs/rudder-go-kit/stats"
+)
+
+// Metrics emitted while loading the tables, on top of the wh_query_count emitted for every query.
+// All of them are tagged with the workspace, source, destination, namespace and table, so that the loads can be attributed to each destination.
+const (
+	// loadTableDurationStat is the time taken to load a table, from downloading the load files to committing, deadlock retries included, tagged with its status on top (timer).
+	// Its histogram buckets (see runner/buckets.go) range from milliseconds to half an hour, so that the percentiles of the load times can be computed.
+	loadTableDurationStat = "mssql_load_table_duration"
+	// loadTableRowsInsertedStat is the number of rows inserted into a table (count)
+	loadTableRowsInsertedStat = "mssql_load_table_rows_inserted"
+	// loadTableRowsUpdatedStat is the number of rows updated in a table (count)
+	loadTableRowsUpdatedStat = "mssql_load_table_rows_updated"
+	// loadTableDeadlockRetriesStat is the number of times the load of a table was retried after being chosen as a deadlock victim (count)
+	loadTableDeadlockRetriesStat = "mssql_load_table_deadlock_retries"
+	// loadTableStagingChunksStat is the number of chunks committed into the staging table, if it is loaded in chunks (count)
+	loadTableStagingChunksStat = "mssql_load_table_staging_chunks"
+	// loadTableNoLoadFilesStat is the number of loads of a table without any load files, which are no-ops (count)
+	loadTableNoLoadFilesStat = "mssql_load_table_no_load_files"
+	// loadTableBadRowsStat is the number of bad rows of a table routed to the discards table, if tolerated (count)
+	loadTableBadRowsStat = "mssql_load_table_bad_rows"
+	// loadTableRowCountDiscrepanciesStat is the number of loads of a table whose rows reported as inserted don't match the change of its row count, if verified (count)
+	loadTableRowCountDiscrepanciesStat = "mssql_load_table_row_count_discrepancies"
+)
+
+// loadTableStatuses are the statuses loadTableDurationStat is tagged with
+const (
+	loadTableSucceeded = "succeeded"
+	loadTableFailed    = "failed"
+)
+
+func (ms *MSSQL) loadTableStatTags(tableName string) stats.Tags {
+	return stats.Tags{
+		"workspaceId": ms.Warehouse.WorkspaceID,
+		"sourceID":    ms.Warehouse.Source.ID,
+		"sourceType":  ms.Warehouse.Source.SourceDefinition.Name,
+		"destID":      ms.Warehouse.Destination.ID,
+		"destType":    ms.Warehouse.Destination.DestinationDefinition.Name,
+		"namespace":   ms.Namespace,
+		"tableName":   tableName,
+	}
+}
@@ -0,0 +1,43 @@
+package mssql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// errNoStorageEgressDataSource is returned when verifying the storage egress of a destination without an external data source configured
+var errNoStorageEgressDataSource = errors.New("no external data source configured to read the object storage with")
+
+// storageEgressQuery reads the object through the external data source (created with CREATE EXTERNAL DATA SOURCE), as a single blob.
+// The object name is relative to the location of the data source, i.e. the bucket or the container.
+func storageEgressQuery(dataSource, objectName string) string {
+	quote := func(value string) string {
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return fmt.Sprintf(`SELECT COUNT(*) FROM OPENROWSET(BULK %s, DATA_SOURCE = %s, SINGLE_BLOB) AS probe;`,
+		quote(objectName),
+		quote(dataSource),
+	)
+}
+
+// VerifyStorageEgress makes the warehouse read the object from the object storage using OPENROWSET(BULK ...),
+// verifying that the warehouse itself can reach the object storage, through the configured external data source.
+func (ms *MSSQL) VerifyStorageEgress(ctx context.Context, objectName string) error {
+	dataSource := warehouseutils.GetConfigValue(warehouseutils.StorageEgressDataSource, ms.Warehouse)
+	if dataSource == "" {
+		return errNoStorageEgressDataSource
+	}
+
+	var count int
+	if err := ms.DB.QueryRowContext(ctx, storageEgressQuery(dataSource, objectName)).Scan(&count); err != nil {
+		return fmt.Errorf("reading object %q using data source %q: %w", objectName, dataSource, err)
+	}
+	if count == 0 {
+		return fmt.Errorf("reading object %q using data source %q: no data read", objectName, dataSource)
+	}
+	return nil
+}
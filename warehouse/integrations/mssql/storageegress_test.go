@@ -0,0 +1,30 @@
+package mssql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+)
+
+func TestStorageEgress(t *testing.T) {
+	t.Run("query", func(t *testing.T) {
+		require.Equal(t,
+			`SELECT COUNT(*) FROM OPENROWSET(BULK 'rudder-test-payload/MSSQL/abc/probe.csv.gz', DATA_SOURCE = 'rudder_storage', SINGLE_BLOB) AS probe;`,
+			storageEgressQuery("rudder_storage", "rudder-test-payload/MSSQL/abc/probe.csv.gz"),
+		)
+		require.Equal(t,
+			`SELECT COUNT(*) FROM OPENROWSET(BULK 'it''s/probe.csv.gz', DATA_SOURCE = 'rudder''s', SINGLE_BLOB) AS probe;`,
+			storageEgressQuery("rudder's", "it's/probe.csv.gz"),
+		)
+	})
+
+	t.Run("no data source", func(t *testing.T) {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		require.ErrorIs(t, ms.VerifyStorageEgress(context.Background(), "probe.csv.gz"), errNoStorageEgressDataSource)
+	})
+}
@@ -0,0 +1,57 @@
+package mssql
+
+import (
+	"strings"
+
+	"github.com/samber/lo"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// Destination settings adding a monotonically increasing IDENTITY column to the tables, assigned by the DB while loading
+const (
+	surrogateKeyColumnSetting = "surrogateKeyColumn"
+	surrogateKeyTablesSetting = "surrogateKeyTables" // comma separated list of tables, all the tables if empty
+)
+
+// surrogateKeyColumn returns the name of the surrogate key column of the table, or an empty string if the table doesn't have one.
+// The users table is merged using all the columns of the warehouse schema and the discards table is internal, so they never have one.
+func (ms *MSSQL) surrogateKeyColumn(tableName string) string {
+	column := strings.TrimSpace(warehouseutils.GetConfigValue(surrogateKeyColumnSetting, ms.Warehouse))
+	if column == "" || tableName == warehouseutils.UsersTable || tableName == warehouseutils.DiscardsTable {
+		return ""
+	}
+
+	tables := lo.Compact(lo.Map(
+		strings.Split(warehouseutils.GetConfigValue(surrogateKeyTablesSetting, ms.Warehouse), ","),
+		func(table string, _ int) string { return strings.TrimSpace(table) },
+	))
+	if len(tables) > 0 && !lo.Contains(tables, tableName) {
+		return ""
+	}
+	return column
+}
+
+// surrogateKeyDefinition returns the definition of the surrogate key column for creating the table, if the table has one.
+// If the schema of the table already contains a column with the same name, it is left as is.
+func (ms *MSSQL) surrogateKeyDefinition(tableName string, schema model.TableSchema) string {
+	column := ms.surrogateKeyColumn(tableName)
+	if column == "" {
+		return ""
+	}
+	if _, ok := schema[column]; ok {
+		ms.logger.Warnf("MSSQL: not adding surrogate key %s to table %s of destination %s, since it is already a column of the table", column, tableName, ms.Warehouse.Destination.ID)
+		return ""
+	}
+	return ms.quoteIdentifier(column) + " bigint IDENTITY(1,1) NOT NULL"
+}
+
+// withoutSurrogateKey returns the schema without the surrogate key column of the table, so that it is always assigned by the DB
+func (ms *MSSQL) withoutSurrogateKey(tableName string, schema model.TableSchema) model.TableSchema {
+	column := ms.surrogateKeyColumn(tableName)
+	if _, ok := schema[column]; column == "" || !ok {
+		return schema
+	}
+	return lo.OmitByKeys(schema, []string{column})
+}
@@ -0,0 +1,112 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestSurrogateKey(t *testing.T) {
+	newMSSQL := func(destConfig map[string]any) *MSSQL {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		ms.Namespace = "namespace"
+		ms.Warehouse = model.Warehouse{
+			Destination: backendconfig.DestinationT{
+				ID:     "test_destination_id",
+				Config: destConfig,
+			},
+		}
+		return ms
+	}
+
+	t.Run("surrogate key column", func(t *testing.T) {
+		testCases := []struct {
+			name           string
+			destConfig     map[string]any
+			tableName      string
+			expectedColumn string
+		}{
+			{
+				name:       "disabled",
+				destConfig: map[string]any{},
+				tableName:  "tracks",
+			},
+			{
+				name:           "all tables",
+				destConfig:     map[string]any{surrogateKeyColumnSetting: "row_key"},
+				tableName:      "tracks",
+				expectedColumn: "row_key",
+			},
+			{
+				name:           "configured table",
+				destConfig:     map[string]any{surrogateKeyColumnSetting: "row_key", surrogateKeyTablesSetting: "pages, tracks"},
+				tableName:      "tracks",
+				expectedColumn: "row_key",
+			},
+			{
+				name:       "not a configured table",
+				destConfig: map[string]any{surrogateKeyColumnSetting: "row_key", surrogateKeyTablesSetting: "pages"},
+				tableName:  "tracks",
+			},
+			{
+				name:       "users table",
+				destConfig: map[string]any{surrogateKeyColumnSetting: "row_key"},
+				tableName:  warehouseutils.UsersTable,
+			},
+			{
+				name:       "discards table",
+				destConfig: map[string]any{surrogateKeyColumnSetting: "row_key"},
+				tableName:  warehouseutils.DiscardsTable,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expectedColumn, newMSSQL(tc.destConfig).surrogateKeyColumn(tc.tableName))
+			})
+		}
+	})
+
+	t.Run("create table", func(t *testing.T) {
+		ms := newMSSQL(map[string]any{surrogateKeyColumnSetting: "row_key"})
+
+		ddl := ms.GenerateCreateTableSQL("tracks", model.TableSchema{
+			"id":          model.StringDataType,
+			"received_at": model.DateTimeDataType,
+		})
+		require.Equal(t, `IF  NOT EXISTS (SELECT 1 FROM sys.objects WHERE object_id = OBJECT_ID(N'"namespace"."tracks"') AND type = N'U')
+	CREATE TABLE "namespace"."tracks" ( "row_key" bigint IDENTITY(1,1) NOT NULL,"id" nvarchar(512),"received_at" datetime2(6) )`, ddl)
+	})
+
+	t.Run("create table with surrogate key in schema", func(t *testing.T) {
+		ms := newMSSQL(map[string]any{surrogateKeyColumnSetting: "row_key"})
+
+		ddl := ms.GenerateCreateTableSQL("tracks", model.TableSchema{
+			"id":      model.StringDataType,
+			"row_key": model.StringDataType,
+		})
+		require.Equal(t, `IF  NOT EXISTS (SELECT 1 FROM sys.objects WHERE object_id = OBJECT_ID(N'"namespace"."tracks"') AND type = N'U')
+	CREATE TABLE "namespace"."tracks" ( "id" nvarchar(512),"row_key" nvarchar(512) )`, ddl)
+	})
+
+	t.Run("load schema", func(t *testing.T) {
+		schema := model.TableSchema{
+			"id":      model.StringDataType,
+			"row_key": model.IntDataType,
+		}
+
+		ms := newMSSQL(map[string]any{surrogateKeyColumnSetting: "row_key"})
+		require.Equal(t, model.TableSchema{"id": model.StringDataType}, ms.withoutSurrogateKey("tracks", schema))
+		require.Equal(t, schema, ms.withoutSurrogateKey(warehouseutils.UsersTable, schema))
+
+		require.Equal(t, schema, newMSSQL(map[string]any{}).withoutSurrogateKey("tracks", schema))
+	})
+}
@@ -0,0 +1,81 @@
+package mssql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+
+	sqlmw "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// swapLoadTablesSetting is the destination setting listing the tables which are fully refreshed on every load, comma separated.
+// The rows of these tables are loaded into a side table, which then replaces the table, so that readers never see a partially loaded table.
+const swapLoadTablesSetting = "swapLoadTables"
+
+// swapTableSuffix is the suffix of the side table replacing the table
+const swapTableSuffix = "__staging"
+
+// swapLoadTable returns true if the table is configured to be fully refreshed by swapping it with a side table
+func (ms *MSSQL) swapLoadTable(tableName string) bool {
+	if tableName == warehouseutils.DiscardsTable {
+		return false
+	}
+	tables := lo.Map(
+		strings.Split(warehouseutils.GetConfigValue(swapLoadTablesSetting, ms.Warehouse), ","),
+		func(table string, _ int) string { return strings.TrimSpace(table) },
+	)
+	return lo.ContainsBy(tables, func(table string) bool {
+		return strings.EqualFold(table, tableName)
+	})
+}
+
+// swapIntoLoadTable replaces the load table with a side table containing the (deduplicated) rows of the staging table, returning the number of rows inserted.
+// The side table is created like the load table, however only the columns and the identity are copied, the indexes and the constraints of the load table aren't.
+// Renaming the tables takes a schema modification lock on them, so readers wait for the transaction to commit and either see the previous or the refreshed rows.
+func (ms *MSSQL) swapIntoLoadTable(
+	ctx context.Context,
+	txn *sqlmw.Tx,
+	tableName string,
+	quotedStagingTableName string,
+	sortedColumnKeys []string,
+) (int64, error) {
+	swapTableName := tableName + swapTableSuffix
+	previousTableName := tableName + "__previous"
+
+	createStmt := fmt.Sprintf(`
+		DROP TABLE IF EXISTS %[1]s;
+		SELECT
+		  TOP 0 * INTO %[1]s
+		FROM
+		  %[2]s;`,
+		ms.quoteTable(swapTableName),
+		ms.quoteTable(tableName),
+	)
+	if _, err := txn.ExecContext(ctx, createStmt); err != nil {
+		return 0, fmt.Errorf("creating swap table: %w", err)
+	}
+
+	rowsInserted, err := ms.insertIntoLoadTable(ctx, txn, swapTableName, quotedStagingTableName, sortedColumnKeys, ms.mergeKey(tableName, sortedColumnKeys), false)
+	if err != nil {
+		return 0, fmt.Errorf("insert into swap table: %w", err)
+	}
+
+	swapStmt := fmt.Sprintf(`
+		EXEC sp_rename %[1]s, %[2]s;
+		EXEC sp_rename %[3]s, %[4]s;
+		DROP TABLE %[5]s;`,
+		quoteString(ms.quoteTable(tableName)),
+		quoteString(previousTableName),
+		quoteString(ms.quoteTable(swapTableName)),
+		quoteString(tableName),
+		ms.quoteTable(previousTableName),
+	)
+	ms.logMergeStatement(tableName, "swap", swapStmt)
+	if _, err := txn.ExecContext(ctx, swapStmt); err != nil {
+		return 0, fmt.Errorf("swapping tables: %w", err)
+	}
+	return rowsInserted, nil
+}
@@ -0,0 +1,69 @@
+package mssql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestSwapLoadTable(t *testing.T) {
+	testCases := []struct {
+		name       string
+		destConfig map[string]any
+		tableName  string
+		expected   bool
+	}{
+		{
+			name:       "not configured",
+			destConfig: map[string]any{},
+			tableName:  "tracks",
+			expected:   false,
+		},
+		{
+			name:       "configured",
+			destConfig: map[string]any{swapLoadTablesSetting: "pages, tracks"},
+			tableName:  "tracks",
+			expected:   true,
+		},
+		{
+			name:       "case insensitive",
+			destConfig: map[string]any{swapLoadTablesSetting: "TRACKS"},
+			tableName:  "tracks",
+			expected:   true,
+		},
+		{
+			name:       "other table",
+			destConfig: map[string]any{swapLoadTablesSetting: "pages"},
+			tableName:  "tracks",
+			expected:   false,
+		},
+		{
+			name:       "discards are never swapped",
+			destConfig: map[string]any{swapLoadTablesSetting: warehouseutils.DiscardsTable},
+			tableName:  warehouseutils.DiscardsTable,
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ms := New(config.New(), logger.NOP, stats.Default)
+			ms.Warehouse = model.Warehouse{
+				Destination: backendconfig.DestinationT{
+					ID:     "test_destination_id",
+					Config: tc.destConfig,
+				},
+			}
+			require.Equal(t, tc.expected, ms.swapLoadTable(tc.tableName))
+		})
+	}
+}
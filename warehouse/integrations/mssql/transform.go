@@ -0,0 +1,124 @@
+package mssql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// valueTransformsSetting is the destination setting mapping columns to the transforms applied to their values at load time, in order.
+// Columns are either the name of the column in any table or table.column. The transforms are either a list or a comma separated string.
+//
+//	"valueTransforms": {"email": ["trim", "lowercase"], "tracks.label": "trim"}
+const valueTransformsSetting = "valueTransforms"
+
+// valueTransform transforms a value after it is converted into the data type of its column, before it is loaded into the staging table.
+// It must return a value of the same type (or nil for NULL), so that the data type of the column never changes.
+type valueTransform func(value interface{}) (interface{}, error)
+
+// stringTransform returns a transform of the string values, the values of the other data types are left untouched
+func stringTransform(fn func(string) string) valueTransform {
+	return func(value interface{}) (interface{}, error) {
+		if s, ok := value.(string); ok {
+			return fn(s), nil
+		}
+		return value, nil
+	}
+}
+
+// valueTransforms are the transforms which can be configured, by name
+var valueTransforms = map[string]valueTransform{
+	"trim":      stringTransform(strings.TrimSpace),
+	"lowercase": stringTransform(strings.ToLower),
+	"uppercase": stringTransform(strings.ToUpper),
+}
+
+// valueTransformsFor returns the transforms of the columns of the table, keyed by column. There are none unless configured.
+// The transforms configured for table.column come after the ones configured for the column in any table.
+func (ms *MSSQL) valueTransformsFor(tableName string) map[string]valueTransform {
+	configured := warehouseutils.GetConfigValueAsMap(valueTransformsSetting, ms.Warehouse.Destination.Config)
+	if len(configured) == 0 {
+		return nil
+	}
+
+	// every column gets the transforms of the column in any table first, then the ones of the column of the table
+	names := make(map[string][]string)
+	for key, value := range configured {
+		if !strings.Contains(key, ".") {
+			names[key] = append(names[key], ms.transformNames(key, value)...)
+		}
+	}
+	for key, value := range configured {
+		if column, ok := strings.CutPrefix(key, tableName+"."); ok {
+			names[column] = append(names[column], ms.transformNames(key, value)...)
+		}
+	}
+
+	transforms := make(map[string]valueTransform)
+	for column, columnNames := range names {
+		var fns []valueTransform
+		for _, name := range columnNames {
+			fn, ok := valueTransforms[name]
+			if !ok {
+				ms.logger.Warnf("MSSQL: ignoring unknown value transform %q of column %s for destination %s", name, column, ms.Warehouse.Destination.ID)
+				continue
+			}
+			fns = append(fns, fn)
+		}
+		if len(fns) > 0 {
+			transforms[column] = chainTransforms(fns)
+		}
+	}
+	return transforms
+}
+
+// transformNames returns the names of the transforms configured for the key
+func (ms *MSSQL) transformNames(key string, value interface{}) []string {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		names = strings.Split(v, ",")
+	case []interface{}:
+		for _, name := range v {
+			if s, ok := name.(string); ok {
+				names = append(names, s)
+			}
+		}
+	default:
+		ms.logger.Warnf("MSSQL: ignoring invalid value transforms %v of %s for destination %s", value, key, ms.Warehouse.Destination.ID)
+	}
+
+	trimmed := make([]string, 0, len(names))
+	for _, name := range names {
+		if name = strings.TrimSpace(name); name != "" {
+			trimmed = append(trimmed, name)
+		}
+	}
+	return trimmed
+}
+
+func chainTransforms(fns []valueTransform) valueTransform {
+	return func(value interface{}) (interface{}, error) {
+		var err error
+		for _, fn := range fns {
+			if value, err = fn(value); err != nil || value == nil {
+				return value, err
+			}
+		}
+		return value, nil
+	}
+}
+
+// applyTransform transforms the value, failing if the transform changes its type
+func applyTransform(fn valueTransform, value interface{}) (interface{}, error) {
+	transformed, err := fn(value)
+	if err != nil {
+		return nil, fmt.Errorf("transforming value: %w", err)
+	}
+	if transformed != nil && reflect.TypeOf(transformed) != reflect.TypeOf(value) {
+		return nil, fmt.Errorf("transforming value: type changed from %T to %T", value, transformed)
+	}
+	return transformed, nil
+}
@@ -0,0 +1,89 @@
+package mssql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestValueTransforms(t *testing.T) {
+	newMSSQL := func(destConfig map[string]any) *MSSQL {
+		ms := New(config.New(), logger.NOP, stats.Default)
+		ms.Warehouse = model.Warehouse{
+			Destination: backendconfig.DestinationT{
+				ID:     "test_destination_id",
+				Config: destConfig,
+			},
+		}
+		return ms
+	}
+
+	t.Run("not configured", func(t *testing.T) {
+		require.Nil(t, newMSSQL(map[string]any{}).valueTransformsFor("tracks"))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		ms := newMSSQL(map[string]any{valueTransformsSetting: map[string]any{
+			"email":        []any{"trim", "unknown"},
+			"tracks.email": "lowercase",
+			"tracks.label": " trim, uppercase ",
+			"pages.label":  "lowercase",
+			"name":         1,
+		}})
+
+		transforms := ms.valueTransformsFor("tracks")
+		require.Len(t, transforms, 2)
+
+		email, err := applyTransform(transforms["email"], "  John@Example.COM ")
+		require.NoError(t, err)
+		require.Equal(t, "john@example.com", email)
+
+		label, err := applyTransform(transforms["label"], " label ")
+		require.NoError(t, err)
+		require.Equal(t, "LABEL", label)
+
+		count, err := applyTransform(transforms["label"], 1)
+		require.NoError(t, err)
+		require.Equal(t, 1, count, "values other than strings are left untouched")
+
+		require.Len(t, ms.valueTransformsFor("identifies"), 1)
+	})
+
+	t.Run("type changed", func(t *testing.T) {
+		toInt := func(interface{}) (interface{}, error) { return 1, nil }
+
+		_, err := applyTransform(toInt, "1")
+		require.EqualError(t, err, "transforming value: type changed from string to int")
+	})
+
+	t.Run("rows", func(t *testing.T) {
+		ms := newMSSQL(map[string]any{valueTransformsSetting: map[string]any{
+			"email": []any{"trim", "lowercase"},
+			"count": "lowercase",
+		}})
+		schema := model.TableSchema{
+			"count": model.IntDataType,
+			"email": model.StringDataType,
+		}
+
+		rows := ms.readRows(context.Background(), []string{writeGzipFile(t, "1.csv.gz", "1, Tést@Example.com \n2,\n")}, schema, rowsOptions{
+			transforms: ms.valueTransformsFor("tracks"),
+		})
+		defer func() { _ = rows.Close() }()
+
+		require.True(t, rows.Next())
+		require.Equal(t, []interface{}{1, str2ucs2("tést@example.com")}, rows.Row().Values)
+		require.True(t, rows.Next())
+		require.Equal(t, []interface{}{2, nil}, rows.Row().Values)
+		require.False(t, rows.Next())
+		require.NoError(t, rows.Err())
+	})
+}
@@ -40,6 +40,10 @@ func (m *mockLoadFileUploader) Download(_ context.Context, tableName string) ([]
 	return m.mockFiles[tableName], m.mockError[tableName]
 }
 
+func (m *mockLoadFileUploader) Verify(_ context.Context, tableName string) error {
+	return m.mockError[tableName]
+}
+
 func cloneFiles(t *testing.T, files []string) []string {
 	tempFiles := make([]string, len(files))
 	for i, file := range files {
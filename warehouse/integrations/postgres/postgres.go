@@ -117,6 +117,7 @@ type Postgres struct {
 	Warehouse          model.Warehouse
 	Uploader           warehouseutils.Uploader
 	connectTimeout     time.Duration
+	readOnly           bool
 	logger             logger.Logger
 	stats              stats.Stats
 	LoadFileDownloader downloader.Downloader
@@ -204,6 +205,11 @@ func (pg *Postgres) connect() (*sqlmiddleware.DB, error) {
 	values := url.Values{}
 	values.Add("sslmode", cred.sslMode)
 
+	if pg.readOnly {
+		// sent as a run-time parameter, so that every transaction of the connection is read-only
+		values.Add("default_transaction_read_only", "on")
+	}
+
 	if cred.timeout > 0 {
 		values.Add("connect_timeout", fmt.Sprintf("%d", cred.timeout/time.Second))
 	}
@@ -527,6 +533,11 @@ func (pg *Postgres) SetConnectionTimeout(timeout time.Duration) {
 	pg.connectTimeout = timeout
 }
 
+// SetReadOnly opens the connections in read-only transaction mode, so that no statement can write to the database. It needs to be called before Setup.
+func (pg *Postgres) SetReadOnly(readOnly bool) {
+	pg.readOnly = readOnly
+}
+
 func (*Postgres) ErrorMappings() []model.JobError {
 	return errorsMappings
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"testing"
@@ -275,6 +276,8 @@ func UploadLoadFile(
 	return uploadOutput
 }
 
+var orderedQueryRegexp = regexp.MustCompile(`(?i)\border\s+by\b`)
+
 // RetrieveRecordsFromWarehouse retrieves records from the warehouse based on the given query.
 // It returns a slice of slices, where each inner slice represents a record's values.
 func RetrieveRecordsFromWarehouse(
@@ -284,6 +287,9 @@ func RetrieveRecordsFromWarehouse(
 ) [][]string {
 	t.Helper()
 
+	// warehouses return rows in an arbitrary physical order, so the records can only be compared if they are explicitly ordered
+	require.Regexp(t, orderedQueryRegexp, query, "query should have an explicit ORDER BY")
+
 	rows, err := db.QueryContext(context.Background(), query)
 	require.NoError(t, err)
 	defer func() { _ = rows.Close() }()
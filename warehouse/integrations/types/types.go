@@ -3,4 +3,27 @@ package types
 type LoadTableStats struct {
 	RowsInserted int64
 	RowsUpdated  int64
+	// BadRows are the rows of the load files which couldn't be loaded and were routed to the discards table instead.
+	// They are only reported by the integrations tolerating bad rows, when enabled.
+	BadRows int64
+	// SchemaMismatches are the columns whose data type in the upload differs from the one in the warehouse.
+	// They are only reported by the integrations supporting it, when enabled.
+	SchemaMismatches []SchemaMismatch
+}
+
+// SchemaMismatch is a column whose data type in the upload differs from the one in the warehouse, e.g. an int column in the upload which is a string column in the warehouse
+type SchemaMismatch struct {
+	Column            string
+	UploadDataType    string
+	WarehouseDataType string
+}
+
+// Capabilities describes the features supported by a warehouse integration
+type Capabilities struct {
+	// Merge is true if the integration deduplicates the rows while loading the tables
+	Merge bool
+	// DeleteByJobs is true if the integration deletes the rows of the previous source job runs
+	DeleteByJobs bool
+	// BulkCopy is true if the integration loads the load files from the object storage in bulk
+	BulkCopy bool
 }
@@ -14,6 +14,7 @@ const (
 	IntDataType            SchemaType = "int"
 	BigIntDataType         SchemaType = "bigint"
 	FloatDataType          SchemaType = "float"
+	DecimalDataType        SchemaType = "decimal"
 	JSONDataType           SchemaType = "json"
 	TextDataType           SchemaType = "text"
 	DateTimeDataType       SchemaType = "datetime"
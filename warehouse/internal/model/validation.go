@@ -1,6 +1,10 @@
 package model
 
-import backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+import (
+	"time"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+)
 
 const (
 	VerifyingObjectStorage       = "Verifying Object Storage"
@@ -9,6 +13,7 @@ const (
 	VerifyingCreateAndAlterTable = "Verifying Create and Alter Table"
 	VerifyingFetchSchema         = "Verifying Fetch Schema"
 	VerifyingLoadTable           = "Verifying Load Table"
+	VerifyingStorageEgress       = "Verifying Storage Egress"
 )
 
 type ValidationRequest struct {
@@ -27,6 +32,11 @@ type Step struct {
 	Name    string `json:"name"`
 	Success bool   `json:"success"`
 	Error   string `json:"error"`
+
+	// Duration, Skipped and SkipReason are only part of the ValidationResult, leaving the legacy response as is
+	Duration   time.Duration `json:"-"`
+	Skipped    bool          `json:"-"`
+	SkipReason string        `json:"-"`
 }
 
 type StepsResponse struct {
@@ -38,3 +48,62 @@ type DestinationValidationResponse struct {
 	Error   string  `json:"error"`
 	Steps   []*Step `json:"steps"`
 }
+
+// ValidationResultVersion is the version of the schema of ValidationResult, bumped on breaking changes only
+const ValidationResultVersion = 1
+
+// ValidationStepStatus is the outcome of a validation step
+type ValidationStepStatus string
+
+const (
+	ValidationStepPassed  ValidationStepStatus = "passed"
+	ValidationStepFailed  ValidationStepStatus = "failed"
+	ValidationStepSkipped ValidationStepStatus = "skipped"
+)
+
+// ValidationStepResult is the result of a validation step in a ValidationResult
+type ValidationStepResult struct {
+	Step       string               `json:"step"`
+	Status     ValidationStepStatus `json:"status"`
+	DurationMs int64                `json:"durationMs"`
+	Error      string               `json:"error"`
+	Skipped    bool                 `json:"skipped"`
+	SkipReason string               `json:"skipReason"`
+}
+
+// ValidationResult is the result of the validation of a destination, with stable JSON keys so that it can be returned as is by APIs.
+// Success is the rollup of the steps: the validation passes only if no step failed.
+type ValidationResult struct {
+	Version int                    `json:"version"`
+	Success bool                   `json:"success"`
+	Error   string                 `json:"error"`
+	Steps   []ValidationStepResult `json:"steps"`
+}
+
+// NewValidationResult returns the ValidationResult of the validation response
+func NewValidationResult(res *DestinationValidationResponse) *ValidationResult {
+	result := &ValidationResult{
+		Version: ValidationResultVersion,
+		Success: res.Success,
+		Error:   res.Error,
+		Steps:   make([]ValidationStepResult, 0, len(res.Steps)),
+	}
+	for _, step := range res.Steps {
+		status := ValidationStepFailed
+		switch {
+		case step.Skipped:
+			status = ValidationStepSkipped
+		case step.Success:
+			status = ValidationStepPassed
+		}
+		result.Steps = append(result.Steps, ValidationStepResult{
+			Step:       step.Name,
+			Status:     status,
+			DurationMs: step.Duration.Milliseconds(),
+			Error:      step.Error,
+			Skipped:    step.Skipped,
+			SkipReason: step.SkipReason,
+		})
+	}
+	return result
+}
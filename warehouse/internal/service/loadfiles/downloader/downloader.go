@@ -2,41 +2,101 @@ package downloader
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
 
+	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/cenkalti/backoff/v4"
+	"github.com/minio/minio-go/v7"
+	"golang.org/x/exp/slices"
 	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/googleapi"
 
 	"github.com/rudderlabs/rudder-go-kit/filemanager"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
 )
 
+// ErrLoadFileNotFound is returned when verifying the load files of a table if some of them are missing from the object storage
+var ErrLoadFileNotFound = errors.New("load file not found")
+
+// IsLoadFileNotFound returns whether a load file couldn't be verified or downloaded because it is missing from the object storage or the access to it is denied
+func IsLoadFileNotFound(err error) bool {
+	return errors.Is(err, ErrLoadFileNotFound) || isPermanentDownloadError(err)
+}
+
 type Downloader interface {
 	Download(ctx context.Context, tableName string) ([]string, error)
+	// Verify checks that the load files of the table (of all the tables if empty) exist in the object storage, without downloading them
+	Verify(ctx context.Context, tableName string) error
 }
 
 type downloaderImpl struct {
-	warehouse  *model.Warehouse
-	uploader   warehouseutils.Uploader
-	numWorkers int
+	warehouse      *model.Warehouse
+	uploader       warehouseutils.Uploader
+	numWorkers     int
+	newFileManager filemanager.Factory
+
+	// maxRetries is the number of times the download of a load file is retried, with an exponential backoff starting from retryBackoff
+	maxRetries   int
+	retryBackoff time.Duration
+	// allowRetry, if set, is consulted before every retry, giving up on the load file if it returns false
+	allowRetry func() bool
+}
+
+type Opt func(*downloaderImpl)
+
+// WithRetries retries the download of every load file from the object storage up to maxRetries times, with an exponential backoff starting from initialBackoff.
+// Load files aren't retried by default.
+func WithRetries(maxRetries int, initialBackoff time.Duration) Opt {
+	return func(l *downloaderImpl) {
+		l.maxRetries = maxRetries
+		l.retryBackoff = initialBackoff
+	}
+}
+
+// WithRetryBudget consults allowRetry before every retry of a load file, e.g. to bound the retries shared with other operations.
+// The download fails with the last error once allowRetry returns false.
+func WithRetryBudget(allowRetry func() bool) Opt {
+	return func(l *downloaderImpl) {
+		l.allowRetry = allowRetry
+	}
+}
+
+// WithFileManagerFactory overrides the factory of the file manager the load files are downloaded with
+func WithFileManagerFactory(factory filemanager.Factory) Opt {
+	return func(l *downloaderImpl) {
+		l.newFileManager = factory
+	}
 }
 
 func NewDownloader(
 	warehouse *model.Warehouse,
 	uploader warehouseutils.Uploader,
 	numWorkers int,
+	opts ...Opt,
 ) Downloader {
-	return &downloaderImpl{
-		warehouse:  warehouse,
-		uploader:   uploader,
-		numWorkers: numWorkers,
+	l := &downloaderImpl{
+		warehouse:      warehouse,
+		uploader:       uploader,
+		numWorkers:     numWorkers,
+		newFileManager: filemanager.New,
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 func (l *downloaderImpl) Download(ctx context.Context, tableName string) ([]string, error) {
@@ -51,23 +111,10 @@ func (l *downloaderImpl) Download(ctx context.Context, tableName string) ([]stri
 	if err != nil {
 		return nil, fmt.Errorf("getting load files metadata: %w", err)
 	}
-	storageProvider := warehouseutils.ObjectStorageType(
-		l.warehouse.Destination.DestinationDefinition.Name,
-		l.warehouse.Destination.Config,
-		l.uploader.UseRudderStorage(),
-	)
 
-	fileManager, err := filemanager.New(&filemanager.Settings{
-		Provider: storageProvider,
-		Config: misc.GetObjectStorageConfig(misc.ObjectStorageOptsT{
-			Provider:         storageProvider,
-			Config:           l.warehouse.Destination.Config,
-			UseRudderStorage: l.uploader.UseRudderStorage(),
-			WorkspaceID:      l.warehouse.Destination.WorkspaceID,
-		}),
-	})
+	fileManager, err := l.fileManager()
 	if err != nil {
-		return nil, fmt.Errorf("creating filemanager for destination: %w", err)
+		return nil, err
 	}
 
 	g, ctx := errgroup.WithContext(ctx)
@@ -94,21 +141,106 @@ func (l *downloaderImpl) Download(ctx context.Context, tableName string) ([]stri
 	return fileNames, nil
 }
 
-func (l *downloaderImpl) downloadSingleObject(ctx context.Context, fileManager filemanager.FileManager, object warehouseutils.LoadFile) (string, error) {
+// Verify lists every load file in the object storage, the object storage having no way to only fetch the metadata of an object.
+// The missing load files are all reported at once, wrapping ErrLoadFileNotFound.
+func (l *downloaderImpl) Verify(ctx context.Context, tableName string) error {
+	objects, err := l.uploader.GetLoadFilesMetadata(ctx, warehouseutils.GetLoadFilesOptions{Table: tableName})
+	if err != nil {
+		return fmt.Errorf("getting load files metadata: %w", err)
+	}
+
+	fileManager, err := l.fileManager()
+	if err != nil {
+		return err
+	}
+
 	var (
-		objectName string
-		tmpDirPath string
-		err        error
-		objectFile *os.File
+		missing     []string
+		missingLock sync.Mutex
 	)
 
-	ObjectStorage := warehouseutils.ObjectStorageType(
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(l.numWorkers)
+
+	for _, object := range objects {
+		object := object
+
+		g.Go(func() error {
+			objectName, err := warehouseutils.GetObjectName(object.Location, l.warehouse.Destination.Config, l.storageProvider())
+			if err != nil {
+				return fmt.Errorf("object name for location: %s, %w", object.Location, err)
+			}
+
+			exists, err := objectExists(ctx, fileManager, objectName)
+			if err != nil {
+				return fmt.Errorf("listing object %s: %w", objectName, err)
+			}
+			if !exists {
+				missingLock.Lock()
+				missing = append(missing, objectName)
+				missingLock.Unlock()
+			}
+			return nil
+		})
+	}
+
+	if err = g.Wait(); err != nil {
+		return fmt.Errorf("verifying batch: %w", err)
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return fmt.Errorf("%w: %s", ErrLoadFileNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// objectExists returns true if the object storage has an object with the exact key.
+// Since keys are listed in lexicographical order, the object is the first one listed with its key as prefix, if it exists.
+func objectExists(ctx context.Context, fileManager filemanager.FileManager, key string) (bool, error) {
+	fileObjects, err := fileManager.ListFilesWithPrefix(ctx, "", key, 1).Next()
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(fileObjects, func(fileObject *filemanager.FileInfo) bool {
+		return fileObject.Key == key
+	}), nil
+}
+
+func (l *downloaderImpl) storageProvider() string {
+	return warehouseutils.ObjectStorageType(
 		l.warehouse.Destination.DestinationDefinition.Name,
 		l.warehouse.Destination.Config,
 		l.uploader.UseRudderStorage(),
 	)
+}
+
+// fileManager returns the file manager of the object storage the load files of the warehouse are stored in
+func (l *downloaderImpl) fileManager() (filemanager.FileManager, error) {
+	storageProvider := l.storageProvider()
+	fileManager, err := l.newFileManager(&filemanager.Settings{
+		Provider: storageProvider,
+		Config: misc.GetObjectStorageConfig(misc.ObjectStorageOptsT{
+			Provider:         storageProvider,
+			Config:           l.warehouse.Destination.Config,
+			UseRudderStorage: l.uploader.UseRudderStorage(),
+			WorkspaceID:      l.warehouse.Destination.WorkspaceID,
+		}),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating filemanager for destination: %w", err)
+	}
+	return fileManager, nil
+}
 
-	if objectName, err = warehouseutils.GetObjectName(object.Location, l.warehouse.Destination.Config, ObjectStorage); err != nil {
+func (l *downloaderImpl) downloadSingleObject(ctx context.Context, fileManager filemanager.FileManager, object warehouseutils.LoadFile) (string, error) {
+	var (
+		objectName string
+		tmpDirPath string
+		err        error
+		objectFile *os.File
+	)
+
+	if objectName, err = warehouseutils.GetObjectName(object.Location, l.warehouse.Destination.Config, l.storageProvider()); err != nil {
 		return "", fmt.Errorf("object name for location: %s, %w", object.Location, err)
 	}
 
@@ -126,7 +258,7 @@ func (l *downloaderImpl) downloadSingleObject(ctx context.Context, fileManager f
 		return "", fmt.Errorf("creating file in tmp dir: %w", err)
 	}
 
-	if err = fileManager.Download(ctx, objectFile, objectName); err != nil {
+	if err = l.downloadWithRetries(ctx, fileManager, objectFile, objectName); err != nil {
 		return "", fmt.Errorf("downloading file from object storage: %w", err)
 	}
 
@@ -136,3 +268,82 @@ func (l *downloaderImpl) downloadSingleObject(ctx context.Context, fileManager f
 
 	return objectFile.Name(), nil
 }
+
+// downloadWithRetries downloads the object into the file, retrying transient failures of the object storage (e.g. 5xx) as configured.
+// Missing objects and denied access aren't retried, see isPermanentDownloadError.
+// The file is emptied before every retry, so that a partially downloaded object is never left behind.
+func (l *downloaderImpl) downloadWithRetries(ctx context.Context, fileManager filemanager.FileManager, objectFile *os.File, objectName string) error {
+	if l.maxRetries <= 0 {
+		return fileManager.Download(ctx, objectFile, objectName)
+	}
+
+	expBackoff := backoff.NewExponentialBackOff()
+	expBackoff.InitialInterval = l.retryBackoff
+	expBackoff.MaxElapsedTime = 0
+
+	var (
+		attempt int
+		lastErr error
+	)
+	operation := func() error {
+		if attempt++; attempt > 1 {
+			if l.allowRetry != nil && !l.allowRetry() {
+				return backoff.Permanent(lastErr)
+			}
+			if err := objectFile.Truncate(0); err != nil {
+				return backoff.Permanent(fmt.Errorf("truncating file: %w", err))
+			}
+			if _, err := objectFile.Seek(0, io.SeekStart); err != nil {
+				return backoff.Permanent(fmt.Errorf("seeking file: %w", err))
+			}
+		}
+
+		err := fileManager.Download(ctx, objectFile, objectName)
+		if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil || isPermanentDownloadError(err)) {
+			return backoff.Permanent(err)
+		}
+		lastErr = err
+		return err
+	}
+	return backoff.Retry(operation, backoff.WithContext(backoff.WithMaxRetries(expBackoff, uint64(l.maxRetries)), ctx))
+}
+
+var (
+	// permanentStatusCodes are the status codes of the object storage failures which retrying doesn't recover from
+	permanentStatusCodes = []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}
+	// permanentErrorCodes are the error codes of the object storage failures which retrying doesn't recover from, across providers
+	permanentErrorCodes = []string{
+		"NoSuchKey", "NoSuchBucket", "AccessDenied", "InvalidAccessKeyId", "SignatureDoesNotMatch", // S3 and MinIO
+		"BlobNotFound", "ContainerNotFound", "AuthorizationFailure", "AuthenticationFailed", // Azure Blob Storage
+	}
+)
+
+// isPermanentDownloadError returns whether the download failed because the object is missing or the access to it is denied
+func isPermanentDownloadError(err error) bool {
+	if errors.Is(err, filemanager.ErrKeyNotFound) || errors.Is(err, storage.ErrObjectNotExist) || errors.Is(err, storage.ErrBucketNotExist) {
+		return true
+	}
+
+	var awsRequestErr awserr.RequestFailure
+	if errors.As(err, &awsRequestErr) {
+		return slices.Contains(permanentStatusCodes, awsRequestErr.StatusCode()) || slices.Contains(permanentErrorCodes, awsRequestErr.Code())
+	}
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		return slices.Contains(permanentErrorCodes, awsErr.Code())
+	}
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return slices.Contains(permanentStatusCodes, googleErr.Code)
+	}
+	var azureErr azblob.StorageError
+	if errors.As(err, &azureErr) {
+		return (azureErr.Response() != nil && slices.Contains(permanentStatusCodes, azureErr.Response().StatusCode)) ||
+			slices.Contains(permanentErrorCodes, string(azureErr.ServiceCode()))
+	}
+	var minioErr minio.ErrorResponse
+	if errors.As(err, &minioErr) {
+		return slices.Contains(permanentStatusCodes, minioErr.StatusCode) || slices.Contains(permanentErrorCodes, minioErr.Code)
+	}
+	return false
+}
@@ -4,15 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"cloud.google.com/go/storage"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/golang/mock/gomock"
 	"github.com/google/uuid"
+	"github.com/minio/minio-go/v7"
 	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/api/googleapi"
 
 	"github.com/rudderlabs/rudder-go-kit/filemanager"
+	"github.com/rudderlabs/rudder-go-kit/filemanager/mock_filemanager"
 	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
 	"github.com/rudderlabs/rudder-server/testhelper/destination"
 	"github.com/rudderlabs/rudder-server/utils/misc"
@@ -172,6 +180,147 @@ func TestDownloader(t *testing.T) {
 	}
 }
 
+func TestDownloaderRetries(t *testing.T) {
+	misc.Init()
+
+	conf := map[string]any{
+		"bucketName":      "testbucket",
+		"accessKeyID":     "MYACCESSKEY",
+		"secretAccessKey": "MYSECRETKEY",
+		"endPoint":        "localhost:9000",
+		"bucketProvider":  "MINIO",
+	}
+	warehouse := &model.Warehouse{
+		Destination: backendconfig.DestinationT{
+			ID:     "test-destination-id",
+			Config: conf,
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: "MSSQL",
+			},
+			WorkspaceID: "test-workspace-id",
+		},
+	}
+	loadFiles := []warehouseutils.LoadFile{{Location: "http://localhost:9000/testbucket/rudder-warehouse-load-objects/test-table/load.csv.gz"}}
+	transientErr := errors.New("InternalError: We encountered an internal error, please try again.: status code: 500")
+
+	// flakyFileManager fails the first failures downloads, partially writing the file every time
+	flakyFileManager := func(t *testing.T, failures int) filemanager.Factory {
+		mockFileManager := mock_filemanager.NewMockFileManager(gomock.NewController(t))
+		mockFileManager.EXPECT().Download(gomock.Any(), gomock.Any(), "rudder-warehouse-load-objects/test-table/load.csv.gz").DoAndReturn(
+			func(_ context.Context, f *os.File, _ string) error {
+				if failures > 0 {
+					failures--
+					_, _ = f.WriteString("partial")
+					return transientErr
+				}
+				_, err := f.WriteString("content")
+				return err
+			},
+		).AnyTimes()
+		return func(*filemanager.Settings) (filemanager.FileManager, error) {
+			return mockFileManager, nil
+		}
+	}
+
+	t.Run("recovers from transient failures", func(t *testing.T) {
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 1,
+			downloader.WithFileManagerFactory(flakyFileManager(t, 2)),
+			downloader.WithRetries(3, time.Millisecond),
+		)
+
+		fileNames, err := lfd.Download(context.Background(), "test-table")
+		require.NoError(t, err)
+		t.Cleanup(func() { misc.RemoveFilePaths(fileNames...) })
+
+		require.Len(t, fileNames, 1)
+		content, err := os.ReadFile(fileNames[0])
+		require.NoError(t, err)
+		require.Equal(t, "content", string(content))
+	})
+
+	t.Run("retries are bounded", func(t *testing.T) {
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 1,
+			downloader.WithFileManagerFactory(flakyFileManager(t, 4)),
+			downloader.WithRetries(3, time.Millisecond),
+		)
+
+		fileNames, err := lfd.Download(context.Background(), "test-table")
+		require.ErrorIs(t, err, transientErr)
+		require.Empty(t, fileNames)
+	})
+
+	t.Run("retry budget exhausted", func(t *testing.T) {
+		budget := 1
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 1,
+			downloader.WithFileManagerFactory(flakyFileManager(t, 2)),
+			downloader.WithRetries(3, time.Millisecond),
+			downloader.WithRetryBudget(func() bool {
+				budget--
+				return budget >= 0
+			}),
+		)
+
+		fileNames, err := lfd.Download(context.Background(), "test-table")
+		require.ErrorIs(t, err, transientErr)
+		require.Empty(t, fileNames)
+		require.Equal(t, -1, budget)
+	})
+
+	t.Run("permanent failures aren't retried", func(t *testing.T) {
+		for _, permanentErr := range []error{
+			filemanager.ErrKeyNotFound,
+			storage.ErrObjectNotExist,
+			awserr.NewRequestFailure(awserr.New("AccessDenied", "Access Denied", nil), http.StatusForbidden, "request-id"),
+			&googleapi.Error{Code: http.StatusNotFound, Message: "No such object"},
+			minio.ErrorResponse{Code: "NoSuchKey", StatusCode: http.StatusNotFound},
+			fmt.Errorf("downloading: %w", minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden}),
+		} {
+			permanentErr := permanentErr
+
+			t.Run(permanentErr.Error(), func(t *testing.T) {
+				mockFileManager := mock_filemanager.NewMockFileManager(gomock.NewController(t))
+				mockFileManager.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).Return(permanentErr).Times(1)
+
+				lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 1,
+					downloader.WithFileManagerFactory(func(*filemanager.Settings) (filemanager.FileManager, error) {
+						return mockFileManager, nil
+					}),
+					downloader.WithRetries(3, time.Millisecond),
+				)
+
+				_, err := lfd.Download(context.Background(), "test-table")
+				require.ErrorIs(t, err, permanentErr)
+			})
+		}
+	})
+
+	t.Run("transient failures with a status code are retried", func(t *testing.T) {
+		transientRequestErr := awserr.NewRequestFailure(awserr.New("InternalError", "We encountered an internal error", nil), http.StatusInternalServerError, "request-id")
+
+		mockFileManager := mock_filemanager.NewMockFileManager(gomock.NewController(t))
+		mockFileManager.EXPECT().Download(gomock.Any(), gomock.Any(), gomock.Any()).Return(transientRequestErr).Times(4)
+
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 1,
+			downloader.WithFileManagerFactory(func(*filemanager.Settings) (filemanager.FileManager, error) {
+				return mockFileManager, nil
+			}),
+			downloader.WithRetries(3, time.Millisecond),
+		)
+
+		_, err := lfd.Download(context.Background(), "test-table")
+		require.ErrorIs(t, err, transientRequestErr)
+	})
+
+	t.Run("without retries", func(t *testing.T) {
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 1,
+			downloader.WithFileManagerFactory(flakyFileManager(t, 1)),
+		)
+
+		_, err := lfd.Download(context.Background(), "test-table")
+		require.ErrorIs(t, err, transientErr)
+	})
+}
+
 func newMockUploader(t testing.TB, loadFiles []warehouseutils.LoadFile) *mockuploader.MockUploader {
 	ctrl := gomock.NewController(t)
 	u := mockuploader.NewMockUploader(ctrl)
@@ -179,3 +328,101 @@ func newMockUploader(t testing.TB, loadFiles []warehouseutils.LoadFile) *mockupl
 	u.EXPECT().UseRudderStorage().Return(false).AnyTimes()
 	return u
 }
+
+// listSession lists the objects of the prefix once
+type listSession struct {
+	fileObjects []*filemanager.FileInfo
+	err         error
+}
+
+func (l *listSession) Next() ([]*filemanager.FileInfo, error) {
+	return l.fileObjects, l.err
+}
+
+func TestDownloaderVerify(t *testing.T) {
+	misc.Init()
+
+	warehouse := &model.Warehouse{
+		Destination: backendconfig.DestinationT{
+			ID: "test-destination-id",
+			Config: map[string]any{
+				"bucketName":     "testbucket",
+				"endPoint":       "localhost:9000",
+				"bucketProvider": "MINIO",
+			},
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: "MSSQL",
+			},
+			WorkspaceID: "test-workspace-id",
+		},
+	}
+	loadFiles := []warehouseutils.LoadFile{
+		{Location: "http://localhost:9000/testbucket/rudder-warehouse-load-objects/test-table/load-1.csv.gz"},
+		{Location: "http://localhost:9000/testbucket/rudder-warehouse-load-objects/test-table/load-2.csv.gz"},
+		{Location: "http://localhost:9000/testbucket/rudder-warehouse-load-objects/test-table/load-3.csv.gz"},
+	}
+
+	// objectStorage lists the objects in the object storage, failing the listing of the keys in failures
+	objectStorage := func(t *testing.T, keys []string, failures map[string]error) filemanager.Factory {
+		mockFileManager := mock_filemanager.NewMockFileManager(gomock.NewController(t))
+		mockFileManager.EXPECT().ListFilesWithPrefix(gomock.Any(), "", gomock.Any(), int64(1)).DoAndReturn(
+			func(_ context.Context, _, prefix string, _ int64) filemanager.ListSession {
+				if err, ok := failures[prefix]; ok {
+					return &listSession{err: err}
+				}
+				for _, key := range keys {
+					if strings.HasPrefix(key, prefix) {
+						return &listSession{fileObjects: []*filemanager.FileInfo{{Key: key}}}
+					}
+				}
+				return &listSession{}
+			},
+		).AnyTimes()
+		return func(*filemanager.Settings) (filemanager.FileManager, error) {
+			return mockFileManager, nil
+		}
+	}
+
+	t.Run("all load files exist", func(t *testing.T) {
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 2,
+			downloader.WithFileManagerFactory(objectStorage(t, []string{
+				"rudder-warehouse-load-objects/test-table/load-1.csv.gz",
+				"rudder-warehouse-load-objects/test-table/load-2.csv.gz",
+				"rudder-warehouse-load-objects/test-table/load-3.csv.gz",
+			}, nil)),
+		)
+		require.NoError(t, lfd.Verify(context.Background(), ""))
+	})
+
+	t.Run("missing load files", func(t *testing.T) {
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 2,
+			downloader.WithFileManagerFactory(objectStorage(t, []string{
+				"rudder-warehouse-load-objects/test-table/load-2.csv.gz",
+				// only shares the prefix of the key of the load file
+				"rudder-warehouse-load-objects/test-table/load-3.csv.gz.tmp",
+			}, nil)),
+		)
+		err := lfd.Verify(context.Background(), "")
+		require.ErrorIs(t, err, downloader.ErrLoadFileNotFound)
+		require.EqualError(t, err, "load file not found: rudder-warehouse-load-objects/test-table/load-1.csv.gz, rudder-warehouse-load-objects/test-table/load-3.csv.gz")
+	})
+
+	t.Run("listing fails", func(t *testing.T) {
+		listErr := errors.New("AccessDenied: Access Denied")
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, loadFiles), 1,
+			downloader.WithFileManagerFactory(objectStorage(t, nil, map[string]error{
+				"rudder-warehouse-load-objects/test-table/load-1.csv.gz": listErr,
+			})),
+		)
+		err := lfd.Verify(context.Background(), "")
+		require.ErrorIs(t, err, listErr)
+		require.NotErrorIs(t, err, downloader.ErrLoadFileNotFound)
+	})
+
+	t.Run("no load files", func(t *testing.T) {
+		lfd := downloader.NewDownloader(warehouse, newMockUploader(t, nil), 1,
+			downloader.WithFileManagerFactory(objectStorage(t, nil, nil)),
+		)
+		require.NoError(t, lfd.Verify(context.Background(), "test-table"))
+	})
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+
+	lf "github.com/rudderlabs/rudder-server/warehouse/logfield"
+)
+
+const defaultCallbackTimeout = 10 * time.Second
+
+// CallbackPayload is the body POSTed to the callback url once an async job reaches a terminal state
+type CallbackPayload struct {
+	ID        string `json:"id"`
+	JobRunID  string `json:"job_run_id"`
+	TaskRunID string `json:"task_run_id"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+func isTerminalStatus(status string) bool {
+	return status == WhJobSucceeded || status == WhJobAborted
+}
+
+func isValidCallbackURL(callbackURL string) bool {
+	u, err := url.ParseRequestURI(callbackURL)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// notifyCallback sends the terminal status of the job to the callback url (if any) present in the job metadata.
+// It is done asynchronously, so that job completion is not blocked by the callback.
+func (a *AsyncJobWh) notifyCallback(id, status, errMessage string, metadata json.RawMessage) {
+	var jobMetadata WhJobsMetaData
+	if err := json.Unmarshal(metadata, &jobMetadata); err != nil {
+		a.logger.Warnw("unmarshalling metadata for async job callback", lf.Error, err.Error())
+		return
+	}
+	if jobMetadata.CallbackURL == "" {
+		return
+	}
+
+	callbackPayload := CallbackPayload{
+		ID:        id,
+		JobRunID:  jobMetadata.JobRunID,
+		TaskRunID: jobMetadata.TaskRunID,
+		Status:    status,
+	}
+	if status != WhJobSucceeded {
+		callbackPayload.Error = errMessage
+	}
+
+	go func() {
+		if err := a.sendCallback(a.context, jobMetadata.CallbackURL, callbackPayload); err != nil {
+			a.logger.Warnw("sending async job callback",
+				"id", id,
+				"callbackURL", jobMetadata.CallbackURL,
+				lf.Error, err.Error(),
+			)
+		}
+	}()
+}
+
+// sendCallback POSTs the payload to the callback url, retrying with an exponential backoff for at most maxCallbackRetries times.
+func (a *AsyncJobWh) sendCallback(ctx context.Context, callbackURL string, payload CallbackPayload) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshalling callback payload: %w", err)
+	}
+
+	operation := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+		if err != nil {
+			return backoff.Permanent(fmt.Errorf("creating callback request: %w", err))
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := a.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("sending callback request: %w", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+			return fmt.Errorf("unexpected callback response status code: %d", resp.StatusCode)
+		}
+		return nil
+	}
+
+	maxRetries := uint64(0)
+	if a.maxCallbackRetries > 0 {
+		maxRetries = uint64(a.maxCallbackRetries)
+	}
+	return backoff.RetryNotify(
+		operation,
+		backoff.WithContext(backoff.WithMaxRetries(backoff.NewExponentialBackOff(), maxRetries), ctx),
+		func(err error, t time.Duration) {
+			a.logger.Debugf("[WH-Jobs]: retrying async job callback in %s: %v", t, err)
+		},
+	)
+}
@@ -0,0 +1,82 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+)
+
+func TestAsyncJobCallback(t *testing.T) {
+	t.Run("valid callback url", func(t *testing.T) {
+		require.True(t, isValidCallbackURL("https://example.com/callback"))
+		require.True(t, isValidCallbackURL("http://localhost:8080/callback?id=1"))
+		require.False(t, isValidCallbackURL("example.com/callback"))
+		require.False(t, isValidCallbackURL("ftp://example.com"))
+		require.False(t, isValidCallbackURL("http://"))
+	})
+
+	t.Run("terminal status", func(t *testing.T) {
+		require.True(t, isTerminalStatus(WhJobSucceeded))
+		require.True(t, isTerminalStatus(WhJobAborted))
+		require.False(t, isTerminalStatus(WhJobFailed))
+		require.False(t, isTerminalStatus(WhJobExecuting))
+		require.False(t, isTerminalStatus(WhJobWaiting))
+	})
+
+	t.Run("success", func(t *testing.T) {
+		var received CallbackPayload
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPost, r.Method)
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		a := New(context.Background(), nil, nil, stats.Default)
+		a.logger = logger.NOP
+		a.maxCallbackRetries = 3
+
+		err := a.sendCallback(context.Background(), srv.URL, CallbackPayload{
+			ID:        "1",
+			JobRunID:  "job_run_id",
+			TaskRunID: "task_run_id",
+			Status:    WhJobAborted,
+			Error:     "some error",
+		})
+		require.NoError(t, err)
+		require.Equal(t, CallbackPayload{
+			ID:        "1",
+			JobRunID:  "job_run_id",
+			TaskRunID: "task_run_id",
+			Status:    WhJobAborted,
+			Error:     "some error",
+		}, received)
+	})
+
+	t.Run("bounded retries", func(t *testing.T) {
+		var attempts atomic.Int64
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer srv.Close()
+
+		a := New(context.Background(), nil, nil, stats.Default)
+		a.logger = logger.NOP
+		a.maxCallbackRetries = 2
+
+		err := a.sendCallback(context.Background(), srv.URL, CallbackPayload{ID: "1", Status: WhJobSucceeded})
+		require.Error(t, err)
+		require.EqualValues(t, 3, attempts.Load())
+	})
+}
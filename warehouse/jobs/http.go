@@ -42,6 +42,11 @@ func (a *AsyncJobWh) InsertJobHandler(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, fmt.Sprintf("invalid payload: %s", err.Error()), http.StatusBadRequest)
 		return
 	}
+	if err := validateJobType(&payload); err != nil {
+		a.logger.Warnw("invalid job type for inserting async job", lf.Error, err.Error())
+		http.Error(w, fmt.Sprintf("invalid payload: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
 
 	// TODO: Move to repository
 	tableNames, err := a.tableNamesBy(payload.SourceID, payload.DestinationID, payload.JobRunID, payload.TaskRunID)
@@ -63,10 +68,11 @@ func (a *AsyncJobWh) InsertJobHandler(w http.ResponseWriter, r *http.Request) {
 	jobIds := make([]int64, 0, len(tableNames))
 	for _, table := range tableNames {
 		metadataJson, err := json.Marshal(WhJobsMetaData{
-			JobRunID:  payload.JobRunID,
-			TaskRunID: payload.TaskRunID,
-			StartTime: payload.StartTime,
-			JobType:   string(notifier.JobTypeAsync),
+			JobRunID:    payload.JobRunID,
+			TaskRunID:   payload.TaskRunID,
+			StartTime:   payload.StartTime,
+			JobType:     string(notifier.JobTypeAsync),
+			CallbackURL: payload.CallbackURL,
 		})
 		if err != nil {
 			a.logger.Errorw("marshalling metadata for inserting async job", lf.Error, err.Error())
@@ -151,7 +157,20 @@ func validatePayload(payload *StartJobReqPayload) error {
 		return errors.New("job_run_id is required")
 	case payload.TaskRunID == "":
 		return errors.New("task_run_id is required")
+	case payload.CallbackURL != "" && !isValidCallbackURL(payload.CallbackURL):
+		return errors.New("callback_url must be a valid http(s) url")
 	default:
 		return nil
 	}
 }
+
+// validateJobType validates the job types of the payload against the supported ones, before the job gets enqueued
+func validateJobType(payload *StartJobReqPayload) error {
+	if payload.Type != "" && payload.Type != string(notifier.JobTypeAsync) {
+		return fmt.Errorf("invalid type %q, valid types are: %s", payload.Type, notifier.JobTypeAsync)
+	}
+	if !lo.Contains(supportedAsyncJobTypes, payload.AsyncJobType) {
+		return fmt.Errorf("invalid async_job_type %q, valid async job types are: %s", payload.AsyncJobType, strings.Join(supportedAsyncJobTypes, ", "))
+	}
+	return nil
+}
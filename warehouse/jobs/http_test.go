@@ -198,6 +198,45 @@ func TestAsyncJobHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("validate job type", func(t *testing.T) {
+		testCases := []struct {
+			name          string
+			payload       StartJobReqPayload
+			expectedError error
+		}{
+			{
+				name:          "invalid type",
+				payload:       StartJobReqPayload{Type: "upload", AsyncJobType: "deletebyjobrunid"},
+				expectedError: errors.New(`invalid type "upload", valid types are: async_job`),
+			},
+			{
+				name:          "missing async job type",
+				payload:       StartJobReqPayload{},
+				expectedError: errors.New(`invalid async_job_type "", valid async job types are: deletebyjobrunid`),
+			},
+			{
+				name:          "invalid async job type",
+				payload:       StartJobReqPayload{AsyncJobType: "deletebyjobid"},
+				expectedError: errors.New(`invalid async_job_type "deletebyjobid", valid async job types are: deletebyjobrunid`),
+			},
+			{
+				name:    "valid async job type",
+				payload: StartJobReqPayload{AsyncJobType: "deletebyjobrunid"},
+			},
+			{
+				name:    "valid type",
+				payload: StartJobReqPayload{Type: "async_job", AsyncJobType: "deletebyjobrunid"},
+			},
+		}
+		for _, tc := range testCases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expectedError, validateJobType(&tc.payload))
+			})
+		}
+	})
+
 	t.Run("InsertJobHandler", func(t *testing.T) {
 		t.Run("Not enabled", func(t *testing.T) {
 			req := httptest.NewRequest(http.MethodPost, "/v1/warehouse/jobs", nil)
@@ -253,13 +292,40 @@ func TestAsyncJobHandlers(t *testing.T) {
 			require.NoError(t, err)
 			require.Equal(t, "invalid payload: source_id is required\n", string(b))
 		})
+		t.Run("invalid job type", func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/warehouse/jobs", bytes.NewReader([]byte(`
+				{
+				  "source_id": "test_source_id",
+				  "destination_id": "test_destination_id",
+				  "job_run_id": "test_source_job_run_id",
+				  "task_run_id": "test_source_task_run_id",
+				  "async_job_type": "unknown"
+				}
+			`)))
+			resp := httptest.NewRecorder()
+
+			jobsManager := AsyncJobWh{
+				db:       db,
+				enabled:  true,
+				logger:   logger.NOP,
+				context:  ctx,
+				notifier: n,
+			}
+			jobsManager.InsertJobHandler(resp, req)
+			require.Equal(t, http.StatusBadRequest, resp.Code)
+
+			b, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, "invalid payload: invalid async_job_type \"unknown\", valid async job types are: deletebyjobrunid\n", string(b))
+		})
 		t.Run("success", func(t *testing.T) {
 			req := httptest.NewRequest(http.MethodPost, "/v1/warehouse/jobs", bytes.NewReader([]byte(`
 				{
 				  "source_id": "test_source_id",
 				  "destination_id": "test_destination_id",
 				  "job_run_id": "test_source_job_run_id",
-				  "task_run_id": "test_source_task_run_id"
+				  "task_run_id": "test_source_task_run_id",
+				  "async_job_type": "deletebyjobrunid"
 				}
 			`)))
 			resp := httptest.NewRecorder()
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"time"
 
 	sqlmw "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
@@ -17,6 +19,7 @@ import (
 
 	"github.com/rudderlabs/rudder-go-kit/config"
 	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	"github.com/rudderlabs/rudder-server/utils/timeutil"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
@@ -27,23 +30,34 @@ func New(
 	ctx context.Context,
 	db *sqlmw.DB,
 	notifier *notifier.Notifier,
+	statsFactory stats.Stats,
 ) *AsyncJobWh {
 	return &AsyncJobWh{
-		db:       db,
-		enabled:  false,
-		notifier: notifier,
-		context:  ctx,
-		logger:   logger.NewLogger().Child("asyncjob"),
+		db:           db,
+		enabled:      false,
+		notifier:     notifier,
+		context:      ctx,
+		logger:       logger.NewLogger().Child("asyncjob"),
+		statsFactory: statsFactory,
+		pendingTypes: make(map[string]struct{}),
+		httpClient: &http.Client{
+			Timeout: defaultCallbackTimeout,
+		},
 	}
 }
 
 func WithConfig(a *AsyncJobWh, config *config.Config) {
 	a.maxBatchSizeToProcess = config.GetInt("Warehouse.jobs.maxBatchSizeToProcess", 10)
+	a.maxConcurrentJobs = config.GetReloadableIntVar(0, 1, "Warehouse.jobs.maxConcurrentJobs")
 	a.maxCleanUpRetries = config.GetInt("Warehouse.jobs.maxCleanUpRetries", 5)
 	a.maxQueryRetries = config.GetInt("Warehouse.jobs.maxQueryRetries", 3)
 	a.maxAttemptsPerJob = config.GetInt("Warehouse.jobs.maxAttemptsPerJob", 3)
+	a.conf = config
 	a.retryTimeInterval = config.GetDuration("Warehouse.jobs.retryTimeInterval", 10, time.Second)
 	a.asyncJobTimeOut = config.GetDuration("Warehouse.jobs.asyncJobTimeOut", 300, time.Second)
+	a.maxCallbackRetries = config.GetInt("Warehouse.jobs.maxCallbackRetries", 3)
+	a.callbackTimeout = config.GetDuration("Warehouse.jobs.callbackTimeout", 10, time.Second)
+	a.httpClient.Timeout = a.callbackTimeout
 }
 
 func (a *AsyncJobWh) tableNamesBy(sourceID, destinationID, jobRunID, taskRunID string) ([]string, error) {
@@ -111,6 +125,7 @@ func (a *AsyncJobWh) addJobsToDB(payload *AsyncJobPayload) (int64, error) {
 }
 
 // Run Async Job runner's main job is to
+// 0. Verify the schema of wh_async_jobs, failing fast if a migration is missing
 // 1. Scan the database for entries into wh_async_jobs
 // 2. Publish data to pg_notifier queue
 // 3. Move any executing jobs to waiting
@@ -119,6 +134,10 @@ func (a *AsyncJobWh) Run() error {
 	a.logger.Info("[WH-Jobs]: Initializing async job runner")
 	g, ctx := errgroup.WithContext(a.context)
 	a.context = ctx
+	if err := a.validateSchema(a.context); err != nil {
+		a.logger.Errorf("[WH-Jobs]: invalid asynctable schema with error %s", err.Error())
+		return err
+	}
 	err := misc.RetryWith(a.context, a.retryTimeInterval, a.maxCleanUpRetries, func(ctx context.Context) error {
 		err := a.cleanUpAsyncTable(ctx)
 		if err != nil {
@@ -171,6 +190,8 @@ func (a *AsyncJobWh) startAsyncJobRunner(ctx context.Context) error {
 		case <-time.After(a.retryTimeInterval):
 		}
 
+		a.emitPendingAsyncJobsStats(ctx)
+
 		pendingAsyncJobs, err := a.getPendingAsyncJobs(ctx)
 		if err != nil {
 			a.logger.Errorf("[WH-Jobs]: unable to get pending async jobs with error %s", err.Error())
@@ -255,6 +276,16 @@ func (a *AsyncJobWh) updateStatusJobPayloadsFromNotifierResponse(r *notifier.Pub
 func (a *AsyncJobWh) getPendingAsyncJobs(ctx context.Context) ([]AsyncJobPayload, error) {
 	asyncJobPayloads := make([]AsyncJobPayload, 0)
 	a.logger.Debug("[WH-Jobs]: Get pending wh async jobs")
+	limit, err := a.pickupLimit(ctx)
+	if err != nil {
+		a.logger.Errorf("[WH-Jobs]: Error in getting the pickup limit for wh async jobs with error %s", err.Error())
+		return asyncJobPayloads, err
+	}
+	if limit == 0 {
+		a.logger.Debug("[WH-Jobs]: Max concurrent wh async jobs reached")
+		return asyncJobPayloads, nil
+	}
+
 	// Filter to get most recent row for the sourceId/destinationID combo and remaining ones should relegate to abort.
 	var attempt int
 	query := fmt.Sprintf(
@@ -266,8 +297,11 @@ func (a *AsyncJobWh) getPendingAsyncJobs(ctx context.Context) ([]AsyncJobPayload
 			async_job_type,
 			metadata,
 			attempt
-		FROM %s WHERE (status=$1 OR status=$2) LIMIT $3`, warehouseutils.WarehouseAsyncJobTable)
-	rows, err := a.db.QueryContext(ctx, query, WhJobWaiting, WhJobFailed, a.maxBatchSizeToProcess)
+		FROM %s WHERE (status=$1 OR status=$2) AND destination_id NOT IN (SELECT destination_id FROM %s) LIMIT $3`,
+		warehouseutils.WarehouseAsyncJobTable,
+		warehouseutils.WarehouseAsyncJobPausesTable,
+	)
+	rows, err := a.db.QueryContext(ctx, query, WhJobWaiting, WhJobFailed, limit)
 	if err != nil {
 		a.logger.Errorf("[WH-Jobs]: Error in getting pending wh async jobs with error %s", err.Error())
 		return asyncJobPayloads, err
@@ -288,8 +322,9 @@ func (a *AsyncJobWh) getPendingAsyncJobs(ctx context.Context) ([]AsyncJobPayload
 			a.logger.Errorf("[WH-Jobs]: Error scanning rows %s\n", err)
 			return asyncJobPayloads, err
 		}
+		asyncJobPayload.MaxAttempts = a.maxAttemptsFor(asyncJobPayload.DestinationID, asyncJobPayload.AsyncJobType)
 		asyncJobPayloads = append(asyncJobPayloads, asyncJobPayload)
-		a.logger.Infof("Adding row with Id = %s & attempt no %d", asyncJobPayload.Id, attempt)
+		a.logger.Infof("Adding row with Id = %s & attempt no %d of max attempts %d", asyncJobPayload.Id, attempt, asyncJobPayload.MaxAttempts)
 	}
 	if err := rows.Err(); err != nil {
 		a.logger.Errorf("[WH-Jobs]: Error in getting pending wh async jobs with error %s", rows.Err().Error())
@@ -298,43 +333,228 @@ func (a *AsyncJobWh) getPendingAsyncJobs(ctx context.Context) ([]AsyncJobPayload
 	return asyncJobPayloads, nil
 }
 
+// pickupLimit returns the number of async jobs which can be picked up, bounded by Warehouse.jobs.maxConcurrentJobs across all destinations.
+// The jobs which can't be picked up are kept waiting.
+func (a *AsyncJobWh) pickupLimit(ctx context.Context) (int, error) {
+	if a.maxConcurrentJobs == nil || a.maxConcurrentJobs.Load() <= 0 {
+		return a.maxBatchSizeToProcess, nil
+	}
+	maxConcurrentJobs := a.maxConcurrentJobs.Load()
+
+	var executing int
+	sqlStatement := fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE status = $1`, warehouseutils.WarehouseAsyncJobTable)
+	if err := a.db.QueryRowContext(ctx, sqlStatement, WhJobExecuting).Scan(&executing); err != nil {
+		return 0, fmt.Errorf("counting executing async jobs: %w", err)
+	}
+
+	available := maxConcurrentJobs - executing
+	if available <= 0 {
+		a.statsFactory.NewStat("wh_async_jobs_concurrency_limit_reached", stats.CountType).Increment()
+		return 0, nil
+	}
+	return min(available, a.maxBatchSizeToProcess), nil
+}
+
+// PauseDestination pauses picking up the async jobs of the destination, without draining the pending ones.
+// The pause is persisted, so that it survives restarts.
+func (a *AsyncJobWh) PauseDestination(ctx context.Context, destinationID string) error {
+	sqlStatement := fmt.Sprintf(`INSERT INTO %s (destination_id, created_at) VALUES ($1, $2) ON CONFLICT (destination_id) DO NOTHING`,
+		warehouseutils.WarehouseAsyncJobPausesTable,
+	)
+	if _, err := a.db.ExecContext(ctx, sqlStatement, destinationID, timeutil.Now()); err != nil {
+		return fmt.Errorf("pausing async jobs for destination %s: %w", destinationID, err)
+	}
+	a.logger.Infof("[WH-Jobs]: Paused async jobs for destination %s", destinationID)
+	return nil
+}
+
+// ResumeDestination resumes picking up the async jobs of a paused destination
+func (a *AsyncJobWh) ResumeDestination(ctx context.Context, destinationID string) error {
+	sqlStatement := fmt.Sprintf(`DELETE FROM %s WHERE destination_id = $1`,
+		warehouseutils.WarehouseAsyncJobPausesTable,
+	)
+	if _, err := a.db.ExecContext(ctx, sqlStatement, destinationID); err != nil {
+		return fmt.Errorf("resuming async jobs for destination %s: %w", destinationID, err)
+	}
+	a.logger.Infof("[WH-Jobs]: Resumed async jobs for destination %s", destinationID)
+	return nil
+}
+
+// PausedDestinations returns the destinations for which the async jobs are paused
+func (a *AsyncJobWh) PausedDestinations(ctx context.Context) ([]string, error) {
+	sqlStatement := fmt.Sprintf(`SELECT destination_id FROM %s ORDER BY destination_id`,
+		warehouseutils.WarehouseAsyncJobPausesTable,
+	)
+	rows, err := a.db.QueryContext(ctx, sqlStatement)
+	if err != nil {
+		return nil, fmt.Errorf("querying paused destinations: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var destinationIDs []string
+	for rows.Next() {
+		var destinationID string
+		if err := rows.Scan(&destinationID); err != nil {
+			return nil, fmt.Errorf("scanning paused destinations: %w", err)
+		}
+		destinationIDs = append(destinationIDs, destinationID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterating paused destinations: %w", err)
+	}
+	return destinationIDs, nil
+}
+
+// maxAttemptsFor returns the maximum number of attempts for the async job type of the destination.
+// Overrides are looked up by destination and async job type first, then by destination, falling back to Warehouse.jobs.maxAttemptsPerJob.
+func (a *AsyncJobWh) maxAttemptsFor(destinationID, asyncJobType string) int {
+	if a.conf == nil {
+		return a.maxAttemptsPerJob
+	}
+	keys := []string{
+		fmt.Sprintf("Warehouse.jobs.%s.%s.maxAttemptsPerJob", destinationID, asyncJobType),
+		fmt.Sprintf("Warehouse.jobs.%s.maxAttemptsPerJob", destinationID),
+	}
+	for _, key := range keys {
+		if a.conf.IsSet(key) {
+			if maxAttempts := a.conf.GetInt(key, a.maxAttemptsPerJob); maxAttempts > 0 {
+				return maxAttempts
+			}
+		}
+	}
+	return a.maxAttemptsPerJob
+}
+
+// emitPendingAsyncJobsStats emits the number of pending async jobs and the age of the oldest pending async job by async job type
+func (a *AsyncJobWh) emitPendingAsyncJobsStats(ctx context.Context) {
+	query := fmt.Sprintf(`
+		SELECT
+		  async_job_type,
+		  COUNT(*),
+		  MIN(created_at)
+		FROM
+		  %s
+		WHERE
+		  status = $1 OR status = $2
+		GROUP BY
+		  async_job_type;
+`,
+		warehouseutils.WarehouseAsyncJobTable,
+	)
+	rows, err := a.db.QueryContext(ctx, query, WhJobWaiting, WhJobFailed)
+	if err != nil {
+		a.logger.Warnf("[WH-Jobs]: Error in getting pending wh async jobs stats with error %s", err.Error())
+		return
+	}
+	defer func() { _ = rows.Close() }()
+
+	now := timeutil.Now()
+	seenTypes := make(map[string]struct{})
+
+	for rows.Next() {
+		var (
+			asyncJobType string
+			count        int64
+			oldest       time.Time
+		)
+		if err := rows.Scan(&asyncJobType, &count, &oldest); err != nil {
+			a.logger.Warnf("[WH-Jobs]: Error scanning pending wh async jobs stats with error %s", err.Error())
+			return
+		}
+		seenTypes[asyncJobType] = struct{}{}
+
+		tags := stats.Tags{"asyncJobType": asyncJobType}
+		a.statsFactory.NewTaggedStat("wh_async_jobs_pending_count", stats.GaugeType, tags).Gauge(count)
+		a.statsFactory.NewTaggedStat("wh_async_jobs_oldest_pending_age", stats.GaugeType, tags).Gauge(now.Sub(oldest).Seconds())
+	}
+	if err := rows.Err(); err != nil {
+		a.logger.Warnf("[WH-Jobs]: Error iterating pending wh async jobs stats with error %s", err.Error())
+		return
+	}
+
+	// reset the gauges for the async job types which don't have any pending jobs anymore
+	for asyncJobType := range a.pendingTypes {
+		if _, ok := seenTypes[asyncJobType]; ok {
+			continue
+		}
+		tags := stats.Tags{"asyncJobType": asyncJobType}
+		a.statsFactory.NewTaggedStat("wh_async_jobs_pending_count", stats.GaugeType, tags).Gauge(0)
+		a.statsFactory.NewTaggedStat("wh_async_jobs_oldest_pending_age", stats.GaugeType, tags).Gauge(0)
+	}
+	a.pendingTypes = seenTypes
+}
+
 // Updates the warehouse async jobs with the status sent as a parameter
 func (a *AsyncJobWh) updateAsyncJobs(ctx context.Context, payloads map[string]AsyncJobStatus) error {
 	a.logger.Info("[WH-Jobs]: Updating wh async jobs to Executing")
 	var err error
 	for _, payload := range payloads {
+		maxAttempts := payload.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = a.maxAttemptsPerJob
+		}
 		if payload.Error != nil {
-			err = a.updateAsyncJobStatus(ctx, payload.Id, payload.Status, payload.Error.Error())
+			err = a.updateAsyncJobStatus(ctx, payload.Id, payload.Status, payload.Error.Error(), maxAttempts)
 			continue
 		}
-		err = a.updateAsyncJobStatus(ctx, payload.Id, payload.Status, "")
+		err = a.updateAsyncJobStatus(ctx, payload.Id, payload.Status, "", maxAttempts)
 	}
 	return err
 }
 
-func (a *AsyncJobWh) updateAsyncJobStatus(ctx context.Context, Id, status, errMessage string) error {
+// updateAsyncJobStatus updates the status of the async job, aborting it once it reaches maxAttempts.
+// The effective maxAttempts is recorded in the metadata of the job for observability, along with the start time of the attempt once the job starts executing.
+func (a *AsyncJobWh) updateAsyncJobStatus(ctx context.Context, Id, status, errMessage string, maxAttempts int) error {
 	a.logger.Infof("[WH-Jobs]: Updating status of wh async jobs to %s", status)
 	sqlStatement := fmt.Sprintf(`UPDATE %s SET status=(CASE
 								WHEN attempt >= $1
 								THEN $2
 								ELSE $3
 								END) ,
-								error=$4 WHERE id=$5 AND status!=$6 AND status!=$7 `,
+								error=$4,
+								metadata=jsonb_set(COALESCE(metadata, '{}'::jsonb), '{max_attempts}', to_jsonb($1::int)) || (CASE
+								WHEN $8::text = ''
+								THEN '{}'::jsonb
+								ELSE jsonb_build_object('attempt_start_time', $8::text)
+								END)
+								WHERE id=$5 AND status!=$6 AND status!=$7
+								RETURNING status, metadata`,
 		warehouseutils.WarehouseAsyncJobTable,
 	)
+	var attemptStartTime string
+	if status == WhJobExecuting {
+		attemptStartTime = timeutil.Now().Format(time.RFC3339Nano)
+	}
 	var err error
 	for retryCount := 0; retryCount < a.maxQueryRetries; retryCount++ {
 		a.logger.Debugf("[WH-Jobs]: updating async jobs table query %s, retry no : %d", sqlStatement, retryCount)
-		_, err := a.db.ExecContext(ctx, sqlStatement,
-			a.maxAttemptsPerJob, WhJobAborted, status, errMessage, Id, WhJobAborted, WhJobSucceeded,
+
+		var (
+			updatedStatus string
+			metadata      json.RawMessage
 		)
+		err = a.db.QueryRowContext(ctx, sqlStatement,
+			maxAttempts, WhJobAborted, status, errMessage, Id, WhJobAborted, WhJobSucceeded, attemptStartTime,
+		).Scan(&updatedStatus, &metadata)
+		if errors.Is(err, sql.ErrNoRows) {
+			a.logger.Debugf("[WH-Jobs]: async job %s is already in a terminal state", Id)
+			return nil
+		}
 		if err == nil {
 			a.logger.Info("Update successful")
 			a.logger.Debugf("query: %s successfully executed", sqlStatement)
+			if isTerminalStatus(updatedStatus) {
+				if completedMetadata, err := a.updateAsyncJobCompletion(ctx, Id); err != nil {
+					a.logger.Warnf("[WH-Jobs]: Unable to record completion of async job %s: %v", Id, err)
+				} else {
+					metadata = completedMetadata
+				}
+				a.notifyCallback(Id, updatedStatus, errMessage, metadata)
+			}
 			if status == WhJobFailed {
 				return a.updateAsyncJobAttempt(ctx, Id)
 			}
-			return err
+			return nil
 		}
 	}
 
@@ -342,6 +562,25 @@ func (a *AsyncJobWh) updateAsyncJobStatus(ctx context.Context, Id, status, errMe
 	return err
 }
 
+// updateAsyncJobCompletion records the end time and the duration, since the start of the last attempt, in the metadata of the job.
+// Jobs which never started executing, e.g. failing to be published, fall back to the time they got created.
+func (a *AsyncJobWh) updateAsyncJobCompletion(ctx context.Context, Id string) (json.RawMessage, error) {
+	sqlStatement := fmt.Sprintf(`UPDATE %s SET metadata = COALESCE(metadata, '{}'::jsonb) || jsonb_build_object(
+									'end_time', $2::text,
+									'duration_ms', GREATEST((EXTRACT(EPOCH FROM ($3::timestamp - COALESCE((metadata->>'attempt_start_time')::timestamp, created_at))) * 1000)::bigint, 0)
+								) WHERE id=$1
+								RETURNING metadata`,
+		warehouseutils.WarehouseAsyncJobTable,
+	)
+	now := timeutil.Now()
+
+	var metadata json.RawMessage
+	if err := a.db.QueryRowContext(ctx, sqlStatement, Id, now.Format(time.RFC3339), now).Scan(&metadata); err != nil {
+		return nil, fmt.Errorf("updating completion of async job: %w", err)
+	}
+	return metadata, nil
+}
+
 func (a *AsyncJobWh) updateAsyncJobAttempt(ctx context.Context, Id string) error {
 	a.logger.Info("[WH-Jobs]: Incrementing wh async jobs attempt")
 	sqlStatement := fmt.Sprintf(`UPDATE %s SET attempt=attempt+1 WHERE id=$1 AND status!=$2 AND status!=$3 `, warehouseutils.WarehouseAsyncJobTable)
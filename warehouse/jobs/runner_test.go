@@ -0,0 +1,348 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ory/dockertest/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-go-kit/stats/memstats"
+	"github.com/rudderlabs/rudder-go-kit/testhelper/docker/resource"
+	migrator "github.com/rudderlabs/rudder-server/services/sql-migrator"
+	sqlmiddleware "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
+)
+
+func TestAsyncJobMaxAttempts(t *testing.T) {
+	t.Run("without config", func(t *testing.T) {
+		a := New(context.Background(), nil, nil, stats.Default)
+		a.maxAttemptsPerJob = 3
+
+		require.Equal(t, 3, a.maxAttemptsFor("destinationID", "deletebyjobrunid"))
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		c := config.New()
+		c.Set("Warehouse.jobs.maxAttemptsPerJob", 5)
+		c.Set("Warehouse.jobs.destinationID.maxAttemptsPerJob", 7)
+		c.Set("Warehouse.jobs.destinationID.deletebyjobrunid.maxAttemptsPerJob", 9)
+		c.Set("Warehouse.jobs.invalidDestinationID.maxAttemptsPerJob", 0)
+
+		a := New(context.Background(), nil, nil, stats.Default)
+		WithConfig(a, c)
+
+		require.Equal(t, 9, a.maxAttemptsFor("destinationID", "deletebyjobrunid"))
+		require.Equal(t, 7, a.maxAttemptsFor("destinationID", "otherjobtype"))
+		require.Equal(t, 5, a.maxAttemptsFor("otherDestinationID", "deletebyjobrunid"))
+		require.Equal(t, 5, a.maxAttemptsFor("invalidDestinationID", "deletebyjobrunid"))
+	})
+
+	t.Run("status carries the effective max attempts", func(t *testing.T) {
+		payloads := []AsyncJobPayload{{Id: "1", MaxAttempts: 9}, {Id: "2", MaxAttempts: 3}}
+
+		m := convertToPayloadStatusStructWithSingleStatus(payloads, WhJobExecuting, nil)
+		require.Equal(t, 9, m["1"].MaxAttempts)
+		require.Equal(t, 3, m["2"].MaxAttempts)
+
+		m = getAsyncStatusMapFromAsyncPayloads(payloads)
+		require.Equal(t, 9, m["1"].MaxAttempts)
+		require.Equal(t, 3, m["2"].MaxAttempts)
+	})
+}
+
+func TestAsyncJobPauses(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pgResource, err := resource.SetupPostgres(pool, t)
+	require.NoError(t, err)
+
+	err = (&migrator.Migrator{
+		Handle:          pgResource.DB,
+		MigrationsTable: "wh_schema_migrations",
+	}).Migrate("warehouse")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	a := New(ctx, sqlmiddleware.New(pgResource.DB), nil, stats.Default)
+	a.logger = logger.NOP
+	WithConfig(a, config.New())
+
+	for _, destinationID := range []string{"destination_1", "destination_2"} {
+		_, err := a.addJobsToDB(&AsyncJobPayload{
+			SourceID:      "source_id",
+			DestinationID: destinationID,
+			TableName:     "table_name",
+			AsyncJobType:  "deletebyjobrunid",
+			WorkspaceID:   "workspace_id",
+			MetaData:      json.RawMessage(`{}`),
+		})
+		require.NoError(t, err)
+	}
+
+	pendingDestinations := func() []string {
+		pendingAsyncJobs, err := a.getPendingAsyncJobs(ctx)
+		require.NoError(t, err)
+
+		var destinationIDs []string
+		for _, pendingAsyncJob := range pendingAsyncJobs {
+			destinationIDs = append(destinationIDs, pendingAsyncJob.DestinationID)
+		}
+		return destinationIDs
+	}
+
+	require.ElementsMatch(t, []string{"destination_1", "destination_2"}, pendingDestinations())
+
+	require.NoError(t, a.PauseDestination(ctx, "destination_1"))
+	require.NoError(t, a.PauseDestination(ctx, "destination_1"))
+	require.Equal(t, []string{"destination_2"}, pendingDestinations())
+
+	// the pause state is persisted
+	restarted := New(ctx, sqlmiddleware.New(pgResource.DB), nil, stats.Default)
+	restarted.logger = logger.NOP
+	WithConfig(restarted, config.New())
+
+	pausedDestinations, err := restarted.PausedDestinations(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"destination_1"}, pausedDestinations)
+
+	require.NoError(t, restarted.ResumeDestination(ctx, "destination_1"))
+	require.ElementsMatch(t, []string{"destination_1", "destination_2"}, pendingDestinations())
+
+	pausedDestinations, err = a.PausedDestinations(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pausedDestinations)
+}
+
+func TestAsyncJobCompletion(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pgResource, err := resource.SetupPostgres(pool, t)
+	require.NoError(t, err)
+
+	err = (&migrator.Migrator{
+		Handle:          pgResource.DB,
+		MigrationsTable: "wh_schema_migrations",
+	}).Migrate("warehouse")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	a := New(ctx, sqlmiddleware.New(pgResource.DB), nil, stats.Default)
+	a.logger = logger.NOP
+	WithConfig(a, config.New())
+
+	metadataOf := func(id int64) WhJobsMetaData {
+		var metadata json.RawMessage
+		err := pgResource.DB.QueryRowContext(ctx, `SELECT metadata FROM wh_async_jobs WHERE id = $1`, id).Scan(&metadata)
+		require.NoError(t, err)
+
+		var jobMetadata WhJobsMetaData
+		require.NoError(t, json.Unmarshal(metadata, &jobMetadata))
+		return jobMetadata
+	}
+
+	id, err := a.addJobsToDB(&AsyncJobPayload{
+		SourceID:      "source_id",
+		DestinationID: "destination_id",
+		TableName:     "table_name",
+		AsyncJobType:  AsyncJobTypeDeleteByJobRunID,
+		WorkspaceID:   "workspace_id",
+		MetaData:      json.RawMessage(`{"job_run_id":"job_run_id","task_run_id":"task_run_id","start_time":"2023-01-01 00:00:00"}`),
+	})
+	require.NoError(t, err)
+
+	jobID := strconv.FormatInt(id, 10)
+
+	// the job got created long before it started executing
+	_, err = pgResource.DB.ExecContext(ctx, `UPDATE wh_async_jobs SET created_at = created_at - INTERVAL '1 hour' WHERE id = $1`, id)
+	require.NoError(t, err)
+
+	require.NoError(t, a.updateAsyncJobStatus(ctx, jobID, WhJobExecuting, "", 3))
+	require.Empty(t, metadataOf(id).EndTime)
+	require.NotEmpty(t, metadataOf(id).AttemptStartTime)
+
+	require.NoError(t, a.updateAsyncJobStatus(ctx, jobID, WhJobSucceeded, "", 3))
+
+	jobMetadata := metadataOf(id)
+	require.Equal(t, "job_run_id", jobMetadata.JobRunID)
+	require.Equal(t, "2023-01-01 00:00:00", jobMetadata.StartTime)
+	require.NotEmpty(t, jobMetadata.EndTime)
+	_, err = time.Parse(time.RFC3339, jobMetadata.EndTime)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, jobMetadata.DurationMs, int64(0))
+	require.Less(t, jobMetadata.DurationMs, time.Hour.Milliseconds())
+}
+
+func TestAsyncJobMaxConcurrentJobs(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pgResource, err := resource.SetupPostgres(pool, t)
+	require.NoError(t, err)
+
+	err = (&migrator.Migrator{
+		Handle:          pgResource.DB,
+		MigrationsTable: "wh_schema_migrations",
+	}).Migrate("warehouse")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	c := config.New()
+	c.Set("Warehouse.jobs.maxConcurrentJobs", 3)
+
+	statsStore := memstats.New()
+
+	a := New(ctx, sqlmiddleware.New(pgResource.DB), nil, statsStore)
+	a.logger = logger.NOP
+	WithConfig(a, c)
+
+	for i := 0; i < 5; i++ {
+		_, err := a.addJobsToDB(&AsyncJobPayload{
+			SourceID:      "source_id",
+			DestinationID: "destination_id",
+			TableName:     "table_name_" + strconv.Itoa(i),
+			AsyncJobType:  AsyncJobTypeDeleteByJobRunID,
+			WorkspaceID:   "workspace_id",
+			MetaData:      json.RawMessage(`{}`),
+		})
+		require.NoError(t, err)
+	}
+
+	pendingAsyncJobs, err := a.getPendingAsyncJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingAsyncJobs, 3)
+
+	_, err = pgResource.DB.ExecContext(ctx, `UPDATE wh_async_jobs SET status = $1 WHERE id IN (SELECT id FROM wh_async_jobs ORDER BY id LIMIT 2)`, WhJobExecuting)
+	require.NoError(t, err)
+
+	pendingAsyncJobs, err = a.getPendingAsyncJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingAsyncJobs, 1)
+	require.Nil(t, statsStore.Get("wh_async_jobs_concurrency_limit_reached", nil))
+
+	// the limit is hot-reloadable
+	c.Set("Warehouse.jobs.maxConcurrentJobs", 2)
+
+	pendingAsyncJobs, err = a.getPendingAsyncJobs(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pendingAsyncJobs)
+	require.EqualValues(t, 1, statsStore.Get("wh_async_jobs_concurrency_limit_reached", nil).LastValue())
+
+	c.Set("Warehouse.jobs.maxConcurrentJobs", 0)
+
+	pendingAsyncJobs, err = a.getPendingAsyncJobs(ctx)
+	require.NoError(t, err)
+	require.Len(t, pendingAsyncJobs, 3)
+}
+
+func TestAsyncJobPendingStats(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pgResource, err := resource.SetupPostgres(pool, t)
+	require.NoError(t, err)
+
+	err = (&migrator.Migrator{
+		Handle:          pgResource.DB,
+		MigrationsTable: "wh_schema_migrations",
+	}).Migrate("warehouse")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	statsStore := memstats.New()
+
+	a := New(ctx, sqlmiddleware.New(pgResource.DB), nil, statsStore)
+	a.logger = logger.NOP
+	WithConfig(a, config.New())
+
+	const otherAsyncJobType = "other_async_job_type"
+
+	var ids []int64
+	for _, asyncJobType := range []string{AsyncJobTypeDeleteByJobRunID, AsyncJobTypeDeleteByJobRunID, otherAsyncJobType} {
+		id, err := a.addJobsToDB(&AsyncJobPayload{
+			SourceID:      "source_id",
+			DestinationID: "destination_id",
+			TableName:     "table_name",
+			AsyncJobType:  asyncJobType,
+			WorkspaceID:   "workspace_id",
+			MetaData:      json.RawMessage(`{}`),
+		})
+		require.NoError(t, err)
+		ids = append(ids, id)
+	}
+
+	// the oldest pending job got created an hour ago
+	_, err = pgResource.DB.ExecContext(ctx, `UPDATE wh_async_jobs SET created_at = created_at - INTERVAL '1 hour' WHERE id = $1`, ids[0])
+	require.NoError(t, err)
+
+	gauge := func(name, asyncJobType string) float64 {
+		m := statsStore.Get(name, stats.Tags{"asyncJobType": asyncJobType})
+		require.NotNil(t, m, "%s of %s should be emitted", name, asyncJobType)
+		return m.LastValue()
+	}
+
+	a.emitPendingAsyncJobsStats(ctx)
+	require.EqualValues(t, 2, gauge("wh_async_jobs_pending_count", AsyncJobTypeDeleteByJobRunID))
+	require.InDelta(t, time.Hour.Seconds(), gauge("wh_async_jobs_oldest_pending_age", AsyncJobTypeDeleteByJobRunID), time.Minute.Seconds())
+	require.EqualValues(t, 1, gauge("wh_async_jobs_pending_count", otherAsyncJobType))
+	require.Less(t, gauge("wh_async_jobs_oldest_pending_age", otherAsyncJobType), time.Minute.Seconds())
+
+	// the gauges are reset once the jobs of a type are no longer pending
+	require.NoError(t, a.updateAsyncJobStatus(ctx, strconv.FormatInt(ids[2], 10), WhJobSucceeded, "", 3))
+
+	a.emitPendingAsyncJobsStats(ctx)
+	require.EqualValues(t, 2, gauge("wh_async_jobs_pending_count", AsyncJobTypeDeleteByJobRunID))
+	require.Zero(t, gauge("wh_async_jobs_pending_count", otherAsyncJobType))
+	require.Zero(t, gauge("wh_async_jobs_oldest_pending_age", otherAsyncJobType))
+}
+
+func TestAsyncJobValidateSchema(t *testing.T) {
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	pgResource, err := resource.SetupPostgres(pool, t)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	a := New(ctx, sqlmiddleware.New(pgResource.DB), nil, stats.Default)
+	a.logger = logger.NOP
+	WithConfig(a, config.New())
+
+	t.Run("missing table", func(t *testing.T) {
+		err := a.validateSchema(ctx)
+		require.ErrorIs(t, err, errMigrationNeeded)
+		require.ErrorContains(t, err, "table wh_async_jobs doesn't exist")
+	})
+
+	err = (&migrator.Migrator{
+		Handle:          pgResource.DB,
+		MigrationsTable: "wh_schema_migrations",
+	}).Migrate("warehouse")
+	require.NoError(t, err)
+
+	t.Run("migrated", func(t *testing.T) {
+		require.NoError(t, a.validateSchema(ctx))
+	})
+
+	t.Run("missing columns", func(t *testing.T) {
+		_, err := pgResource.DB.ExecContext(ctx, `ALTER TABLE wh_async_jobs DROP COLUMN workspace_id;`)
+		require.NoError(t, err)
+
+		err = a.validateSchema(ctx)
+		require.ErrorIs(t, err, errMigrationNeeded)
+		require.ErrorContains(t, err, "table wh_async_jobs is missing the columns workspace_id")
+		require.ErrorIs(t, a.Run(), errMigrationNeeded, "the runner fails fast")
+	})
+}
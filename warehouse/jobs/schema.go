@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// errMigrationNeeded is returned if the async jobs table doesn't have the schema the async jobs are read and written with
+var errMigrationNeeded = errors.New("migration needed")
+
+// asyncJobColumns are the columns of the async jobs table the async jobs are read and written with
+var asyncJobColumns = []string{"id", "source_id", "destination_id", "tablename", "async_job_type", "workspace_id", "metadata", "status"}
+
+// validateSchema verifies that the async jobs table has all the columns the async jobs are read and written with,
+// so that a missing migration fails the runner at startup, rather than every query at runtime.
+func (a *AsyncJobWh) validateSchema(ctx context.Context) error {
+	rows, err := a.db.QueryContext(ctx, `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1;
+`,
+		warehouseutils.WarehouseAsyncJobTable,
+	)
+	if err != nil {
+		return fmt.Errorf("querying the columns of %s: %w", warehouseutils.WarehouseAsyncJobTable, err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var columns []string
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return fmt.Errorf("scanning the columns of %s: %w", warehouseutils.WarehouseAsyncJobTable, err)
+		}
+		columns = append(columns, column)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterating the columns of %s: %w", warehouseutils.WarehouseAsyncJobTable, err)
+	}
+
+	if len(columns) == 0 {
+		return fmt.Errorf("%w: table %s doesn't exist, make sure the warehouse migrations ran", errMigrationNeeded, warehouseutils.WarehouseAsyncJobTable)
+	}
+	if missing := lo.Without(asyncJobColumns, columns...); len(missing) > 0 {
+		return fmt.Errorf("%w: table %s is missing the columns %s, make sure the warehouse migrations ran",
+			errMigrationNeeded, warehouseutils.WarehouseAsyncJobTable, strings.Join(missing, ", "),
+		)
+	}
+	return nil
+}
@@ -3,13 +3,17 @@ package jobs
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"time"
 
 	"github.com/rudderlabs/rudder-server/services/notifier"
+	"github.com/rudderlabs/rudder-server/utils/misc"
 
 	sqlmw "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
 
+	"github.com/rudderlabs/rudder-go-kit/config"
 	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
 )
 
 // StartJobReqPayload For processing requests payload in handlers.go
@@ -23,6 +27,7 @@ type StartJobReqPayload struct {
 	TaskRunID     string `json:"task_run_id"`
 	AsyncJobType  string `json:"async_job_type"`
 	WorkspaceID   string `json:"workspace_id"`
+	CallbackURL   string `json:"callback_url"`
 }
 
 type AsyncJobWh struct {
@@ -32,11 +37,18 @@ type AsyncJobWh struct {
 	context               context.Context
 	logger                logger.Logger
 	maxBatchSizeToProcess int
+	maxConcurrentJobs     misc.ValueLoader[int]
 	maxCleanUpRetries     int
 	maxQueryRetries       int
 	retryTimeInterval     time.Duration
 	maxAttemptsPerJob     int
+	conf                  *config.Config
 	asyncJobTimeOut       time.Duration
+	httpClient            *http.Client
+	maxCallbackRetries    int
+	callbackTimeout       time.Duration
+	statsFactory          stats.Stats
+	pendingTypes          map[string]struct{}
 }
 
 type WhJobsMetaData struct {
@@ -44,6 +56,13 @@ type WhJobsMetaData struct {
 	TaskRunID string `json:"task_run_id"`
 	JobType   string `json:"jobtype"`
 	StartTime string `json:"start_time"`
+	// CallbackURL if set, is notified once the job reaches a terminal state
+	CallbackURL string `json:"callback_url,omitempty"`
+	// AttemptStartTime is populated every time the job starts executing
+	AttemptStartTime string `json:"attempt_start_time,omitempty"`
+	// EndTime and DurationMs are populated once the job reaches a terminal state, DurationMs being measured since AttemptStartTime
+	EndTime    string `json:"end_time,omitempty"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
 }
 
 // AsyncJobPayload For creating job payload to wh_async_jobs table
@@ -55,6 +74,16 @@ type AsyncJobPayload struct {
 	AsyncJobType  string          `json:"async_job_type"`
 	WorkspaceID   string          `json:"workspace_id"`
 	MetaData      json.RawMessage `json:"metadata"`
+	// MaxAttempts is the effective maximum number of attempts for the job, resolved when the job is picked up
+	MaxAttempts int `json:"max_attempts,omitempty"`
+}
+
+// AsyncJobTypeDeleteByJobRunID deletes the records loaded by a source job run
+const AsyncJobTypeDeleteByJobRunID = "deletebyjobrunid"
+
+// supportedAsyncJobTypes are the async job types which can be run by the slaves
+var supportedAsyncJobTypes = []string{
+	AsyncJobTypeDeleteByJobRunID,
 }
 
 const (
@@ -83,7 +112,8 @@ type WhAsyncJobRunner interface {
 }
 
 type AsyncJobStatus struct {
-	Id     string
-	Status string
-	Error  error
+	Id          string
+	Status      string
+	Error       error
+	MaxAttempts int
 }
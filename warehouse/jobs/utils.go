@@ -8,9 +8,10 @@ func convertToPayloadStatusStructWithSingleStatus(payloads []AsyncJobPayload, st
 	asyncJobStatusMap := make(map[string]AsyncJobStatus)
 	for _, payload := range payloads {
 		asyncJobStatusMap[payload.Id] = AsyncJobStatus{
-			Id:     payload.Id,
-			Status: status,
-			Error:  err,
+			Id:          payload.Id,
+			Status:      status,
+			Error:       err,
+			MaxAttempts: payload.MaxAttempts,
 		}
 	}
 	return asyncJobStatusMap
@@ -33,8 +34,9 @@ func getAsyncStatusMapFromAsyncPayloads(payloads []AsyncJobPayload) map[string]A
 	asyncJobStatusMap := make(map[string]AsyncJobStatus)
 	for _, payload := range payloads {
 		asyncJobStatusMap[payload.Id] = AsyncJobStatus{
-			Id:     payload.Id,
-			Status: WhJobFailed,
+			Id:          payload.Id,
+			Status:      WhJobFailed,
+			MaxAttempts: payload.MaxAttempts,
 		}
 	}
 	return asyncJobStatusMap
@@ -0,0 +1,223 @@
+package router
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/rudderlabs/rudder-server/utils/timeutil"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// EligibilityReason is the reason for which an upload can, or can't, be started now for a warehouse
+type EligibilityReason string
+
+// Reasons for which an upload can be started, the same as the trigger reasons of the created uploads
+const (
+	EligibilityReasonStartUploadAlways    EligibilityReason = TriggerReasonStartUploadAlways
+	EligibilityReasonManual               EligibilityReason = TriggerReasonManual
+	EligibilityReasonSyncFrequencyIgnored EligibilityReason = TriggerReasonSyncFrequencyIgnored
+	EligibilityReasonUploadFrequency      EligibilityReason = TriggerReasonUploadFrequency
+	EligibilityReasonSyncFrequency        EligibilityReason = TriggerReasonSyncFrequency
+	EligibilityReasonScheduled            EligibilityReason = TriggerReasonScheduled
+)
+
+// Reasons for which an upload can't be started
+const (
+	EligibilityReasonSyncDisabled               EligibilityReason = "sync_disabled"                 // the syncs of the warehouse are disabled
+	EligibilityReasonMinUploadInterval          EligibilityReason = "min_upload_interval"           // the last upload was created less than the minimum upload interval ago
+	EligibilityReasonUploadFrequencyNotExceeded EligibilityReason = "upload_frequency_not_exceeded" // the last upload was created less than the upload frequency ago
+	EligibilityReasonSyncFrequencyNotExceeded   EligibilityReason = "sync_frequency_not_exceeded"   // the last upload was created less than the sync frequency ago, without a start time
+	EligibilityReasonExcludeWindow              EligibilityReason = "exclude_window"                // the current time exists in an exclude window
+	EligibilityReasonNotSyncDay                 EligibilityReason = "not_sync_day"                  // the current day isn't one of the sync days
+	EligibilityReasonBeforeScheduledTime        EligibilityReason = "before_scheduled_time"         // an upload was already created since the previous scheduled time
+)
+
+// Eligibility tells whether an upload can be started now for a warehouse, and why
+type Eligibility struct {
+	Eligible bool
+	Reason   EligibilityReason
+	// NextEligibleAt is the time from which the reason for which the upload can't be started no longer holds, if not eligible.
+	// Other reasons may still hold the upload back then, e.g. the end of an exclude window falling on a day which isn't a sync day.
+	NextEligibleAt time.Time
+
+	// err describes the reason for which the upload can't be started
+	err error
+}
+
+// Err returns an error describing the reason for which the upload can't be started, or nil if it is eligible
+func (e Eligibility) Err() error {
+	if e.Eligible {
+		return nil
+	}
+	return e.err
+}
+
+func eligible(reason EligibilityReason) Eligibility {
+	return Eligibility{Eligible: true, Reason: reason}
+}
+
+func notEligible(reason EligibilityReason, nextEligibleAt time.Time, err error) Eligibility {
+	return Eligibility{Reason: reason, NextEligibleAt: nextEligibleAt, err: err}
+}
+
+// Schedule is the sync schedule of a warehouse, which uploads are started according to
+type Schedule struct {
+	SyncFrequency string
+	SyncStartAt   string
+	// ExcludeWindows are the daily windows during which uploads are not started, as local times of the Location
+	ExcludeWindows []ExcludeWindow
+	// SyncDays are the days of the week on which uploads can be started in the Location, every day if empty
+	SyncDays []time.Weekday
+	// Location is the timezone of the exclude windows and the sync days, UTC if nil
+	Location *time.Location
+	// UploadFrequency is the interval between uploads if the schedule has no valid sync frequency
+	UploadFrequency time.Duration
+}
+
+// ExcludeWindow is a daily window during which uploads are not started, between its start and end times (e.g. 22:00 and 02:00)
+type ExcludeWindow struct {
+	StartTime string
+	EndTime   string
+}
+
+// ScheduleOf returns the schedule configured for the warehouse, without the upload frequency which isn't part of its configuration
+func ScheduleOf(warehouse model.Warehouse) Schedule {
+	days, loc := syncDaysOfWeek(warehouse)
+	return Schedule{
+		SyncFrequency: warehouseutils.GetConfigValue(warehouseutils.SyncFrequency, warehouse),
+		SyncStartAt:   warehouseutils.GetConfigValue(warehouseutils.SyncStartAt, warehouse),
+		ExcludeWindows: lo.Map(excludeWindows(warehouse.Destination.Config), func(w excludeWindow, _ int) ExcludeWindow {
+			return ExcludeWindow{StartTime: w.startTime, EndTime: w.endTime}
+		}),
+		SyncDays: lo.Keys(days),
+		Location: loc,
+	}
+}
+
+// scheduled returns true if uploads are started at scheduled times, rather than every upload frequency
+func (s Schedule) scheduled() bool {
+	_, err := parseSyncFrequency(s.SyncFrequency)
+	return err == nil && s.SyncStartAt != ""
+}
+
+// rolling returns the interval between uploads if they are started every sync frequency since the last upload,
+// i.e. the schedule has a valid sync frequency without any start time to anchor the scheduled times to
+func (s Schedule) rolling() (time.Duration, bool) {
+	freqInMin, err := parseSyncFrequency(s.SyncFrequency)
+	if err != nil || s.SyncStartAt != "" {
+		return 0, false
+	}
+	return time.Duration(freqInMin) * time.Minute, true
+}
+
+func (s Schedule) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// excluded returns the eligibility of an upload at now if it is held back by the exclude windows or the sync days
+func (s Schedule) excluded(now time.Time) (Eligibility, bool) {
+	loc := s.location()
+	windows := lo.Map(s.ExcludeWindows, func(w ExcludeWindow, _ int) excludeWindow {
+		return excludeWindow{startTime: w.StartTime, endTime: w.EndTime}
+	})
+	if checkCurrentTimeExistsInExcludeWindows(now.In(loc), windows) {
+		return notEligible(EligibilityReasonExcludeWindow, excludeWindowsEndAt(now.In(loc), windows),
+			fmt.Errorf("exclude window: current time exists in exclude window"),
+		), true
+	}
+
+	days := lo.SliceToMap(s.SyncDays, func(day time.Weekday) (time.Weekday, struct{}) {
+		return day, struct{}{}
+	})
+	if !isSyncDay(now, days, loc) {
+		return notEligible(EligibilityReasonNotSyncDay, nextSyncDayAt(now, days, loc),
+			fmt.Errorf("sync days of week: %s is not a sync day", now.In(loc).Weekday()),
+		), true
+	}
+	return Eligibility{}, false
+}
+
+// ScheduledEligibility returns whether an upload can be started at now according to the schedule, given the time the last upload was created at (zero if none).
+// It doesn't depend on any state, so that schedules can be previewed, e.g. whether a configuration syncs at a given time.
+// Manual triggers, forced uploads and paused syncs aren't part of the schedule and are left to the caller.
+func ScheduledEligibility(schedule Schedule, lastUploadCreatedAt, now time.Time) Eligibility {
+	if eligibility, excluded := schedule.excluded(now); excluded {
+		return eligibility
+	}
+
+	// a sync frequency without start time is a rolling interval since the last upload, whichever time it was created at
+	if interval, ok := schedule.rolling(); ok {
+		if lastUploadCreatedAt.IsZero() || now.Sub(lastUploadCreatedAt) >= interval {
+			return eligible(EligibilityReasonSyncFrequency)
+		}
+		return notEligible(EligibilityReasonSyncFrequencyNotExceeded, lastUploadCreatedAt.Add(interval),
+			fmt.Errorf("sync frequency: an upload was created less than %s ago", interval),
+		)
+	}
+
+	// invalid sync frequencies are treated as if there was no schedule, falling back to the upload frequency
+	if !schedule.scheduled() {
+		uploadFrequency := schedule.UploadFrequency
+		if lastUploadCreatedAt.IsZero() || now.Sub(lastUploadCreatedAt) > uploadFrequency {
+			return eligible(EligibilityReasonUploadFrequency)
+		}
+		return notEligible(EligibilityReasonUploadFrequencyNotExceeded, lastUploadCreatedAt.Add(uploadFrequency),
+			fmt.Errorf("upload frequency exceeded"),
+		)
+	}
+
+	// start upload only if no upload has started in current window
+	// e.g. with prev scheduled time 14:00 and current time 15:00, start only if prev upload hasn't started after 14:00
+	if lastUploadCreatedAt.Before(prevScheduledTime(schedule.SyncFrequency, schedule.SyncStartAt, now)) {
+		return eligible(EligibilityReasonScheduled)
+	}
+
+	var nextScheduledTime time.Time
+	if upcomingTimes := upcomingScheduledTimes(schedule.SyncFrequency, schedule.SyncStartAt, now, 1); len(upcomingTimes) > 0 {
+		nextScheduledTime = upcomingTimes[0]
+	}
+	return notEligible(EligibilityReasonBeforeScheduledTime, nextScheduledTime, fmt.Errorf("before scheduled time"))
+}
+
+// excludeWindowsEndAt returns the time at which the current time stops existing in the exclude windows, following the windows overlapping each other
+func excludeWindowsEndAt(currentTime time.Time, windows []excludeWindow) time.Time {
+	endAt := currentTime
+	for i := 0; i <= len(windows); i++ {
+		var inWindow bool
+		for _, w := range windows {
+			if !checkCurrentTimeExistsInExcludeWindow(endAt, w.startTime, w.endTime) {
+				continue
+			}
+			inWindow = true
+
+			windowEndAt := timeutil.StartOfDay(endAt).Add(time.Duration(timeutil.MinsOfDay(w.endTime)) * time.Minute)
+			if !windowEndAt.After(endAt) {
+				windowEndAt = windowEndAt.AddDate(0, 0, 1)
+			}
+			endAt = windowEndAt
+			break
+		}
+		if !inWindow {
+			break
+		}
+	}
+	return endAt
+}
+
+// nextSyncDayAt returns the start of the next sync day after the current time in the location
+func nextSyncDayAt(currentTime time.Time, days map[time.Weekday]struct{}, loc *time.Location) time.Time {
+	startOfDay := timeutil.StartOfDay(currentTime.In(loc))
+	for i := 1; i <= 7; i++ {
+		day := startOfDay.AddDate(0, 0, i)
+		if isSyncDay(day, days, loc) {
+			return day
+		}
+	}
+	return time.Time{}
+}
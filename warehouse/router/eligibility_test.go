@@ -0,0 +1,368 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	backendConfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+func TestRouter_UploadEligibility(t *testing.T) {
+	newRouter := func(now time.Time) *Router {
+		r := &Router{}
+		r.now = func() time.Time {
+			return now
+		}
+		r.triggerStore = &sync.Map{}
+		r.config.uploadFreqInS = misc.SingleValueLoader(int64(1800))
+		r.config.warehouseSyncFreqIgnore = misc.SingleValueLoader(false)
+		r.createJobMarkerMap = make(map[string]time.Time)
+		return r
+	}
+
+	t.Run("triggered", func(t *testing.T) {
+		w := model.Warehouse{Identifier: "test_identifier_eligibility_triggered"}
+
+		r := newRouter(time.Now())
+		r.triggerStore.Store(w.Identifier, struct{}{})
+
+		eligibility, err := r.UploadEligibility(context.Background(), w)
+		require.NoError(t, err)
+		require.True(t, eligibility.Eligible)
+		require.Equal(t, EligibilityReasonManual, eligibility.Reason)
+		require.True(t, eligibility.NextEligibleAt.IsZero())
+		require.NoError(t, eligibility.Err())
+	})
+
+	t.Run("sync disabled", func(t *testing.T) {
+		w := model.Warehouse{
+			Identifier: "test_identifier_eligibility_sync_disabled",
+			Destination: backendConfig.DestinationT{
+				Config: map[string]interface{}{
+					"disableSync": true,
+				},
+			},
+		}
+
+		r := newRouter(time.Now())
+		r.triggerStore.Store(w.Identifier, struct{}{})
+
+		eligibility, err := r.UploadEligibility(context.Background(), w)
+		require.NoError(t, err)
+		require.False(t, eligibility.Eligible)
+		require.Equal(t, EligibilityReasonSyncDisabled, eligibility.Reason)
+		require.True(t, eligibility.NextEligibleAt.IsZero())
+		require.EqualError(t, eligibility.Err(), "sync disabled: syncs are disabled for the warehouse")
+
+		canCreate, err := r.canCreateUpload(context.Background(), w)
+		require.EqualError(t, err, "sync disabled: syncs are disabled for the warehouse")
+		require.False(t, canCreate)
+	})
+
+	t.Run("min upload interval", func(t *testing.T) {
+		w := model.Warehouse{
+			Identifier: "test_identifier_eligibility_min_upload_interval",
+			Destination: backendConfig.DestinationT{
+				Config: map[string]interface{}{
+					"minUploadInterval": "1h",
+				},
+			},
+		}
+		now := time.Date(2009, time.November, 10, 5, 30, 0, 0, time.UTC)
+
+		r := newRouter(now)
+		r.triggerStore.Store(w.Identifier, struct{}{})
+		r.updateCreateJobMarker(w, now.Add(-10*time.Minute))
+
+		eligibility, err := r.UploadEligibility(context.Background(), w)
+		require.NoError(t, err)
+		require.False(t, eligibility.Eligible)
+		require.Equal(t, EligibilityReasonMinUploadInterval, eligibility.Reason)
+		require.Equal(t, now.Add(50*time.Minute), eligibility.NextEligibleAt)
+		require.EqualError(t, eligibility.Err(), "min upload interval: an upload was created less than 1h0m0s ago")
+
+		canCreate, err := r.canCreateUpload(context.Background(), w)
+		require.EqualError(t, err, "min upload interval: an upload was created less than 1h0m0s ago")
+		require.False(t, canCreate)
+
+		r.updateCreateJobMarker(w, now.Add(-2*time.Hour))
+
+		eligibility, err = r.UploadEligibility(context.Background(), w)
+		require.NoError(t, err)
+		require.True(t, eligibility.Eligible)
+		require.Equal(t, EligibilityReasonManual, eligibility.Reason)
+	})
+
+	t.Run("minUploadInterval", func(t *testing.T) {
+		testCases := []struct {
+			interval         interface{}
+			expectedInterval time.Duration
+		}{
+			{interval: nil, expectedInterval: 0},
+			{interval: "30", expectedInterval: 30 * time.Minute},
+			{interval: " 90s ", expectedInterval: 90 * time.Second},
+			{interval: "2h", expectedInterval: 2 * time.Hour},
+			{interval: "-1h", expectedInterval: 0},
+			{interval: "-30", expectedInterval: 0},
+			{interval: "hourly", expectedInterval: 0},
+		}
+
+		for _, tc := range testCases {
+			t.Run(fmt.Sprint(tc.interval), func(t *testing.T) {
+				w := model.Warehouse{
+					Destination: backendConfig.DestinationT{
+						Config: map[string]interface{}{
+							"minUploadInterval": tc.interval,
+						},
+					},
+				}
+				require.Equal(t, tc.expectedInterval, minUploadInterval(w))
+			})
+		}
+	})
+
+	t.Run("upload frequency", func(t *testing.T) {
+		w := model.Warehouse{Identifier: "test_identifier_eligibility_upload_frequency"}
+		now := time.Date(2009, time.November, 10, 5, 30, 0, 0, time.UTC)
+
+		r := newRouter(now)
+		r.updateCreateJobMarker(w, now.Add(-10*time.Minute))
+
+		eligibility, err := r.UploadEligibility(context.Background(), w)
+		require.NoError(t, err)
+		require.False(t, eligibility.Eligible)
+		require.Equal(t, EligibilityReasonUploadFrequencyNotExceeded, eligibility.Reason)
+		require.Equal(t, now.Add(20*time.Minute), eligibility.NextEligibleAt)
+		require.EqualError(t, eligibility.Err(), "upload frequency exceeded")
+
+		r.updateCreateJobMarker(w, now.Add(-time.Hour))
+
+		eligibility, err = r.UploadEligibility(context.Background(), w)
+		require.NoError(t, err)
+		require.True(t, eligibility.Eligible)
+		require.Equal(t, EligibilityReasonUploadFrequency, eligibility.Reason)
+	})
+
+	t.Run("exclude window", func(t *testing.T) {
+		w := model.Warehouse{
+			Identifier: "test_identifier_eligibility_exclude_window",
+			Destination: backendConfig.DestinationT{
+				Config: map[string]interface{}{
+					"excludeWindow": []interface{}{
+						map[string]interface{}{
+							"excludeWindowStartTime": "22:00",
+							"excludeWindowEndTime":   "02:00",
+						},
+						map[string]interface{}{
+							"excludeWindowStartTime": "01:30",
+							"excludeWindowEndTime":   "03:00",
+						},
+					},
+					"syncTimezone": "America/New_York",
+				},
+			},
+		}
+		ny, err := time.LoadLocation("America/New_York")
+		require.NoError(t, err)
+
+		// 23:00 in New York, the overlapping windows end at 03:00 the next day
+		r := newRouter(time.Date(2009, time.November, 10, 4, 0, 0, 0, time.UTC))
+
+		eligibility, err := r.UploadEligibility(context.Background(), w)
+		require.NoError(t, err)
+		require.False(t, eligibility.Eligible)
+		require.Equal(t, EligibilityReasonExcludeWindow, eligibility.Reason)
+		require.True(t, time.Date(2009, time.November, 10, 3, 0, 0, 0, ny).Equal(eligibility.NextEligibleAt), eligibility.NextEligibleAt)
+		require.EqualError(t, eligibility.Err(), "exclude window: current time exists in exclude window")
+	})
+
+	t.Run("not a sync day", func(t *testing.T) {
+		w := model.Warehouse{
+			Identifier: "test_identifier_eligibility_not_a_sync_day",
+			Destination: backendConfig.DestinationT{
+				Config: map[string]interface{}{
+					"syncDaysOfWeek": "mon,tue,wed,thu,fri",
+				},
+			},
+		}
+
+		// Saturday
+		r := newRouter(time.Date(2009, time.November, 14, 5, 30, 0, 0, time.UTC))
+
+		eligibility, err := r.UploadEligibility(context.Background(), w)
+		require.NoError(t, err)
+		require.False(t, eligibility.Eligible)
+		require.Equal(t, EligibilityReasonNotSyncDay, eligibility.Reason)
+		require.Equal(t, time.Date(2009, time.November, 16, 0, 0, 0, 0, time.UTC), eligibility.NextEligibleAt)
+		require.EqualError(t, eligibility.Err(), "sync days of week: Saturday is not a sync day")
+	})
+}
+
+func TestScheduledEligibility(t *testing.T) {
+	// Tuesday
+	now := time.Date(2009, time.November, 10, 5, 30, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name                   string
+		schedule               Schedule
+		lastUploadCreatedAt    time.Time
+		expectedEligible       bool
+		expectedReason         EligibilityReason
+		expectedNextEligibleAt time.Time
+	}{
+		{
+			name:             "scheduled",
+			schedule:         Schedule{SyncFrequency: "60", SyncStartAt: "00:00"},
+			expectedEligible: true,
+			expectedReason:   EligibilityReasonScheduled,
+		},
+		{
+			name:                "scheduled with upload created before the previous scheduled time",
+			schedule:            Schedule{SyncFrequency: "60", SyncStartAt: "00:00"},
+			lastUploadCreatedAt: now.Add(-time.Hour),
+			expectedEligible:    true,
+			expectedReason:      EligibilityReasonScheduled,
+		},
+		{
+			name:                   "before scheduled time",
+			schedule:               Schedule{SyncFrequency: "60", SyncStartAt: "00:00"},
+			lastUploadCreatedAt:    now.Add(-10 * time.Minute),
+			expectedReason:         EligibilityReasonBeforeScheduledTime,
+			expectedNextEligibleAt: time.Date(2009, time.November, 10, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "exclude window",
+			schedule: Schedule{
+				SyncFrequency:  "60",
+				SyncStartAt:    "00:00",
+				ExcludeWindows: []ExcludeWindow{{StartTime: "05:00", EndTime: "06:00"}},
+			},
+			expectedReason:         EligibilityReasonExcludeWindow,
+			expectedNextEligibleAt: time.Date(2009, time.November, 10, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "not a sync day",
+			schedule: Schedule{
+				SyncFrequency: "60",
+				SyncStartAt:   "00:00",
+				SyncDays:      []time.Weekday{time.Monday},
+			},
+			expectedReason:         EligibilityReasonNotSyncDay,
+			expectedNextEligibleAt: time.Date(2009, time.November, 16, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sync day in the location",
+			schedule: Schedule{
+				SyncFrequency: "60",
+				SyncStartAt:   "00:00",
+				SyncDays:      []time.Weekday{time.Monday},
+				Location:      time.FixedZone("UTC-8", -8*60*60),
+			},
+			expectedEligible: true,
+			expectedReason:   EligibilityReasonScheduled,
+		},
+		{
+			name:                "upload frequency exceeded",
+			schedule:            Schedule{UploadFrequency: 30 * time.Minute},
+			lastUploadCreatedAt: now.Add(-time.Hour),
+			expectedEligible:    true,
+			expectedReason:      EligibilityReasonUploadFrequency,
+		},
+		{
+			name:                   "upload frequency not exceeded",
+			schedule:               Schedule{UploadFrequency: 30 * time.Minute},
+			lastUploadCreatedAt:    now.Add(-10 * time.Minute),
+			expectedReason:         EligibilityReasonUploadFrequencyNotExceeded,
+			expectedNextEligibleAt: now.Add(20 * time.Minute),
+		},
+		{
+			name:                   "sync frequency without start time",
+			schedule:               Schedule{SyncFrequency: "120", UploadFrequency: 30 * time.Minute},
+			lastUploadCreatedAt:    now.Add(-time.Hour),
+			expectedReason:         EligibilityReasonSyncFrequencyNotExceeded,
+			expectedNextEligibleAt: now.Add(time.Hour),
+		},
+		{
+			name:                "sync frequency without start time exceeded",
+			schedule:            Schedule{SyncFrequency: "2h", UploadFrequency: 30 * time.Minute},
+			lastUploadCreatedAt: now.Add(-2 * time.Hour),
+			expectedEligible:    true,
+			expectedReason:      EligibilityReasonSyncFrequency,
+		},
+		{
+			name:             "first upload with sync frequency without start time",
+			schedule:         Schedule{SyncFrequency: "120"},
+			expectedEligible: true,
+			expectedReason:   EligibilityReasonSyncFrequency,
+		},
+		{
+			name: "sync frequency without start time in exclude window",
+			schedule: Schedule{
+				SyncFrequency:  "120",
+				ExcludeWindows: []ExcludeWindow{{StartTime: "05:00", EndTime: "06:00"}},
+			},
+			lastUploadCreatedAt:    now.Add(-3 * time.Hour),
+			expectedReason:         EligibilityReasonExcludeWindow,
+			expectedNextEligibleAt: time.Date(2009, time.November, 10, 6, 0, 0, 0, time.UTC),
+		},
+		{
+			name:                   "invalid sync frequency without start time",
+			schedule:               Schedule{SyncFrequency: "daily", UploadFrequency: 30 * time.Minute},
+			lastUploadCreatedAt:    now.Add(-10 * time.Minute),
+			expectedReason:         EligibilityReasonUploadFrequencyNotExceeded,
+			expectedNextEligibleAt: now.Add(20 * time.Minute),
+		},
+		{
+			name:             "first upload without schedule",
+			schedule:         Schedule{UploadFrequency: 30 * time.Minute},
+			expectedEligible: true,
+			expectedReason:   EligibilityReasonUploadFrequency,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			eligibility := ScheduledEligibility(tc.schedule, tc.lastUploadCreatedAt, now)
+			require.Equal(t, tc.expectedEligible, eligibility.Eligible)
+			require.Equal(t, tc.expectedReason, eligibility.Reason)
+			require.True(t, tc.expectedNextEligibleAt.Equal(eligibility.NextEligibleAt), eligibility.NextEligibleAt)
+			if tc.expectedEligible {
+				require.NoError(t, eligibility.Err())
+			} else {
+				require.Error(t, eligibility.Err())
+			}
+		})
+	}
+}
+
+func TestScheduleOf(t *testing.T) {
+	w := model.Warehouse{
+		Destination: backendConfig.DestinationT{
+			Config: map[string]interface{}{
+				"syncFrequency": "30",
+				"syncStartAt":   "10:00",
+				"excludeWindow": map[string]interface{}{
+					"excludeWindowStartTime": "02:00",
+					"excludeWindowEndTime":   "03:00",
+				},
+				"syncDaysOfWeek": "mon",
+				"syncTimezone":   "America/New_York",
+			},
+		},
+	}
+
+	schedule := ScheduleOf(w)
+	require.Equal(t, "30", schedule.SyncFrequency)
+	require.Equal(t, "10:00", schedule.SyncStartAt)
+	require.Equal(t, []ExcludeWindow{{StartTime: "02:00", EndTime: "03:00"}}, schedule.ExcludeWindows)
+	require.Equal(t, []time.Weekday{time.Monday}, schedule.SyncDays)
+	require.Equal(t, "America/New_York", schedule.Location.String())
+	require.Zero(t, schedule.UploadFrequency)
+}
@@ -0,0 +1,117 @@
+package router
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+// UploadEventType is the type of upload lifecycle event
+type UploadEventType string
+
+const (
+	UploadEventSkipped   UploadEventType = "skipped"   // no uploads were created for the warehouse, see Reason
+	UploadEventCreated   UploadEventType = "created"   // uploads were created for the pending staging files of the warehouse, see TriggerReason
+	UploadEventStarted   UploadEventType = "started"   // an upload started running
+	UploadEventSucceeded UploadEventType = "succeeded" // an upload run succeeded
+	UploadEventFailed    UploadEventType = "failed"    // an upload run failed, see Reason
+)
+
+// Reasons for which uploads are created for a warehouse
+const (
+	TriggerReasonStartUploadAlways    = "start_upload_always"    // forced from rudder-cli
+	TriggerReasonManual               = "triggered"              // triggered manually
+	TriggerReasonSyncFrequencyIgnored = "sync_frequency_ignored" // upload frequency exceeded, while the sync frequency is ignored
+	TriggerReasonUploadFrequency      = "upload_frequency"       // upload frequency exceeded, without a configured schedule
+	TriggerReasonSyncFrequency        = "sync_frequency"         // sync frequency exceeded since the last upload, without a configured start time
+	TriggerReasonScheduled            = "scheduled"              // scheduled time reached
+)
+
+// UploadEvent is a lifecycle event of the uploads of a warehouse
+type UploadEvent struct {
+	Type                UploadEventType
+	WarehouseIdentifier string
+	WorkspaceID         string
+	SourceID            string
+	DestinationID       string
+	DestinationType     string
+
+	UploadID      int64         // set for started, succeeded and failed events
+	TriggerReason string        // set for created events
+	Reason        string        // set for skipped and failed events
+	Duration      time.Duration // time taken for creating the uploads, or for running the upload for succeeded and failed events
+}
+
+// UploadObserver receives the upload lifecycle events of the router, e.g. for forwarding them to monitoring.
+// It is called synchronously from the router goroutines, so it needs to be thread-safe and shouldn't block.
+type UploadObserver interface {
+	OnUploadEvent(event UploadEvent)
+}
+
+type Opt func(r *Router)
+
+// WithUploadObserver registers an observer for the upload lifecycle events
+func WithUploadObserver(observer UploadObserver) Opt {
+	return func(r *Router) {
+		r.uploadObserver = observer
+	}
+}
+
+// notifyUploadEvent sends the event for the warehouse to the upload observer, if any
+func (r *Router) notifyUploadEvent(warehouse model.Warehouse, event UploadEvent) {
+	if r.uploadObserver == nil {
+		return
+	}
+
+	event.WarehouseIdentifier = warehouse.Identifier
+	event.WorkspaceID = warehouse.WorkspaceID
+	event.SourceID = warehouse.Source.ID
+	event.DestinationID = warehouse.Destination.ID
+	event.DestinationType = warehouse.Destination.DestinationDefinition.Name
+	r.uploadObserver.OnUploadEvent(event)
+}
+
+// uploadSkipReasons keeps the last skip reason of every warehouse
+type uploadSkipReasons struct {
+	mu      sync.Mutex
+	reasons map[string]string // warehouse identifier -> skip reason
+}
+
+// swap sets the skip reason of the warehouse, returning whether it changed. An empty reason clears it.
+func (s *uploadSkipReasons) swap(identifier, reason string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.reasons == nil {
+		s.reasons = make(map[string]string)
+	}
+	changed := s.reasons[identifier] != reason
+	if reason == "" {
+		delete(s.reasons, identifier)
+	} else {
+		s.reasons[identifier] = reason
+	}
+	return changed
+}
+
+// notifyUploadSkipped sends a skipped event for the warehouse only when its skip reason changes,
+// so that the observer isn't notified on every iteration of the main loop for the same reason
+func (r *Router) notifyUploadSkipped(warehouse model.Warehouse, reason string) {
+	if r.uploadObserver == nil {
+		return
+	}
+	if !r.uploadSkipReasons.swap(warehouse.Identifier, reason) {
+		return
+	}
+	r.notifyUploadEvent(warehouse, UploadEvent{Type: UploadEventSkipped, Reason: reason})
+}
+
+// notifyUploadsCreated sends a created event for the warehouse, clearing its skip reason, so that the next skip is sent again
+func (r *Router) notifyUploadsCreated(warehouse model.Warehouse, triggerReason string, duration time.Duration) {
+	if r.uploadObserver == nil {
+		return
+	}
+	r.uploadSkipReasons.swap(warehouse.Identifier, "")
+	r.notifyUploadEvent(warehouse, UploadEvent{Type: UploadEventCreated, TriggerReason: triggerReason, Duration: duration})
+}
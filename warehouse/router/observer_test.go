@@ -0,0 +1,173 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/utils/misc"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+)
+
+type recordingObserver struct {
+	events []UploadEvent
+}
+
+func (o *recordingObserver) OnUploadEvent(event UploadEvent) {
+	o.events = append(o.events, event)
+}
+
+func TestRouter_UploadObserver(t *testing.T) {
+	warehouse := model.Warehouse{
+		Identifier:  "test_identifier",
+		WorkspaceID: "workspace_id",
+		Source:      backendconfig.SourceT{ID: "source_id"},
+		Destination: backendconfig.DestinationT{
+			ID: "destination_id",
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: "POSTGRES",
+			},
+		},
+	}
+
+	t.Run("no observer", func(t *testing.T) {
+		r := Router{}
+		r.notifyUploadEvent(warehouse, UploadEvent{Type: UploadEventStarted, UploadID: 1})
+	})
+
+	t.Run("observer", func(t *testing.T) {
+		observer := &recordingObserver{}
+
+		r := Router{}
+		WithUploadObserver(observer)(&r)
+		r.notifyUploadEvent(warehouse, UploadEvent{Type: UploadEventFailed, UploadID: 1, Reason: "some error", Duration: time.Minute})
+
+		require.Equal(t, []UploadEvent{{
+			Type:                UploadEventFailed,
+			WarehouseIdentifier: "test_identifier",
+			WorkspaceID:         "workspace_id",
+			SourceID:            "source_id",
+			DestinationID:       "destination_id",
+			DestinationType:     "POSTGRES",
+			UploadID:            1,
+			Reason:              "some error",
+			Duration:            time.Minute,
+		}}, observer.events)
+	})
+
+	t.Run("skipped only when the reason changes", func(t *testing.T) {
+		observer := &recordingObserver{}
+
+		r := Router{}
+		WithUploadObserver(observer)(&r)
+
+		other := warehouse
+		other.Identifier = "other_identifier"
+
+		r.notifyUploadSkipped(warehouse, "no pending staging files")
+		r.notifyUploadSkipped(warehouse, "no pending staging files")
+		r.notifyUploadSkipped(other, "no pending staging files")
+		r.notifyUploadSkipped(warehouse, "sync disabled")
+		r.notifyUploadSkipped(warehouse, "sync disabled")
+		r.notifyUploadsCreated(warehouse, TriggerReasonManual, time.Second)
+		r.notifyUploadSkipped(warehouse, "sync disabled")
+
+		type summary struct {
+			Type       UploadEventType
+			Identifier string
+			Reason     string
+		}
+		summaries := make([]summary, 0, len(observer.events))
+		for _, event := range observer.events {
+			reason := event.Reason
+			if event.Type == UploadEventCreated {
+				reason = event.TriggerReason
+			}
+			summaries = append(summaries, summary{Type: event.Type, Identifier: event.WarehouseIdentifier, Reason: reason})
+		}
+		require.Equal(t, []summary{
+			{Type: UploadEventSkipped, Identifier: "test_identifier", Reason: "no pending staging files"},
+			{Type: UploadEventSkipped, Identifier: "other_identifier", Reason: "no pending staging files"},
+			{Type: UploadEventSkipped, Identifier: "test_identifier", Reason: "sync disabled"},
+			{Type: UploadEventCreated, Identifier: "test_identifier", Reason: TriggerReasonManual},
+			{Type: UploadEventSkipped, Identifier: "test_identifier", Reason: "sync disabled"},
+		}, summaries)
+	})
+
+	t.Run("trigger reasons", func(t *testing.T) {
+		t.Run("triggered", func(t *testing.T) {
+			r := Router{}
+			r.triggerStore = &sync.Map{}
+			r.triggerStore.Store(warehouse.Identifier, struct{}{})
+
+			reason, err := r.uploadTriggerReason(context.Background(), warehouse)
+			require.NoError(t, err)
+			require.Equal(t, TriggerReasonManual, reason)
+		})
+
+		t.Run("start upload always for the warehouse", func(t *testing.T) {
+			StartUploadAlwaysWarehouses.Add(warehouse.Identifier)
+			defer StartUploadAlwaysWarehouses.Remove(warehouse.Identifier)
+
+			r := Router{}
+			r.triggerStore = &sync.Map{}
+
+			reason, err := r.uploadTriggerReason(context.Background(), warehouse)
+			require.NoError(t, err)
+			require.Equal(t, TriggerReasonStartUploadAlways, reason)
+
+			other := warehouse
+			other.Identifier = "other_identifier"
+			r.now = time.Now
+			r.config.uploadFreqInS = misc.SingleValueLoader(int64(1800))
+			r.config.warehouseSyncFreqIgnore = misc.SingleValueLoader(false)
+
+			reason, err = r.uploadTriggerReason(context.Background(), other)
+			require.NoError(t, err)
+			require.Equal(t, TriggerReasonUploadFrequency, reason)
+		})
+
+		t.Run("sync frequency ignored", func(t *testing.T) {
+			r := Router{}
+			r.config.uploadFreqInS = misc.SingleValueLoader(int64(1800))
+			r.config.warehouseSyncFreqIgnore = misc.SingleValueLoader(true)
+			r.triggerStore = &sync.Map{}
+
+			reason, err := r.uploadTriggerReason(context.Background(), warehouse)
+			require.NoError(t, err)
+			require.Equal(t, TriggerReasonSyncFrequencyIgnored, reason)
+		})
+
+		t.Run("upload frequency", func(t *testing.T) {
+			r := Router{}
+			r.now = time.Now
+			r.config.uploadFreqInS = misc.SingleValueLoader(int64(1800))
+			r.config.warehouseSyncFreqIgnore = misc.SingleValueLoader(false)
+			r.triggerStore = &sync.Map{}
+
+			reason, err := r.uploadTriggerReason(context.Background(), warehouse)
+			require.NoError(t, err)
+			require.Equal(t, TriggerReasonUploadFrequency, reason)
+		})
+
+		t.Run("skipped", func(t *testing.T) {
+			now := time.Now()
+
+			r := Router{}
+			r.now = func() time.Time { return now }
+			r.config.uploadFreqInS = misc.SingleValueLoader(int64(1800))
+			r.config.warehouseSyncFreqIgnore = misc.SingleValueLoader(true)
+			r.createJobMarkerMap = make(map[string]time.Time)
+			r.triggerStore = &sync.Map{}
+			r.updateCreateJobMarker(warehouse, now)
+
+			reason, err := r.uploadTriggerReason(context.Background(), warehouse)
+			require.Error(t, err)
+			require.Empty(t, reason)
+		})
+	})
+}
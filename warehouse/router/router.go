@@ -5,7 +5,6 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
-	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -40,6 +39,7 @@ import (
 	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
 	"github.com/rudderlabs/rudder-server/warehouse/internal/repo"
 	"github.com/rudderlabs/rudder-server/warehouse/internal/service"
+	"github.com/rudderlabs/rudder-server/warehouse/logfield"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
 	"github.com/rudderlabs/rudder-server/warehouse/validations"
 )
@@ -79,6 +79,10 @@ type Router struct {
 	inProgressMap     map[workerIdentifierMapKey][]jobID
 	inProgressMapLock sync.RWMutex
 
+	// excludeWindowsConflicts keeps the warehouses whose scheduled times all exist in the exclude windows, keyed by source and destination ID.
+	// It is only used by the backend config subscriber.
+	excludeWindowsConflicts map[string]bool
+
 	activeWorkerCount atomic.Int32
 	now               func() time.Time
 	nowSQL            string
@@ -88,10 +92,12 @@ type Router struct {
 	backgroundGroup errgroup.Group
 	backgroundWait  func() error
 
-	tenantManager    *multitenant.Manager
-	bcManager        *bcm.BackendConfigManager
-	uploadJobFactory UploadJobFactory
-	notifier         *notifier.Notifier
+	tenantManager     *multitenant.Manager
+	bcManager         *bcm.BackendConfigManager
+	uploadJobFactory  UploadJobFactory
+	notifier          *notifier.Notifier
+	uploadObserver    UploadObserver
+	uploadSkipReasons uploadSkipReasons
 
 	config struct {
 		maxConcurrentUploadJobs           int
@@ -134,6 +140,7 @@ func New(
 	bcManager *bcm.BackendConfigManager,
 	encodingFactory *encoding.Factory,
 	triggerStore *sync.Map,
+	opts ...Opt,
 ) (*Router, error) {
 	r := &Router{}
 
@@ -198,6 +205,10 @@ func New(
 	r.config.enableJitterForSyncs = r.conf.GetReloadableBoolVar(false, "Warehouse.enableJitterForSyncs")
 	r.config.warehouseSyncFreqIgnore = r.conf.GetReloadableBoolVar(false, "Warehouse.warehouseSyncFreqIgnore")
 
+	for _, opt := range opts {
+		opt(r)
+	}
+
 	r.stats.processingPendingJobsStat = r.statsFactory.NewTaggedStat("wh_processing_pending_jobs", stats.GaugeType, stats.Tags{
 		"destType": r.destType,
 	})
@@ -239,6 +250,9 @@ func New(
 	g.Go(misc.WithBugsnagForWarehouse(func() error {
 		return r.CronTracker(gCtx)
 	}))
+	g.Go(misc.WithBugsnagForWarehouse(func() error {
+		return r.CronStagingTablesJanitor(gCtx)
+	}))
 
 	return r, nil
 }
@@ -253,6 +267,8 @@ func (r *Router) backendConfigSubscriber(ctx context.Context) {
 		})
 
 		for _, warehouse := range warehouses {
+			r.checkExcludeWindowsConflict(warehouse)
+
 			if warehouseutils.IDResolutionEnabled() && slices.Contains(warehouseutils.IdentityEnabledWarehouses, r.destType) {
 				r.setupIdentityTables(ctx, warehouse)
 				if r.config.shouldPopulateHistoricIdentities && warehouse.Destination.Enabled {
@@ -292,6 +308,46 @@ func (r *Router) backendConfigSubscriber(ctx context.Context) {
 	}
 }
 
+// checkExcludeWindowsConflict warns if all the scheduled times of the warehouse exist in its exclude windows.
+// It only logs when the conflict appears or gets resolved, not on every config update.
+func (r *Router) checkExcludeWindowsConflict(warehouse model.Warehouse) {
+	syncFrequency := warehouseutils.GetConfigValue(warehouseutils.SyncFrequency, warehouse)
+	syncStartAt := warehouseutils.GetConfigValue(warehouseutils.SyncStartAt, warehouse)
+	windows := excludeWindows(warehouse.Destination.Config)
+
+	conflict := scheduledTimesExcluded(syncFrequency, syncStartAt, windows, syncTimezone(warehouse))
+
+	if r.excludeWindowsConflicts == nil {
+		r.excludeWindowsConflicts = make(map[string]bool)
+	}
+	key := warehouse.Source.ID + "_" + warehouse.Destination.ID
+	if r.excludeWindowsConflicts[key] == conflict {
+		return
+	}
+	if conflict {
+		r.excludeWindowsConflicts[key] = true
+	} else {
+		delete(r.excludeWindowsConflicts, key)
+	}
+
+	logFields := []any{
+		logfield.SourceID, warehouse.Source.ID,
+		logfield.DestinationID, warehouse.Destination.ID,
+		logfield.DestinationType, warehouse.Destination.DestinationDefinition.Name,
+		logfield.WorkspaceID, warehouse.WorkspaceID,
+		"syncFrequency", syncFrequency,
+		"syncStartAt", syncStartAt,
+		"excludeWindows", lo.Map(windows, func(w excludeWindow, _ int) string {
+			return w.startTime + "-" + w.endTime
+		}),
+	}
+	if !conflict {
+		r.logger.Infow("scheduled times no longer all exist in the exclude window", logFields...)
+		return
+	}
+	r.logger.Warnw("all scheduled times exist in the exclude window, uploads only start after the exclude window ends", logFields...)
+}
+
 // workerIdentifier get name of the worker (`destID_namespace`) to be stored in map wh.workerChannelMap
 func (r *Router) workerIdentifier(warehouse model.Warehouse) (identifier string) {
 	if r.config.allowMultipleSourcesForJobsPickup {
@@ -308,9 +364,15 @@ func (r *Router) initWorker() chan *UploadJob {
 			for uploadJob := range workerChan {
 				r.incrementActiveWorkers()
 
+				r.notifyUploadEvent(uploadJob.warehouse, UploadEvent{Type: UploadEventStarted, UploadID: uploadJob.upload.ID})
+				start := r.now()
+
 				err := uploadJob.run()
 				if err != nil {
 					r.logger.Errorf("[WH] Failed in handle Upload jobs for worker: %+w", err)
+					r.notifyUploadEvent(uploadJob.warehouse, UploadEvent{Type: UploadEventFailed, UploadID: uploadJob.upload.ID, Reason: err.Error(), Duration: r.now().Sub(start)})
+				} else {
+					r.notifyUploadEvent(uploadJob.warehouse, UploadEvent{Type: UploadEventSucceeded, UploadID: uploadJob.upload.ID, Duration: r.now().Sub(start)})
 				}
 
 				r.removeDestInProgress(uploadJob.warehouse, uploadJob.upload.ID)
@@ -582,7 +644,10 @@ func (r *Router) mainLoop(ctx context.Context) {
 }
 
 func (r *Router) createJobs(ctx context.Context, warehouse model.Warehouse) (err error) {
-	if ok, err := r.canCreateUpload(ctx, warehouse); !ok {
+	triggerReason, err := r.uploadTriggerReason(ctx, warehouse)
+	if err != nil {
+		r.notifyUploadSkipped(warehouse, err.Error())
+
 		r.statsFactory.NewTaggedStat("wh_scheduler.upload_sync_skipped", stats.CountType, stats.Tags{
 			"workspaceId":   warehouse.WorkspaceID,
 			"destinationID": warehouse.Destination.ID,
@@ -615,6 +680,7 @@ func (r *Router) createJobs(ctx context.Context, warehouse model.Warehouse) (err
 
 	if len(stagingFilesList) == 0 {
 		r.logger.Debugf("[WH]: Found no pending staging files for %s", warehouse.Identifier)
+		r.notifyUploadSkipped(warehouse, "no pending staging files")
 		return nil
 	}
 
@@ -625,6 +691,7 @@ func (r *Router) createJobs(ctx context.Context, warehouse model.Warehouse) (err
 	})
 	defer uploadJobCreationStat.RecordDuration()()
 
+	uploadJobCreationStart := r.now()
 	uploadStartAfter := r.uploadStartAfterTime()
 	err = r.createUploadJobsFromStagingFiles(ctx, warehouse, stagingFilesList, priority, uploadStartAfter)
 	if err != nil {
@@ -632,6 +699,7 @@ func (r *Router) createJobs(ctx context.Context, warehouse model.Warehouse) (err
 	}
 
 	r.updateCreateJobMarker(warehouse, uploadStartAfter)
+	r.notifyUploadsCreated(warehouse, triggerReason, r.now().Sub(uploadJobCreationStart))
 
 	return nil
 }
@@ -729,12 +797,24 @@ func (r *Router) uploadFrequencyExceeded(warehouse model.Warehouse, syncFrequenc
 	return r.now().Sub(lastCreatedAt) > time.Duration(freqInS)*time.Second
 }
 
+// uploadFrequencyExceededAt returns the time at which the upload frequency is exceeded since the last upload created for the warehouse
+func (r *Router) uploadFrequencyExceededAt(warehouse model.Warehouse, syncFrequency string) time.Time {
+	r.createJobMarkerMapLock.RLock()
+	lastCreatedAt, ok := r.createJobMarkerMap[warehouse.Identifier]
+	r.createJobMarkerMapLock.RUnlock()
+
+	if !ok {
+		return r.now()
+	}
+	return lastCreatedAt.Add(time.Duration(r.uploadFreqInS(syncFrequency)) * time.Second)
+}
+
 func (r *Router) uploadFreqInS(syncFrequency string) int64 {
-	freqInMin, err := strconv.ParseInt(syncFrequency, 10, 64)
+	freqInMin, err := parseSyncFrequency(syncFrequency)
 	if err != nil {
 		return r.config.uploadFreqInS.Load()
 	}
-	return freqInMin * 60
+	return int64(freqInMin) * 60
 }
 
 func (r *Router) updateCreateJobMarker(warehouse model.Warehouse, lastProcessedTime time.Time) {
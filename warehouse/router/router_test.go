@@ -27,6 +27,7 @@ import (
 	"github.com/rudderlabs/rudder-go-kit/stats/memstats"
 	"github.com/rudderlabs/rudder-server/enterprise/reporting"
 	mocksBackendConfig "github.com/rudderlabs/rudder-server/mocks/backend-config"
+	mock_logger "github.com/rudderlabs/rudder-server/mocks/utils/logger"
 	"github.com/rudderlabs/rudder-server/services/controlplane"
 	"github.com/rudderlabs/rudder-server/services/controlplane/identity"
 	"github.com/rudderlabs/rudder-server/utils/pubsub"
@@ -1218,3 +1219,43 @@ func TestRouter(t *testing.T) {
 		)
 	})
 }
+
+func TestRouter_CheckExcludeWindowsConflict(t *testing.T) {
+	warehouseWith := func(syncStartAt string) model.Warehouse {
+		return model.Warehouse{
+			WorkspaceID: "test-workspace-id",
+			Source:      backendconfig.SourceT{ID: "test-source-id"},
+			Destination: backendconfig.DestinationT{
+				ID: "test-destination-id",
+				Config: map[string]interface{}{
+					"syncFrequency": "1440",
+					"syncStartAt":   syncStartAt,
+					"excludeWindow": map[string]interface{}{
+						"excludeWindowStartTime": "02:00",
+						"excludeWindowEndTime":   "03:00",
+					},
+				},
+				DestinationDefinition: backendconfig.DestinationDefinitionT{Name: warehouseutils.RS},
+			},
+		}
+	}
+
+	mockCtrl := gomock.NewController(t)
+	mockLogger := mock_logger.NewMockLogger(mockCtrl)
+
+	r := Router{logger: mockLogger}
+
+	gomock.InOrder(
+		mockLogger.EXPECT().Warnw("all scheduled times exist in the exclude window, uploads only start after the exclude window ends", gomock.Any()).Times(1),
+		mockLogger.EXPECT().Infow("scheduled times no longer all exist in the exclude window", gomock.Any()).Times(1),
+	)
+
+	// warned once while the conflict lasts
+	r.checkExcludeWindowsConflict(warehouseWith("02:30"))
+	r.checkExcludeWindowsConflict(warehouseWith("02:30"))
+	r.checkExcludeWindowsConflict(warehouseWith("02:45"))
+
+	// resolved once
+	r.checkExcludeWindowsConflict(warehouseWith("04:00"))
+	r.checkExcludeWindowsConflict(warehouseWith("04:00"))
+}
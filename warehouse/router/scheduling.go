@@ -3,13 +3,16 @@ package router
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/samber/lo"
 
+	"github.com/rudderlabs/rudder-go-kit/stats"
 	"github.com/rudderlabs/rudder-server/utils/timeutil"
 	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
@@ -19,62 +22,193 @@ import (
 var (
 	scheduledTimesCache     map[string][]int
 	scheduledTimesCacheLock sync.RWMutex
+	// scheduledTimesStats returns the stats the hits, misses and size of the scheduled times cache are reported with
+	scheduledTimesStats = func() stats.Stats { return stats.Default }
 
 	StartUploadAlways atomic.Bool
+	// StartUploadAlwaysWarehouses are the warehouses for which uploads are forced to start always, whereas StartUploadAlways forces them for all the warehouses
+	StartUploadAlwaysWarehouses = NewWarehouseSet()
 )
 
+// WarehouseSet is a set of warehouse identifiers, safe for concurrent use
+type WarehouseSet struct {
+	mu          sync.RWMutex
+	identifiers map[string]struct{}
+}
+
+func NewWarehouseSet() *WarehouseSet {
+	return &WarehouseSet{identifiers: make(map[string]struct{})}
+}
+
+// Add adds the warehouse identifier to the set
+func (s *WarehouseSet) Add(identifier string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identifiers[identifier] = struct{}{}
+}
+
+// Remove removes the warehouse identifier from the set
+func (s *WarehouseSet) Remove(identifier string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.identifiers, identifier)
+}
+
+// Contains indicates if the warehouse identifier is in the set
+func (s *WarehouseSet) Contains(identifier string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.identifiers[identifier]
+	return ok
+}
+
+// List returns the warehouse identifiers of the set, sorted
+func (s *WarehouseSet) List() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	identifiers := lo.Keys(s.identifiers)
+	sort.Strings(identifiers)
+	return identifiers
+}
+
 func init() {
 	scheduledTimesCache = map[string][]int{}
 }
 
 // canCreateUpload indicates if an upload can be started now for the warehouse based on its configured schedule
 func (r *Router) canCreateUpload(ctx context.Context, warehouse model.Warehouse) (bool, error) {
+	eligibility, err := r.UploadEligibility(ctx, warehouse)
+	if err != nil {
+		return false, err
+	}
+	return eligibility.Eligible, eligibility.Err()
+}
+
+// uploadTriggerReason returns the reason for which an upload can be started now for the warehouse, or an error explaining why it can't
+func (r *Router) uploadTriggerReason(ctx context.Context, warehouse model.Warehouse) (string, error) {
+	eligibility, err := r.UploadEligibility(ctx, warehouse)
+	if err != nil {
+		return "", err
+	}
+	if !eligibility.Eligible {
+		return "", eligibility.Err()
+	}
+	return string(eligibility.Reason), nil
+}
+
+// UploadEligibility returns whether an upload can be started now for the warehouse based on its configured schedule, along with the reason.
+// An error is only returned if the eligibility can't be determined.
+func (r *Router) UploadEligibility(ctx context.Context, warehouse model.Warehouse) (Eligibility, error) {
+	// pausing the syncs of the warehouse takes precedence over forcing or triggering them
+	if syncDisabled(warehouse) {
+		return notEligible(EligibilityReasonSyncDisabled, time.Time{}, fmt.Errorf("sync disabled: syncs are disabled for the warehouse")), nil
+	}
+
+	// the minimum interval between uploads holds back forced and triggered uploads as well, so that the destination is never hammered
+	if interval := minUploadInterval(warehouse); interval > 0 {
+		r.createJobMarkerMapLock.RLock()
+		lastCreatedAt, ok := r.createJobMarkerMap[warehouse.Identifier]
+		r.createJobMarkerMapLock.RUnlock()
+
+		if ok && r.now().Sub(lastCreatedAt) < interval {
+			return notEligible(EligibilityReasonMinUploadInterval, lastCreatedAt.Add(interval),
+				fmt.Errorf("min upload interval: an upload was created less than %s ago", interval),
+			), nil
+		}
+	}
+
 	// can be set from rudder-cli to force uploads always
-	if StartUploadAlways.Load() {
-		return true, nil
+	if StartUploadAlways.Load() || StartUploadAlwaysWarehouses.Contains(warehouse.Identifier) {
+		return eligible(EligibilityReasonStartUploadAlways), nil
 	}
 
-	// return true if the upload was triggered
+	// the upload was triggered manually
 	if _, isTriggered := r.triggerStore.Load(warehouse.Identifier); isTriggered {
-		return true, nil
+		return eligible(EligibilityReasonManual), nil
 	}
 
 	if r.config.warehouseSyncFreqIgnore.Load() {
 		if r.uploadFrequencyExceeded(warehouse, "") {
-			return true, nil
+			return eligible(EligibilityReasonSyncFrequencyIgnored), nil
 		}
-		return false, fmt.Errorf("ignore sync freq: upload frequency exceeded")
+		return notEligible(EligibilityReasonUploadFrequencyNotExceeded, r.uploadFrequencyExceededAt(warehouse, ""),
+			fmt.Errorf("ignore sync freq: upload frequency exceeded"),
+		), nil
 	}
 
-	// gets exclude window start time and end time
-	excludeWindow := warehouseutils.GetConfigValueAsMap(warehouseutils.ExcludeWindow, warehouse.Destination.Config)
-	excludeWindowStartTime, excludeWindowEndTime := excludeWindowStartEndTimes(excludeWindow)
+	now := r.now()
 
-	if checkCurrentTimeExistsInExcludeWindow(r.now().UTC(), excludeWindowStartTime, excludeWindowEndTime) {
-		return false, fmt.Errorf("exclude window: current time exists in exclude window")
+	schedule := ScheduleOf(warehouse)
+	// the exclude windows and the sync days are checked first, so that neither the last upload nor the upload frequency are needed meanwhile
+	if eligibility, excluded := schedule.excluded(now); excluded {
+		return eligibility, nil
 	}
 
-	syncFrequency := warehouseutils.GetConfigValue(warehouseutils.SyncFrequency, warehouse)
-	syncStartAt := warehouseutils.GetConfigValue(warehouseutils.SyncStartAt, warehouse)
-	if syncFrequency == "" || syncStartAt == "" {
-		if r.uploadFrequencyExceeded(warehouse, syncFrequency) {
-			return true, nil
+	// the last upload is read from the database for the schedules to hold across restarts, unlike the upload frequency which starts over
+	var lastUploadCreatedAt time.Time
+	if _, rolling := schedule.rolling(); rolling || schedule.scheduled() {
+		var err error
+		if lastUploadCreatedAt, err = r.uploadRepo.LastCreatedAt(ctx, warehouse.Source.ID, warehouse.Destination.ID); err != nil {
+			return Eligibility{}, err
 		}
-		return false, fmt.Errorf("upload frequency exceeded")
+	} else {
+		schedule.UploadFrequency = time.Duration(r.config.uploadFreqInS.Load()) * time.Second
+
+		r.createJobMarkerMapLock.RLock()
+		lastUploadCreatedAt = r.createJobMarkerMap[warehouse.Identifier]
+		r.createJobMarkerMapLock.RUnlock()
+	}
+	return ScheduledEligibility(schedule, lastUploadCreatedAt, now), nil
+}
+
+// syncDisabled returns true if the syncs of the warehouse are paused through its configuration, e.g. while investigating a problematic warehouse.
+// The staging files keep piling up meanwhile, and are synced once the syncs are enabled again.
+func syncDisabled(warehouse model.Warehouse) bool {
+	return warehouseutils.GetConfigValueBoolString(warehouseutils.DisableSync, warehouse) == "true"
+}
+
+// minUploadInterval returns the minimum interval between the uploads of the warehouse, whatever starts them, or zero if there is none.
+// The interval is configured either as a number of minutes (e.g. "30") or as a duration (e.g. "1h"), invalid or negative intervals being ignored.
+func minUploadInterval(warehouse model.Warehouse) time.Duration {
+	intervalConfig := strings.TrimSpace(warehouseutils.GetConfigValue(warehouseutils.MinUploadInterval, warehouse))
+	if intervalConfig == "" {
+		return 0
 	}
 
-	prevScheduledTime := prevScheduledTime(syncFrequency, syncStartAt, r.now())
-	lastUploadCreatedAt, err := r.uploadRepo.LastCreatedAt(ctx, warehouse.Source.ID, warehouse.Destination.ID)
+	interval, err := time.ParseDuration(intervalConfig)
 	if err != nil {
-		return false, err
+		minutes, err := strconv.Atoi(intervalConfig)
+		if err != nil {
+			return 0
+		}
+		interval = time.Duration(minutes) * time.Minute
+	}
+	return max(interval, 0)
+}
+
+// excludeWindow is a daily window during which uploads are not started
+type excludeWindow struct {
+	startTime string
+	endTime   string
+}
+
+// excludeWindows returns the exclude windows configured for the destination.
+// The exclude window can either be a single window, or a list of windows e.g. for multiple maintenance windows per day.
+func excludeWindows(destConfig map[string]interface{}) []excludeWindow {
+	windowsConfig, ok := destConfig[warehouseutils.ExcludeWindow].([]interface{})
+	if !ok {
+		startTime, endTime := excludeWindowStartEndTimes(warehouseutils.GetConfigValueAsMap(warehouseutils.ExcludeWindow, destConfig))
+		return []excludeWindow{{startTime: startTime, endTime: endTime}}
 	}
 
-	// start upload only if no upload has started in current window
-	// e.g. with prev scheduled time 14:00 and current time 15:00, start only if prev upload hasn't started after 14:00
-	if lastUploadCreatedAt.Before(prevScheduledTime) {
-		return true, nil
+	windows := make([]excludeWindow, 0, len(windowsConfig))
+	for _, windowConfig := range windowsConfig {
+		if w, ok := windowConfig.(map[string]interface{}); ok {
+			startTime, endTime := excludeWindowStartEndTimes(w)
+			windows = append(windows, excludeWindow{startTime: startTime, endTime: endTime})
+		}
 	}
-	return false, fmt.Errorf("before scheduled time")
+	return windows
 }
 
 func excludeWindowStartEndTimes(excludeWindow map[string]interface{}) (string, string) {
@@ -119,6 +253,120 @@ func checkCurrentTimeExistsInExcludeWindow(currentTime time.Time, windowStartTim
 	return false
 }
 
+// checkCurrentTimeExistsInExcludeWindows returns true if the current time exists in any of the exclude windows
+func checkCurrentTimeExistsInExcludeWindows(currentTime time.Time, windows []excludeWindow) bool {
+	return lo.ContainsBy(windows, func(w excludeWindow) bool {
+		return checkCurrentTimeExistsInExcludeWindow(currentTime, w.startTime, w.endTime)
+	})
+}
+
+// ExcludedWarehouse is a warehouse whose uploads are currently held back by its exclude windows
+type ExcludedWarehouse struct {
+	Warehouse model.Warehouse
+	// WindowEndsAt is when the current time stops existing in the exclude windows of the warehouse, following the windows overlapping each other
+	WindowEndsAt time.Time
+}
+
+// WarehousesInExcludeWindow returns the warehouses known to the router whose current time, in their sync timezone, exists in their exclude windows.
+// It helps understanding why a set of warehouses isn't syncing right now, e.g. on an operational dashboard.
+func (r *Router) WarehousesInExcludeWindow() []ExcludedWarehouse {
+	r.configSubscriberLock.RLock()
+	warehouses := append([]model.Warehouse{}, r.warehouses...)
+	r.configSubscriberLock.RUnlock()
+
+	now := r.now()
+
+	var excluded []ExcludedWarehouse
+	for _, warehouse := range warehouses {
+		currentTime := now.In(syncTimezone(warehouse))
+		windows := excludeWindows(warehouse.Destination.Config)
+		if !checkCurrentTimeExistsInExcludeWindows(currentTime, windows) {
+			continue
+		}
+		excluded = append(excluded, ExcludedWarehouse{
+			Warehouse:    warehouse,
+			WindowEndsAt: excludeWindowsEndAt(currentTime, windows),
+		})
+	}
+	return excluded
+}
+
+// scheduledTimesExcluded returns true if all the scheduled times of the schedule exist in the exclude windows, the windows being local times of the location.
+// In that case uploads never start at their scheduled times, and are only started when the exclude windows end.
+func scheduledTimesExcluded(syncFrequency, syncStartAt string, windows []excludeWindow, loc *time.Location) bool {
+	if _, err := parseSyncFrequency(syncFrequency); err != nil || syncStartAt == "" || len(windows) == 0 {
+		return false
+	}
+
+	allStartTimes := scheduledTimes(syncFrequency, syncStartAt)
+	if len(allStartTimes) == 0 {
+		return false
+	}
+
+	startOfDay := timeutil.StartOfDay(time.Now().UTC())
+	return lo.EveryBy(allStartTimes, func(t int) bool {
+		return checkCurrentTimeExistsInExcludeWindows(startOfDay.Add(time.Minute*time.Duration(t)).In(loc), windows)
+	})
+}
+
+// syncDaysOfWeek returns the days of the week on which uploads can be started, along with the timezone in which they are evaluated.
+// The days are configured either as a list or as a comma separated string of day names (e.g. "monday,tuesday" or "mon,tue").
+// No days means that uploads can be started every day, while the timezone defaults to UTC.
+func syncDaysOfWeek(warehouse model.Warehouse) (map[time.Weekday]struct{}, *time.Location) {
+	var dayNames []string
+	if daysConfig, ok := warehouse.Destination.Config[warehouseutils.SyncDaysOfWeek].([]interface{}); ok {
+		for _, day := range daysConfig {
+			if dayName, ok := day.(string); ok {
+				dayNames = append(dayNames, dayName)
+			}
+		}
+	} else if daysConfig := warehouseutils.GetConfigValue(warehouseutils.SyncDaysOfWeek, warehouse); daysConfig != "" {
+		dayNames = strings.Split(daysConfig, ",")
+	}
+
+	days := make(map[time.Weekday]struct{})
+	for _, dayName := range dayNames {
+		if day, ok := parseWeekday(dayName); ok {
+			days[day] = struct{}{}
+		}
+	}
+
+	return days, syncTimezone(warehouse)
+}
+
+// syncTimezone returns the timezone in which the sync days and the exclude windows of the warehouse are evaluated, defaulting to UTC
+func syncTimezone(warehouse model.Warehouse) *time.Location {
+	loc, err := time.LoadLocation(warehouseutils.GetConfigValue(warehouseutils.SyncTimezone, warehouse))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseWeekday parses full or abbreviated day names, case-insensitively
+func parseWeekday(dayName string) (time.Weekday, bool) {
+	dayName = strings.ToLower(strings.TrimSpace(dayName))
+	if len(dayName) < 3 {
+		return 0, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		fullName := strings.ToLower(day.String())
+		if dayName == fullName || dayName == fullName[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// isSyncDay returns true if the current time in the location falls on one of the days, or if there are no days
+func isSyncDay(currentTime time.Time, days map[time.Weekday]struct{}, loc *time.Location) bool {
+	if len(days) == 0 {
+		return true
+	}
+	_, ok := days[currentTime.In(loc).Weekday()]
+	return ok
+}
+
 // prevScheduledTime returns the closest previous scheduled time
 // e.g. Syncing every 3hrs starting at 13:00 (scheduled times: 13:00, 16:00, 19:00, 22:00, 01:00, 04:00, 07:00, 10:00)
 // prev scheduled time for current time (e.g. 18:00 -> 16:00 same day, 00:30 -> 22:00 prev day)
@@ -152,6 +400,57 @@ func prevScheduledTime(syncFrequency, syncStartAt string, currTime time.Time) ti
 	return timeutil.StartOfDay(now).Add(time.Minute * time.Duration(allStartTimes[pos]))
 }
 
+// upcomingScheduledTimes returns the next count scheduled times after from
+// e.g. Syncing every 7hrs starting at 05:00 (scheduled times: 05:00, 12:00, 19:00)
+// next 3 scheduled times after 18:00 are 19:00 same day, 05:00 and 12:00 next day
+func upcomingScheduledTimes(syncFrequency, syncStartAt string, from time.Time, count int) []time.Time {
+	allStartTimes := scheduledTimes(syncFrequency, syncStartAt)
+	if count <= 0 || len(allStartTimes) == 0 {
+		return nil
+	}
+
+	from = from.UTC()
+	upcomingTimes := make([]time.Time, 0, count)
+	for day := timeutil.StartOfDay(from); ; day = day.Add(24 * time.Hour) {
+		for _, t := range allStartTimes {
+			scheduledTime := day.Add(time.Minute * time.Duration(t))
+			if !scheduledTime.After(from) {
+				continue
+			}
+			upcomingTimes = append(upcomingTimes, scheduledTime)
+			if len(upcomingTimes) == count {
+				return upcomingTimes
+			}
+		}
+	}
+}
+
+// parseSyncFrequency returns the sync frequency in minutes.
+// The sync frequency is either a number of minutes (e.g. "180") or a duration (e.g. "3h" or "90m"), which is truncated to whole minutes.
+// Sync frequencies shorter than a minute are invalid.
+func parseSyncFrequency(syncFrequency string) (int, error) {
+	syncFrequency = strings.TrimSpace(syncFrequency)
+	if syncFrequency == "" {
+		return 0, fmt.Errorf("empty sync frequency")
+	}
+
+	minutes, err := strconv.Atoi(syncFrequency)
+	if err != nil {
+		d, durationErr := time.ParseDuration(syncFrequency)
+		if durationErr != nil {
+			return 0, fmt.Errorf("invalid sync frequency %q: neither a number of minutes nor a duration", syncFrequency)
+		}
+		if d < time.Minute {
+			return 0, fmt.Errorf("invalid sync frequency %q: shorter than a minute", syncFrequency)
+		}
+		return int(d / time.Minute), nil
+	}
+	if minutes < 1 {
+		return 0, fmt.Errorf("invalid sync frequency %q: shorter than a minute", syncFrequency)
+	}
+	return minutes, nil
+}
+
 // scheduledTimes returns all possible start times (minutes from start of day) as per schedule
 // e.g. Syncing every 3hrs starting at 13:00 (scheduled times: 13:00, 16:00, 19:00, 22:00, 01:00, 04:00, 07:00, 10:00)
 func scheduledTimes(syncFrequency, syncStartAt string) []int {
@@ -160,13 +459,20 @@ func scheduledTimes(syncFrequency, syncStartAt string) []int {
 	scheduledTimesCacheLock.RUnlock()
 
 	if ok {
+		scheduledTimesStats().NewStat("wh_scheduler.scheduled_times_cache_hits", stats.CountType).Increment()
 		return cachedTimes
 	}
+	scheduledTimesStats().NewStat("wh_scheduler.scheduled_times_cache_misses", stats.CountType).Increment()
 
 	syncStartAtInMin := timeutil.MinsOfDay(syncStartAt)
-	syncFrequencyInMin, _ := strconv.Atoi(syncFrequency)
 	times := []int{syncStartAtInMin}
 
+	// callers validate the sync frequency beforehand, an invalid one results in a single scheduled time per day
+	syncFrequencyInMin, err := parseSyncFrequency(syncFrequency)
+	if err != nil {
+		return times
+	}
+
 	counter := 1
 
 	for {
@@ -193,7 +499,10 @@ func scheduledTimes(syncFrequency, syncStartAt string) []int {
 
 	scheduledTimesCacheLock.Lock()
 	scheduledTimesCache[fmt.Sprintf(`%s-%s`, syncFrequency, syncStartAt)] = times
+	cacheSize := len(scheduledTimesCache)
 	scheduledTimesCacheLock.Unlock()
 
+	scheduledTimesStats().NewStat("wh_scheduler.scheduled_times_cache_size", stats.GaugeType).Gauge(cacheSize)
+
 	return times
 }
@@ -12,7 +12,10 @@ import (
 	"github.com/rudderlabs/rudder-server/utils/misc"
 
 	"github.com/ory/dockertest/v3"
+	"github.com/samber/lo"
 
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-go-kit/stats/memstats"
 	"github.com/rudderlabs/rudder-go-kit/testhelper/docker/resource"
 	backendConfig "github.com/rudderlabs/rudder-server/backend-config"
 	migrator "github.com/rudderlabs/rudder-server/services/sql-migrator"
@@ -96,6 +99,145 @@ func TestRouter_CanCreateUpload(t *testing.T) {
 		}
 	})
 
+	t.Run("parseSyncFrequency", func(t *testing.T) {
+		testCases := []struct {
+			syncFrequency   string
+			expectedMinutes int
+			expectedError   string
+		}{
+			{syncFrequency: "30", expectedMinutes: 30},
+			{syncFrequency: " 1440 ", expectedMinutes: 1440},
+			{syncFrequency: "3h", expectedMinutes: 180},
+			{syncFrequency: "90m", expectedMinutes: 90},
+			{syncFrequency: "1h30m", expectedMinutes: 90},
+			{syncFrequency: "90s", expectedMinutes: 1},
+			{syncFrequency: "", expectedError: "empty sync frequency"},
+			{syncFrequency: "0", expectedError: `invalid sync frequency "0": shorter than a minute`},
+			{syncFrequency: "-30", expectedError: `invalid sync frequency "-30": shorter than a minute`},
+			{syncFrequency: "30s", expectedError: `invalid sync frequency "30s": shorter than a minute`},
+			{syncFrequency: "-3h", expectedError: `invalid sync frequency "-3h": shorter than a minute`},
+			{syncFrequency: "daily", expectedError: `invalid sync frequency "daily": neither a number of minutes nor a duration`},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.syncFrequency, func(t *testing.T) {
+				minutes, err := parseSyncFrequency(tc.syncFrequency)
+				if tc.expectedError != "" {
+					require.EqualError(t, err, tc.expectedError)
+					return
+				}
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedMinutes, minutes)
+			})
+		}
+	})
+
+	t.Run("upcomingScheduledTimes", func(t *testing.T) {
+		testCases := []struct {
+			name                   string
+			syncFrequency          string
+			syncStartAt            string
+			from                   time.Time
+			count                  int
+			expectedScheduledTimes []time.Time
+		}{
+			{
+				name:          "should return upcoming scheduled times in the same day",
+				syncFrequency: "30",
+				syncStartAt:   "14:00",
+				from:          time.Date(2020, 4, 27, 20, 23, 54, 3424534, time.UTC),
+				count:         3,
+				expectedScheduledTimes: []time.Time{
+					time.Date(2020, 4, 27, 20, 30, 0, 0, time.UTC),
+					time.Date(2020, 4, 27, 21, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 27, 21, 30, 0, 0, time.UTC),
+				},
+			},
+			{
+				name:          "should not return from if it is a scheduled time",
+				syncFrequency: "30",
+				syncStartAt:   "14:00",
+				from:          time.Date(2020, 4, 27, 20, 30, 0, 0, time.UTC),
+				count:         1,
+				expectedScheduledTimes: []time.Time{
+					time.Date(2020, 4, 27, 21, 0, 0, 0, time.UTC),
+				},
+			},
+			{
+				name:          "should roll over to the next days",
+				syncFrequency: "180",
+				syncStartAt:   "22:00",
+				from:          time.Date(2020, 4, 27, 22, 23, 54, 3424534, time.UTC),
+				count:         3,
+				expectedScheduledTimes: []time.Time{
+					time.Date(2020, 4, 28, 1, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 28, 4, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 28, 7, 0, 0, 0, time.UTC),
+				},
+			},
+			{
+				name:          "should restart the schedule every day if the frequency doesn't divide the day",
+				syncFrequency: "420",
+				syncStartAt:   "05:00",
+				from:          time.Date(2020, 4, 27, 18, 0, 0, 0, time.UTC),
+				count:         5,
+				expectedScheduledTimes: []time.Time{
+					time.Date(2020, 4, 27, 19, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 28, 5, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 28, 12, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 28, 19, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 29, 5, 0, 0, 0, time.UTC),
+				},
+			},
+			{
+				name:          "should return scheduled times for a frequency longer than a day",
+				syncFrequency: "1440",
+				syncStartAt:   "10:00",
+				from:          time.Date(2020, 4, 27, 10, 0, 0, 0, time.UTC),
+				count:         2,
+				expectedScheduledTimes: []time.Time{
+					time.Date(2020, 4, 28, 10, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 29, 10, 0, 0, 0, time.UTC),
+				},
+			},
+			{
+				name:          "should return scheduled times in UTC",
+				syncFrequency: "720",
+				syncStartAt:   "00:00",
+				from:          time.Date(2020, 4, 27, 23, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)),
+				count:         2,
+				expectedScheduledTimes: []time.Time{
+					time.Date(2020, 4, 28, 0, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 28, 12, 0, 0, 0, time.UTC),
+				},
+			},
+			{
+				name:          "should accept a duration as frequency",
+				syncFrequency: "3h",
+				syncStartAt:   "22:00",
+				from:          time.Date(2020, 4, 27, 22, 23, 54, 3424534, time.UTC),
+				count:         2,
+				expectedScheduledTimes: []time.Time{
+					time.Date(2020, 4, 28, 1, 0, 0, 0, time.UTC),
+					time.Date(2020, 4, 28, 4, 0, 0, 0, time.UTC),
+				},
+			},
+			{
+				name:          "should return nothing for no count",
+				syncFrequency: "30",
+				syncStartAt:   "14:00",
+				from:          time.Date(2020, 4, 27, 20, 30, 0, 0, time.UTC),
+				count:         0,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expectedScheduledTimes, upcomingScheduledTimes(tc.syncFrequency, tc.syncStartAt, tc.from, tc.count))
+			})
+		}
+	})
+
 	t.Run("excludeWindowStartEndTimes", func(t *testing.T) {
 		testCases := []struct {
 			name          string
@@ -126,6 +268,231 @@ func TestRouter_CanCreateUpload(t *testing.T) {
 		}
 	})
 
+	t.Run("excludeWindows", func(t *testing.T) {
+		testCases := []struct {
+			name            string
+			destConfig      map[string]interface{}
+			expectedWindows []excludeWindow
+		}{
+			{
+				name:            "no exclude window",
+				destConfig:      map[string]interface{}{},
+				expectedWindows: []excludeWindow{{}},
+			},
+			{
+				name: "single exclude window",
+				destConfig: map[string]interface{}{
+					"excludeWindow": map[string]interface{}{
+						"excludeWindowStartTime": "02:00",
+						"excludeWindowEndTime":   "03:00",
+					},
+				},
+				expectedWindows: []excludeWindow{{startTime: "02:00", endTime: "03:00"}},
+			},
+			{
+				name: "multiple exclude windows",
+				destConfig: map[string]interface{}{
+					"excludeWindow": []interface{}{
+						map[string]interface{}{
+							"excludeWindowStartTime": "02:00",
+							"excludeWindowEndTime":   "03:00",
+						},
+						"invalid",
+						map[string]interface{}{
+							"excludeWindowStartTime": "14:00",
+							"excludeWindowEndTime":   "14:30",
+						},
+					},
+				},
+				expectedWindows: []excludeWindow{
+					{startTime: "02:00", endTime: "03:00"},
+					{startTime: "14:00", endTime: "14:30"},
+				},
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expectedWindows, excludeWindows(tc.destConfig))
+			})
+		}
+	})
+	t.Run("checkCurrentTimeExistsInExcludeWindows", func(t *testing.T) {
+		windows := []excludeWindow{
+			{startTime: "02:00", endTime: "03:00"},
+			{startTime: "14:00", endTime: "14:30"},
+		}
+
+		require.True(t, checkCurrentTimeExistsInExcludeWindows(time.Date(2009, time.November, 10, 2, 30, 0, 0, time.UTC), windows))
+		require.True(t, checkCurrentTimeExistsInExcludeWindows(time.Date(2009, time.November, 10, 14, 15, 0, 0, time.UTC), windows))
+		require.False(t, checkCurrentTimeExistsInExcludeWindows(time.Date(2009, time.November, 10, 10, 0, 0, 0, time.UTC), windows))
+		require.False(t, checkCurrentTimeExistsInExcludeWindows(time.Date(2009, time.November, 10, 14, 45, 0, 0, time.UTC), windows))
+		require.False(t, checkCurrentTimeExistsInExcludeWindows(time.Date(2009, time.November, 10, 14, 15, 0, 0, time.UTC), nil))
+	})
+	t.Run("scheduledTimesExcluded", func(t *testing.T) {
+		kolkata, err := time.LoadLocation("Asia/Kolkata")
+		require.NoError(t, err)
+
+		testCases := []struct {
+			name          string
+			syncFrequency string
+			syncStartAt   string
+			windows       []excludeWindow
+			loc           *time.Location
+			expected      bool
+		}{
+			{
+				name:          "daily sync inside exclude window",
+				syncFrequency: "1440",
+				syncStartAt:   "02:30",
+				windows:       []excludeWindow{{startTime: "02:00", endTime: "03:00"}},
+				expected:      true,
+			},
+			{
+				name:          "daily sync outside exclude window",
+				syncFrequency: "1440",
+				syncStartAt:   "04:00",
+				windows:       []excludeWindow{{startTime: "02:00", endTime: "03:00"}},
+				expected:      false,
+			},
+			{
+				name:          "some scheduled times outside exclude window",
+				syncFrequency: "360",
+				syncStartAt:   "02:30",
+				windows:       []excludeWindow{{startTime: "02:00", endTime: "03:00"}},
+				expected:      false,
+			},
+			{
+				name:          "all scheduled times inside multiple exclude windows",
+				syncFrequency: "720",
+				syncStartAt:   "02:30",
+				windows: []excludeWindow{
+					{startTime: "02:00", endTime: "03:00"},
+					{startTime: "14:00", endTime: "15:00"},
+				},
+				expected: true,
+			},
+			{
+				name:          "daily sync inside exclude window spanning midnight",
+				syncFrequency: "1440",
+				syncStartAt:   "01:00",
+				windows:       []excludeWindow{{startTime: "22:00", endTime: "02:00"}},
+				expected:      true,
+			},
+			{
+				name:          "no exclude window",
+				syncFrequency: "1440",
+				syncStartAt:   "02:30",
+				windows:       []excludeWindow{{}},
+				expected:      false,
+			},
+			{
+				name:     "no schedule",
+				windows:  []excludeWindow{{startTime: "02:00", endTime: "03:00"}},
+				expected: false,
+			},
+			{
+				name:          "daily sync inside local exclude window",
+				syncFrequency: "1440",
+				syncStartAt:   "20:45",
+				windows:       []excludeWindow{{startTime: "02:00", endTime: "03:00"}},
+				loc:           kolkata,
+				expected:      true,
+			},
+			{
+				name:          "daily sync outside local exclude window",
+				syncFrequency: "1440",
+				syncStartAt:   "02:30",
+				windows:       []excludeWindow{{startTime: "02:00", endTime: "03:00"}},
+				loc:           kolkata,
+				expected:      false,
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				loc := tc.loc
+				if loc == nil {
+					loc = time.UTC
+				}
+				require.Equal(t, tc.expected, scheduledTimesExcluded(tc.syncFrequency, tc.syncStartAt, tc.windows, loc))
+			})
+		}
+	})
+	t.Run("syncDaysOfWeek", func(t *testing.T) {
+		testCases := []struct {
+			name         string
+			destConfig   map[string]interface{}
+			expectedDays []time.Weekday
+			expectedLoc  string
+		}{
+			{
+				name:        "no sync days",
+				destConfig:  map[string]interface{}{},
+				expectedLoc: "UTC",
+			},
+			{
+				name: "comma separated days",
+				destConfig: map[string]interface{}{
+					"syncDaysOfWeek": "Monday, tue,invalid,SUN",
+					"syncTimezone":   "Asia/Kolkata",
+				},
+				expectedDays: []time.Weekday{time.Sunday, time.Monday, time.Tuesday},
+				expectedLoc:  "Asia/Kolkata",
+			},
+			{
+				name: "list of days",
+				destConfig: map[string]interface{}{
+					"syncDaysOfWeek": []interface{}{"saturday", "sunday", 1},
+					"syncTimezone":   "Invalid/Timezone",
+				},
+				expectedDays: []time.Weekday{time.Sunday, time.Saturday},
+				expectedLoc:  "UTC",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				days, loc := syncDaysOfWeek(model.Warehouse{
+					Destination: backendConfig.DestinationT{Config: tc.destConfig},
+				})
+				require.ElementsMatch(t, tc.expectedDays, lo.Keys(days))
+				require.Equal(t, tc.expectedLoc, loc.String())
+			})
+		}
+	})
+	t.Run("isSyncDay", func(t *testing.T) {
+		weekdays := map[time.Weekday]struct{}{
+			time.Monday: {}, time.Tuesday: {}, time.Wednesday: {}, time.Thursday: {}, time.Friday: {},
+		}
+		kolkata, err := time.LoadLocation("Asia/Kolkata")
+		require.NoError(t, err)
+		losAngeles, err := time.LoadLocation("America/Los_Angeles")
+		require.NoError(t, err)
+
+		testCases := []struct {
+			name        string
+			currentTime time.Time
+			loc         *time.Location
+			expected    bool
+		}{
+			{name: "friday", currentTime: time.Date(2009, time.November, 13, 23, 0, 0, 0, time.UTC), loc: time.UTC, expected: true},
+			{name: "saturday", currentTime: time.Date(2009, time.November, 14, 0, 30, 0, 0, time.UTC), loc: time.UTC, expected: false},
+			{name: "sunday", currentTime: time.Date(2009, time.November, 15, 23, 59, 0, 0, time.UTC), loc: time.UTC, expected: false},
+			{name: "monday", currentTime: time.Date(2009, time.November, 16, 0, 0, 0, 0, time.UTC), loc: time.UTC, expected: true},
+			{name: "sunday in utc is monday in kolkata", currentTime: time.Date(2009, time.November, 15, 20, 0, 0, 0, time.UTC), loc: kolkata, expected: true},
+			{name: "monday in utc is sunday in los angeles", currentTime: time.Date(2009, time.November, 16, 3, 0, 0, 0, time.UTC), loc: losAngeles, expected: false},
+			{name: "saturday in utc is friday in los angeles", currentTime: time.Date(2009, time.November, 14, 3, 0, 0, 0, time.UTC), loc: losAngeles, expected: true},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				require.Equal(t, tc.expected, isSyncDay(tc.currentTime, weekdays, tc.loc))
+			})
+		}
+
+		require.True(t, isSyncDay(time.Date(2009, time.November, 14, 0, 0, 0, 0, time.UTC), nil, time.UTC))
+	})
 	t.Run("checkCurrentTimeExistsInExcludeWindow", func(t *testing.T) {
 		testCases := []struct {
 			currentTime   time.Time
@@ -176,6 +543,11 @@ func TestRouter_CanCreateUpload(t *testing.T) {
 				windowStart: "22:00",
 				windowEnd:   "",
 			},
+			{
+				currentTime: time.Date(2009, time.November, 10, 7, 5, 0, 0, time.UTC),
+				windowStart: "22:00",
+				windowEnd:   "06:00",
+			},
 		}
 
 		for i, tc := range testCases {
@@ -288,6 +660,64 @@ func TestRouter_CanCreateUpload(t *testing.T) {
 			require.False(t, canCreate)
 		})
 
+		t.Run("current time exists in the local exclude window", func(t *testing.T) {
+			w := model.Warehouse{
+				Identifier: "test_identifier_check_current_window_local",
+				Destination: backendConfig.DestinationT{
+					Config: map[string]interface{}{
+						"excludeWindow": map[string]interface{}{
+							"excludeWindowStartTime": "02:00",
+							"excludeWindowEndTime":   "03:00",
+						},
+						"syncTimezone":   "America/New_York",
+						"syncDaysOfWeek": "tue",
+					},
+				},
+			}
+
+			r := Router{}
+			r.triggerStore = &sync.Map{}
+			r.config.warehouseSyncFreqIgnore = misc.SingleValueLoader(false)
+
+			// 02:30 in New York
+			r.now = func() time.Time {
+				return time.Date(2009, time.November, 10, 7, 30, 0, 0, time.UTC)
+			}
+			canCreate, err := r.canCreateUpload(context.Background(), w)
+			require.EqualError(t, err, "exclude window: current time exists in exclude window")
+			require.False(t, canCreate)
+
+			// 02:30 on Tuesday in UTC, i.e. 21:30 on Monday in New York
+			r.now = func() time.Time {
+				return time.Date(2009, time.November, 10, 2, 30, 0, 0, time.UTC)
+			}
+			canCreate, err = r.canCreateUpload(context.Background(), w)
+			require.EqualError(t, err, "sync days of week: Monday is not a sync day")
+			require.False(t, canCreate)
+		})
+
+		t.Run("not a sync day", func(t *testing.T) {
+			w := model.Warehouse{
+				Identifier: "test_identifier_not_a_sync_day",
+				Destination: backendConfig.DestinationT{
+					Config: map[string]interface{}{
+						"syncDaysOfWeek": "mon,tue,wed,thu,fri",
+					},
+				},
+			}
+
+			r := Router{}
+			r.triggerStore = &sync.Map{}
+			r.config.warehouseSyncFreqIgnore = misc.SingleValueLoader(false)
+			r.now = func() time.Time {
+				return time.Date(2009, time.November, 14, 5, 30, 0, 0, time.UTC)
+			}
+
+			canCreate, err := r.canCreateUpload(context.Background(), w)
+			require.EqualError(t, err, "sync days of week: Saturday is not a sync day")
+			require.False(t, canCreate)
+		})
+
 		t.Run("no sync start at and frequency not exceeded", func(t *testing.T) {
 			w := model.Warehouse{
 				Identifier: "test_identifier_no_sync_start_at_frequency_not_exceeded",
@@ -438,6 +868,129 @@ func TestRouter_CanCreateUpload(t *testing.T) {
 					require.True(t, canCreate)
 				})
 			}
+
+			t.Run("sync frequency without start time", func(t *testing.T) {
+				w := model.Warehouse{
+					Identifier: "test_identifier_last_created_at_rolling",
+					Source: backendConfig.SourceT{
+						ID: sourceID,
+					},
+					Destination: backendConfig.DestinationT{
+						ID: destinationID,
+						Config: map[string]interface{}{
+							"syncFrequency": "30",
+						},
+					},
+				}
+
+				r := Router{}
+				r.triggerStore = &sync.Map{}
+				r.config.warehouseSyncFreqIgnore = misc.SingleValueLoader(false)
+				r.config.uploadFreqInS = misc.SingleValueLoader(int64(1800))
+				r.createJobMarkerMap = make(map[string]time.Time)
+				r.uploadRepo = repoUpload
+
+				// the last upload of the database is the one the interval is rolling from, not the last processed time of the router
+				r.now = func() time.Time { return now.Add(10 * time.Minute) }
+				canCreate, err := r.canCreateUpload(context.Background(), w)
+				require.EqualError(t, err, "sync frequency: an upload was created less than 30m0s ago")
+				require.False(t, canCreate)
+
+				r.now = func() time.Time { return now.Add(30 * time.Minute) }
+				canCreate, err = r.canCreateUpload(context.Background(), w)
+				require.NoError(t, err)
+				require.True(t, canCreate)
+			})
 		})
 	})
 }
+
+func TestWarehouseSet(t *testing.T) {
+	s := NewWarehouseSet()
+	require.Empty(t, s.List())
+	require.False(t, s.Contains("POSTGRES:source:destination"))
+
+	s.Add("POSTGRES:source:destination")
+	s.Add("BQ:source:destination")
+	s.Add("POSTGRES:source:destination")
+	require.True(t, s.Contains("POSTGRES:source:destination"))
+	require.Equal(t, []string{"BQ:source:destination", "POSTGRES:source:destination"}, s.List())
+
+	s.Remove("POSTGRES:source:destination")
+	s.Remove("unknown")
+	require.False(t, s.Contains("POSTGRES:source:destination"))
+	require.Equal(t, []string{"BQ:source:destination"}, s.List())
+}
+
+func TestScheduledTimesCacheStats(t *testing.T) {
+	statsStore := memstats.New()
+	scheduledTimesStats = func() stats.Stats { return statsStore }
+	t.Cleanup(func() { scheduledTimesStats = func() stats.Stats { return stats.Default } })
+
+	scheduledTimesCacheLock.Lock()
+	scheduledTimesCache = map[string][]int{}
+	scheduledTimesCacheLock.Unlock()
+
+	require.Equal(t, []int{60, 780}, scheduledTimes("720", "13:00"))
+	require.Nil(t, statsStore.Get("wh_scheduler.scheduled_times_cache_hits", nil))
+	require.EqualValues(t, 1, statsStore.Get("wh_scheduler.scheduled_times_cache_misses", nil).LastValue())
+	require.EqualValues(t, 1, statsStore.Get("wh_scheduler.scheduled_times_cache_size", nil).LastValue())
+
+	require.Equal(t, []int{60, 780}, scheduledTimes("720", "13:00"))
+	require.EqualValues(t, 1, statsStore.Get("wh_scheduler.scheduled_times_cache_hits", nil).LastValue())
+	require.EqualValues(t, 1, statsStore.Get("wh_scheduler.scheduled_times_cache_size", nil).LastValue())
+
+	require.Equal(t, []int{0, 720}, scheduledTimes("720", "00:00"))
+	require.EqualValues(t, 2, statsStore.Get("wh_scheduler.scheduled_times_cache_size", nil).LastValue())
+}
+
+func TestRouter_WarehousesInExcludeWindow(t *testing.T) {
+	newWarehouse := func(destinationID string, conf map[string]interface{}) model.Warehouse {
+		return model.Warehouse{
+			Destination: backendConfig.DestinationT{
+				ID:     destinationID,
+				Config: conf,
+			},
+		}
+	}
+
+	var (
+		outside = newWarehouse("outside", map[string]interface{}{
+			"excludeWindow": map[string]interface{}{"excludeWindowStartTime": "01:00", "excludeWindowEndTime": "02:00"},
+		})
+		inside = newWarehouse("inside", map[string]interface{}{
+			"excludeWindow": map[string]interface{}{"excludeWindowStartTime": "22:00", "excludeWindowEndTime": "05:00"},
+		})
+		overlapping = newWarehouse("overlapping", map[string]interface{}{
+			"excludeWindow": []interface{}{
+				map[string]interface{}{"excludeWindowStartTime": "22:00", "excludeWindowEndTime": "23:30"},
+				map[string]interface{}{"excludeWindowStartTime": "23:00", "excludeWindowEndTime": "01:00"},
+			},
+		})
+		timezone = newWarehouse("timezone", map[string]interface{}{
+			"excludeWindow": map[string]interface{}{"excludeWindowStartTime": "03:00", "excludeWindowEndTime": "05:00"},
+			"syncTimezone":  "Asia/Kolkata",
+		})
+		noWindow = newWarehouse("no_window", map[string]interface{}{})
+	)
+
+	now := time.Date(2023, 1, 1, 22, 30, 0, 0, time.UTC)
+	r := &Router{
+		warehouses: []model.Warehouse{outside, inside, overlapping, timezone, noWindow},
+		now:        func() time.Time { return now },
+	}
+
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	excluded := r.WarehousesInExcludeWindow()
+	require.Equal(t, []string{"inside", "overlapping", "timezone"}, lo.Map(excluded, func(w ExcludedWarehouse, _ int) string {
+		return w.Warehouse.Destination.ID
+	}))
+	require.True(t, time.Date(2023, 1, 2, 5, 0, 0, 0, time.UTC).Equal(excluded[0].WindowEndsAt))
+	require.True(t, time.Date(2023, 1, 2, 1, 0, 0, 0, time.UTC).Equal(excluded[1].WindowEndsAt))
+	require.True(t, time.Date(2023, 1, 2, 5, 0, 0, 0, kolkata).Equal(excluded[2].WindowEndsAt))
+
+	r.warehouses = []model.Warehouse{outside, noWindow}
+	require.Empty(t, r.WarehousesInExcludeWindow())
+}
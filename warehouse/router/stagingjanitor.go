@@ -0,0 +1,89 @@
+package router
+
+import (
+	"context"
+	"time"
+
+	"github.com/samber/lo"
+
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-server/warehouse/integrations/manager"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	"github.com/rudderlabs/rudder-server/warehouse/logfield"
+)
+
+// staleStagingTablesReapedStat is the number of stale staging tables dropped from a warehouse by a run of the staging tables janitor
+const staleStagingTablesReapedStat = "warehouse_stale_staging_tables_reaped"
+
+// CronStagingTablesJanitor periodically (every Warehouse.stagingTablesJanitorFrequency) drops the staging tables of the warehouses
+// created longer than Warehouse.stagingTablesTTL ago, reclaiming the space of the staging tables left behind by crashed loads.
+// Only the warehouses which can drop their stale staging tables on their own are cleaned up, nothing is dropped if the TTL isn't positive.
+func (r *Router) CronStagingTablesJanitor(ctx context.Context) error {
+	for {
+		if ttl := r.conf.GetDurationVar(0, time.Hour, "Warehouse.stagingTablesTTL"); ttl > 0 {
+			r.runStagingTablesJanitor(ctx, ttl)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(r.conf.GetDurationVar(1, time.Hour, "Warehouse.stagingTablesJanitorFrequency")):
+		}
+	}
+}
+
+func (r *Router) runStagingTablesJanitor(ctx context.Context, ttl time.Duration) {
+	whManager, err := manager.New(r.destType, r.conf, r.logger, r.statsFactory)
+	if err != nil {
+		r.logger.Warnw("creating manager for the staging tables janitor", logfield.Error, err.Error())
+		return
+	}
+	dropper, ok := whManager.(manager.StaleStagingTablesDropper)
+	if !ok {
+		return
+	}
+
+	r.configSubscriberLock.RLock()
+	warehouses := append([]model.Warehouse{}, r.warehouses...)
+	r.configSubscriberLock.RUnlock()
+
+	r.reapStaleStagingTables(ctx, dropper, warehouses, ttl)
+}
+
+// reapStaleStagingTables drops the stale staging tables of every warehouse once, the warehouses sharing the same destination and namespace being cleaned up together.
+// The number of staging tables dropped is reported for every warehouse, failures to drop them are only logged.
+func (r *Router) reapStaleStagingTables(ctx context.Context, dropper manager.StaleStagingTablesDropper, warehouses []model.Warehouse, ttl time.Duration) {
+	warehouses = lo.UniqBy(warehouses, func(warehouse model.Warehouse) string {
+		return warehouse.Destination.ID + "_" + warehouse.Namespace
+	})
+	for _, warehouse := range warehouses {
+		if ctx.Err() != nil {
+			return
+		}
+
+		dropped, err := dropper.DropStaleStagingTables(ctx, warehouse, ttl)
+		if err != nil {
+			r.logger.Warnw("dropping stale staging tables",
+				logfield.DestinationID, warehouse.Destination.ID,
+				logfield.DestinationType, warehouse.Destination.DestinationDefinition.Name,
+				logfield.WorkspaceID, warehouse.WorkspaceID,
+				logfield.Namespace, warehouse.Namespace,
+				logfield.Error, err.Error(),
+			)
+		}
+		if dropped > 0 {
+			r.logger.Infow("dropped stale staging tables",
+				logfield.DestinationID, warehouse.Destination.ID,
+				logfield.DestinationType, warehouse.Destination.DestinationDefinition.Name,
+				logfield.WorkspaceID, warehouse.WorkspaceID,
+				logfield.Namespace, warehouse.Namespace,
+				"count", dropped,
+			)
+		}
+		r.statsFactory.NewTaggedStat(staleStagingTablesReapedStat, stats.CountType, stats.Tags{
+			"workspaceId": warehouse.WorkspaceID,
+			"destType":    r.destType,
+			"destID":      warehouse.Destination.ID,
+		}).Count(dropped)
+	}
+}
@@ -0,0 +1,85 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-go-kit/stats/memstats"
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// staleStagingTablesDropper drops the configured number of staging tables of every destination, failing for the destinations without any
+type staleStagingTablesDropper struct {
+	dropped   map[string]int
+	olderThan []time.Duration
+	calls     []string
+}
+
+func (d *staleStagingTablesDropper) DropStaleStagingTables(_ context.Context, warehouse model.Warehouse, olderThan time.Duration) (int, error) {
+	d.calls = append(d.calls, warehouse.Destination.ID+"_"+warehouse.Namespace)
+	d.olderThan = append(d.olderThan, olderThan)
+	dropped, ok := d.dropped[warehouse.Destination.ID]
+	if !ok {
+		return 0, errors.New("connection refused")
+	}
+	return dropped, nil
+}
+
+func TestRouter_ReapStaleStagingTables(t *testing.T) {
+	newWarehouse := func(sourceID, destinationID, namespace string) model.Warehouse {
+		return model.Warehouse{
+			WorkspaceID: "test-workspace-id",
+			Source:      backendconfig.SourceT{ID: sourceID},
+			Destination: backendconfig.DestinationT{ID: destinationID},
+			Namespace:   namespace,
+		}
+	}
+
+	statsStore := memstats.New()
+	r := &Router{
+		destType:     warehouseutils.MSSQL,
+		logger:       logger.NOP,
+		statsFactory: statsStore,
+	}
+
+	dropper := &staleStagingTablesDropper{dropped: map[string]int{"dest1": 2, "dest2": 0}}
+	r.reapStaleStagingTables(context.Background(), dropper, []model.Warehouse{
+		newWarehouse("source1", "dest1", "ns"),
+		newWarehouse("source2", "dest1", "ns"),
+		newWarehouse("source1", "dest2", "ns"),
+		newWarehouse("source1", "dest3", "ns"),
+	}, time.Hour)
+
+	require.Equal(t, []string{"dest1_ns", "dest2_ns", "dest3_ns"}, dropper.calls, "warehouses sharing the destination and namespace should be cleaned up once")
+	require.Equal(t, []time.Duration{time.Hour, time.Hour, time.Hour}, dropper.olderThan)
+
+	reaped := func(destID string) float64 {
+		m := statsStore.Get(staleStagingTablesReapedStat, stats.Tags{
+			"workspaceId": "test-workspace-id",
+			"destType":    warehouseutils.MSSQL,
+			"destID":      destID,
+		})
+		require.NotNil(t, m)
+		return m.LastValue()
+	}
+	require.EqualValues(t, 2, reaped("dest1"))
+	require.EqualValues(t, 0, reaped("dest2"))
+	require.EqualValues(t, 0, reaped("dest3"))
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		dropper := &staleStagingTablesDropper{}
+		r.reapStaleStagingTables(ctx, dropper, []model.Warehouse{newWarehouse("source1", "dest1", "ns")}, time.Hour)
+		require.Empty(t, dropper.calls)
+	})
+}
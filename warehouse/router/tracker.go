@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/rudderlabs/rudder-server/warehouse/logfield"
@@ -86,20 +85,18 @@ func (r *Router) Track(
 	})
 	trackUploadMissingStat.Gauge(0)
 
-	if !source.Enabled || !destination.Enabled {
+	if !source.Enabled || !destination.Enabled || syncDisabled(*warehouse) {
 		return nil
 	}
 
-	excludeWindow := warehouseutils.GetConfigValueAsMap(warehouseutils.ExcludeWindow, warehouse.Destination.Config)
-	excludeWindowStartTime, excludeWindowEndTime := excludeWindowStartEndTimes(excludeWindow)
-	if checkCurrentTimeExistsInExcludeWindow(now(), excludeWindowStartTime, excludeWindowEndTime) {
+	if checkCurrentTimeExistsInExcludeWindows(now().In(syncTimezone(*warehouse)), excludeWindows(warehouse.Destination.Config)) {
 		return nil
 	}
 
 	if sf := warehouseutils.GetConfigValue(warehouseutils.SyncFrequency, *warehouse); sf != "" {
 		syncFrequency = sf
 	}
-	if value, err := strconv.Atoi(syncFrequency); err == nil {
+	if value, err := parseSyncFrequency(syncFrequency); err == nil {
 		timeWindow += time.Duration(value) * time.Minute
 	}
 
@@ -40,6 +40,7 @@ func TestRouter_Track(t *testing.T) {
 		name             string
 		destID           string
 		destDisabled     bool
+		syncDisabled     bool
 		wantErr          error
 		missing          bool
 		NowSQL           string
@@ -80,6 +81,12 @@ func TestRouter_Track(t *testing.T) {
 			destID:  "test-destinationID-1",
 			missing: true,
 		},
+		{
+			name:         "sync disabled",
+			destID:       "test-destinationID-1",
+			syncDisabled: true,
+			missing:      false,
+		},
 		{
 			name:    "throw error while fetching last upload time",
 			destID:  destID,
@@ -143,6 +150,7 @@ func TestRouter_Track(t *testing.T) {
 					Config: map[string]any{
 						"syncFrequency": "10",
 						"excludeWindow": tc.exclusionWindow,
+						"disableSync":   tc.syncDisabled,
 					},
 				},
 			}
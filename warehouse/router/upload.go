@@ -117,6 +117,7 @@ type UploadJob struct {
 		maxParallelLoadsWorkspaceIDs        map[string]interface{}
 		columnsBatchSize                    int
 		longRunningUploadStatThresholdInMin time.Duration
+		numWorkersVerifyLoadFiles           int
 	}
 
 	errorHandler    ErrorHandler
@@ -217,6 +218,7 @@ func (f *UploadJobFactory) NewUploadJob(ctx context.Context, dto *model.UploadJo
 	uj.config.reportingEnabled = f.conf.GetBool("Reporting.enabled", types.DefaultReportingEnabled)
 	uj.config.columnsBatchSize = f.conf.GetInt(fmt.Sprintf("Warehouse.%s.columnsBatchSize", whutils.WHDestNameMap[uj.upload.DestinationType]), 100)
 	uj.config.maxParallelLoadsWorkspaceIDs = f.conf.GetStringMap(fmt.Sprintf("Warehouse.%s.maxParallelLoadsWorkspaceIDs", whutils.WHDestNameMap[uj.upload.DestinationType]), nil)
+	uj.config.numWorkersVerifyLoadFiles = f.conf.GetInt(fmt.Sprintf("Warehouse.%s.numWorkersVerifyLoadFiles", whutils.WHDestNameMap[uj.upload.DestinationType]), 8)
 	uj.config.longRunningUploadStatThresholdInMin = f.conf.GetDurationVar(120, time.Minute, "Warehouse.longRunningUploadStatThreshold", "Warehouse.longRunningUploadStatThresholdInMin")
 	uj.config.minUploadBackoff = f.conf.GetDurationVar(60, time.Second, "Warehouse.minUploadBackoff", "Warehouse.minUploadBackoffInS")
 	uj.config.maxUploadBackoff = f.conf.GetDurationVar(1800, time.Second, "Warehouse.maxUploadBackoff", "Warehouse.maxUploadBackoffInS")
@@ -500,6 +502,9 @@ func (job *UploadJob) run() (err error) {
 
 		case model.CreatedRemoteSchema:
 			newStatus = nextUploadState.failed
+			if err = job.verifyLoadFiles(job.ctx); err != nil {
+				break
+			}
 			if job.schemaHandle.IsWarehouseSchemaEmpty() {
 				if err = whManager.CreateSchema(job.ctx); err != nil {
 					break
@@ -1863,6 +1868,18 @@ func (job *UploadJob) GetLoadFilesMetadata(ctx context.Context, options whutils.
 	return
 }
 
+// verifyLoadFiles fails the upload if some of its load files are missing from the object storage, for the destinations opting in (verifyLoadFiles).
+// It runs before the remote schema is created, so that nothing is created in the warehouse for load files which can't be loaded.
+func (job *UploadJob) verifyLoadFiles(ctx context.Context) error {
+	if whutils.GetConfigValueBoolString(whutils.VerifyLoadFiles, job.warehouse) != "true" {
+		return nil
+	}
+	if err := downloader.NewDownloader(&job.warehouse, job, job.config.numWorkersVerifyLoadFiles).Verify(ctx, ""); err != nil {
+		return fmt.Errorf("verifying load files: %w", err)
+	}
+	return nil
+}
+
 func (job *UploadJob) GetSampleLoadFileLocation(ctx context.Context, tableName string) (location string, err error) {
 	locations, err := job.GetLoadFilesMetadata(ctx, whutils.GetLoadFilesOptions{Table: tableName, Limit: 1})
 	if err != nil {
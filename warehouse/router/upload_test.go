@@ -4,23 +4,28 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/ory/dockertest/v3"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 
 	"github.com/rudderlabs/rudder-go-kit/config"
+	"github.com/rudderlabs/rudder-go-kit/filemanager"
 	"github.com/rudderlabs/rudder-go-kit/logger"
 	"github.com/rudderlabs/rudder-go-kit/stats"
 	"github.com/rudderlabs/rudder-go-kit/stats/memstats"
 	"github.com/rudderlabs/rudder-go-kit/testhelper/docker/resource"
 	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
 	"github.com/rudderlabs/rudder-server/services/alerta"
+	"github.com/rudderlabs/rudder-server/testhelper/destination"
 	sqlmiddleware "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
 	"github.com/rudderlabs/rudder-server/warehouse/integrations/redshift"
 	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/service/loadfiles/downloader"
 	"github.com/rudderlabs/rudder-server/warehouse/schema"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
 )
@@ -693,3 +698,121 @@ func TestUploadJob_CanAppend(t *testing.T) {
 		})
 	}
 }
+
+func TestUploadJob_VerifyLoadFiles(t *testing.T) {
+	db := setupUploadTest(t, "testdata/sql/upload_test.sql")
+
+	pool, err := dockertest.NewPool("")
+	require.NoError(t, err)
+
+	minioResource, err := destination.SetupMINIO(pool, t)
+	require.NoError(t, err)
+
+	storageConfig := map[string]any{
+		"bucketName":       minioResource.BucketName,
+		"accessKeyID":      minioResource.AccessKey,
+		"secretAccessKey":  minioResource.SecretKey,
+		"endPoint":         minioResource.Endpoint,
+		"forcePathStyle":   true,
+		"s3ForcePathStyle": true,
+		"disableSSL":       true,
+		"region":           minioResource.SiteRegion,
+		"enableSSE":        false,
+		"bucketProvider":   warehouseutils.MINIO,
+	}
+
+	fm, err := filemanager.New(&filemanager.Settings{
+		Provider: warehouseutils.MINIO,
+		Config:   storageConfig,
+	})
+	require.NoError(t, err)
+
+	f, err := os.CreateTemp(t.TempDir(), "load_file.csv.gz")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	f, err = os.Open(f.Name())
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+
+	uploadedFile, err := fm.Upload(context.Background(), f, "verify-load-files")
+	require.NoError(t, err)
+
+	missingLocation := strings.Replace(uploadedFile.Location, "verify-load-files", "verify-load-files-missing", 1)
+
+	_, err = db.Exec(`
+		INSERT INTO wh_load_files (id, staging_file_id, location, source_id, destination_id, destination_type, table_name, total_events, created_at, metadata)
+		VALUES
+		  (100, 100, $1, 'test-sourceID', 'test-destinationID', 'POSTGRES', 'test-table', 1, NOW(), '{}'),
+		  (101, 101, $2, 'test-sourceID', 'test-destinationID', 'POSTGRES', 'test-table', 1, NOW(), '{}');
+	`, uploadedFile.Location, missingLocation)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name            string
+		verifyLoadFiles bool
+		stagingFiles    []*model.StagingFile
+		wantErr         error
+	}{
+		{
+			name:         "disabled with missing load files",
+			stagingFiles: []*model.StagingFile{{ID: 100}, {ID: 101}},
+		},
+		{
+			name:            "enabled with existing load files",
+			verifyLoadFiles: true,
+			stagingFiles:    []*model.StagingFile{{ID: 100}},
+		},
+		{
+			name:            "enabled with missing load files",
+			verifyLoadFiles: true,
+			stagingFiles:    []*model.StagingFile{{ID: 100}, {ID: 101}},
+			wantErr:         downloader.ErrLoadFileNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			destinationConfig := lo.Assign(storageConfig)
+			if tc.verifyLoadFiles {
+				destinationConfig[warehouseutils.VerifyLoadFiles] = true
+			}
+
+			ujf := &UploadJobFactory{
+				conf:         config.New(),
+				logger:       logger.NOP,
+				statsFactory: stats.Default,
+				db:           sqlmiddleware.New(db),
+			}
+			job := ujf.NewUploadJob(context.Background(), &model.UploadJob{
+				Upload: model.Upload{
+					ID:              1,
+					DestinationID:   "test-destinationID",
+					DestinationType: warehouseutils.POSTGRES,
+					SourceID:        "test-sourceID",
+				},
+				Warehouse: model.Warehouse{
+					Type: warehouseutils.POSTGRES,
+					Destination: backendconfig.DestinationT{
+						ID:     "test-destinationID",
+						Config: destinationConfig,
+					},
+					Source: backendconfig.SourceT{
+						ID: "test-sourceID",
+					},
+				},
+				StagingFiles: tc.stagingFiles,
+			}, nil)
+
+			err := job.verifyLoadFiles(context.Background())
+			if tc.wantErr != nil {
+				require.ErrorIs(t, err, tc.wantErr)
+				require.ErrorContains(t, err, "verify-load-files-missing")
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package schema
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rudderlabs/rudder-go-kit/stats"
+)
+
+// introspectionWaitTimeStat is the time spent waiting for the schema introspections of other warehouses to complete,
+// before the schema of a warehouse can be fetched
+const introspectionWaitTimeStat = "warehouse_schema_introspection_wait_time"
+
+var (
+	// introspections limits the concurrent schema introspections, across all the warehouses
+	introspections introspectionLimiter
+	// introspectionStats returns the stats the time spent waiting for the schema introspections is reported with
+	introspectionStats = func() stats.Stats { return stats.Default }
+)
+
+// introspectionLimiter is a semaphore shared by the uploads of all the warehouses, so that a burst of uploads
+// (e.g. at startup) doesn't introspect the schemas of a shared destination all at once
+type introspectionLimiter struct {
+	mu  sync.Mutex
+	sem chan struct{}
+}
+
+// semaphore returns the semaphore allowing limit concurrent schema introspections.
+// The semaphore is replaced if the limit changed, the introspections already running releasing the previous one.
+func (l *introspectionLimiter) semaphore(limit int) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.sem == nil || cap(l.sem) != limit {
+		l.sem = make(chan struct{}, limit)
+	}
+	return l.sem
+}
+
+// acquireIntrospection waits for the schema of the warehouse to be allowed to be introspected, returning the function releasing it.
+// Introspections are unlimited if Warehouse.maxConcurrentSchemaIntrospections isn't positive.
+func (sh *Schema) acquireIntrospection(ctx context.Context) (func(), error) {
+	if sh.maxConcurrentIntrospections <= 0 {
+		return func() {}, nil
+	}
+
+	sem := introspections.semaphore(sh.maxConcurrentIntrospections)
+
+	start := time.Now()
+	defer func() {
+		introspectionStats().NewTaggedStat(introspectionWaitTimeStat, stats.TimerType, stats.Tags{
+			"workspaceId": sh.warehouse.WorkspaceID,
+			"destType":    sh.warehouse.Destination.DestinationDefinition.Name,
+			"destID":      sh.warehouse.Destination.ID,
+		}).Since(start)
+	}()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -0,0 +1,110 @@
+package schema
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/logger"
+	"github.com/rudderlabs/rudder-go-kit/stats"
+	"github.com/rudderlabs/rudder-go-kit/stats/memstats"
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// blockingFetchSchemaRepo fetches an empty schema once released, counting the concurrent fetches
+type blockingFetchSchemaRepo struct {
+	release       chan struct{}
+	running       atomic.Int64
+	maxConcurrent atomic.Int64
+}
+
+func (m *blockingFetchSchemaRepo) FetchSchema(context.Context) (model.Schema, model.Schema, error) {
+	running := m.running.Add(1)
+	defer m.running.Add(-1)
+	for {
+		maxConcurrent := m.maxConcurrent.Load()
+		if running <= maxConcurrent || m.maxConcurrent.CompareAndSwap(maxConcurrent, running) {
+			break
+		}
+	}
+	<-m.release
+	return model.Schema{}, model.Schema{}, nil
+}
+
+func TestSchema_FetchSchemaFromWarehouseIntrospectionLimit(t *testing.T) {
+	statsStore := memstats.New()
+	introspectionStats = func() stats.Stats { return statsStore }
+	t.Cleanup(func() { introspectionStats = func() stats.Stats { return stats.Default } })
+
+	newSchema := func(maxConcurrentIntrospections int) *Schema {
+		return &Schema{
+			warehouse: model.Warehouse{
+				WorkspaceID: "test-workspace-id",
+				Destination: backendconfig.DestinationT{
+					ID: "test_destination_id",
+					DestinationDefinition: backendconfig.DestinationDefinitionT{
+						Name: warehouseutils.RS,
+					},
+				},
+			},
+			log:                         logger.NOP,
+			maxConcurrentIntrospections: maxConcurrentIntrospections,
+		}
+	}
+	// fetchAll fetches the schema of count warehouses at once, releasing the fetches once running of them are running
+	fetchAll := func(maxConcurrentIntrospections, count, running int) *blockingFetchSchemaRepo {
+		repo := &blockingFetchSchemaRepo{release: make(chan struct{})}
+
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, newSchema(maxConcurrentIntrospections).FetchSchemaFromWarehouse(context.Background(), repo))
+			}()
+		}
+		require.Eventually(t, func() bool {
+			return repo.running.Load() == int64(running)
+		}, time.Second, time.Millisecond)
+		close(repo.release)
+		wg.Wait()
+		return repo
+	}
+
+	t.Run("limited", func(t *testing.T) {
+		repo := fetchAll(2, 5, 2)
+		require.EqualValues(t, 2, repo.maxConcurrent.Load())
+
+		waitTimes := statsStore.Get(introspectionWaitTimeStat, stats.Tags{
+			"workspaceId": "test-workspace-id",
+			"destType":    warehouseutils.RS,
+			"destID":      "test_destination_id",
+		})
+		require.NotNil(t, waitTimes)
+		require.NotEmpty(t, waitTimes.Durations())
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		repo := fetchAll(0, 5, 5)
+		require.EqualValues(t, 5, repo.maxConcurrent.Load())
+	})
+
+	t.Run("cancelled while waiting", func(t *testing.T) {
+		repo := &blockingFetchSchemaRepo{release: make(chan struct{})}
+		t.Cleanup(func() { close(repo.release) })
+
+		go func() { _ = newSchema(1).FetchSchemaFromWarehouse(context.Background(), repo) }()
+		require.Eventually(t, func() bool { return repo.running.Load() == 1 }, time.Second, time.Millisecond)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := newSchema(1).FetchSchemaFromWarehouse(ctx, repo)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -47,6 +47,8 @@ type Schema struct {
 	stagingFilesSchemaPaginationSize int
 	skipDeepEqualSchemas             bool
 	enableIDResolution               bool
+	discardsExtraColumns             []string
+	maxConcurrentIntrospections      int
 
 	localSchema                     model.Schema
 	localSchemaMu                   sync.RWMutex
@@ -70,6 +72,8 @@ func New(
 		stagingFilesSchemaPaginationSize: conf.GetInt("Warehouse.stagingFilesSchemaPaginationSize", 100),
 		skipDeepEqualSchemas:             conf.GetBool("Warehouse.skipDeepEqualSchemas", false),
 		enableIDResolution:               conf.GetBool("Warehouse.enableIDResolution", false),
+		discardsExtraColumns:             conf.GetStringSlice("Warehouse.discardsExtraColumns", nil),
+		maxConcurrentIntrospections:      conf.GetInt("Warehouse.maxConcurrentSchemaIntrospections", 0),
 	}
 }
 
@@ -97,7 +101,7 @@ func (sh *Schema) ConsolidateStagingFilesUsingLocalSchema(ctx context.Context, s
 	consolidatedSchema = overrideUsersWithIdentifiesSchema(consolidatedSchema, sh.warehouse.Type, sh.localSchema)
 	sh.localSchemaMu.RUnlock()
 
-	consolidatedSchema = enhanceDiscardsSchema(consolidatedSchema, sh.warehouse.Type)
+	consolidatedSchema = enhanceDiscardsSchema(consolidatedSchema, sh.warehouse.Type, sh.discardsExtraColumns)
 	consolidatedSchema = enhanceSchemaWithIDResolution(consolidatedSchema, sh.isIDResolutionEnabled(), sh.warehouse.Type)
 
 	return consolidatedSchema, nil
@@ -188,12 +192,18 @@ func overrideUsersWithIdentifiesSchema(consolidatedSchema model.Schema, warehous
 
 // enhanceDiscardsSchema adds the discards table to the schema
 // For bq, adds the loaded_at column to be segment compatible
-func enhanceDiscardsSchema(consolidatedSchema model.Schema, warehouseType string) model.Schema {
+// Extends the discards table with the extra columns configured, ignoring the unknown ones
+func enhanceDiscardsSchema(consolidatedSchema model.Schema, warehouseType string, extraColumns []string) model.Schema {
 	discards := model.TableSchema{}
 
 	for colName, colType := range whutils.DiscardsSchema {
 		discards[whutils.ToProviderCase(warehouseType, colName)] = colType
 	}
+	for _, colName := range extraColumns {
+		if colType, ok := whutils.DiscardsExtraColumns[colName]; ok {
+			discards[whutils.ToProviderCase(warehouseType, colName)] = colType
+		}
+	}
 
 	if warehouseType == whutils.BQ {
 		discards[whutils.ToProviderCase(warehouseType, "loaded_at")] = "datetime"
@@ -318,11 +328,17 @@ func (sh *Schema) GetLocalSchema(ctx context.Context) (model.Schema, error) {
 }
 
 // FetchSchemaFromWarehouse
-// 1. Fetches schema from warehouse
-// 2. Removes deprecated columns from schema
-// 3. Updates local warehouse schema and unrecognized schema instance
+// 1. Waits for the schema introspections of other warehouses, if they are limited
+// 2. Fetches schema from warehouse
+// 3. Removes deprecated columns from schema
+// 4. Updates local warehouse schema and unrecognized schema instance
 func (sh *Schema) FetchSchemaFromWarehouse(ctx context.Context, repo fetchSchemaRepo) error {
+	release, err := sh.acquireIntrospection(ctx)
+	if err != nil {
+		return fmt.Errorf("waiting for schema introspection: %w", err)
+	}
 	warehouseSchema, unrecognizedWarehouseSchema, err := repo.FetchSchema(ctx)
+	release()
 	if err != nil {
 		return fmt.Errorf("fetching schema: %w", err)
 	}
@@ -681,14 +681,15 @@ func TestSchema_ConsolidateStagingFilesUsingLocalSchema(t *testing.T) {
 	ctx := context.Background()
 
 	testsCases := []struct {
-		name                string
-		warehouseType       string
-		warehouseSchema     model.Schema
-		mockSchemas         []model.Schema
-		mockErr             error
-		expectedSchema      model.Schema
-		wantError           error
-		idResolutionEnabled bool
+		name                 string
+		warehouseType        string
+		warehouseSchema      model.Schema
+		mockSchemas          []model.Schema
+		mockErr              error
+		expectedSchema       model.Schema
+		wantError            error
+		idResolutionEnabled  bool
+		discardsExtraColumns []string
 	}{
 		{
 			name:          "error fetching staging schema",
@@ -697,6 +698,25 @@ func TestSchema_ConsolidateStagingFilesUsingLocalSchema(t *testing.T) {
 			mockErr:       errors.New("test error"),
 			wantError:     errors.New("getting staging files schema: test error"),
 		},
+		{
+			name:                 "discards extra columns",
+			warehouseType:        warehouseutils.RS,
+			mockSchemas:          []model.Schema{},
+			discardsExtraColumns: []string{"is_null", "reason", "source_id", "unknown"},
+			expectedSchema: model.Schema{
+				"rudder_discards": model.TableSchema{
+					"column_name":  "string",
+					"column_value": "string",
+					"is_null":      "boolean",
+					"reason":       "string",
+					"received_at":  "datetime",
+					"row_id":       "string",
+					"source_id":    "string",
+					"table_name":   "string",
+					"uuid_ts":      "datetime",
+				},
+			},
+		},
 
 		{
 			name:          "discards schema for bigquery",
@@ -1685,6 +1705,7 @@ func TestSchema_ConsolidateStagingFilesUsingLocalSchema(t *testing.T) {
 				log:                              logger.NOP,
 				stagingFileRepo:                  mockRepo,
 				enableIDResolution:               tc.idResolutionEnabled,
+				discardsExtraColumns:             tc.discardsExtraColumns,
 				localSchema:                      tc.warehouseSchema,
 				stagingFilesSchemaPaginationSize: 2,
 			}
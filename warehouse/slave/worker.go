@@ -476,6 +476,14 @@ func (w *worker) runAsyncJob(ctx context.Context, asyncjob jobs.AsyncJobPayload)
 		return result, err
 	}
 
+	if asyncjob.AsyncJobType == jobs.AsyncJobTypeDeleteByJobRunID && !manager.CapabilitiesOf(integrationsManager).DeleteByJobs {
+		w.log.Infof("Skipping async job:%v for table:%s, deleting by job run id is not supported by destination:%s",
+			asyncjob.Id, asyncjob.TableName, warehouse.Destination.ID,
+		)
+		result.Result = true
+		return result, nil
+	}
+
 	integrationsManager.SetConnectionTimeout(warehouseutils.GetConnectionTimeout(
 		warehouse.Destination.DestinationDefinition.Name,
 		warehouse.Destination.ID,
@@ -493,7 +501,7 @@ func (w *worker) runAsyncJob(ctx context.Context, asyncjob jobs.AsyncJobPayload)
 	}
 
 	switch asyncjob.AsyncJobType {
-	case "deletebyjobrunid":
+	case jobs.AsyncJobTypeDeleteByJobRunID:
 		err = integrationsManager.DeleteBy(ctx, []string{asyncjob.TableName}, warehouseutils.DeleteByParams{
 			SourceId:  asyncjob.SourceID,
 			TaskRunId: metadata.TaskRunId,
@@ -451,6 +451,12 @@ func (jr *jobRun) cleanup() {
 	}
 }
 
+// hasDiscardsColumn returns true if the discards table schema of the upload has the column e.g. one of the DiscardsExtraColumns
+func (jr *jobRun) hasDiscardsColumn(columnName string) bool {
+	_, ok := jr.job.UploadSchema[jr.job.discardsTable()][jr.job.columnName(columnName)]
+	return ok
+}
+
 func (jr *jobRun) handleDiscardTypes(tableName, columnName string, columnVal interface{}, columnData types.Data, violatedConstraints *constraints.Violation, discardWriter encoding.LoadFileWriter) error {
 	rowID, hasID := columnData[jr.job.columnName("id")]
 	receivedAt, hasReceivedAt := columnData[jr.job.columnName("received_at")]
@@ -467,10 +473,25 @@ func (jr *jobRun) handleDiscardTypes(tableName, columnName string, columnVal int
 	}
 	if hasID && hasReceivedAt {
 		eventLoader := jr.encodingFactory.NewEventLoader(discardWriter, jr.job.LoadFileType, jr.job.DestinationType)
+		reason := warehouseutils.DiscardReasonDataTypeMismatch
+		if violatedConstraints.IsViolated {
+			reason = warehouseutils.DiscardReasonConstraintViolation
+		}
+
+		// columns are added in sorted order, the same as the one of the discards table schema (needed in case of csv load file)
 		eventLoader.AddColumn("column_name", warehouseutils.DiscardsSchema["column_name"], columnName)
 		eventLoader.AddColumn("column_value", warehouseutils.DiscardsSchema["column_value"], fmt.Sprintf("%v", columnVal))
+		if jr.hasDiscardsColumn("is_null") {
+			eventLoader.AddColumn("is_null", warehouseutils.DiscardsExtraColumns["is_null"], columnVal == nil)
+		}
+		if jr.hasDiscardsColumn("reason") {
+			eventLoader.AddColumn("reason", warehouseutils.DiscardsExtraColumns["reason"], reason)
+		}
 		eventLoader.AddColumn("received_at", warehouseutils.DiscardsSchema["received_at"], receivedAt)
 		eventLoader.AddColumn("row_id", warehouseutils.DiscardsSchema["row_id"], rowID)
+		if jr.hasDiscardsColumn("source_id") {
+			eventLoader.AddColumn("source_id", warehouseutils.DiscardsExtraColumns["source_id"], jr.job.SourceID)
+		}
 		eventLoader.AddColumn("table_name", warehouseutils.DiscardsSchema["table_name"], tableName)
 
 		if eventLoader.IsLoadTimeColumn("uuid_ts") {
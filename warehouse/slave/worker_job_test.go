@@ -399,6 +399,73 @@ func TestSlaveJob(t *testing.T) {
 		})
 	})
 
+	t.Run("discards with extra columns", func(t *testing.T) {
+		discardWriter := &mockLoadFileWriter{}
+
+		p := payload{
+			SourceID:        "test_source_id",
+			DestinationType: warehouseutils.RS,
+			LoadFileType:    warehouseutils.LoadFileTypeCsv,
+			UploadSchema: model.Schema{
+				warehouseutils.DiscardsTable: model.TableSchema{
+					"column_name":  "string",
+					"column_value": "string",
+					"is_null":      "boolean",
+					"reason":       "string",
+					"received_at":  "datetime",
+					"row_id":       "string",
+					"source_id":    "string",
+					"table_name":   "string",
+					"uuid_ts":      "datetime",
+				},
+			},
+		}
+
+		now := time.Date(2020, 4, 27, 20, 0, 0, 0, time.UTC)
+
+		jr := newJobRun(p, config.Default, logger.NOP, stats.Default, encoding.NewFactory(config.Default))
+		jr.uuidTS = now
+		jr.now = func() time.Time {
+			return now
+		}
+
+		err = jr.handleDiscardTypes("test_table", "loaded_at", "test_discard_column",
+			map[string]interface{}{
+				"id":          "test_id",
+				"received_at": now,
+			},
+			&constraints.Violation{},
+			discardWriter,
+		)
+		require.NoError(t, err)
+
+		err = jr.handleDiscardTypes("test_table", "loaded_at", "test_constrains",
+			map[string]interface{}{},
+			&constraints.Violation{
+				IsViolated:         true,
+				ViolatedIdentifier: "test_violated_identifier",
+			},
+			discardWriter,
+		)
+		require.NoError(t, err)
+
+		err = jr.handleDiscardTypes("test_table", "loaded_at", nil,
+			map[string]interface{}{
+				"id":          "test_id",
+				"received_at": now,
+			},
+			&constraints.Violation{},
+			discardWriter,
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, discardWriter.data, []string{
+			"loaded_at,test_discard_column,false,data type mismatch,2020-04-27 20:00:00 +0000 UTC,test_id,test_source_id,test_table,2020-04-27T20:00:00.000Z",
+			"loaded_at,test_constrains,false,constraint violation,2020-04-27T20:00:00.000Z,test_violated_identifier,test_source_id,test_table,2020-04-27T20:00:00.000Z",
+			"loaded_at,<nil>,true,data type mismatch,2020-04-27 20:00:00 +0000 UTC,test_id,test_source_id,test_table,2020-04-27T20:00:00.000Z",
+		})
+	})
+
 	t.Run("upload load files", func(t *testing.T) {
 		ctxCancel, cancel := context.WithCancel(context.Background())
 		cancel()
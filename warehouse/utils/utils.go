@@ -16,6 +16,7 @@ import (
 	"sort"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/samber/lo"
 
@@ -62,12 +63,13 @@ const (
 
 // warehouse table names
 const (
-	WarehouseStagingFilesTable = "wh_staging_files"
-	WarehouseLoadFilesTable    = "wh_load_files"
-	WarehouseUploadsTable      = "wh_uploads"
-	WarehouseTableUploadsTable = "wh_table_uploads"
-	WarehouseSchemasTable      = "wh_schemas"
-	WarehouseAsyncJobTable     = "wh_async_jobs"
+	WarehouseStagingFilesTable   = "wh_staging_files"
+	WarehouseLoadFilesTable      = "wh_load_files"
+	WarehouseUploadsTable        = "wh_uploads"
+	WarehouseTableUploadsTable   = "wh_table_uploads"
+	WarehouseSchemasTable        = "wh_schemas"
+	WarehouseAsyncJobTable       = "wh_async_jobs"
+	WarehouseAsyncJobPausesTable = "wh_async_job_pauses"
 )
 
 const (
@@ -79,6 +81,12 @@ const (
 	ExcludeWindow           = "excludeWindow"
 	ExcludeWindowStartTime  = "excludeWindowStartTime"
 	ExcludeWindowEndTime    = "excludeWindowEndTime"
+	SyncDaysOfWeek          = "syncDaysOfWeek"
+	SyncTimezone            = "syncTimezone"
+	DisableSync             = "disableSync"
+	MinUploadInterval       = "minUploadInterval"
+	VerifyLoadFiles         = "verifyLoadFiles"
+	StorageEgressDataSource = "storageEgressDataSource"
 )
 
 const (
@@ -183,6 +191,21 @@ var DiscardsSchema = map[string]string{
 	"uuid_ts":      "datetime",
 }
 
+// DiscardsExtraColumns are the optional columns which can be added to the discards table, on top of DiscardsSchema.
+// Once present in the discards table schema, they are populated while routing the bad values to the discards table.
+// is_null tells apart the NULL values from the empty ones, column_value being a string either way.
+var DiscardsExtraColumns = map[string]string{
+	"is_null":   "boolean",
+	"reason":    "string",
+	"source_id": "string",
+}
+
+const (
+	DiscardReasonDataTypeMismatch    = "data type mismatch"
+	DiscardReasonConstraintViolation = "constraint violation"
+	DiscardReasonColumnCountMismatch = "column count mismatch"
+)
+
 const (
 	LoadFileTypeCsv     = "csv"
 	LoadFileTypeJson    = "json"
@@ -548,6 +571,46 @@ func ToSafeNamespace(provider, name string) string {
 	return misc.TruncateStr(namespace, 127)
 }
 
+// namespaceLengthLimits are the maximum lengths of a namespace (schema, dataset or database name) supported by the warehouses
+var namespaceLengthLimits = map[string]int{
+	RS:           127,
+	BQ:           1024,
+	SNOWFLAKE:    255,
+	POSTGRES:     63,
+	MSSQL:        128,
+	AzureSynapse: 128,
+	DELTALAKE:    255,
+}
+
+const defaultNamespaceLengthLimit = 127
+
+// namespaceTruncatingProviders are the warehouses truncating the namespaces exceeding their length limit, instead of rejecting them.
+// Their namespaces have always been used truncated, hence aren't rejected for their length.
+var namespaceTruncatingProviders = []string{POSTGRES}
+
+var namespaceRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidateNamespace validates that the namespace is a legal identifier for the warehouse, within the length supported by it (unless the warehouse truncates it).
+// Namespaces are expected to be converted using ToSafeNamespace, except for ClickHouse where the configured database is used as is.
+func ValidateNamespace(provider, namespace string) error {
+	if strings.TrimSpace(namespace) == "" {
+		return errors.New("namespace is empty")
+	}
+
+	limit, ok := namespaceLengthLimits[provider]
+	if !ok {
+		limit = defaultNamespaceLengthLimit
+	}
+	if length := utf8.RuneCountInString(namespace); length > limit && !lo.Contains(namespaceTruncatingProviders, provider) {
+		return fmt.Errorf("namespace %q is %d characters long, exceeding the limit of %d characters for %s", namespace, length, limit, provider)
+	}
+
+	if provider != CLICKHOUSE && !namespaceRegex.MatchString(namespace) {
+		return fmt.Errorf("namespace %q should only contain letters, digits and underscores and should not start with a digit", namespace)
+	}
+	return nil
+}
+
 /*
 ToProviderCase converts string provided to case generally accepted in the warehouse for table, column, schema names etc.
 e.g. columns are uppercase in SNOWFLAKE and lowercase etc. in REDSHIFT, BIGQUERY etc
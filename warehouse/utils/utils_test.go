@@ -474,6 +474,40 @@ func TestToSafeNamespace(t *testing.T) {
 	}
 }
 
+func TestValidateNamespace(t *testing.T) {
+	testCases := []struct {
+		name      string
+		provider  string
+		namespace string
+		wantErr   string
+	}{
+		{name: "valid", provider: MSSQL, namespace: "rudder_namespace"},
+		{name: "leading underscore", provider: MSSQL, namespace: "_9_namespace"},
+		{name: "empty", provider: MSSQL, namespace: "", wantErr: "namespace is empty"},
+		{name: "blank", provider: MSSQL, namespace: "  ", wantErr: "namespace is empty"},
+		{name: "leading digit", provider: MSSQL, namespace: "9namespace", wantErr: "should only contain letters, digits and underscores"},
+		{name: "special characters", provider: MSSQL, namespace: "name space", wantErr: "should only contain letters, digits and underscores"},
+		{name: "within limit", provider: MSSQL, namespace: strings.Repeat("a", 128)},
+		{name: "exceeding limit", provider: MSSQL, namespace: strings.Repeat("a", 129), wantErr: "exceeding the limit of 128 characters for MSSQL"},
+		{name: "exceeding postgres limit", provider: POSTGRES, namespace: strings.Repeat("a", 64)},
+		{name: "exceeding postgres limit with special characters", provider: POSTGRES, namespace: strings.Repeat("a", 64) + "-", wantErr: "should only contain letters, digits and underscores"},
+		{name: "exceeding redshift limit", provider: RS, namespace: strings.Repeat("a", 128), wantErr: "exceeding the limit of 127 characters for RS"},
+		{name: "exceeding default limit", provider: S3Datalake, namespace: strings.Repeat("a", 128), wantErr: "exceeding the limit of 127 characters for S3_DATALAKE"},
+		{name: "clickhouse database", provider: CLICKHOUSE, namespace: "rudder-db"},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateNamespace(tc.provider, tc.namespace)
+			if tc.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tc.wantErr)
+		})
+	}
+}
+
 func TestGetObjectLocation(t *testing.T) {
 	inputs := []struct {
 		provider       string
@@ -0,0 +1,63 @@
+package validations
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rudderlabs/rudder-go-kit/filemanager"
+	"github.com/rudderlabs/rudder-go-kit/filemanager/mock_filemanager"
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+type listSession struct {
+	err error
+}
+
+func (s *listSession) Next() ([]*filemanager.FileInfo, error) {
+	return nil, s.err
+}
+
+func TestListFiles(t *testing.T) {
+	dest := &backendconfig.DestinationT{
+		DestinationDefinition: backendconfig.DestinationDefinitionT{
+			Name: warehouseutils.POSTGRES,
+		},
+		Config: map[string]interface{}{
+			"bucketProvider": "MINIO",
+			"bucketName":     "testbucket",
+			"prefix":         "some-prefix",
+		},
+	}
+
+	withFileManager := func(t *testing.T, listErr error) {
+		mockFileManager := mock_filemanager.NewMockFileManager(gomock.NewController(t))
+		mockFileManager.EXPECT().SetTimeout(gomock.Any()).AnyTimes()
+		mockFileManager.EXPECT().Prefix().Return("some-prefix").AnyTimes()
+		mockFileManager.EXPECT().ListFilesWithPrefix(gomock.Any(), "", "some-prefix", int64(1)).Return(&listSession{err: listErr})
+
+		factory := fileManagerFactory
+		t.Cleanup(func() { fileManagerFactory = factory })
+		fileManagerFactory = func(*filemanager.Settings) (filemanager.FileManager, error) {
+			return mockFileManager, nil
+		}
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		withFileManager(t, nil)
+		require.NoError(t, listFiles(context.Background(), dest))
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		deniedErr := errors.New("AccessDenied: Access Denied")
+		withFileManager(t, deniedErr)
+
+		err := listFiles(context.Background(), dest)
+		require.ErrorIs(t, err, deniedErr)
+		require.ErrorContains(t, err, `listing files with prefix "some-prefix", make sure the credentials are allowed to list the files of the bucket`)
+	})
+}
@@ -0,0 +1,98 @@
+package validations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+// permissionDeniedRe matches the errors returned by the warehouses when the user lacks a privilege,
+// e.g. "pq: permission denied for schema" or "CREATE TABLE permission denied in database" and "The INSERT permission was denied on the object"
+var permissionDeniedRe = regexp.MustCompile(`(?i)permission denied|permission was denied|do(es)? not have permission`)
+
+// privilegesGrant renders the statements granting privileges to the user on the database or the namespace of the destination
+type privilegesGrant func(database, namespace, user string) string
+
+// stepPrivileges are the privileges required by the validation steps, by destination type.
+// Only the warehouses granting privileges to users are covered, the others report their permission errors as is.
+var stepPrivileges = map[string]map[string][]privilegesGrant{
+	warehouseutils.POSTGRES: {
+		model.VerifyingCreateSchema:        {pgGrant("CREATE", "DATABASE", onDatabase)},
+		model.VerifyingCreateAndAlterTable: {pgGrant("USAGE, CREATE", "SCHEMA", onNamespace)},
+		model.VerifyingFetchSchema:         {pgGrant("USAGE", "SCHEMA", onNamespace)},
+		model.VerifyingLoadTable:           {pgGrant("USAGE, CREATE", "SCHEMA", onNamespace), pgGrant("INSERT", "ALL TABLES IN SCHEMA", onNamespace)},
+	},
+	warehouseutils.RS: {
+		model.VerifyingCreateSchema:        {pgGrant("CREATE", "DATABASE", onDatabase)},
+		model.VerifyingCreateAndAlterTable: {pgGrant("USAGE, CREATE", "SCHEMA", onNamespace)},
+		model.VerifyingFetchSchema:         {pgGrant("USAGE", "SCHEMA", onNamespace)},
+		model.VerifyingLoadTable:           {pgGrant("USAGE, CREATE", "SCHEMA", onNamespace), pgGrant("INSERT", "ALL TABLES IN SCHEMA", onNamespace)},
+	},
+	warehouseutils.MSSQL: {
+		model.VerifyingCreateSchema:        {msGrant("CREATE SCHEMA", nil)},
+		model.VerifyingCreateAndAlterTable: {msGrant("CREATE TABLE", nil), msGrant("ALTER", onNamespace)},
+		model.VerifyingFetchSchema:         {msGrant("VIEW DEFINITION", onNamespace)},
+		model.VerifyingLoadTable:           {msGrant("CREATE TABLE", nil), msGrant("ALTER, INSERT", onNamespace)},
+		model.VerifyingStorageEgress:       {msGrant("ADMINISTER DATABASE BULK OPERATIONS", nil)},
+	},
+	warehouseutils.AzureSynapse: {
+		model.VerifyingCreateSchema:        {msGrant("CREATE SCHEMA", nil)},
+		model.VerifyingCreateAndAlterTable: {msGrant("CREATE TABLE", nil), msGrant("ALTER", onNamespace)},
+		model.VerifyingFetchSchema:         {msGrant("VIEW DEFINITION", onNamespace)},
+		model.VerifyingLoadTable:           {msGrant("CREATE TABLE", nil), msGrant("ALTER, INSERT", onNamespace)},
+	},
+}
+
+// onDatabase and onNamespace select the object the privileges are granted on
+func onDatabase(database, _ string) string   { return database }
+func onNamespace(_, namespace string) string { return namespace }
+
+// pgGrant grants the privileges on the object with the Postgres syntax, e.g. GRANT USAGE ON SCHEMA "ns" TO "user";
+func pgGrant(privileges, objectType string, object func(database, namespace string) string) privilegesGrant {
+	quote := func(identifier string) string {
+		return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+	}
+	return func(db, ns, user string) string {
+		return fmt.Sprintf("GRANT %s ON %s %s TO %s;", privileges, objectType, quote(object(db, ns)), quote(user))
+	}
+}
+
+// msGrant grants the privileges with the SQL Server syntax, either on the schema (e.g. GRANT ALTER ON SCHEMA::[ns] TO [user];),
+// or on the database if there is no object (e.g. GRANT CREATE TABLE TO [user];)
+func msGrant(privileges string, object func(database, namespace string) string) privilegesGrant {
+	quote := func(identifier string) string {
+		return "[" + strings.ReplaceAll(identifier, "]", "]]") + "]"
+	}
+	return func(db, ns, user string) string {
+		if object == nil {
+			return fmt.Sprintf("GRANT %s TO %s;", privileges, quote(user))
+		}
+		return fmt.Sprintf("GRANT %s ON SCHEMA::%s TO %s;", privileges, quote(object(db, ns)), quote(user))
+	}
+}
+
+// requiredPrivilegesError returns the error of the step, appending the statements granting the privileges required by the step if the error is a permission one.
+// The error is returned as is otherwise, or if the privileges of the step are unknown for the destination.
+func requiredPrivilegesError(dest *backendconfig.DestinationT, step string, err error) error {
+	if err == nil || !permissionDeniedRe.MatchString(err.Error()) {
+		return err
+	}
+	grants, ok := stepPrivileges[dest.DestinationDefinition.Name][step]
+	if !ok {
+		return err
+	}
+
+	warehouse := createDummyWarehouse(dest)
+	db := warehouseutils.GetConfigValue("database", warehouse)
+	user := warehouseutils.GetConfigValue("user", warehouse)
+
+	statements := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		statements = append(statements, grant(db, warehouse.Namespace, user))
+	}
+	return fmt.Errorf("%w: the required privileges can be granted with: %s", err, strings.Join(statements, " "))
+}
@@ -0,0 +1,104 @@
+package validations
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
+)
+
+func TestRequiredPrivilegesError(t *testing.T) {
+	newDestination := func(destType string) *backendconfig.DestinationT {
+		return &backendconfig.DestinationT{
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: destType,
+			},
+			Config: map[string]interface{}{
+				"database":  "db",
+				"user":      "rudder",
+				"namespace": "test_namespace",
+			},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		destType      string
+		step          string
+		err           error
+		expectedError string
+	}{
+		{
+			name:     "no error",
+			destType: warehouseutils.POSTGRES,
+			step:     model.VerifyingCreateSchema,
+		},
+		{
+			name:          "not a permission error",
+			destType:      warehouseutils.POSTGRES,
+			step:          model.VerifyingCreateSchema,
+			err:           errors.New("pq: relation does not exist"),
+			expectedError: "pq: relation does not exist",
+		},
+		{
+			name:          "postgres create schema",
+			destType:      warehouseutils.POSTGRES,
+			step:          model.VerifyingCreateSchema,
+			err:           errors.New("pq: permission denied for database db"),
+			expectedError: `pq: permission denied for database db: the required privileges can be granted with: GRANT CREATE ON DATABASE "db" TO "rudder";`,
+		},
+		{
+			name:          "redshift create and alter table",
+			destType:      warehouseutils.RS,
+			step:          model.VerifyingCreateAndAlterTable,
+			err:           errors.New("alter table: pq: permission denied for schema test_namespace"),
+			expectedError: `alter table: pq: permission denied for schema test_namespace: the required privileges can be granted with: GRANT USAGE, CREATE ON SCHEMA "test_namespace" TO "rudder";`,
+		},
+		{
+			name:          "mssql create table",
+			destType:      warehouseutils.MSSQL,
+			step:          model.VerifyingCreateAndAlterTable,
+			err:           errors.New("create table: mssql: CREATE TABLE permission denied in database 'db'."),
+			expectedError: `create table: mssql: CREATE TABLE permission denied in database 'db'.: the required privileges can be granted with: GRANT CREATE TABLE TO [rudder]; GRANT ALTER ON SCHEMA::[test_namespace] TO [rudder];`,
+		},
+		{
+			name:          "azure synapse load table",
+			destType:      warehouseutils.AzureSynapse,
+			step:          model.VerifyingLoadTable,
+			err:           errors.New("load test table: mssql: The INSERT permission was denied on the object 'setup_test_staging'"),
+			expectedError: `load test table: mssql: The INSERT permission was denied on the object 'setup_test_staging': the required privileges can be granted with: GRANT CREATE TABLE TO [rudder]; GRANT ALTER, INSERT ON SCHEMA::[test_namespace] TO [rudder];`,
+		},
+		{
+			name:          "mssql storage egress",
+			destType:      warehouseutils.MSSQL,
+			step:          model.VerifyingStorageEgress,
+			err:           errors.New("verify storage egress: mssql: You do not have permission to use the bulk load statement."),
+			expectedError: `verify storage egress: mssql: You do not have permission to use the bulk load statement.: the required privileges can be granted with: GRANT ADMINISTER DATABASE BULK OPERATIONS TO [rudder];`,
+		},
+		{
+			name:          "unknown privileges",
+			destType:      warehouseutils.SNOWFLAKE,
+			step:          model.VerifyingCreateSchema,
+			err:           errors.New("003041 (42710): SQL compilation error: current role has no privileges on it, you do not have permission"),
+			expectedError: "003041 (42710): SQL compilation error: current role has no privileges on it, you do not have permission",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := requiredPrivilegesError(newDestination(tc.destType), tc.step, tc.err)
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tc.expectedError)
+			require.ErrorIs(t, err, tc.err)
+		})
+	}
+}
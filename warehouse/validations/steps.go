@@ -10,7 +10,7 @@ import (
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
 )
 
-func validateStepFunc(_ context.Context, destination *backendconfig.DestinationT, _ string) (json.RawMessage, error) {
+func validateStepFunc(_ context.Context, destination *backendconfig.DestinationT, _ string, _ validateOptions) (json.RawMessage, error) {
 	return json.Marshal(StepsToValidate(destination))
 }
 
@@ -70,8 +70,24 @@ func StepsToValidate(dest *backendconfig.DestinationT) *model.StepsResponse {
 				Name: model.VerifyingLoadTable,
 			},
 		)
+		if verifiesStorageEgress(dest) {
+			steps = append(steps, &model.Step{
+				ID:   len(steps) + 1,
+				Name: model.VerifyingStorageEgress,
+			})
+		}
 	}
 	return &model.StepsResponse{
 		Steps: steps,
 	}
 }
+
+// verifiesStorageEgress reports whether the warehouse of the destination is verified to read from the object storage itself.
+// Only MSSQL supports it for now, once an external data source to read the object storage with is configured.
+func verifiesStorageEgress(dest *backendconfig.DestinationT) bool {
+	if dest.DestinationDefinition.Name != warehouseutils.MSSQL {
+		return false
+	}
+	dataSource, _ := dest.Config[warehouseutils.StorageEgressDataSource].(string)
+	return dataSource != ""
+}
@@ -83,6 +83,42 @@ func TestValidationSteps(t *testing.T) {
 				model.VerifyingLoadTable,
 			},
 		},
+		{
+			name: "MSSQL",
+			dest: backendconfig.DestinationT{
+				DestinationDefinition: backendconfig.DestinationDefinitionT{
+					Name: warehouseutils.MSSQL,
+				},
+			},
+			steps: []string{
+				model.VerifyingObjectStorage,
+				model.VerifyingConnections,
+				model.VerifyingCreateSchema,
+				model.VerifyingCreateAndAlterTable,
+				model.VerifyingFetchSchema,
+				model.VerifyingLoadTable,
+			},
+		},
+		{
+			name: "MSSQL with storage egress",
+			dest: backendconfig.DestinationT{
+				DestinationDefinition: backendconfig.DestinationDefinitionT{
+					Name: warehouseutils.MSSQL,
+				},
+				Config: map[string]interface{}{
+					"storageEgressDataSource": "rudder_storage",
+				},
+			},
+			steps: []string{
+				model.VerifyingObjectStorage,
+				model.VerifyingConnections,
+				model.VerifyingCreateSchema,
+				model.VerifyingCreateAndAlterTable,
+				model.VerifyingFetchSchema,
+				model.VerifyingLoadTable,
+				model.VerifyingStorageEgress,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
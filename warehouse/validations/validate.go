@@ -8,15 +8,20 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"golang.org/x/sync/errgroup"
+
 	"github.com/rudderlabs/rudder-go-kit/config"
 	"github.com/rudderlabs/rudder-go-kit/filemanager"
+	"github.com/rudderlabs/rudder-go-kit/logger"
 	"github.com/rudderlabs/rudder-go-kit/stats"
 	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	"github.com/rudderlabs/rudder-server/warehouse/encoding"
 	"github.com/rudderlabs/rudder-server/warehouse/integrations/manager"
+	sqlmiddleware "github.com/rudderlabs/rudder-server/warehouse/integrations/middleware/sqlquerywrapper"
 	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
 	"github.com/rudderlabs/rudder-server/warehouse/logfield"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
@@ -28,6 +33,7 @@ type Validator interface {
 
 type objectStorage struct {
 	destination *backendconfig.DestinationT
+	probe       *probeObject
 }
 
 type connections struct {
@@ -36,12 +42,14 @@ type connections struct {
 }
 
 type createSchema struct {
-	manager manager.WarehouseOperations
+	manager     manager.WarehouseOperations
+	destination *backendconfig.DestinationT
 }
 
 type createAlterTable struct {
-	manager manager.WarehouseOperations
-	table   string
+	manager     manager.WarehouseOperations
+	destination *backendconfig.DestinationT
+	table       string
 }
 
 type fetchSchema struct {
@@ -53,6 +61,25 @@ type loadTable struct {
 	manager     manager.WarehouseOperations
 	destination *backendconfig.DestinationT
 	table       string
+	progress    func(ProgressPhase)
+	probe       *probeObject
+}
+
+type storageEgress struct {
+	manager     manager.WarehouseOperations
+	destination *backendconfig.DestinationT
+	probe       *probeObject
+}
+
+// readOnlySteps are the validation steps which don't mutate the destination, neither the object storage nor the warehouse
+var readOnlySteps = map[string]struct{}{
+	model.VerifyingConnections: {},
+	model.VerifyingFetchSchema: {},
+}
+
+// readOnlyConnections is implemented by the warehouse integrations which can open their connections in read-only transaction mode
+type readOnlyConnections interface {
+	SetReadOnly(readOnly bool)
 }
 
 type DestinationValidator interface {
@@ -69,11 +96,48 @@ func (*destinationValidationImpl) Validate(ctx context.Context, dest *backendcon
 	return validateDestination(ctx, dest, "")
 }
 
-func validateDestinationFunc(ctx context.Context, dest *backendconfig.DestinationT, stepToValidate string) (json.RawMessage, error) {
-	return json.Marshal(validateDestination(ctx, dest, stepToValidate))
+func validateDestinationFunc(ctx context.Context, dest *backendconfig.DestinationT, stepToValidate string, opts validateOptions) (json.RawMessage, error) {
+	return json.Marshal(validateDestinationWithOptions(ctx, dest, stepToValidate, opts))
+}
+
+// ValidateMany validates the destinations concurrently (up to Warehouse.Validations.ValidateManyParallelism at a time), returning the response of every destination in the same order.
+// Destinations sharing the same object storage configuration reuse the result of a single object storage probe.
+// Once the context is cancelled, the destinations not validated yet are not validated and their responses contain the context error.
+func ValidateMany(ctx context.Context, dests []backendconfig.DestinationT, stepToValidate string) []*model.DestinationValidationResponse {
+	var (
+		responses = make([]*model.DestinationValidationResponse, len(dests))
+		opts      = validateOptions{probes: newObjectStorageProbes()}
+	)
+
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(max(validateManyParallelism, 1))
+
+	for i := range dests {
+		i := i
+		if gCtx.Err() != nil {
+			responses[i] = &model.DestinationValidationResponse{Error: gCtx.Err().Error()}
+			continue
+		}
+		g.Go(func() error {
+			if gCtx.Err() != nil {
+				responses[i] = &model.DestinationValidationResponse{Error: gCtx.Err().Error()}
+				return nil
+			}
+			responses[i] = validateDestinationWithOptions(gCtx, &dests[i], stepToValidate, opts)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return responses
 }
 
 func validateDestination(ctx context.Context, dest *backendconfig.DestinationT, stepToValidate string) *model.DestinationValidationResponse {
+	return validateDestinationWithOptions(ctx, dest, stepToValidate, validateOptions{})
+}
+
+// validateDestinationWithOptions validates the destination, reporting the progress and reusing the object storage probes if provided
+func validateDestinationWithOptions(ctx context.Context, dest *backendconfig.DestinationT, stepToValidate string, opts validateOptions) *model.DestinationValidationResponse {
 	var (
 		destID          = dest.ID
 		destType        = dest.DestinationDefinition.Name
@@ -82,7 +146,13 @@ func validateDestination(ctx context.Context, dest *backendconfig.DestinationT,
 		err             error
 	)
 
-	pkgLogger.Infow("validate destination configuration",
+	log := opts.log()
+
+	// the steps needing an object in the object storage share a single one, deleted once all the steps are validated
+	probe := &probeObject{destination: dest}
+	defer probe.delete(context.WithoutCancel(ctx), log)
+
+	log.Infow("validate destination configuration",
 		logfield.DestinationID, destID,
 		logfield.DestinationType, destType,
 		logfield.DestinationRevisionID, dest.RevisionID,
@@ -114,18 +184,30 @@ func validateDestination(ctx context.Context, dest *backendconfig.DestinationT,
 			}
 		}
 
+		if _, ok := readOnlySteps[vs.Name]; opts.readOnly && !ok {
+			return &model.DestinationValidationResponse{
+				Error: fmt.Sprintf("Step %s is not allowed in read-only mode", vs.Name),
+			}
+		}
+
 		stepsToValidate = append(stepsToValidate, vs)
 	} else {
-		stepsToValidate = append(stepsToValidate, StepsToValidate(dest).Steps...)
+		for _, s := range StepsToValidate(dest).Steps {
+			if _, ok := readOnlySteps[s.Name]; opts.readOnly && !ok {
+				continue
+			}
+			stepsToValidate = append(stepsToValidate, s)
+		}
 	}
 
 	// Iterate over all selected steps and validate
-	for _, step := range stepsToValidate {
-		if validator, err = NewValidator(ctx, step.Name, dest); err != nil {
+	for i, step := range stepsToValidate {
+		if validator, err = newValidator(ctx, step.Name, dest, opts.readOnly); err != nil {
 			err = fmt.Errorf("creating validator: %v", err)
 			step.Error = err.Error()
+			skipSteps(stepsToValidate[i+1:], fmt.Sprintf("%s failed", step.Name))
 
-			pkgLogger.Warnw("creating validator",
+			log.Warnw("creating validator",
 				logfield.DestinationID, destID,
 				logfield.DestinationType, destType,
 				logfield.DestinationRevisionID, dest.RevisionID,
@@ -136,16 +218,61 @@ func validateDestination(ctx context.Context, dest *backendconfig.DestinationT,
 			break
 		}
 
-		if stepError := validator.Validate(ctx); stepError != nil {
+		switch v := validator.(type) {
+		case *loadTable:
+			stepName := step.Name
+			v.progress = func(phase ProgressPhase) {
+				opts.reportProgress(stepName, phase)
+			}
+			v.probe = probe
+		case *objectStorage:
+			v.probe = probe
+		case *storageEgress:
+			v.probe = probe
+		}
+
+		validate := validator.Validate
+		if step.Name == model.VerifyingObjectStorage && opts.probes != nil {
+			validate = func(ctx context.Context) error {
+				return opts.probes.validate(ctx, dest, validator)
+			}
+		}
+
+		var (
+			queriesMu sync.Mutex
+			queries   []string
+		)
+		stepCtx := sqlmiddleware.WithQueryRecorder(ctx, func(query string, _ time.Duration) {
+			queriesMu.Lock()
+			defer queriesMu.Unlock()
+			queries = append(queries, query)
+		})
+
+		stepStart := time.Now()
+		if stepError := validate(stepCtx); stepError != nil {
 			err = stepError
 			step.Error = stepError.Error()
 		} else {
 			step.Success = true
 		}
+		step.Duration = time.Since(stepStart)
+
+		queriesMu.Lock()
+		log.Infow("validated destination configuration step",
+			logfield.DestinationID, destID,
+			logfield.DestinationType, destType,
+			logfield.DestinationRevisionID, dest.RevisionID,
+			logfield.WorkspaceID, dest.WorkspaceID,
+			logfield.DestinationValidationsStep, step.Name,
+			logfield.DestinationValidationsStepDuration, step.Duration,
+			logfield.DestinationValidationsStepSuccess, step.Success,
+			logfield.Query, queries,
+		)
+		queriesMu.Unlock()
 
 		// if any of steps fails, the whole validation fails
 		if !step.Success {
-			pkgLogger.Warnw("not able to validate destination configuration",
+			log.Warnw("not able to validate destination configuration",
 				logfield.DestinationID, destID,
 				logfield.DestinationType, destType,
 				logfield.DestinationRevisionID, dest.RevisionID,
@@ -153,6 +280,7 @@ func validateDestination(ctx context.Context, dest *backendconfig.DestinationT,
 				logfield.DestinationValidationsStep, step.Name,
 				logfield.Error, step.Error,
 			)
+			skipSteps(stepsToValidate[i+1:], fmt.Sprintf("%s failed", step.Name))
 			break
 		}
 	}
@@ -168,19 +296,79 @@ func validateDestination(ctx context.Context, dest *backendconfig.DestinationT,
 	return res
 }
 
+// skipSteps marks the steps as skipped for the reason, e.g. when a previous step failed
+func skipSteps(steps []*model.Step, reason string) {
+	for _, step := range steps {
+		step.Skipped = true
+		step.SkipReason = reason
+	}
+}
+
+// ValidateAll validates all the steps of the destination, up to the first failing one, returning a result which can be returned as is by APIs.
+// The steps following the failing one are skipped.
+func ValidateAll(ctx context.Context, dest *backendconfig.DestinationT) *model.ValidationResult {
+	return model.NewValidationResult(validateDestination(ctx, dest, ""))
+}
+
+// FetchSchema returns the schema of the namespace of the destination in the warehouse (table -> column -> data type), e.g. to display it.
+// The connection is opened in read-only transaction mode by the warehouses supporting it. Columns with data types unknown to RudderStack are left out.
+func FetchSchema(ctx context.Context, dest *backendconfig.DestinationT) (model.Schema, error) {
+	if err := warehouseutils.ValidateNamespace(dest.DestinationDefinition.Name, configuredNamespaceInDestination(dest)); err != nil {
+		return nil, fmt.Errorf("validating namespace: %w", err)
+	}
+
+	operations, err := createManager(ctx, dest, true)
+	if err != nil {
+		return nil, fmt.Errorf("create manager: %w", err)
+	}
+	defer operations.Cleanup(ctx)
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	schema, _, err := operations.FetchSchema(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fetch schema: %w", err)
+	}
+	return schema, nil
+}
+
+func fetchSchemaFunc(ctx context.Context, dest *backendconfig.DestinationT, _ string, _ validateOptions) (json.RawMessage, error) {
+	schema, err := FetchSchema(ctx, dest)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(schema)
+}
+
 func NewValidator(ctx context.Context, step string, dest *backendconfig.DestinationT) (Validator, error) {
+	return newValidator(ctx, step, dest, false)
+}
+
+// newValidator returns the validator for the step, refusing the steps which mutate the destination in read-only mode
+func newValidator(ctx context.Context, step string, dest *backendconfig.DestinationT, readOnly bool) (Validator, error) {
 	var (
 		operations manager.WarehouseOperations
 		err        error
 	)
 
+	if _, ok := readOnlySteps[step]; readOnly && !ok {
+		return nil, fmt.Errorf("step %s is not allowed in read-only mode", step)
+	}
+
+	if step != model.VerifyingObjectStorage {
+		if err = warehouseutils.ValidateNamespace(dest.DestinationDefinition.Name, configuredNamespaceInDestination(dest)); err != nil {
+			return nil, fmt.Errorf("validating namespace: %w", err)
+		}
+	}
+
 	switch step {
 	case model.VerifyingObjectStorage:
 		return &objectStorage{
 			destination: dest,
 		}, nil
 	case model.VerifyingConnections:
-		if operations, err = createManager(ctx, dest); err != nil {
+		if operations, err = createManager(ctx, dest, readOnly); err != nil {
 			return nil, fmt.Errorf("create manager: %w", err)
 		}
 		return &connections{
@@ -188,22 +376,24 @@ func NewValidator(ctx context.Context, step string, dest *backendconfig.Destinat
 			manager:     operations,
 		}, nil
 	case model.VerifyingCreateSchema:
-		if operations, err = createManager(ctx, dest); err != nil {
+		if operations, err = createManager(ctx, dest, readOnly); err != nil {
 			return nil, fmt.Errorf("create manager: %w", err)
 		}
 		return &createSchema{
-			manager: operations,
+			destination: dest,
+			manager:     operations,
 		}, nil
 	case model.VerifyingCreateAndAlterTable:
-		if operations, err = createManager(ctx, dest); err != nil {
+		if operations, err = createManager(ctx, dest, readOnly); err != nil {
 			return nil, fmt.Errorf("create manager: %w", err)
 		}
 		return &createAlterTable{
-			table:   getTable(dest),
-			manager: operations,
+			destination: dest,
+			table:       getTable(dest),
+			manager:     operations,
 		}, nil
 	case model.VerifyingFetchSchema:
-		if operations, err = createManager(ctx, dest); err != nil {
+		if operations, err = createManager(ctx, dest, readOnly); err != nil {
 			return nil, fmt.Errorf("create manager: %w", err)
 		}
 		return &fetchSchema{
@@ -211,7 +401,7 @@ func NewValidator(ctx context.Context, step string, dest *backendconfig.Destinat
 			manager:     operations,
 		}, nil
 	case model.VerifyingLoadTable:
-		if operations, err = createManager(ctx, dest); err != nil {
+		if operations, err = createManager(ctx, dest, readOnly); err != nil {
 			return nil, fmt.Errorf("create manager: %w", err)
 		}
 		return &loadTable{
@@ -219,30 +409,145 @@ func NewValidator(ctx context.Context, step string, dest *backendconfig.Destinat
 			manager:     operations,
 			table:       getTable(dest),
 		}, nil
+	case model.VerifyingStorageEgress:
+		if operations, err = createManager(ctx, dest, readOnly); err != nil {
+			return nil, fmt.Errorf("create manager: %w", err)
+		}
+		return &storageEgress{
+			destination: dest,
+			manager:     operations,
+		}, nil
 	}
 
 	return nil, fmt.Errorf("invalid step: %s", step)
 }
 
-func (os *objectStorage) Validate(ctx context.Context) error {
+// objectStorageProbes shares the object storage validations between the destinations using the same object storage configuration
+type objectStorageProbes struct {
+	mu     sync.Mutex
+	probes map[string]*objectStorageProbe
+}
+
+type objectStorageProbe struct {
+	once sync.Once
+	err  error
+}
+
+func newObjectStorageProbes() *objectStorageProbes {
+	return &objectStorageProbes{
+		probes: make(map[string]*objectStorageProbe),
+	}
+}
+
+// validate runs the validator only once for every object storage configuration
+func (p *objectStorageProbes) validate(ctx context.Context, dest *backendconfig.DestinationT, validator Validator) error {
+	key, err := objectStorageProbeKey(dest)
+	if err != nil {
+		return validator.Validate(ctx)
+	}
+
+	p.mu.Lock()
+	probe, ok := p.probes[key]
+	if !ok {
+		probe = &objectStorageProbe{}
+		p.probes[key] = probe
+	}
+	p.mu.Unlock()
+
+	probe.once.Do(func() {
+		probe.err = validator.Validate(ctx)
+	})
+	return probe.err
+}
+
+// objectStorageProbeKey identifies the object storage configuration of the destination, along with the type of the load files uploaded to it
+func objectStorageProbeKey(dest *backendconfig.DestinationT) (string, error) {
 	var (
-		tempPath     string
-		err          error
-		uploadObject filemanager.UploadedFile
+		destType = dest.DestinationDefinition.Name
+		conf     = dest.Config
+		provider = warehouseutils.ObjectStorageType(destType, conf, misc.IsConfiguredToUseRudderObjectStorage(conf))
 	)
 
-	if tempPath, err = CreateTempLoadFile(os.destination); err != nil {
-		return fmt.Errorf("creating temp load file: %w", err)
+	storageConfig, err := json.Marshal(misc.GetObjectStorageConfig(misc.ObjectStorageOptsT{
+		Provider:         provider,
+		Config:           conf,
+		UseRudderStorage: misc.IsConfiguredToUseRudderObjectStorage(conf),
+		WorkspaceID:      dest.WorkspaceID,
+	}))
+	if err != nil {
+		return "", fmt.Errorf("marshalling object storage config: %w", err)
 	}
+	return provider + ":" + warehouseutils.GetLoadFileType(destType) + ":" + string(storageConfig), nil
+}
+
+// probeObject is the object uploaded to the object storage of the destination by the validation steps needing one.
+// It is uploaded once by the first step needing it and reused by the following steps.
+type probeObject struct {
+	destination *backendconfig.DestinationT
 
-	if uploadObject, err = uploadFile(ctx, os.destination, tempPath); err != nil {
-		return fmt.Errorf("upload file: %w", err)
+	once     sync.Once
+	uploaded filemanager.UploadedFile
+	err      error
+}
+
+// upload uploads the probe object, unless it is already uploaded. A nil probe object uploads a new object every time.
+func (p *probeObject) upload(ctx context.Context, dest *backendconfig.DestinationT) (filemanager.UploadedFile, error) {
+	if p == nil {
+		return uploadProbeObject(ctx, dest)
+	}
+	p.once.Do(func() {
+		p.uploaded, p.err = uploadProbeObject(ctx, p.destination)
+	})
+	return p.uploaded, p.err
+}
+
+// delete deletes the probe object from the object storage, if it was uploaded
+func (p *probeObject) delete(ctx context.Context, log logger.Logger) {
+	p.once.Do(func() {}) // the probe object is never uploaded once deleted
+	if p.err != nil || p.uploaded.ObjectName == "" {
+		return
+	}
+
+	fm, err := createFileManager(p.destination)
+	if err == nil {
+		err = fm.Delete(ctx, []string{p.uploaded.ObjectName})
+	}
+	if err != nil {
+		log.Warnw("deleting probe object",
+			logfield.DestinationID, p.destination.ID,
+			logfield.DestinationType, p.destination.DestinationDefinition.Name,
+			logfield.Error, err.Error(),
+		)
+	}
+}
+
+func uploadProbeObject(ctx context.Context, dest *backendconfig.DestinationT) (filemanager.UploadedFile, error) {
+	tempPath, err := CreateTempLoadFile(dest)
+	if err != nil {
+		return filemanager.UploadedFile{}, fmt.Errorf("creating temp load file: %w", err)
+	}
+
+	uploadObject, err := uploadFile(ctx, dest, tempPath)
+	if err != nil {
+		return filemanager.UploadedFile{}, fmt.Errorf("upload file: %w", err)
+	}
+	return uploadObject, nil
+}
+
+func (os *objectStorage) Validate(ctx context.Context) error {
+	uploadObject, err := os.probe.upload(ctx, os.destination)
+	if err != nil {
+		return err
 	}
 
 	if err = downloadFile(ctx, os.destination, uploadObject.ObjectName); err != nil {
 		return fmt.Errorf("download file: %w", err)
 	}
 
+	if err = listFiles(ctx, os.destination); err != nil {
+		return fmt.Errorf("list files: %w", err)
+	}
+
 	return nil
 }
 
@@ -258,12 +563,16 @@ func (c *connections) Validate(ctx context.Context) error {
 func (cs *createSchema) Validate(ctx context.Context) error {
 	defer cs.manager.Cleanup(ctx)
 
-	return cs.manager.CreateSchema(ctx)
+	return requiredPrivilegesError(cs.destination, model.VerifyingCreateSchema, cs.manager.CreateSchema(ctx))
 }
 
 func (cat *createAlterTable) Validate(ctx context.Context) error {
 	defer cat.manager.Cleanup(ctx)
 
+	return requiredPrivilegesError(cat.destination, model.VerifyingCreateAndAlterTable, cat.createAlterTable(ctx))
+}
+
+func (cat *createAlterTable) createAlterTable(ctx context.Context) error {
 	if err := cat.manager.CreateTable(ctx, cat.table, tableSchemaMap); err != nil {
 		return fmt.Errorf("create table: %w", err)
 	}
@@ -283,41 +592,93 @@ func (fs *fetchSchema) Validate(ctx context.Context) error {
 	defer fs.manager.Cleanup(ctx)
 
 	if _, _, err := fs.manager.FetchSchema(ctx); err != nil {
-		return fmt.Errorf("fetch schema: %w", err)
+		return requiredPrivilegesError(fs.destination, model.VerifyingFetchSchema, fmt.Errorf("fetch schema: %w", err))
 	}
 	return nil
 }
 
 func (lt *loadTable) Validate(ctx context.Context) error {
+	defer lt.manager.Cleanup(ctx)
+
+	return requiredPrivilegesError(lt.destination, model.VerifyingLoadTable, lt.loadTable(ctx))
+}
+
+func (lt *loadTable) loadTable(ctx context.Context) error {
 	var (
 		destinationType = lt.destination.DestinationDefinition.Name
 		loadFileType    = warehouseutils.GetLoadFileType(destinationType)
 
-		tempPath     string
 		uploadOutput filemanager.UploadedFile
 		err          error
 	)
 
-	defer lt.manager.Cleanup(ctx)
-
-	if tempPath, err = CreateTempLoadFile(lt.destination); err != nil {
-		return fmt.Errorf("create temp load file: %w", err)
+	if !manager.CapabilitiesOf(lt.manager).BulkCopy {
+		pkgLogger.Infow("skipping load table validation, loading the load files in bulk is not supported",
+			logfield.DestinationID, lt.destination.ID,
+			logfield.DestinationType, destinationType,
+		)
+		return nil
 	}
 
-	if uploadOutput, err = uploadFile(ctx, lt.destination, tempPath); err != nil {
-		return fmt.Errorf("upload file: %w", err)
+	lt.reportProgress(ProgressUploadStarted)
+
+	if uploadOutput, err = lt.probe.upload(ctx, lt.destination); err != nil {
+		return err
 	}
 
+	lt.reportProgress(ProgressUploadDone)
+
 	if err = lt.manager.CreateTable(ctx, lt.table, tableSchemaMap); err != nil {
 		return fmt.Errorf("create table: %w", err)
 	}
 
+	lt.reportProgress(ProgressCreateTableDone)
+
 	defer func() { _ = lt.manager.DropTable(ctx, lt.table) }()
 
 	if err = lt.manager.LoadTestTable(ctx, uploadOutput.Location, lt.table, payloadMap, loadFileType); err != nil {
 		return fmt.Errorf("load test table: %w", err)
 	}
 
+	lt.reportProgress(ProgressLoadDone)
+
+	return nil
+}
+
+func (lt *loadTable) reportProgress(phase ProgressPhase) {
+	if lt.progress != nil {
+		lt.progress(phase)
+	}
+}
+
+func (se *storageEgress) Validate(ctx context.Context) error {
+	defer se.manager.Cleanup(ctx)
+
+	return requiredPrivilegesError(se.destination, model.VerifyingStorageEgress, se.verifyStorageEgress(ctx))
+}
+
+// verifyStorageEgress makes the warehouse read the probe object from the object storage, as it does when loading from it
+func (se *storageEgress) verifyStorageEgress(ctx context.Context) error {
+	verifier, ok := se.manager.(manager.StorageEgressVerifier)
+	if !ok {
+		pkgLogger.Infow("skipping storage egress validation, reading from the object storage is not supported",
+			logfield.DestinationID, se.destination.ID,
+			logfield.DestinationType, se.destination.DestinationDefinition.Name,
+		)
+		return nil
+	}
+
+	uploadOutput, err := se.probe.upload(ctx, se.destination)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	if err = verifier.VerifyStorageEgress(ctx, uploadOutput.ObjectName); err != nil {
+		return fmt.Errorf("verify storage egress: %w", err)
+	}
 	return nil
 }
 
@@ -447,6 +808,21 @@ func downloadFile(ctx context.Context, dest *backendconfig.DestinationT, locatio
 	return nil
 }
 
+// listFiles lists the files under the configured prefix of the object storage, as needed by e.g. the incremental loads.
+// Listing is a distinct permission from uploading and downloading the files (e.g. s3:ListBucket), hence it is checked on its own.
+func listFiles(ctx context.Context, dest *backendconfig.DestinationT) error {
+	fm, err := createFileManager(dest)
+	if err != nil {
+		return err
+	}
+
+	prefix := fm.Prefix()
+	if _, err = fm.ListFilesWithPrefix(ctx, "", prefix, 1).Next(); err != nil {
+		return fmt.Errorf("listing files with prefix %q, make sure the credentials are allowed to list the files of the bucket: %w", prefix, err)
+	}
+	return nil
+}
+
 func createFileManager(dest *backendconfig.DestinationT) (filemanager.FileManager, error) {
 	var (
 		destType = dest.DestinationDefinition.Name
@@ -472,7 +848,7 @@ func createFileManager(dest *backendconfig.DestinationT) (filemanager.FileManage
 	return fileManager, nil
 }
 
-func createManager(ctx context.Context, dest *backendconfig.DestinationT) (manager.WarehouseOperations, error) {
+func createManager(ctx context.Context, dest *backendconfig.DestinationT, readOnly bool) (manager.WarehouseOperations, error) {
 	var (
 		destType  = dest.DestinationDefinition.Name
 		warehouse = createDummyWarehouse(dest)
@@ -487,6 +863,12 @@ func createManager(ctx context.Context, dest *backendconfig.DestinationT) (manag
 
 	operations.SetConnectionTimeout(queryTimeout)
 
+	if readOnly {
+		if roc, ok := operations.(readOnlyConnections); ok {
+			roc.SetReadOnly(true)
+		}
+	}
+
 	if err = operations.Setup(ctx, warehouse, &dummyUploader{
 		dest: dest,
 	}); err != nil {
@@ -2,8 +2,10 @@ package validations_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/rudderlabs/rudder-go-kit/testhelper/docker/resource"
@@ -221,7 +223,7 @@ func TestValidator(t *testing.T) {
 					"password":  password,
 					"namespace": "test_namespace_with_no_privilege",
 				},
-				wantError: errors.New("pq: permission denied for database jobsdb"),
+				wantError: errors.New(`pq: permission denied for database jobsdb: the required privileges can be granted with: GRANT CREATE ON DATABASE "jobsdb" TO "test_user_with_no_privilege";`),
 			},
 			{
 				name: "with privilege",
@@ -298,7 +300,7 @@ func TestValidator(t *testing.T) {
 					"user":     userWithNoPrivilege,
 					"password": password,
 				},
-				wantError: errors.New("create table: pq: permission denied for schema test_namespace"),
+				wantError: errors.New(`create table: pq: permission denied for schema test_namespace: the required privileges can be granted with: GRANT USAGE, CREATE ON SCHEMA "test_namespace" TO "test_user_with_no_privilege";`),
 			},
 			{
 				name: "create table privilege",
@@ -306,7 +308,7 @@ func TestValidator(t *testing.T) {
 					"user":     userWithCreateTablePrivilege,
 					"password": password,
 				},
-				wantError: errors.New("alter table: pq: permission denied for schema test_namespace"),
+				wantError: errors.New(`alter table: pq: permission denied for schema test_namespace: the required privileges can be granted with: GRANT USAGE, CREATE ON SCHEMA "test_namespace" TO "test_user_with_create_table_privilege";`),
 			},
 			{
 				name: "alter privilege",
@@ -421,6 +423,55 @@ func TestValidator(t *testing.T) {
 		require.NoError(t, v.Validate(ctx))
 	})
 
+	t.Run("FetchSchema", func(t *testing.T) {
+		tr := setup(t, pool)
+		pgResource, minioResource := tr.pgResource, tr.minioResource
+
+		dest := &backendconfig.DestinationT{
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: warehouseutils.POSTGRES,
+			},
+			Config: map[string]interface{}{
+				"host":            pgResource.Host,
+				"port":            pgResource.Port,
+				"database":        pgResource.Database,
+				"user":            pgResource.User,
+				"password":        pgResource.Password,
+				"sslMode":         sslmode,
+				"namespace":       namespace,
+				"bucketProvider":  provider,
+				"bucketName":      minioResource.BucketName,
+				"accessKeyID":     minioResource.AccessKey,
+				"secretAccessKey": minioResource.SecretKey,
+				"endPoint":        minioResource.Endpoint,
+			},
+		}
+
+		_, err = pgResource.DB.Exec(fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", namespace))
+		require.NoError(t, err)
+
+		_, err = pgResource.DB.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s.%s(id bigint, val text, received_at timestamptz)", namespace, table))
+		require.NoError(t, err)
+
+		schema, err := validations.FetchSchema(ctx, dest)
+		require.NoError(t, err)
+		require.Equal(t, model.Schema{
+			table: {
+				"id":          "int",
+				"val":         "string",
+				"received_at": "datetime",
+			},
+		}, schema)
+
+		res, err := validations.Validate(ctx, &model.ValidationRequest{
+			Path:        "schema",
+			Destination: dest,
+		})
+		require.NoError(t, err)
+		require.Empty(t, res.Error)
+		require.JSONEq(t, `{"test_table":{"id":"int","val":"string","received_at":"datetime"}}`, res.Data)
+	})
+
 	t.Run("Load table", func(t *testing.T) {
 		t.Parallel()
 
@@ -451,7 +502,7 @@ func TestValidator(t *testing.T) {
 					"user":     userWithNoPrivilege,
 					"password": password,
 				},
-				wantError: errors.New("create table: pq: permission denied for schema test_namespace"),
+				wantError: errors.New(`create table: pq: permission denied for schema test_namespace: the required privileges can be granted with: GRANT USAGE, CREATE ON SCHEMA "test_namespace" TO "test_user_with_no_privilege"; GRANT INSERT ON ALL TABLES IN SCHEMA "test_namespace" TO "test_user_with_no_privilege";`),
 			},
 			{
 				name: "create table privilege",
@@ -459,7 +510,7 @@ func TestValidator(t *testing.T) {
 					"user":     userWithCreateTablePrivilege,
 					"password": password,
 				},
-				wantError: errors.New("load test table: pq: permission denied for schema test_namespace"),
+				wantError: errors.New(`load test table: pq: permission denied for schema test_namespace: the required privileges can be granted with: GRANT USAGE, CREATE ON SCHEMA "test_namespace" TO "test_user_with_create_table_privilege"; GRANT INSERT ON ALL TABLES IN SCHEMA "test_namespace" TO "test_user_with_create_table_privilege";`),
 			},
 			{
 				name: "insert privilege",
@@ -543,3 +594,123 @@ func TestValidator(t *testing.T) {
 		}
 	})
 }
+
+func TestNewValidator_Namespace(t *testing.T) {
+	misc.Init()
+	warehouseutils.Init()
+	validations.Init()
+
+	t.Run("exceeding the length limit", func(t *testing.T) {
+		// the clickhouse database is used as is for the namespace, without being sanitized nor truncated
+		v, err := validations.NewValidator(context.Background(), model.VerifyingConnections, &backendconfig.DestinationT{
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: warehouseutils.CLICKHOUSE,
+			},
+			Config: map[string]interface{}{
+				"database": strings.Repeat("database", 20),
+			},
+		})
+		require.ErrorContains(t, err, "validating namespace")
+		require.Nil(t, v)
+	})
+	t.Run("exceeding the length limit of a truncating warehouse", func(t *testing.T) {
+		v, err := validations.NewValidator(context.Background(), model.VerifyingConnections, &backendconfig.DestinationT{
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: warehouseutils.POSTGRES,
+			},
+			Config: map[string]interface{}{
+				"namespace": strings.Repeat("namespace", 10),
+			},
+		})
+		require.NoError(t, err, "postgres truncates the namespaces exceeding its length limit")
+		require.NotNil(t, v)
+	})
+	t.Run("object storage does not need a namespace", func(t *testing.T) {
+		v, err := validations.NewValidator(context.Background(), model.VerifyingObjectStorage, &backendconfig.DestinationT{
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: warehouseutils.CLICKHOUSE,
+			},
+			Config: map[string]interface{}{
+				"database": "",
+			},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, v)
+	})
+}
+
+func TestValidateMany(t *testing.T) {
+	misc.Init()
+	warehouseutils.Init()
+	validations.Init()
+
+	dests := []backendconfig.DestinationT{
+		{
+			ID: "test-destination-1",
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: warehouseutils.POSTGRES,
+			},
+		},
+		{
+			ID: "test-destination-2",
+			DestinationDefinition: backendconfig.DestinationDefinitionT{
+				Name: warehouseutils.SNOWFLAKE,
+			},
+		},
+	}
+
+	t.Run("invalid step", func(t *testing.T) {
+		responses := validations.ValidateMany(context.Background(), dests, "1000")
+		require.Len(t, responses, len(dests))
+		for _, response := range responses {
+			require.Equal(t, "Invalid step: 1000", response.Error)
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		responses := validations.ValidateMany(ctx, dests, "")
+		require.Len(t, responses, len(dests))
+		for _, response := range responses {
+			require.Equal(t, context.Canceled.Error(), response.Error)
+		}
+	})
+
+	t.Run("no destinations", func(t *testing.T) {
+		require.Empty(t, validations.ValidateMany(context.Background(), nil, ""))
+	})
+}
+
+func TestValidateAll(t *testing.T) {
+	misc.Init()
+	warehouseutils.Init()
+	validations.Init()
+
+	result := validations.ValidateAll(context.Background(), &backendconfig.DestinationT{
+		DestinationDefinition: backendconfig.DestinationDefinitionT{
+			Name: warehouseutils.POSTGRES,
+		},
+	})
+	require.Equal(t, model.ValidationResultVersion, result.Version)
+	require.False(t, result.Success)
+	require.Equal(t, "upload file: creating file manager: service provider not supported: ", result.Error)
+	require.Len(t, result.Steps, 6)
+
+	require.Equal(t, model.VerifyingObjectStorage, result.Steps[0].Step)
+	require.Equal(t, model.ValidationStepFailed, result.Steps[0].Status)
+	require.Equal(t, result.Error, result.Steps[0].Error)
+	require.False(t, result.Steps[0].Skipped)
+	for _, step := range result.Steps[1:] {
+		require.Equal(t, model.ValidationStepSkipped, step.Status)
+		require.True(t, step.Skipped)
+		require.Equal(t, "Verifying Object Storage failed", step.SkipReason)
+		require.Zero(t, step.DurationMs)
+		require.Empty(t, step.Error)
+	}
+
+	data, err := json.Marshal(result.Steps[1])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"step":"Verifying Connections","status":"skipped","durationMs":0,"error":"","skipped":true,"skipReason":"Verifying Object Storage failed"}`, string(data))
+}
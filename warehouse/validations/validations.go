@@ -27,6 +27,7 @@ var (
 	fileManagerFactory      filemanager.Factory
 	objectStorageTimeout    time.Duration
 	queryTimeout            time.Duration
+	validateManyParallelism int
 )
 
 var (
@@ -44,7 +45,66 @@ var (
 )
 
 type validationFunc struct {
-	Func func(context.Context, *backendconfig.DestinationT, string) (json.RawMessage, error)
+	Func func(context.Context, *backendconfig.DestinationT, string, validateOptions) (json.RawMessage, error)
+}
+
+// ProgressPhase is a sub-phase of a validation step
+type ProgressPhase string
+
+const (
+	ProgressUploadStarted   ProgressPhase = "upload started"
+	ProgressUploadDone      ProgressPhase = "upload done"
+	ProgressCreateTableDone ProgressPhase = "create table done"
+	ProgressLoadDone        ProgressPhase = "load done"
+)
+
+// ProgressFunc is called every time a validation step completes one of its sub-phases
+type ProgressFunc func(step string, phase ProgressPhase)
+
+type validateOptions struct {
+	progress ProgressFunc
+	probes   *objectStorageProbes
+	readOnly bool
+	logger   logger.Logger
+}
+
+// log returns the injected logger, defaulting to the package logger
+func (o validateOptions) log() logger.Logger {
+	if o.logger != nil {
+		return o.logger
+	}
+	return pkgLogger
+}
+
+// reportProgress calls the progress callback, if any
+func (o validateOptions) reportProgress(step string, phase ProgressPhase) {
+	if o.progress != nil {
+		o.progress(step, phase)
+	}
+}
+
+type ValidateOption func(*validateOptions)
+
+// WithProgress reports the progress of the long validation steps (e.g. Verifying Load Table) to the callback
+func WithProgress(progress ProgressFunc) ValidateOption {
+	return func(o *validateOptions) {
+		o.progress = progress
+	}
+}
+
+// WithLogger logs the validation, including the audit log of every step (name, duration, success and the queries run with their secrets redacted), through the logger
+func WithLogger(log logger.Logger) ValidateOption {
+	return func(o *validateOptions) {
+		o.logger = log
+	}
+}
+
+// WithReadOnly restricts the validation to the steps which don't mutate the destination (i.e. Verifying Connections and Verifying Fetch Schema).
+// The connections are opened in read-only transaction mode by the warehouses supporting it. Requesting any other step fails the validation.
+func WithReadOnly() ValidateOption {
+	return func(o *validateOptions) {
+		o.readOnly = true
+	}
 }
 
 func Init() {
@@ -55,18 +115,24 @@ func Init() {
 
 	// Since we have a cp-router default timeout of 30 seconds, keeping the query timeout to 25 seconds
 	queryTimeout = config.GetDuration("Warehouse.Validations.QueryTimeout", 25, time.Second)
+	validateManyParallelism = config.GetInt("Warehouse.Validations.ValidateManyParallelism", 5)
 }
 
 // Validate the destination by running all the validation steps
-func Validate(ctx context.Context, req *model.ValidationRequest) (*model.ValidationResponse, error) {
+func Validate(ctx context.Context, req *model.ValidationRequest, opts ...ValidateOption) (*model.ValidationResponse, error) {
 	res := &model.ValidationResponse{}
 
+	var options validateOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	f, ok := validationFunctions()[req.Path]
 	if !ok {
 		return res, fmt.Errorf("invalid path: %s", req.Path)
 	}
 
-	result, requestError := f.Func(ctx, req.Destination, req.Step)
+	result, requestError := f.Func(ctx, req.Destination, req.Step, options)
 	res.Data = string(result)
 
 	if requestError != nil {
@@ -83,5 +149,8 @@ func validationFunctions() map[string]*validationFunc {
 		"steps": {
 			Func: validateStepFunc,
 		},
+		"schema": {
+			Func: fetchSchemaFunc,
+		},
 	}
 }
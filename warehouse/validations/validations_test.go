@@ -3,14 +3,19 @@ package validations_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
+	"github.com/golang/mock/gomock"
 	"github.com/ory/dockertest/v3"
 	"github.com/stretchr/testify/require"
 
 	backendconfig "github.com/rudderlabs/rudder-server/backend-config"
+	mock_logger "github.com/rudderlabs/rudder-server/mocks/utils/logger"
 	"github.com/rudderlabs/rudder-server/utils/misc"
 	"github.com/rudderlabs/rudder-server/warehouse/internal/model"
+	"github.com/rudderlabs/rudder-server/warehouse/logfield"
 	warehouseutils "github.com/rudderlabs/rudder-server/warehouse/utils"
 	"github.com/rudderlabs/rudder-server/warehouse/validations"
 )
@@ -58,6 +63,22 @@ func TestValidate(t *testing.T) {
 		require.JSONEq(t, res.Data, `{"steps":[{"id":1,"name":"Verifying Object Storage","success":false,"error":""},{"id":2,"name":"Verifying Connections","success":false,"error":""},{"id":3,"name":"Verifying Create Schema","success":false,"error":""},{"id":4,"name":"Verifying Create and Alter Table","success":false,"error":""},{"id":5,"name":"Verifying Fetch Schema","success":false,"error":""},{"id":6,"name":"Verifying Load Table","success":false,"error":""}]}`)
 	})
 
+	t.Run("schema", func(t *testing.T) {
+		t.Parallel()
+
+		res, err := validations.Validate(ctx, &model.ValidationRequest{
+			Path: "schema",
+			Destination: &backendconfig.DestinationT{
+				DestinationDefinition: backendconfig.DestinationDefinitionT{
+					Name: "invalid",
+				},
+			},
+		})
+		require.NoError(t, err)
+		require.Empty(t, res.Data)
+		require.Equal(t, "create manager: getting manager: provider of type invalid is not configured for WarehouseManager", res.Error)
+	})
+
 	t.Run("validate", func(t *testing.T) {
 		t.Parallel()
 
@@ -177,6 +198,157 @@ func TestValidate(t *testing.T) {
 			require.NoError(t, err)
 			require.Empty(t, res.Error)
 			require.JSONEq(t, res.Data, `{"success":true,"error":"","steps":[{"id":1,"name":"Verifying Object Storage","success":true,"error":""},{"id":2,"name":"Verifying Connections","success":true,"error":""},{"id":3,"name":"Verifying Create Schema","success":true,"error":""},{"id":4,"name":"Verifying Create and Alter Table","success":true,"error":""},{"id":5,"name":"Verifying Fetch Schema","success":true,"error":""},{"id":6,"name":"Verifying Load Table","success":true,"error":""}]}`)
+
+			// the probe object shared by the steps is deleted once validated
+			var objects []string
+			for objInfo := range minioResource.Client.ListObjectsV2(minioResource.BucketName, "", true, nil) {
+				require.NoError(t, objInfo.Err)
+				objects = append(objects, objInfo.Key)
+			}
+			require.Empty(t, objects)
+		})
+
+		t.Run("progress", func(t *testing.T) {
+			t.Parallel()
+
+			tr := setup(t, pool)
+			pgResource, minioResource := tr.pgResource, tr.minioResource
+
+			var phases []validations.ProgressPhase
+
+			res, err := validations.Validate(ctx, &model.ValidationRequest{
+				Path: "validate",
+				Step: "6",
+				Destination: &backendconfig.DestinationT{
+					DestinationDefinition: backendconfig.DestinationDefinitionT{
+						Name: warehouseutils.POSTGRES,
+					},
+					Config: map[string]interface{}{
+						"host":            pgResource.Host,
+						"port":            pgResource.Port,
+						"database":        pgResource.Database,
+						"user":            pgResource.User,
+						"password":        pgResource.Password,
+						"sslMode":         sslmode,
+						"namespace":       namespace,
+						"bucketProvider":  provider,
+						"bucketName":      minioResource.BucketName,
+						"accessKeyID":     minioResource.AccessKey,
+						"secretAccessKey": minioResource.SecretKey,
+						"endPoint":        minioResource.Endpoint,
+					},
+				},
+			}, validations.WithProgress(func(step string, phase validations.ProgressPhase) {
+				require.Equal(t, model.VerifyingLoadTable, step)
+				phases = append(phases, phase)
+			}))
+			require.NoError(t, err)
+			require.Empty(t, res.Error)
+			require.Equal(t, []validations.ProgressPhase{
+				validations.ProgressUploadStarted,
+				validations.ProgressUploadDone,
+				validations.ProgressCreateTableDone,
+				validations.ProgressLoadDone,
+			}, phases)
+		})
+
+		t.Run("audit log", func(t *testing.T) {
+			t.Parallel()
+
+			tr := setup(t, pool)
+			pgResource, minioResource := tr.pgResource, tr.minioResource
+
+			mockCtrl := gomock.NewController(t)
+			mockLogger := mock_logger.NewMockLogger(mockCtrl)
+
+			var stepLog map[string]interface{}
+			mockLogger.EXPECT().Infow("validate destination configuration", gomock.Any()).Times(1)
+			mockLogger.EXPECT().Infow("validated destination configuration step", gomock.Any()).Times(1).Do(func(_ string, kvs ...interface{}) {
+				stepLog = make(map[string]interface{})
+				for i := 0; i+1 < len(kvs); i += 2 {
+					stepLog[kvs[i].(string)] = kvs[i+1]
+				}
+			})
+
+			res, err := validations.Validate(ctx, &model.ValidationRequest{
+				Path: "validate",
+				Step: "3",
+				Destination: &backendconfig.DestinationT{
+					DestinationDefinition: backendconfig.DestinationDefinitionT{
+						Name: warehouseutils.POSTGRES,
+					},
+					Config: map[string]interface{}{
+						"host":            pgResource.Host,
+						"port":            pgResource.Port,
+						"database":        pgResource.Database,
+						"user":            pgResource.User,
+						"password":        pgResource.Password,
+						"sslMode":         sslmode,
+						"namespace":       namespace,
+						"bucketProvider":  provider,
+						"bucketName":      minioResource.BucketName,
+						"accessKeyID":     minioResource.AccessKey,
+						"secretAccessKey": minioResource.SecretKey,
+						"endPoint":        minioResource.Endpoint,
+					},
+				},
+			}, validations.WithLogger(mockLogger))
+			require.NoError(t, err)
+			require.Empty(t, res.Error)
+			require.JSONEq(t, res.Data, `{"success":true,"error":"","steps":[{"id":3,"name":"Verifying Create Schema","success":true,"error":""}]}`)
+
+			require.Equal(t, model.VerifyingCreateSchema, stepLog[logfield.DestinationValidationsStep])
+			require.Equal(t, true, stepLog[logfield.DestinationValidationsStepSuccess])
+			require.Positive(t, stepLog[logfield.DestinationValidationsStepDuration])
+			require.Contains(t, strings.Join(stepLog[logfield.Query].([]string), ";"), fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %q`, namespace))
+		})
+
+		t.Run("read only", func(t *testing.T) {
+			t.Parallel()
+
+			tr := setup(t, pool)
+			pgResource, minioResource := tr.pgResource, tr.minioResource
+
+			dest := &backendconfig.DestinationT{
+				DestinationDefinition: backendconfig.DestinationDefinitionT{
+					Name: warehouseutils.POSTGRES,
+				},
+				Config: map[string]interface{}{
+					"host":            pgResource.Host,
+					"port":            pgResource.Port,
+					"database":        pgResource.Database,
+					"user":            pgResource.User,
+					"password":        pgResource.Password,
+					"sslMode":         sslmode,
+					"namespace":       namespace,
+					"bucketProvider":  provider,
+					"bucketName":      minioResource.BucketName,
+					"accessKeyID":     minioResource.AccessKey,
+					"secretAccessKey": minioResource.SecretKey,
+					"endPoint":        minioResource.Endpoint,
+				},
+			}
+
+			t.Run("all steps", func(t *testing.T) {
+				res, err := validations.Validate(ctx, &model.ValidationRequest{
+					Path:        "validate",
+					Step:        "",
+					Destination: dest,
+				}, validations.WithReadOnly())
+				require.NoError(t, err)
+				require.Empty(t, res.Error)
+				require.JSONEq(t, res.Data, `{"success":true,"error":"","steps":[{"id":2,"name":"Verifying Connections","success":true,"error":""},{"id":5,"name":"Verifying Fetch Schema","success":true,"error":""}]}`)
+			})
+			t.Run("mutating step", func(t *testing.T) {
+				res, err := validations.Validate(ctx, &model.ValidationRequest{
+					Path:        "validate",
+					Step:        "3",
+					Destination: dest,
+				}, validations.WithReadOnly())
+				require.NoError(t, err)
+				require.Empty(t, res.Error)
+				require.JSONEq(t, res.Data, `{"success":false,"error":"Step Verifying Create Schema is not allowed in read-only mode","steps":null}`)
+			})
 		})
 
 		t.Run("steps in order", func(t *testing.T) {